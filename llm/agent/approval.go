@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"compass/llm/tools"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// DefaultApprovalTools 默认需要用户批准后才执行的工具。move 和 copy 设置 overwrite 时会覆盖已有文件；
+// restore 只把回收站中的文件放回原路径且从不覆盖，因此不拦截
+var DefaultApprovalTools = []string{
+	tools.WriteToolName,
+	tools.EditToolName,
+	tools.DeleteToolName,
+	tools.MoveToolName,
+	tools.CopyToolName,
+	tools.BashToolName,
+}
+
+// ApprovalToolsFromEnv 读取 COMPASS_APPROVE_TOOLS：逗号分隔的工具名，
+// 未设置时使用 DefaultApprovalTools，"none" 表示不拦截任何工具
+func ApprovalToolsFromEnv() []string {
+	val, ok := os.LookupEnv("COMPASS_APPROVE_TOOLS")
+	if !ok {
+		return DefaultApprovalTools
+	}
+	if strings.EqualFold(strings.TrimSpace(val), "none") {
+		return nil
+	}
+	var names []string
+	for _, name := range strings.Split(val, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ApprovalRequest 中断时携带的待批准工具调用
+type ApprovalRequest struct {
+	Tool      string `json:"tool"`
+	Arguments string `json:"arguments"`
+	CallID    string `json:"call_id"`
+}
+
+// String 格式化为展示给用户的提示
+func (r *ApprovalRequest) String() string {
+	args := r.Arguments
+	var v any
+	if err := json.Unmarshal([]byte(args), &v); err == nil {
+		if pretty, err := json.MarshalIndent(v, "", "  "); err == nil {
+			args = string(pretty)
+		}
+	}
+	return fmt.Sprintf("`%s`\n```json\n%s\n```", r.Tool, args)
+}
+
+// ApprovalResult 恢复运行时传给被中断工具的用户决定
+type ApprovalResult struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func init() {
+	// 中断信息和恢复数据随检查点序列化
+	schema.RegisterName[*ApprovalRequest]("compass_approval_request")
+	schema.RegisterName[*ApprovalResult]("compass_approval_result")
+}
+
+// ApprovalMiddleware 拦截 guarded 中的工具：首次调用时中断并携带 ApprovalRequest，
+// 恢复后根据 ApprovalResult 执行工具或返回拒绝说明
+func ApprovalMiddleware(guarded []string) compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
+				if !slices.Contains(guarded, in.Name) {
+					return next(ctx, in)
+				}
+
+				request := &ApprovalRequest{Tool: in.Name, Arguments: in.Arguments, CallID: in.CallID}
+				wasInterrupted, _, _ := tool.GetInterruptState[any](ctx)
+				if !wasInterrupted {
+					return nil, tool.Interrupt(ctx, request)
+				}
+				// 恢复的是其他中断点时继续等待批准
+				isResume, hasData, result := tool.GetResumeContext[*ApprovalResult](ctx)
+				if !isResume {
+					return nil, tool.Interrupt(ctx, request)
+				}
+				if hasData && result.Approved {
+					return next(ctx, in)
+				}
+
+				msg := fmt.Sprintf("The user rejected this %s call; do not retry it unless asked.", in.Name)
+				if hasData && result.Reason != "" {
+					msg += " Reason: " + result.Reason
+				}
+				return &compose.ToolOutput{Result: msg}, nil
+			}
+		},
+	}
+}
+
+// pendingApproval 被中断、等待用户批准的一次运行
+type pendingApproval struct {
+	checkPointID string
+	requests     map[string]*ApprovalRequest // 中断点 ID -> 待批准的工具调用
+}
+
+// approvalRequests 从中断事件中取出待批准的工具调用
+func approvalRequests(info *adk.InterruptInfo) map[string]*ApprovalRequest {
+	requests := make(map[string]*ApprovalRequest)
+	for _, ic := range info.InterruptContexts {
+		if req, ok := ic.Info.(*ApprovalRequest); ok && ic.IsRootCause {
+			requests[ic.ID] = req
+		}
+	}
+	return requests
+}
+
+// approvalPrompt 生成请求用户批准的系统消息
+func approvalPrompt(requests map[string]*ApprovalRequest) string {
+	var sb strings.Builder
+	sb.WriteString("以下工具调用需要批准:\n\n")
+	for _, id := range sortedKeys(requests) {
+		sb.WriteString(requests[id].String())
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("输入 /approve 执行，或 /reject 拒绝")
+	return sb.String()
+}
+
+// sortedKeys 返回按字典序排列的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// memoryCheckPointStore 在内存中保存被中断运行的检查点
+type memoryCheckPointStore struct {
+	mu          sync.Mutex
+	checkPoints map[string][]byte
+}
+
+func newMemoryCheckPointStore() *memoryCheckPointStore {
+	return &memoryCheckPointStore{checkPoints: make(map[string][]byte)}
+}
+
+// Get 实现 adk.CheckPointStore
+func (s *memoryCheckPointStore) Get(ctx context.Context, checkPointID string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.checkPoints[checkPointID]
+	return data, ok, nil
+}
+
+// Set 实现 adk.CheckPointStore
+func (s *memoryCheckPointStore) Set(ctx context.Context, checkPointID string, checkPoint []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkPoints[checkPointID] = checkPoint
+	return nil
+}
+
+// Delete 实现 adk.CheckPointDeleter
+func (s *memoryCheckPointStore) Delete(ctx context.Context, checkPointID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkPoints, checkPointID)
+	return nil
+}
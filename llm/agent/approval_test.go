@@ -0,0 +1,145 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"compass/llm/tools"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// TestApprovalInterruptsGuardedTool 验证受保护的工具在批准前不执行，批准后从中断处继续
+func TestApprovalInterruptsGuardedTool(t *testing.T) {
+	t.Setenv("COMPASS_APPROVE_TOOLS", "echo")
+	rt, log, events := newPlanRuntime(t, PlanOff)
+
+	if err := rt.Run("question"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+	if !rt.PendingApproval() {
+		t.Fatalf("工具调用应等待批准, 消息: %+v", msgs)
+	}
+	last := msgs[len(msgs)-1]
+	if last.Role != schema.System || !strings.Contains(last.Content, "`echo`") {
+		t.Errorf("应发布包含工具名的批准提示: %+v", last)
+	}
+	if steps := log.list(); strings.Join(steps, ",") != "call" {
+		t.Fatalf("批准前不应执行工具, 实际: %v", steps)
+	}
+
+	if err := rt.ResolveApproval(true); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+	if strings.Join(log.list(), ",") != "call,tool,answer" {
+		t.Errorf("批准后应执行工具并回答, 实际: %v", log.list())
+	}
+	if rt.PendingApproval() {
+		t.Error("批准后不应再有等待中的调用")
+	}
+}
+
+// TestApprovalReject 验证拒绝后工具不执行，模型收到拒绝说明
+func TestApprovalReject(t *testing.T) {
+	t.Setenv("COMPASS_APPROVE_TOOLS", "echo")
+	rt, log, events := newPlanRuntime(t, PlanOff)
+
+	rt.Run("question")
+	collectUntilFinished(t, events)
+	if err := rt.ResolveApproval(false); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+
+	if strings.Join(log.list(), ",") != "call,answer" {
+		t.Errorf("拒绝后不应执行工具, 实际: %v", log.list())
+	}
+	history, _ := rt.store.List(context.Background())
+	var rejected bool
+	for _, msg := range history {
+		if msg.Role == schema.Tool && strings.Contains(msg.Content, "rejected") {
+			rejected = true
+		}
+	}
+	if !rejected {
+		t.Errorf("历史中应包含拒绝结果: %+v", history)
+	}
+}
+
+// TestApprovalDroppedByNewInput 验证新输入放弃等待中的调用，并补上工具结果
+func TestApprovalDroppedByNewInput(t *testing.T) {
+	t.Setenv("COMPASS_APPROVE_TOOLS", "echo")
+	rt, _, events := newPlanRuntime(t, PlanOff)
+
+	rt.Run("question")
+	collectUntilFinished(t, events)
+	rt.Run("another question")
+	collectUntilFinished(t, events)
+
+	history, _ := rt.store.List(context.Background())
+	if len(history) < 3 || history[2].Role != schema.Tool || history[2].ToolCallID != "call_1" {
+		t.Errorf("被放弃的工具调用应有对应结果: %+v", history)
+	}
+}
+
+// moveTool 记录执行的假 move 工具
+type moveTool struct {
+	log *planLog
+}
+
+func (t *moveTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: tools.MoveToolName, Desc: "Move a file."}, nil
+}
+
+func (t *moveTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+	t.log.add("move")
+	return "moved", nil
+}
+
+// TestApprovalGuardsOverwritingMove 验证默认配置下覆盖目标文件的 move 需要批准
+func TestApprovalGuardsOverwritingMove(t *testing.T) {
+	t.Setenv("COMPASS_APPROVE_TOOLS", "")
+	os.Unsetenv("COMPASS_APPROVE_TOOLS")
+	log := &planLog{}
+	reply := func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		if call == 1 {
+			return []*schema.Message{schema.AssistantMessage("", []schema.ToolCall{{
+				ID:       "call_1",
+				Function: schema.FunctionCall{Name: tools.MoveToolName, Arguments: `{"source": "a.txt", "destination": "b.txt", "overwrite": true}`},
+			}})}, nil
+		}
+		return []*schema.Message{schema.AssistantMessage("done", nil)}, nil
+	}
+	rt, events := newTestRuntime(t, &scriptedModel{reply: reply}, &moveTool{log: log})
+
+	if err := rt.Run("replace b.txt with a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+	if !rt.PendingApproval() {
+		t.Fatalf("覆盖文件的 move 应等待批准, 消息: %+v", msgs)
+	}
+	if last := msgs[len(msgs)-1]; !strings.Contains(last.Content, `"overwrite": true`) {
+		t.Errorf("批准提示应包含调用参数: %q", last.Content)
+	}
+	if steps := log.list(); len(steps) != 0 {
+		t.Errorf("批准前不应移动文件, 实际: %v", steps)
+	}
+}
+
+// TestApprovalToolsFromEnv 验证受保护工具列表的解析
+func TestApprovalToolsFromEnv(t *testing.T) {
+	t.Setenv("COMPASS_APPROVE_TOOLS", " write, bash ,")
+	if got := ApprovalToolsFromEnv(); strings.Join(got, ",") != "write,bash" {
+		t.Errorf("got %v", got)
+	}
+	t.Setenv("COMPASS_APPROVE_TOOLS", "none")
+	if got := ApprovalToolsFromEnv(); got != nil {
+		t.Errorf("none 应关闭批准, got %v", got)
+	}
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"compass/llm/tools"
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// AutoSaveAnswersFromEnv 读取 COMPASS_AUTO_SAVE_ANSWERS："true" 时将经过网络调研的最终回答自动存入知识库
+func AutoSaveAnswersFromEnv() bool {
+	return os.Getenv("COMPASS_AUTO_SAVE_ANSWERS") == "true"
+}
+
+// hasWebSources 判断本轮是否查阅过网页
+func hasWebSources(sources []string) bool {
+	for _, s := range sources {
+		if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+			return true
+		}
+	}
+	return false
+}
+
+// autoSaveAnswer 将经过网络调研的最终回答存入知识库，相同回答只保存一次。
+// 保存失败只记录日志，不影响本轮运行结果。
+func (r *Runtime) autoSaveAnswer(history []adk.Message, answer adk.Message, sources []string) {
+	if !hasWebSources(sources) {
+		return
+	}
+
+	var query string
+	if idx := lastUserIndex(history); idx != -1 {
+		query = history[idx].Content
+	}
+
+	saved, err := tools.SaveAnswer(r.ctx, tools.AutoSaveCollectionFromEnv(), tools.SavedAnswer{
+		Query:   query,
+		Answer:  answer.Content,
+		Sources: sources,
+		SavedAt: time.Now(),
+	})
+	if err != nil {
+		slog.Error("自动保存回答失败", "err", err)
+		return
+	}
+	if saved {
+		r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
+			Role:    schema.System,
+			Content: "已将回答保存到知识库",
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"compass/llm"
+	"compass/llm/tools"
+	"compass/llm/vector"
+)
+
+// answerStore 记录写入文档的内存向量存储，只实现自动保存用到的方法，
+// 调用其余方法时因嵌入的接口为 nil 而 panic
+type answerStore struct {
+	vector.VectorStore
+	docs []llm.Document
+}
+
+func (s *answerStore) AddBatch(ctx context.Context, docs []llm.Document, opts ...vector.AddOption) error {
+	s.docs = append(s.docs, docs...)
+	return nil
+}
+
+func (s *answerStore) List(ctx context.Context, filter llm.ListFilter) ([]llm.Document, error) {
+	var docs []llm.Document
+	for _, d := range s.docs {
+		if filter.Source == "" || d.Source == filter.Source {
+			docs = append(docs, d)
+		}
+	}
+	return docs, nil
+}
+
+// useAnswerStore 安装内存知识库，测试结束后移除
+func useAnswerStore(t *testing.T) *answerStore {
+	t.Helper()
+	store := &answerStore{}
+	tools.InitKnowledgeVectorStore(store, nil, nil)
+	t.Cleanup(func() { tools.InitKnowledgeVectorStore(nil, nil, nil) })
+	return store
+}
+
+// TestAutoSaveAnswer 验证启用时经过网络调研的回答带元数据存入知识库，且只保存一次
+func TestAutoSaveAnswer(t *testing.T) {
+	store := useAnswerStore(t)
+	rt, events := newSourcesRuntime(t, true)
+	rt.autoSaveAnswers = true
+
+	if err := rt.Run("tell me about go"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+	if last := msgs[len(msgs)-1]; last.Content != "已将回答保存到知识库" {
+		t.Errorf("应提示回答已保存, 实际: %q", last.Content)
+	}
+
+	if len(store.docs) != 1 {
+		t.Fatalf("保存了 %d 个分块, want 1", len(store.docs))
+	}
+	doc := store.docs[0]
+	// 保存的是不含来源脚注的原始回答，来源记录在元数据中
+	if doc.Content != "Go is great." || doc.FileType != tools.SavedAnswerFileType {
+		t.Errorf("保存的文档不正确: %+v", doc)
+	}
+	if doc.Metadata["query"] != "tell me about go" || doc.Metadata["saved_at"] == "" {
+		t.Errorf("元数据不正确: %+v", doc.Metadata)
+	}
+	sources, _ := doc.Metadata["sources"].([]string)
+	if len(sources) != 2 || sources[0] != "https://go.dev/doc/" || sources[1] != "https://go.dev/blog/" {
+		t.Errorf("来源元数据不正确: %v", sources)
+	}
+
+	// 相同回答不重复保存
+	if err := rt.Run("tell me about go again"); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+	if len(store.docs) != 1 {
+		t.Errorf("重复回答不应再次保存, 实际 %d 个分块", len(store.docs))
+	}
+}
+
+// TestAutoSaveAnswerDisabled 验证关闭时不保存回答
+func TestAutoSaveAnswerDisabled(t *testing.T) {
+	store := useAnswerStore(t)
+	rt, events := newSourcesRuntime(t, false)
+
+	if err := rt.Run("tell me about go"); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+	if len(store.docs) != 0 {
+		t.Errorf("关闭自动保存时不应写入知识库: %+v", store.docs)
+	}
+}
+
+// TestHasWebSources 验证只有网页来源才算网络调研
+func TestHasWebSources(t *testing.T) {
+	if hasWebSources([]string{"/docs/manual.md"}) {
+		t.Error("知识库来源不算网络调研")
+	}
+	if !hasWebSources([]string{"/docs/manual.md", "https://go.dev/"}) {
+		t.Error("包含网页来源应算网络调研")
+	}
+}
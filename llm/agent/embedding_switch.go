@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"compass/llm/providers"
+	"compass/llm/tools"
+	"compass/llm/vector"
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrEmbeddingDimMismatch 新 embedding 模型的向量维度与现有索引不一致
+var ErrEmbeddingDimMismatch = errors.New("embedding dimension mismatch")
+
+// EmbedderFactory 根据 provider 和模型名创建 embedding 模型
+type EmbedderFactory func(ctx context.Context, provider, name string) (embedding.Embedder, error)
+
+// SwitchEmbeddingModel 切换知识库使用的 embedding 模型。
+// 新模型的向量维度必须与现有索引一致，否则拒绝切换并说明如何重建索引。
+func (r *Runtime) SwitchEmbeddingModel(provider, name string) error {
+	swapper, ok := r.vectorStore.(vector.EmbedderSwapper)
+	if !ok {
+		return errors.New("知识库未启用或不支持切换 embedding 模型")
+	}
+
+	embedder, err := r.newEmbedder(r.ctx, provider, name)
+	if err != nil {
+		return fmt.Errorf("创建 embedding 模型失败: %w", err)
+	}
+
+	dim, err := vector.ProbeDimension(r.ctx, embedder)
+	if err != nil {
+		return err
+	}
+	if want := swapper.EmbeddingDim(); dim != want {
+		return fmt.Errorf("%w: %s 生成 %d 维向量，现有索引为 %d 维。"+
+			"请设置 VECTOR_DIM=%d 和新的 VECTOR_INDEX_NAME 后重启，并重新导入文档",
+			ErrEmbeddingDimMismatch, embeddingLabel(provider, name), dim, want, dim)
+	}
+
+	swapper.SetEmbedder(embedder)
+	tools.SetKnowledgeEmbedder(embedder)
+	r.embedder = embedder
+	return nil
+}
+
+// EmbeddingCommand 处理 /embedding 命令：无参数时列出已配置的 provider，
+// "<provider> [model]" 切换模型。结果以系统消息发布。
+func (r *Runtime) EmbeddingCommand(args []string) {
+	var content string
+	switch {
+	case len(args) == 0:
+		names := providers.EmbeddingProviders()
+		if len(names) == 0 {
+			content = "没有已配置的 embedding provider（需要 EMBEDDING_MODEL_API_KEY 或 EMBEDDING_<NAME>_API_KEY）"
+		} else {
+			content = "已配置的 embedding provider: " + strings.Join(names, ", ") +
+				"\n使用 /embedding <provider> [model] 切换"
+		}
+	case len(args) > 2:
+		content = "用法: /embedding <provider> [model]"
+	default:
+		provider, name := args[0], ""
+		if len(args) == 2 {
+			name = args[1]
+		}
+		if err := r.SwitchEmbeddingModel(provider, name); err != nil {
+			content = fmt.Sprintf("错误: %v", err)
+		} else {
+			content = fmt.Sprintf("已切换到 %s；之前导入的文档仍是旧模型的向量，建议重新导入以保证检索质量",
+				embeddingLabel(provider, name))
+		}
+	}
+
+	r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
+		Role:    schema.System,
+		Content: content,
+	})
+}
+
+// embeddingLabel 返回用于展示的 provider/模型名
+func embeddingLabel(provider, name string) string {
+	if name == "" {
+		return provider
+	}
+	return provider + "/" + name
+}
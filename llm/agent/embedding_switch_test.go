@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// dimEmbedder 返回固定维度向量的假 embedding 模型
+type dimEmbedder struct {
+	dim int
+}
+
+func (e *dimEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = make([]float64, e.dim)
+	}
+	return vectors, nil
+}
+
+// swappableStore 记录当前 embedding 模型的假向量存储
+type swappableStore struct {
+	answerStore
+	dim      int
+	embedder embedding.Embedder
+}
+
+func (s *swappableStore) EmbeddingDim() int { return s.dim }
+
+func (s *swappableStore) SetEmbedder(embedder embedding.Embedder) { s.embedder = embedder }
+
+func (s *swappableStore) Close() error { return nil }
+
+// newSwitchRuntime 创建带 1024 维知识库的 Runtime，provider 对应的假模型维度由 dims 给出
+func newSwitchRuntime(t *testing.T, dims map[string]int) (*Runtime, *swappableStore) {
+	t.Helper()
+	rt, _ := newTestRuntime(t, &scriptedModel{reply: textReply("answer")})
+
+	store := &swappableStore{dim: 1024}
+	rt.vectorStore = store
+	rt.newEmbedder = func(ctx context.Context, provider, name string) (embedding.Embedder, error) {
+		dim, ok := dims[provider]
+		if !ok {
+			return nil, errors.New("provider not configured")
+		}
+		return &dimEmbedder{dim: dim}, nil
+	}
+	return rt, store
+}
+
+// TestSwitchEmbeddingModel 验证维度一致时切换成功
+func TestSwitchEmbeddingModel(t *testing.T) {
+	rt, store := newSwitchRuntime(t, map[string]int{"glm": 1024})
+
+	if err := rt.SwitchEmbeddingModel("glm", "embedding-3"); err != nil {
+		t.Fatalf("维度一致的切换应成功: %v", err)
+	}
+	if e, ok := store.embedder.(*dimEmbedder); !ok || e.dim != 1024 {
+		t.Errorf("存储未使用新模型: %+v", store.embedder)
+	}
+}
+
+// TestSwitchEmbeddingModelDimMismatch 验证维度不一致时拒绝切换并给出重建指引
+func TestSwitchEmbeddingModelDimMismatch(t *testing.T) {
+	rt, store := newSwitchRuntime(t, map[string]int{"openai": 1536})
+
+	err := rt.SwitchEmbeddingModel("openai", "text-embedding-3-small")
+	if !errors.Is(err, ErrEmbeddingDimMismatch) {
+		t.Fatalf("维度不一致应返回 ErrEmbeddingDimMismatch, 实际: %v", err)
+	}
+	for _, want := range []string{"1536", "1024", "VECTOR_DIM=1536", "VECTOR_INDEX_NAME"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("错误信息缺少 %q: %v", want, err)
+		}
+	}
+	if store.embedder != nil {
+		t.Error("拒绝切换时不应替换模型")
+	}
+
+	if err := rt.SwitchEmbeddingModel("missing", ""); err == nil {
+		t.Error("未配置的 provider 应返回错误")
+	}
+}
+
+// TestSwitchEmbeddingModelWithoutStore 验证未启用知识库时返回错误
+func TestSwitchEmbeddingModelWithoutStore(t *testing.T) {
+	rt, _ := newSwitchRuntime(t, map[string]int{"glm": 1024})
+	rt.vectorStore = nil
+	if err := rt.SwitchEmbeddingModel("glm", ""); err == nil {
+		t.Error("未启用知识库时应返回错误")
+	}
+}
@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudwego/eino/adk"
+)
+
+// DefaultConversationFile FileStore 默认使用的历史文件
+const DefaultConversationFile = ".compass/conversation.jsonl"
+
+// FileStore 将对话以 JSONL 追加写入文件的对话存储，启动时从文件恢复历史。
+// 内存中的 token 预算窗口和工具结果压缩与 MemoryStore 相同，文件中被淘汰的旧消息会定期压实。
+type FileStore struct {
+	mem   *MemoryStore
+	path  string
+	mu    sync.Mutex // 保护文件写入
+	lines int        // 文件当前行数
+}
+
+// NewFileStore 创建文件存储，文件已存在时加载其中的历史
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("创建对话目录失败: %w", err)
+	}
+
+	s := &FileStore{mem: NewMemoryStore(), path: path}
+	msgs, err := readConversationFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s.mem.restore(msgs)
+	s.lines = len(msgs)
+
+	// 历史超出预算时重写文件，只保留窗口内的消息
+	if s.lines > s.mem.len() {
+		if err := s.rewrite(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// readConversationFile 读取 JSONL 历史文件；文件不存在时返回空历史，无法解析的行会被跳过
+func readConversationFile(path string) ([]adk.Message, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取对话文件失败: %w", err)
+	}
+
+	var msgs []adk.Message
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg adk.Message
+		if err := json.Unmarshal(line, &msg); err != nil || msg == nil {
+			slog.Warn("跳过无法解析的对话记录", "err", err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取对话文件失败: %w", err)
+	}
+	return msgs, nil
+}
+
+// Add 添加一条消息并追加写入文件
+func (s *FileStore) Add(ctx context.Context, msg adk.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.mem.push(ctx, msg)
+	line, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开对话文件失败: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("写入对话文件失败: %w", err)
+	}
+	s.lines++
+
+	// 文件累积的已淘汰消息多于窗口内的消息时压实
+	if s.lines > 2*s.mem.len() {
+		return s.rewrite()
+	}
+	return nil
+}
+
+// List 获取所有消息
+func (s *FileStore) List(ctx context.Context) ([]adk.Message, error) {
+	return s.mem.List(ctx)
+}
+
+// Clear 清空所有消息并清空文件
+func (s *FileStore) Clear(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.mem.Clear(ctx); err != nil {
+		return err
+	}
+	return s.rewrite()
+}
+
+// Truncate 只保留前 n 条消息，并同步重写文件
+func (s *FileStore) Truncate(ctx context.Context, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.mem.Truncate(ctx, n); err != nil {
+		return err
+	}
+	return s.rewrite()
+}
+
+// rewrite 用内存中的历史原子地重写文件（调用方持有 s.mu）
+func (s *FileStore) rewrite() error {
+	msgs, _ := s.mem.List(context.Background())
+
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		line, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("序列化消息失败: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("写入对话文件失败: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("写入对话文件失败: %w", err)
+	}
+	s.lines = len(msgs)
+	return nil
+}
@@ -0,0 +1,127 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// TestFileStorePersistsAcrossReopen 验证消息写入文件并在重新打开时恢复
+func TestFileStorePersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "conversation.jsonl")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Add(ctx, schema.UserMessage("question"))
+	s.Add(ctx, schema.ToolMessage(strings.Repeat("x", 5000), "call_1"))
+	s.Add(ctx, schema.AssistantMessage("answer", nil))
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, _ := reopened.List(ctx)
+	if len(history) != 3 {
+		t.Fatalf("期望恢复 3 条消息, 实际 %d", len(history))
+	}
+	if history[0].Content != "question" || history[2].Content != "answer" {
+		t.Errorf("恢复的消息顺序或内容错误: %+v", history)
+	}
+	if tool := history[1].Content; len(tool) >= 5000 || !strings.Contains(tool, "[Content truncated") {
+		t.Errorf("工具响应应压缩后再保存, 实际长度 %d", len(tool))
+	}
+}
+
+// TestFileStoreTruncateAndClear 验证截断和清空同步到文件
+func TestFileStoreTruncateAndClear(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "conversation.jsonl")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []string{"a", "b", "c"} {
+		s.Add(ctx, schema.UserMessage(c))
+	}
+	if err := s.Truncate(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := reopened.List(ctx); len(history) != 1 || history[0].Content != "a" {
+		t.Errorf("截断后应只保留第一条消息: %+v", history)
+	}
+
+	if err := reopened.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("清空后文件应为空, 实际: %q", data)
+	}
+}
+
+// TestFileStoreCompactsOldMessages 验证文件中被淘汰的旧消息会被压实
+func TestFileStoreCompactsOldMessages(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "conversation.jsonl")
+	t.Setenv("CONVERSATION_MAX_TOKENS", "100")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 60; i++ {
+		s.Add(ctx, schema.UserMessage(strings.Repeat("m", 30)))
+	}
+	kept := s.mem.len()
+	if kept == 0 || kept >= 60 {
+		t.Fatalf("超出预算时应淘汰旧消息, 实际保留 %d 条", kept)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines > 2*kept {
+		t.Errorf("文件行数应不超过 %d, 实际 %d", 2*kept, lines)
+	}
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := reopened.List(ctx); len(history) != kept {
+		t.Errorf("恢复后应保留 %d 条消息, 实际 %d", kept, len(history))
+	}
+}
+
+// TestConversationStoreFromEnv 验证根据环境变量选择对话存储
+func TestConversationStoreFromEnv(t *testing.T) {
+	ctx := context.Background()
+
+	t.Setenv("CONVERSATION_STORE", "")
+	if s, err := ConversationStoreFromEnv(ctx); err != nil {
+		t.Fatal(err)
+	} else if _, ok := s.(*MemoryStore); !ok {
+		t.Errorf("默认应使用内存存储, 实际 %T", s)
+	}
+
+	t.Setenv("CONVERSATION_STORE", "file")
+	t.Setenv("CONVERSATION_FILE", filepath.Join(t.TempDir(), "c.jsonl"))
+	if s, err := ConversationStoreFromEnv(ctx); err != nil {
+		t.Fatal(err)
+	} else if _, ok := s.(*FileStore); !ok {
+		t.Errorf("file 应使用文件存储, 实际 %T", s)
+	}
+
+	t.Setenv("CONVERSATION_STORE", "bogus")
+	if _, err := ConversationStoreFromEnv(ctx); err == nil {
+		t.Error("未知的存储类型应返回错误")
+	}
+}
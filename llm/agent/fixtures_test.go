@@ -0,0 +1,193 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// replyFunc 生成第 call 次（从 1 开始）调用的回复片段，非流式调用时拼接为一条消息
+type replyFunc func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error)
+
+// scriptedModel 记录每次调用的输入，按 reply 生成回复的假模型；usage 非空时附加到回复上
+type scriptedModel struct {
+	reply replyFunc
+	usage *schema.TokenUsage
+
+	mu     sync.Mutex
+	inputs [][]*schema.Message
+}
+
+// respond 记录输入并生成回复片段；reply 调用期间不持有锁，可以阻塞
+func (m *scriptedModel) respond(ctx context.Context, input []*schema.Message) ([]*schema.Message, error) {
+	m.mu.Lock()
+	m.inputs = append(m.inputs, input)
+	call := len(m.inputs)
+	m.mu.Unlock()
+
+	msgs, err := m.reply(ctx, input, call)
+	if err != nil || m.usage == nil || len(msgs) == 0 {
+		return msgs, err
+	}
+	last := *msgs[len(msgs)-1]
+	last.ResponseMeta = &schema.ResponseMeta{Usage: m.usage}
+	return append(msgs[:len(msgs)-1:len(msgs)-1], &last), nil
+}
+
+func (m *scriptedModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	msgs, err := m.respond(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return schema.ConcatMessages(msgs)
+}
+
+func (m *scriptedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msgs, err := m.respond(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray(msgs), nil
+}
+
+func (m *scriptedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+// calls 返回已调用的次数
+func (m *scriptedModel) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.inputs)
+}
+
+// lastInput 返回最近一次调用的输入
+func (m *scriptedModel) lastInput() []*schema.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inputs[len(m.inputs)-1]
+}
+
+// textReply 每次调用都返回固定回复
+func textReply(content string) replyFunc {
+	return func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		return []*schema.Message{schema.AssistantMessage(content, nil)}, nil
+	}
+}
+
+// echoToolCall 调用 echo 工具的助手消息
+func echoToolCall() *schema.Message {
+	return schema.AssistantMessage("", []schema.ToolCall{{
+		ID:       "call_1",
+		Function: schema.FunctionCall{Name: "echo", Arguments: `{}`},
+	}})
+}
+
+// planLog 按顺序记录模型和工具的调用
+type planLog struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (l *planLog) add(step string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.steps = append(l.steps, step)
+}
+
+func (l *planLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.steps...)
+}
+
+// planReply 计划请求返回 JSON 计划，否则先调用 echo 工具再回答，每一步记录到 log
+func planReply(log *planLog) replyFunc {
+	return func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		if len(input) > 0 && input[0].Role == schema.System && strings.HasPrefix(input[0].Content, planPrompt) {
+			log.add("plan")
+			return []*schema.Message{schema.AssistantMessage("```json\n{\"steps\": [{\"tool\": \"echo\", \"rationale\": \"check the input\"}]}\n```", nil)}, nil
+		}
+		if input[len(input)-1].Role == schema.Tool {
+			log.add("answer")
+			return []*schema.Message{schema.AssistantMessage("done", nil)}, nil
+		}
+		log.add("call")
+		return []*schema.Message{echoToolCall()}, nil
+	}
+}
+
+// echoTool 记录执行的假工具
+type echoTool struct {
+	log *planLog
+}
+
+func (t *echoTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "echo", Desc: "Echo the input.\nMore details."}, nil
+}
+
+func (t *echoTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+	t.log.add("tool")
+	return "echoed", nil
+}
+
+// newTestRuntime 使用假模型和工具创建 Runtime，并订阅其事件
+func newTestRuntime(t *testing.T, m *scriptedModel, tools ...tool.BaseTool) (*Runtime, <-chan pubsub.Event[adk.Message]) {
+	t.Helper()
+	rt, err := NewRuntime(context.Background(), m, tools)
+	if err != nil {
+		t.Fatalf("创建 Runtime 失败: %v", err)
+	}
+	t.Cleanup(rt.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return rt, rt.Broker().Subscribe(ctx)
+}
+
+// newPlanRuntime 创建使用 planReply 模型和 echo 工具的 Runtime
+func newPlanRuntime(t *testing.T, mode PlanMode) (*Runtime, *planLog, <-chan pubsub.Event[adk.Message]) {
+	t.Helper()
+	log := &planLog{}
+	rt, events := newTestRuntime(t, &scriptedModel{reply: planReply(log)}, &echoTool{log: log})
+	rt.planMode = mode
+	return rt, log, events
+}
+
+// collectUntilFinished 收集一轮运行发布的消息
+func collectUntilFinished(t *testing.T, events <-chan pubsub.Event[adk.Message]) []adk.Message {
+	t.Helper()
+	var msgs []adk.Message
+	for {
+		select {
+		case e := <-events:
+			if e.Type == pubsub.FinishedEvent {
+				return msgs
+			}
+			if e.Type == pubsub.UpdatedEvent {
+				msgs = append(msgs, e.Payload)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("等待运行结束超时")
+		}
+	}
+}
+
+// collectContents 收集一轮运行发布的消息内容
+func collectContents(t *testing.T, events <-chan pubsub.Event[adk.Message]) []string {
+	t.Helper()
+	var contents []string
+	for _, msg := range collectUntilFinished(t, events) {
+		contents = append(contents, msg.Content)
+	}
+	return contents
+}
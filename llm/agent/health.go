@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"compass/llm/tools"
+	"compass/llm/vector"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+)
+
+// healthCheckTimeout 单项检查的时间上限
+const healthCheckTimeout = 30 * time.Second
+
+// HealthStatus 单项检查的状态
+type HealthStatus string
+
+const (
+	HealthOK      HealthStatus = "ok"      // 依赖可用
+	HealthFailed  HealthStatus = "failed"  // 依赖不可用
+	HealthSkipped HealthStatus = "skipped" // 未配置，无需检查
+)
+
+// HealthCheckResult 单个依赖的检查结果
+type HealthCheckResult struct {
+	Name     string
+	Status   HealthStatus
+	Detail   string // 成功时的说明，失败时的原因
+	Duration time.Duration
+}
+
+// HealthReport 健康检查报告
+type HealthReport struct {
+	Checks []HealthCheckResult
+}
+
+// Failed 返回失败的检查项
+func (r HealthReport) Failed() []HealthCheckResult {
+	var failed []HealthCheckResult
+	for _, c := range r.Checks {
+		if c.Status == HealthFailed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// String 将报告格式化为对齐的表格
+func (r HealthReport) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Name, c.Status, c.Duration.Round(time.Millisecond), c.Detail)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// checkWebSearch 检查网页搜索连通性，测试中可替换
+var checkWebSearch = tools.CheckSearch
+
+// HealthCheck 依次检查聊天模型、embedding 模型、Redis、向量存储和网页搜索，
+// 返回每项的结果。有检查失败时同时返回错误，报告仍然完整。
+func (r *Runtime) HealthCheck(ctx context.Context) (HealthReport, error) {
+	var report HealthReport
+	run := func(name string, check func(ctx context.Context) (HealthStatus, string)) {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+		start := time.Now()
+		status, detail := check(checkCtx)
+		report.Checks = append(report.Checks, HealthCheckResult{
+			Name:     name,
+			Status:   status,
+			Detail:   detail,
+			Duration: time.Since(start),
+		})
+	}
+
+	run("chat model", r.checkChatModel)
+	run("embedding model", r.checkEmbedder)
+	run("redis", checkRedis)
+	run("vector store", r.checkVectorStore)
+	run("web search", func(ctx context.Context) (HealthStatus, string) {
+		n, err := checkWebSearch(ctx)
+		if err != nil {
+			return HealthFailed, err.Error()
+		}
+		if n == 0 {
+			return HealthFailed, "搜索没有返回结果，可能被限流或拦截"
+		}
+		return HealthOK, fmt.Sprintf("%d 条结果", n)
+	})
+
+	if failed := report.Failed(); len(failed) > 0 {
+		names := make([]string, len(failed))
+		for i, c := range failed {
+			names[i] = c.Name
+		}
+		return report, fmt.Errorf("%d 项检查失败: %s", len(failed), strings.Join(names, ", "))
+	}
+	return report, nil
+}
+
+// checkChatModel 用简单提示词调用聊天模型
+func (r *Runtime) checkChatModel(ctx context.Context) (HealthStatus, string) {
+	if r.chatModel == nil {
+		return HealthFailed, "未创建聊天模型"
+	}
+	msg, err := r.chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("Reply with OK.")})
+	if err != nil {
+		return HealthFailed, err.Error()
+	}
+	reply := strings.TrimSpace(msg.Content)
+	if len([]rune(reply)) > 40 {
+		reply = string([]rune(reply)[:40]) + "..."
+	}
+	return HealthOK, fmt.Sprintf("回复 %q", reply)
+}
+
+// checkEmbedder 用探测文本调用 embedding 模型，并核对向量维度
+func (r *Runtime) checkEmbedder(ctx context.Context) (HealthStatus, string) {
+	if r.embedder == nil {
+		return HealthFailed, r.knowledgeDisabledReason()
+	}
+	dim, err := vector.ProbeDimension(ctx, r.embedder)
+	if err != nil {
+		return HealthFailed, err.Error()
+	}
+	if swapper, ok := r.vectorStore.(vector.EmbedderSwapper); ok && swapper.EmbeddingDim() != dim {
+		return HealthFailed, fmt.Sprintf("生成 %d 维向量，索引为 %d 维", dim, swapper.EmbeddingDim())
+	}
+	return HealthOK, fmt.Sprintf("%d 维", dim)
+}
+
+// checkRedis 对 REDIS_ADDR 执行 PING，未配置时跳过
+func checkRedis(ctx context.Context) (HealthStatus, string) {
+	if os.Getenv("REDIS_ADDR") == "" {
+		return HealthSkipped, "REDIS_ADDR 未设置，使用本地向量存储"
+	}
+	cfg := vector.DefaultRedisConfig()
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	defer client.Close()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return HealthFailed, err.Error()
+	}
+	return HealthOK, cfg.Addr
+}
+
+// checkVectorStore 统计向量存储中的文档数
+func (r *Runtime) checkVectorStore(ctx context.Context) (HealthStatus, string) {
+	if r.vectorStore == nil {
+		return HealthFailed, r.knowledgeDisabledReason()
+	}
+	n, err := r.vectorStore.Count(ctx)
+	if err != nil {
+		return HealthFailed, err.Error()
+	}
+	return HealthOK, fmt.Sprintf("%d 个文档块", n)
+}
+
+// knowledgeDisabledReason 说明知识库为何未启用
+func (r *Runtime) knowledgeDisabledReason() string {
+	if r.vectorStoreErr != nil {
+		return "知识库未启用: " + r.vectorStoreErr.Error()
+	}
+	return "知识库未启用"
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestHealthCheck 验证报告包含每项检查，失败项返回错误并说明知识库未启用的原因
+func TestHealthCheck(t *testing.T) {
+	t.Setenv("REDIS_ADDR", "")
+	rt, _ := newTestRuntime(t, &scriptedModel{reply: textReply("answer")})
+	rt.vectorStoreErr = errors.New("embedding 模型未配置")
+
+	orig := checkWebSearch
+	t.Cleanup(func() { checkWebSearch = orig })
+	checkWebSearch = func(ctx context.Context) (int, error) { return 3, nil }
+
+	report, err := rt.HealthCheck(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "embedding model, vector store") {
+		t.Fatalf("expected embedding and vector store failures, got %v", err)
+	}
+
+	want := map[string]HealthStatus{
+		"chat model":      HealthOK,
+		"embedding model": HealthFailed,
+		"redis":           HealthSkipped,
+		"vector store":    HealthFailed,
+		"web search":      HealthOK,
+	}
+	if len(report.Checks) != len(want) {
+		t.Fatalf("unexpected checks: %+v", report.Checks)
+	}
+	for _, c := range report.Checks {
+		if c.Status != want[c.Name] {
+			t.Errorf("%s: status %s, want %s (%s)", c.Name, c.Status, want[c.Name], c.Detail)
+		}
+	}
+	if out := report.String(); !strings.Contains(out, "embedding 模型未配置") || !strings.Contains(out, `"answer"`) {
+		t.Errorf("report lacks details:\n%s", out)
+	}
+}
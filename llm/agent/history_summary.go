@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// historySummaryExtraKey 标记由淘汰消息生成的摘要，存放在 Message.Extra 中
+	historySummaryExtraKey = "compass_history_summary"
+	// historySummaryHeading 摘要消息的开头
+	historySummaryHeading = "Summary of earlier conversation:\n"
+	// maxHistorySummaryInput 发送给摘要模型的对话文本上限（字符数）
+	maxHistorySummaryInput = 64 * 1024
+)
+
+const historySummaryPrompt = `You compress older parts of a conversation between a user and a technical learning assistant.
+Write a short summary (at most 10 bullet points) that preserves:
+- what the user asked for and any stated preferences or constraints
+- key facts, decisions and conclusions reached
+- URLs, file paths, commands and names that later turns may refer to
+
+If a previous summary is given, merge it with the new messages into one summary.
+Respond with ONLY the summary, in the language of the conversation.`
+
+// HistorySummaryFromEnv 读取 CONVERSATION_SUMMARIZE："true" 时内存存储淘汰消息前先由摘要模型压缩为摘要
+func HistorySummaryFromEnv() bool {
+	return os.Getenv("CONVERSATION_SUMMARIZE") == "true"
+}
+
+// isHistorySummary 判断消息是否为淘汰消息的摘要
+func isHistorySummary(msg adk.Message) bool {
+	if msg == nil || msg.Extra == nil {
+		return false
+	}
+	summary, _ := msg.Extra[historySummaryExtraKey].(bool)
+	return summary
+}
+
+// historySummaryMessage 创建摘要系统消息
+func historySummaryMessage(summary string) adk.Message {
+	msg := schema.SystemMessage(historySummaryHeading + strings.TrimSpace(summary))
+	msg.Extra = map[string]any{historySummaryExtraKey: true}
+	return msg
+}
+
+// evictForSummary 按 token 预算淘汰旧消息，返回保留已有摘要的窗口、已有摘要和被淘汰的消息。
+// 调用方将已有摘要和淘汰的消息合并为新摘要后替换窗口中的旧摘要；摘要失败时保留旧摘要，
+// 新淘汰的消息直接丢弃。
+func (s *MemoryStore) evictForSummary(msgs []adk.Message) (window []adk.Message, prev adk.Message, evicted []adk.Message) {
+	rest := make([]adk.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if prev == nil && isHistorySummary(msg) {
+			prev = msg
+			continue
+		}
+		rest = append(rest, msg)
+	}
+
+	// 已有摘要占用的预算从窗口中扣除
+	budget := s.maxTokens
+	if prev != nil {
+		budget -= estimateTokens(prev)
+	}
+	kept, evicted := splitTokenBudget(rest, budget)
+	return withHistorySummary(kept, prev), prev, evicted
+}
+
+// replaceHistorySummary 用新摘要替换窗口中的旧摘要
+func replaceHistorySummary(msgs []adk.Message, summary adk.Message) []adk.Message {
+	rest := make([]adk.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		if !isHistorySummary(msg) {
+			rest = append(rest, msg)
+		}
+	}
+	return withHistorySummary(rest, summary)
+}
+
+// withHistorySummary 将摘要插入窗口开头（保留的第一条系统消息之后）
+func withHistorySummary(msgs []adk.Message, summary adk.Message) []adk.Message {
+	if summary == nil {
+		return msgs
+	}
+	at := 0
+	if len(msgs) > 0 && msgs[0].Role == schema.System {
+		at = 1
+	}
+	result := make([]adk.Message, 0, len(msgs)+1)
+	result = append(result, msgs[:at]...)
+	result = append(result, summary)
+	return append(result, msgs[at:]...)
+}
+
+// summarizeHistory 调用模型将已有摘要和淘汰的消息压缩为一条摘要消息
+func summarizeHistory(ctx context.Context, m model.BaseChatModel, prev adk.Message, evicted []adk.Message) (adk.Message, error) {
+	var sb strings.Builder
+	if prev != nil {
+		sb.WriteString("Previous summary:\n")
+		sb.WriteString(strings.TrimPrefix(prev.Content, historySummaryHeading))
+		sb.WriteString("\n\nNew messages:\n")
+	}
+	for _, msg := range evicted {
+		sb.WriteString(formatHistoryMessage(msg))
+		sb.WriteString("\n")
+	}
+	input := sb.String()
+	if len(input) > maxHistorySummaryInput {
+		input = input[:maxHistorySummaryInput]
+	}
+
+	resp, err := m.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(historySummaryPrompt),
+		schema.UserMessage(input),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(resp.Content) == "" {
+		return nil, errors.New("摘要为空")
+	}
+	return historySummaryMessage(resp.Content), nil
+}
+
+// formatHistoryMessage 将消息格式化为一行对话记录
+func formatHistoryMessage(msg adk.Message) string {
+	line := fmt.Sprintf("%s: %s", msg.Role, msg.Content)
+	for _, tc := range msg.ToolCalls {
+		line += fmt.Sprintf("\n%s called %s(%s)", msg.Role, tc.Function.Name, tc.Function.Arguments)
+	}
+	return line
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// PlanExtraKey 标记计划消息的 schema.Message.Extra 键，供界面区分渲染
+const PlanExtraKey = "compass_plan"
+
+// PlanMode 计划展示模式
+type PlanMode int
+
+const (
+	// PlanOff 不生成计划
+	PlanOff PlanMode = iota
+	// PlanShow 先展示计划再执行
+	PlanShow
+	// PlanApprove 展示计划并等待用户批准后执行
+	PlanApprove
+)
+
+// PlanModeFromEnv 读取 COMPASS_SHOW_PLAN：
+//   - "true": 执行前展示计划
+//   - "approve": 展示计划，并在用户输入 /approve 后执行
+func PlanModeFromEnv() PlanMode {
+	switch strings.ToLower(os.Getenv("COMPASS_SHOW_PLAN")) {
+	case "true":
+		return PlanShow
+	case "approve":
+		return PlanApprove
+	}
+	return PlanOff
+}
+
+// PlanStep 计划中的一次工具调用
+type PlanStep struct {
+	Tool      string `json:"tool"`
+	Rationale string `json:"rationale"`
+}
+
+// Plan 模型在执行前给出的工具调用计划
+type Plan struct {
+	Steps []PlanStep `json:"steps"`
+}
+
+// planPrompt 要求模型只输出 JSON 格式的计划
+const planPrompt = `Before answering, outline which tools you intend to call for the user's latest request.
+Do NOT call any tools and do NOT answer the request yet.
+
+Respond with ONLY a JSON object in this format:
+{"steps": [{"tool": "<tool name>", "rationale": "<why this call is needed>"}]}
+
+Use an empty list if the request can be answered without tools.
+
+Available tools:
+`
+
+// generatePlan 基于当前对话历史让模型输出计划
+func generatePlan(ctx context.Context, chatModel model.BaseChatModel, toolsList []tool.BaseTool, history []*schema.Message) (*Plan, error) {
+	var sb strings.Builder
+	sb.WriteString(planPrompt)
+	for _, t := range toolsList {
+		info, err := t.Info(ctx)
+		if err != nil {
+			continue
+		}
+		desc, _, _ := strings.Cut(info.Desc, "\n")
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", info.Name, desc))
+	}
+
+	input := make([]*schema.Message, 0, len(history)+1)
+	input = append(input, schema.SystemMessage(sb.String()))
+	input = append(input, history...)
+
+	resp, err := chatModel.Generate(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("生成计划失败: %w", err)
+	}
+	return parsePlan(resp.Content)
+}
+
+// parsePlan 从模型回复中解析计划，容忍 markdown 代码块等包裹内容
+func parsePlan(content string) (*Plan, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start == -1 || end < start {
+		return nil, errors.New("计划中没有 JSON 对象")
+	}
+
+	var plan Plan
+	if err := json.Unmarshal([]byte(content[start:end+1]), &plan); err != nil {
+		return nil, fmt.Errorf("解析计划失败: %w", err)
+	}
+	return &plan, nil
+}
+
+// Markdown 将计划格式化为展示给用户的 markdown
+func (p *Plan) Markdown() string {
+	if len(p.Steps) == 0 {
+		return "Answer directly without tools."
+	}
+
+	var sb strings.Builder
+	for i, step := range p.Steps {
+		sb.WriteString(fmt.Sprintf("%d. `%s`", i+1, step.Tool))
+		if step.Rationale != "" {
+			sb.WriteString(" — " + step.Rationale)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Message 生成用于发布的计划消息（不写入对话存储）
+func (p *Plan) Message() *schema.Message {
+	return &schema.Message{
+		Role:    schema.Assistant,
+		Content: p.Markdown(),
+		Extra:   map[string]any{PlanExtraKey: true},
+	}
+}
+
+// IsPlanMessage 判断消息是否为计划消息
+func IsPlanMessage(msg *schema.Message) bool {
+	if msg == nil || msg.Extra == nil {
+		return false
+	}
+	v, _ := msg.Extra[PlanExtraKey].(bool)
+	return v
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestPlanShownBeforeToolExecution 验证计划在工具执行前生成并发布
+func TestPlanShownBeforeToolExecution(t *testing.T) {
+	rt, log, events := newPlanRuntime(t, PlanShow)
+
+	if err := rt.Run("question"); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := collectUntilFinished(t, events)
+	if len(msgs) == 0 || !IsPlanMessage(msgs[0]) {
+		t.Fatalf("第一条消息应为计划: %+v", msgs)
+	}
+	if !strings.Contains(msgs[0].Content, "`echo` — check the input") {
+		t.Errorf("计划内容未正确渲染: %q", msgs[0].Content)
+	}
+	for _, msg := range msgs[1:] {
+		if IsPlanMessage(msg) {
+			t.Errorf("计划只应发布一次: %+v", msg)
+		}
+	}
+
+	steps := log.list()
+	if strings.Join(steps, ",") != "plan,call,tool,answer" {
+		t.Errorf("计划应先于工具执行, 实际顺序: %v", steps)
+	}
+
+	history, _ := rt.store.List(context.Background())
+	for _, msg := range history {
+		if IsPlanMessage(msg) {
+			t.Error("计划消息不应写入对话历史")
+		}
+	}
+}
+
+// TestPlanApproval 验证 approve 模式在批准前不执行工具
+func TestPlanApproval(t *testing.T) {
+	rt, log, events := newPlanRuntime(t, PlanApprove)
+
+	if err := rt.Run("question"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+	if len(msgs) == 0 || !IsPlanMessage(msgs[0]) {
+		t.Fatalf("第一条消息应为计划: %+v", msgs)
+	}
+	if !rt.PendingPlan() {
+		t.Fatal("计划应等待批准")
+	}
+	if steps := log.list(); len(steps) != 1 {
+		t.Fatalf("批准前不应执行任何工具, 实际: %v", steps)
+	}
+
+	if err := rt.ApprovePlan(); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+	if strings.Join(log.list(), ",") != "plan,call,tool,answer" {
+		t.Errorf("批准后应执行计划, 实际顺序: %v", log.list())
+	}
+	if err := rt.ApprovePlan(); err == nil {
+		t.Error("没有等待中的计划时批准应返回错误")
+	}
+}
+
+// TestPlanReject 验证放弃计划会移除对应的用户消息
+func TestPlanReject(t *testing.T) {
+	rt, log, events := newPlanRuntime(t, PlanApprove)
+
+	rt.Run("question")
+	collectUntilFinished(t, events)
+
+	if err := rt.RejectPlan(); err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := rt.store.List(context.Background()); len(history) != 0 {
+		t.Errorf("放弃计划后应移除用户消息, 实际: %+v", history)
+	}
+	if steps := log.list(); len(steps) != 1 {
+		t.Errorf("放弃计划后不应执行工具, 实际: %v", steps)
+	}
+}
+
+// TestParsePlan 验证计划解析
+func TestParsePlan(t *testing.T) {
+	plan, err := parsePlan(`Here is my plan: {"steps": [{"tool": "web_search", "rationale": "latest version"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Steps) != 1 || plan.Steps[0].Tool != "web_search" {
+		t.Errorf("解析结果错误: %+v", plan)
+	}
+	if _, err := parsePlan("no plan"); err == nil {
+		t.Error("没有 JSON 时应返回错误")
+	}
+}
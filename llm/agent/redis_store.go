@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"compass/llm/vector"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultConversationRedisKey RedisStore 默认使用的列表键
+const DefaultConversationRedisKey = "compass:conversation"
+
+// RedisStore 以 Redis 列表保存对话的存储，连接配置复用向量存储的 Redis 配置。
+// token 预算窗口和工具结果压缩与 MemoryStore 相同。
+type RedisStore struct {
+	client          *redis.Client
+	key             string
+	maxTokens       int
+	maxToolResponse int
+}
+
+// NewRedisStore 连接 Redis 并创建对话存储
+func NewRedisStore(ctx context.Context, cfg vector.RedisConfig, key string) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		PoolSize: cfg.PoolSize,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
+	}
+	return &RedisStore{
+		client:          client,
+		key:             key,
+		maxTokens:       MaxTokensFromEnv(),
+		maxToolResponse: defaultMaxToolResponse,
+	}, nil
+}
+
+// Add 添加一条消息，超出 token 预算时淘汰最旧的消息
+func (s *RedisStore) Add(ctx context.Context, msg adk.Message) error {
+	data, err := json.Marshal(compressToolResponse(msg, s.maxToolResponse))
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %w", err)
+	}
+	if err := s.client.RPush(ctx, s.key, data).Err(); err != nil {
+		return fmt.Errorf("写入 Redis 失败: %w", err)
+	}
+
+	msgs, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+	kept := fitTokenBudget(msgs, s.maxTokens)
+	if len(kept) == len(msgs) {
+		return nil
+	}
+
+	// 保留的系统消息可能不与其余消息相邻，因此整体重写列表
+	items := make([]any, 0, len(kept))
+	for _, m := range kept {
+		item, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("序列化消息失败: %w", err)
+		}
+		items = append(items, item)
+	}
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, s.key)
+	pipe.RPush(ctx, s.key, items...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("写入 Redis 失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取所有消息，无法解析的记录会被跳过
+func (s *RedisStore) List(ctx context.Context) ([]adk.Message, error) {
+	items, err := s.client.LRange(ctx, s.key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取 Redis 失败: %w", err)
+	}
+
+	msgs := make([]adk.Message, 0, len(items))
+	for _, item := range items {
+		var msg adk.Message
+		if err := json.Unmarshal([]byte(item), &msg); err != nil || msg == nil {
+			slog.Warn("跳过无法解析的对话记录", "err", err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// Clear 清空所有消息
+func (s *RedisStore) Clear(ctx context.Context) error {
+	if err := s.client.Del(ctx, s.key).Err(); err != nil {
+		return fmt.Errorf("清空 Redis 对话失败: %w", err)
+	}
+	return nil
+}
+
+// Truncate 只保留前 n 条消息
+func (s *RedisStore) Truncate(ctx context.Context, n int) error {
+	if n <= 0 {
+		return s.Clear(ctx)
+	}
+	if err := s.client.LTrim(ctx, s.key, 0, int64(n-1)).Err(); err != nil {
+		return fmt.Errorf("截断 Redis 对话失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭 Redis 连接
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+var (
+	// ErrRunTimeout 运行超出时间预算
+	ErrRunTimeout = errors.New("run exceeded time budget")
+	// ErrRunCanceled 运行被用户取消
+	ErrRunCanceled = errors.New("run canceled")
+)
+
+// RunTimeoutFromEnv 从 COMPASS_RUN_TIMEOUT 读取单轮运行时间上限（Go duration 格式），0 或未设置表示不限制
+func RunTimeoutFromEnv() time.Duration {
+	if val := os.Getenv("COMPASS_RUN_TIMEOUT"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// startRun 创建本轮运行的 context：超时后以 ErrRunTimeout 结束，Cancel 时以 ErrRunCanceled 结束。
+// 返回的函数用于结束本轮运行并释放资源。
+func (r *Runtime) startRun() (context.Context, func()) {
+	ctx, cancelCause := context.WithCancelCause(r.ctx)
+	stopTimeout := func() {}
+	if r.runTimeout > 0 {
+		ctx, stopTimeout = context.WithTimeoutCause(ctx, r.runTimeout,
+			fmt.Errorf("%w of %s", ErrRunTimeout, r.runTimeout))
+	}
+
+	done := make(chan struct{})
+	r.runMu.Lock()
+	r.cancelRun = cancelCause
+	r.runDone = done
+	r.runMu.Unlock()
+
+	return ctx, func() {
+		stopTimeout()
+		cancelCause(nil)
+		r.runMu.Lock()
+		r.cancelRun = nil
+		r.runDone = nil
+		r.runMu.Unlock()
+		close(done)
+	}
+}
+
+// Cancel 取消正在进行的运行，没有运行时返回 false
+func (r *Runtime) Cancel() bool {
+	r.runMu.Lock()
+	defer r.runMu.Unlock()
+	if r.cancelRun == nil {
+		return false
+	}
+	r.cancelRun(ErrRunCanceled)
+	return true
+}
+
+// Clear 取消正在进行的运行并等待其结束，然后清空对话历史、等待批准的计划或工具调用和 token 用量。
+// 清空后发布 DeletedEvent，它排在本轮运行的所有事件之后，订阅者据此清空界面
+func (r *Runtime) Clear(ctx context.Context) error {
+	r.runMu.Lock()
+	done := r.runDone
+	if r.cancelRun != nil {
+		r.cancelRun(ErrRunCanceled)
+	}
+	r.runMu.Unlock()
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	r.pendingPlan.Store(false)
+	r.takeApproval()
+	if err := r.store.Clear(ctx); err != nil {
+		return err
+	}
+	r.ResetTokenUsage()
+	r.broker.Publish(pubsub.DeletedEvent, nil)
+	return nil
+}
+
+// stoppedEarly 判断运行是否因超时、用户取消或超出 token 预算而提前结束
+func stoppedEarly(cause error) bool {
+	return errors.Is(cause, ErrRunTimeout) || errors.Is(cause, ErrRunCanceled) || errors.Is(cause, ErrTokenBudget)
+}
+
+// finishStopped 结束超时或被取消的运行：为没有结果的工具调用补上说明，
+// 使下一轮可以基于同一历史继续，然后发布原因
+func (r *Runtime) finishStopped(cause error) error {
+	r.settleToolCalls()
+	if !errors.Is(cause, ErrRunCanceled) {
+		return r.failRun(cause)
+	}
+	r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
+		Role:    schema.System,
+		Content: "已取消本轮运行",
+	})
+	r.broker.Publish(pubsub.FinishedEvent, nil)
+	return cause
+}
+
+// settleToolCalls 为历史中没有对应结果的工具调用补上中止说明。
+// 存储会按 token 预算或摘要从开头缩短历史，因此检查整个历史而不是本轮开始后的部分
+func (r *Runtime) settleToolCalls() {
+	history, err := r.store.List(r.ctx)
+	if err != nil {
+		return
+	}
+
+	answered := make(map[string]bool)
+	for _, msg := range history {
+		if msg.Role == schema.Tool {
+			answered[msg.ToolCallID] = true
+		}
+	}
+	for _, msg := range history {
+		for _, call := range msg.ToolCalls {
+			if answered[call.ID] {
+				continue
+			}
+			result := schema.ToolMessage("The run was stopped before this call finished.", call.ID)
+			if err := r.store.Add(r.ctx, result); err != nil {
+				slog.Error("存储消息失败", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// stuckReply 调用时通知 called，然后阻塞到 release 关闭，且不响应 context，模拟卡住的 Agent
+func stuckReply(called chan<- struct{}, release <-chan struct{}) replyFunc {
+	return func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+		<-release
+		return []*schema.Message{schema.AssistantMessage("too late", nil)}, nil
+	}
+}
+
+// newSlowRuntime 创建模型卡住到测试结束的 Runtime，模型被调用时 called 收到通知
+func newSlowRuntime(t *testing.T, timeout time.Duration) (*Runtime, <-chan struct{}, <-chan pubsub.Event[adk.Message]) {
+	t.Helper()
+	called, release := make(chan struct{}, 1), make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	rt, events := newTestRuntime(t, &scriptedModel{reply: stuckReply(called, release)})
+	rt.runTimeout = timeout
+	return rt, called, events
+}
+
+// TestRunTimeout 验证超出时间上限的运行被终止并报告超时
+func TestRunTimeout(t *testing.T) {
+	rt, _, events := newSlowRuntime(t, 50*time.Millisecond)
+
+	start := time.Now()
+	err := rt.Run("slow question")
+	if !errors.Is(err, ErrRunTimeout) {
+		t.Fatalf("期望 ErrRunTimeout, 实际: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("运行应在超时后立即结束, 实际耗时 %v", elapsed)
+	}
+
+	msgs := collectContents(t, events)
+	if len(msgs) == 0 || !strings.Contains(msgs[len(msgs)-1], "run exceeded time budget of 50ms") {
+		t.Errorf("应发布超时消息, 实际: %v", msgs)
+	}
+}
+
+// TestRunTimeoutReaderExits 验证超时后读取事件的 goroutine 在 Agent 停止后退出
+func TestRunTimeoutReaderExits(t *testing.T) {
+	release := make(chan struct{})
+	rt, _ := newTestRuntime(t, &scriptedModel{reply: stuckReply(make(chan struct{}, 1), release)})
+	rt.runTimeout = 50 * time.Millisecond
+
+	before := runtime.NumGoroutine()
+	if err := rt.Run("slow question"); !errors.Is(err, ErrRunTimeout) {
+		t.Fatalf("期望 ErrRunTimeout, 实际: %v", err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("超时后 goroutine 未退出: %d > %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestRunCancel 验证取消运行不会被报告为超时
+func TestRunCancel(t *testing.T) {
+	rt, called, events := newSlowRuntime(t, time.Minute)
+	if rt.Cancel() {
+		t.Error("没有运行时 Cancel 应返回 false")
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- rt.Run("slow question") }()
+	<-called
+	if !rt.Cancel() {
+		t.Fatal("运行中 Cancel 应返回 true")
+	}
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrRunCanceled) {
+			t.Fatalf("期望 ErrRunCanceled, 实际: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消后运行应立即结束")
+	}
+
+	for _, msg := range collectContents(t, events) {
+		if strings.Contains(msg, "time budget") {
+			t.Errorf("取消不应报告为超时: %q", msg)
+		}
+	}
+}
+
+// TestClearWaitsForRun 验证清空会等待被取消的运行结束，清空事件排在本轮所有事件之后
+func TestClearWaitsForRun(t *testing.T) {
+	rt, called, events := newSlowRuntime(t, time.Minute)
+	ctx := context.Background()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- rt.Run("slow question") }()
+	<-called
+	if err := rt.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := rt.Store().List(ctx); len(history) != 0 {
+		t.Errorf("清空后历史应为空, 实际: %v", history)
+	}
+	if err := <-errCh; !errors.Is(err, ErrRunCanceled) {
+		t.Fatalf("期望 ErrRunCanceled, 实际: %v", err)
+	}
+
+	var types []pubsub.EventType
+	for event := range events {
+		types = append(types, event.Type)
+		if event.Type == pubsub.DeletedEvent {
+			break
+		}
+	}
+	if len(types) < 2 || types[len(types)-2] != pubsub.FinishedEvent {
+		t.Errorf("清空事件应紧跟在运行结束事件之后, 实际: %v", types)
+	}
+}
+
+// blockingTool 第一次调用时阻塞到 context 取消，之后立即返回
+type blockingTool struct {
+	log      *planLog
+	started  chan struct{}
+	canceled chan struct{}
+	once     sync.Once
+}
+
+func (t *blockingTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "echo", Desc: "Echo the input."}, nil
+}
+
+func (t *blockingTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+	first := false
+	t.once.Do(func() { first = true })
+	if !first {
+		t.log.add("tool")
+		return "echoed", nil
+	}
+	close(t.started)
+	<-ctx.Done()
+	close(t.canceled)
+	return "", ctx.Err()
+}
+
+// TestRunCancelDuringToolCall 验证取消会中止进行中的工具调用，并为其补上结果，下一轮可以继续
+func TestRunCancelDuringToolCall(t *testing.T) {
+	log := &planLog{}
+	blocking := &blockingTool{log: log, started: make(chan struct{}), canceled: make(chan struct{})}
+	rt, events := newTestRuntime(t, &scriptedModel{reply: planReply(log)}, blocking)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- rt.Run("question") }()
+	<-blocking.started
+	// 等待工具调用消息发布后再取消
+	for e := range events {
+		if len(e.Payload.ToolCalls) > 0 {
+			break
+		}
+	}
+	rt.Cancel()
+	if err := <-errCh; !errors.Is(err, ErrRunCanceled) {
+		t.Fatalf("期望 ErrRunCanceled, 实际: %v", err)
+	}
+	select {
+	case <-blocking.canceled:
+	case <-time.After(time.Second):
+		t.Fatal("工具应收到取消")
+	}
+	msgs := collectUntilFinished(t, events)
+	if last := msgs[len(msgs)-1]; last.Role != schema.System || last.Content != "已取消本轮运行" {
+		t.Errorf("应发布取消消息, 实际: %+v", last)
+	}
+
+	history, _ := rt.store.List(context.Background())
+	last := history[len(history)-1]
+	if last.Role != schema.Tool || last.ToolCallID != "call_1" {
+		t.Fatalf("被中止的工具调用应有对应结果: %+v", history)
+	}
+
+	if err := rt.Run("again"); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+	if steps := log.list(); strings.Join(steps, ",") != "call,call,tool,answer" {
+		t.Errorf("取消后应能继续运行, 实际: %v", steps)
+	}
+}
+
+// TestRunCancelAfterHistoryTrimmed 验证本轮运行期间历史按 token 预算从开头缩短后，
+// 被中止的工具调用仍会补上结果
+func TestRunCancelAfterHistoryTrimmed(t *testing.T) {
+	log := &planLog{}
+	blocking := &blockingTool{log: log, started: make(chan struct{}), canceled: make(chan struct{})}
+	rt, events := newTestRuntime(t, &scriptedModel{reply: planReply(log)}, blocking)
+	store := NewMemoryStore()
+	store.maxTokens = 90
+	ctx := context.Background()
+	store.Add(ctx, schema.UserMessage(sized(40)))
+	store.Add(ctx, schema.AssistantMessage(sized(40), nil))
+	rt.store = store
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- rt.Run("question") }()
+	<-blocking.started
+	for e := range events {
+		if len(e.Payload.ToolCalls) > 0 {
+			break
+		}
+	}
+	rt.Cancel()
+	if err := <-errCh; !errors.Is(err, ErrRunCanceled) {
+		t.Fatalf("期望 ErrRunCanceled, 实际: %v", err)
+	}
+
+	history, _ := store.List(ctx)
+	if history[0].Role == schema.User {
+		t.Fatalf("工具调用后最旧的消息应被淘汰: %v", contents(history))
+	}
+	last := history[len(history)-1]
+	if last.Role != schema.Tool || last.ToolCallID != "call_1" {
+		t.Errorf("被中止的工具调用应有对应结果: %+v", history)
+	}
+}
+
+// TestRunCancelDuringPlan 验证生成计划时也可以取消，且不会继续执行
+func TestRunCancelDuringPlan(t *testing.T) {
+	// 模型阻塞到 context 取消，模拟响应缓慢但支持取消的模型
+	called := make(chan struct{})
+	rt, _ := newTestRuntime(t, &scriptedModel{reply: func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		close(called)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}})
+	rt.planMode = PlanShow
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- rt.Run("question") }()
+	<-called
+	if !rt.Cancel() {
+		t.Fatal("生成计划时 Cancel 应返回 true")
+	}
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrRunCanceled) {
+			t.Fatalf("期望 ErrRunCanceled, 实际: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消后运行应立即结束")
+	}
+}
+
+// TestRunTimeoutFromEnv 验证 COMPASS_RUN_TIMEOUT 的解析
+func TestRunTimeoutFromEnv(t *testing.T) {
+	for val, want := range map[string]time.Duration{
+		"":        0,
+		"90s":     90 * time.Second,
+		"0":       0,
+		"invalid": 0,
+		"-5m":     0,
+	} {
+		t.Setenv("COMPASS_RUN_TIMEOUT", val)
+		if got := RunTimeoutFromEnv(); got != want {
+			t.Errorf("COMPASS_RUN_TIMEOUT=%q: got %v, want %v", val, got, want)
+		}
+	}
+}
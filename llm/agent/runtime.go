@@ -2,9 +2,17 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"compass/llm/parser"
 	"compass/llm/providers"
@@ -24,31 +32,71 @@ import (
 
 // Runtime Agent 运行时
 type Runtime struct {
-	agent       adk.Agent
-	runner      *adk.Runner
-	store       ConversationStore
-	broker      *pubsub.Broker[adk.Message]
-	ctx         context.Context
-	cancelFunc  context.CancelFunc
-	cozeClient  cozeloop.Client
-	vectorStore vector.VectorStore // Vector store for knowledge base
+	agent        adk.Agent
+	runner       *adk.Runner
+	streamRunner *adk.Runner // 流式运行使用的 Runner
+	store        ConversationStore
+	broker       *pubsub.Broker[adk.Message]
+	ctx          context.Context
+	cancelFunc   context.CancelFunc
+	cozeClient   cozeloop.Client
+	trace        io.Closer          // TRACE_FILE 追踪文件，未启用时为 nil
+	vectorStore  vector.VectorStore // Vector store for knowledge base
+	embedder     embedding.Embedder // 知识库当前使用的 embedding 模型
+	newEmbedder  EmbedderFactory    // 切换 embedding 模型时创建新模型
+	workDir      string             // 会话隔离工作目录（未启用时为空）
+
+	chatModel   model.ToolCallingChatModel
+	tools       []tool.BaseTool
+	planMode    PlanMode    // 执行前计划展示模式
+	pendingPlan atomic.Bool // 是否有等待批准的计划
+	streaming   atomic.Bool // 最近一次运行是否为流式
+
+	runSeq     atomic.Uint64    // 生成每轮运行的检查点 ID
+	approvalMu sync.Mutex       // 保护 approval
+	approval   *pendingApproval // 等待批准的工具调用，没有时为 nil
+
+	sourcesFooter   bool // 是否在最终回答后附加来源脚注
+	autoSaveAnswers bool // 是否将经过网络调研的最终回答存入知识库
+
+	knowledgeCacheTTL time.Duration // 单轮内知识库检索结果的缓存时长，0 表示不缓存
+
+	vectorStoreErr error // 向量存储初始化失败的原因，用于健康检查
+
+	usageMu             sync.Mutex // 保护 usage
+	usage               TokenUsage // 本会话累计的 token 用量
+	maxTokensPerSession int        // 会话 token 预算，0 表示不限制
+
+	runTimeout time.Duration           // 单轮运行时间上限，0 表示不限制
+	runMu      sync.Mutex              // 保护 cancelRun 和 runDone
+	cancelRun  context.CancelCauseFunc // 取消当前运行，没有运行时为 nil
+	runDone    chan struct{}           // 当前运行结束时关闭，没有运行时为 nil
 }
 
 // NewRuntime 创建新的 Agent 运行时
 func NewRuntime(ctx context.Context, chatModel model.ToolCallingChatModel, toolsList []tool.BaseTool) (*Runtime, error) {
 	// 创建 TechTutor Agent
 	agt, err := NewTechTutorAgent(ctx, &TechTutorConfig{
-		ChatModel: chatModel,
-		Tools:     toolsList,
+		ChatModel:     chatModel,
+		Tools:         toolsList,
+		ApprovalTools: ApprovalToolsFromEnv(),
+		ReadOnly:      tools.ReadOnlyFromEnv(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("创建 Agent 失败: %w", err)
 	}
 
-	// 创建 Runner
+	// 创建 Runner，检查点用于工具等待批准后恢复运行
+	checkPoints := newMemoryCheckPointStore()
 	runner := adk.NewRunner(ctx, adk.RunnerConfig{
 		Agent:           agt,
 		EnableStreaming: false, // 非流式
+		CheckPointStore: checkPoints,
+	})
+	streamRunner := adk.NewRunner(ctx, adk.RunnerConfig{
+		Agent:           agt,
+		EnableStreaming: true,
+		CheckPointStore: checkPoints,
 	})
 
 	// 创建消息 Broker
@@ -57,24 +105,59 @@ func NewRuntime(ctx context.Context, chatModel model.ToolCallingChatModel, tools
 	// 创建上下文
 	childCtx, cancel := context.WithCancel(ctx)
 
+	// 会话工作目录隔离：文件和 bash 工具以临时目录为基准解析相对路径
+	var workDir string
+	if os.Getenv("SESSION_WORKDIR_ISOLATION") == "true" {
+		workDir, err = tools.NewSessionWorkDir()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		childCtx = tools.WithWorkDir(childCtx, workDir)
+	}
+
 	return &Runtime{
-		agent:      agt,
-		runner:     runner,
-		store:      NewMemoryStore(),
-		broker:     broker,
-		ctx:        childCtx,
-		cancelFunc: cancel,
+		agent:        agt,
+		runner:       runner,
+		streamRunner: streamRunner,
+		store:        NewMemoryStore(),
+		broker:       broker,
+		ctx:          childCtx,
+		cancelFunc:   cancel,
+		workDir:      workDir,
+		newEmbedder:  providers.CreateEmbeddingModelFor,
+		chatModel:    chatModel,
+		tools:        toolsList,
+		planMode:     PlanModeFromEnv(),
+		runTimeout:   RunTimeoutFromEnv(),
+
+		maxTokensPerSession: MaxTokensPerSessionFromEnv(),
+
+		sourcesFooter:   SourcesFooterFromEnv(),
+		autoSaveAnswers: AutoSaveAnswersFromEnv(),
+
+		knowledgeCacheTTL: tools.KnowledgeCacheTTLFromEnv(),
 	}, nil
 }
 
-// Run 运行 Agent 处理用户输入
+// Run 运行 Agent 处理用户输入，等待每条消息完整生成后再发布（适合批量使用）
 func (r *Runtime) Run(userPrompt string) error {
+	r.streaming.Store(false)
+	return r.run(userPrompt)
+}
+
+// run 存储并发布用户消息，然后运行 Agent
+func (r *Runtime) run(userPrompt string) error {
 	// 创建用户消息
 	userMsg := &schema.Message{
 		Role:    schema.User,
 		Content: userPrompt,
 	}
 
+	// 新的输入取代尚未批准的计划和工具调用
+	r.pendingPlan.Store(false)
+	r.dropApproval()
+
 	// 添加到存储
 	if err := r.store.Add(r.ctx, userMsg); err != nil {
 		return fmt.Errorf("存储用户消息失败: %w", err)
@@ -82,54 +165,355 @@ func (r *Runtime) Run(userPrompt string) error {
 	// 发布消息
 	r.broker.Publish(pubsub.CreatedEvent, userMsg)
 
+	return r.runAgent()
+}
+
+// Retry 删除最后一轮助手回复（含工具调用和结果），基于前一条用户消息重新生成。
+// 若最后一条消息就是用户消息（例如上次运行失败），则直接重新运行。
+func (r *Runtime) Retry() error {
+	history, err := r.store.List(r.ctx)
+	if err != nil {
+		return r.failRun(fmt.Errorf("获取历史消息失败: %w", err))
+	}
+
+	idx := lastUserIndex(history)
+	if idx == -1 {
+		return r.failRun(errors.New("没有可重试的用户消息"))
+	}
+
+	// 丢弃用户消息之后的助手回复和工具结果，等待批准的调用随之作废
+	r.takeApproval()
+	if err := r.store.Truncate(r.ctx, idx+1); err != nil {
+		return r.failRun(fmt.Errorf("删除上一轮回复失败: %w", err))
+	}
+
+	return r.runAgent()
+}
+
+// ApprovePlan 批准等待中的计划并开始执行
+func (r *Runtime) ApprovePlan() error {
+	if !r.pendingPlan.CompareAndSwap(true, false) {
+		return r.failRun(errors.New("没有等待批准的计划"))
+	}
+	return r.execute()
+}
+
+// RejectPlan 放弃等待中的计划，并移除对应的用户消息
+func (r *Runtime) RejectPlan() error {
+	if !r.pendingPlan.CompareAndSwap(true, false) {
+		return r.failRun(errors.New("没有等待批准的计划"))
+	}
+
+	history, err := r.store.List(r.ctx)
+	if err != nil {
+		return r.failRun(fmt.Errorf("获取历史消息失败: %w", err))
+	}
+	if idx := lastUserIndex(history); idx != -1 {
+		if err := r.store.Truncate(r.ctx, idx); err != nil {
+			return r.failRun(fmt.Errorf("移除用户消息失败: %w", err))
+		}
+	}
+
+	r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
+		Role:    schema.System,
+		Content: "已放弃计划",
+	})
+	r.broker.Publish(pubsub.FinishedEvent, nil)
+	return nil
+}
+
+// PendingPlan 是否有等待批准的计划
+func (r *Runtime) PendingPlan() bool {
+	return r.pendingPlan.Load()
+}
+
+// PendingApproval 是否有等待批准的工具调用
+func (r *Runtime) PendingApproval() bool {
+	r.approvalMu.Lock()
+	defer r.approvalMu.Unlock()
+	return r.approval != nil
+}
+
+// ResolveApproval 批准或拒绝等待中的工具调用，并从中断处恢复运行
+func (r *Runtime) ResolveApproval(approved bool) error {
+	pending := r.takeApproval()
+	if pending == nil {
+		return r.failRun(errors.New("没有等待批准的工具调用"))
+	}
+
+	targets := make(map[string]any, len(pending.requests))
+	for id := range pending.requests {
+		targets[id] = &ApprovalResult{Approved: approved}
+	}
+	return r.consume(pending.checkPointID, func(ctx context.Context, runner *adk.Runner, _ []adk.Message, opts []adk.AgentRunOption) (*adk.AsyncIterator[*adk.AgentEvent], error) {
+		return runner.ResumeWithParams(ctx, pending.checkPointID, &adk.ResumeParams{Targets: targets}, opts...)
+	})
+}
+
+// takeApproval 取出并清除等待批准的工具调用
+func (r *Runtime) takeApproval() *pendingApproval {
+	r.approvalMu.Lock()
+	defer r.approvalMu.Unlock()
+	pending := r.approval
+	r.approval = nil
+	return pending
+}
+
+// dropApproval 放弃等待批准的工具调用，为其补上拒绝结果，使历史中的工具调用都有对应结果
+func (r *Runtime) dropApproval() {
+	pending := r.takeApproval()
+	if pending == nil {
+		return
+	}
+	for _, id := range sortedKeys(pending.requests) {
+		result := schema.ToolMessage("The user did not approve this call and moved on.", pending.requests[id].CallID)
+		if err := r.store.Add(r.ctx, result); err != nil {
+			slog.Error("存储消息失败", "err", err)
+		}
+	}
+}
+
+// lastUserIndex 返回最后一条用户消息的位置，不存在时返回 -1
+func lastUserIndex(msgs []adk.Message) int {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == schema.User {
+			return i
+		}
+	}
+	return -1
+}
+
+// failRun 发布错误消息并结束本轮运行
+func (r *Runtime) failRun(err error) error {
+	r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
+		Role:    schema.System,
+		Content: fmt.Sprintf("错误: %v", err),
+	})
+	r.broker.Publish(pubsub.FinishedEvent, nil)
+	return err
+}
+
+// runAgent 基于当前历史运行 Agent；启用计划模式时先生成并展示计划
+func (r *Runtime) runAgent() error {
+	if r.planMode == PlanOff {
+		return r.execute()
+	}
+
+	history, err := r.store.List(r.ctx)
+	if err != nil {
+		return r.failRun(fmt.Errorf("获取历史消息失败: %w", err))
+	}
+
+	// 会话 token 预算用完时不再生成计划
+	if err := r.checkTokenBudget(); err != nil {
+		return r.failRun(err)
+	}
+
+	// 生成计划同样受时间上限、取消和 token 预算控制
+	planCtx, endPlan := r.startRun()
+	planCtx, stopOverBudget := context.WithCancelCause(planCtx)
+	planModel := &usageModel{BaseChatModel: r.chatModel, name: "plan", handler: r.usageHandler(stopOverBudget)}
+	plan, err := generatePlan(planCtx, planModel, r.tools, history)
+	cause := context.Cause(planCtx)
+	stopOverBudget(nil)
+	if stoppedEarly(cause) {
+		// 发布结束事件后再结束运行，等待运行结束的调用方在本轮所有事件之后继续
+		defer endPlan()
+		return r.finishStopped(cause)
+	}
+	endPlan()
+	if err != nil {
+		// 计划只是辅助信息，失败时直接执行
+		slog.Warn("生成计划失败", "err", err)
+		return r.execute()
+	}
+	r.broker.Publish(pubsub.UpdatedEvent, plan.Message())
+
+	if r.planMode == PlanApprove && len(plan.Steps) > 0 {
+		r.pendingPlan.Store(true)
+		r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
+			Role:    schema.System,
+			Content: "输入 /approve 执行计划，或 /reject 放弃",
+		})
+		r.broker.Publish(pubsub.FinishedEvent, nil)
+		return nil
+	}
+
+	return r.execute()
+}
+
+// execute 基于当前历史运行 Agent 并发布消息
+func (r *Runtime) execute() error {
+	checkPointID := fmt.Sprintf("run-%d", r.runSeq.Add(1))
+	return r.consume(checkPointID, func(ctx context.Context, runner *adk.Runner, history []adk.Message, opts []adk.AgentRunOption) (*adk.AsyncIterator[*adk.AgentEvent], error) {
+		return runner.Run(ctx, history, append(opts, adk.WithCheckPointID(checkPointID))...), nil
+	})
+}
+
+// runStarter 以 opts 启动或恢复 Agent 运行，返回事件迭代器
+type runStarter func(ctx context.Context, runner *adk.Runner, history []adk.Message, opts []adk.AgentRunOption) (*adk.AsyncIterator[*adk.AgentEvent], error)
+
+// consume 启动或恢复一轮运行并发布消息；工具等待批准时记录中断并结束本轮
+func (r *Runtime) consume(checkPointID string, start runStarter) error {
 	// 获取历史消息
 	history, err := r.store.List(r.ctx)
 	if err != nil {
 		return fmt.Errorf("获取历史消息失败: %w", err)
 	}
 
-	// 运行 Agent
-	iter := r.runner.Run(r.ctx, history)
+	// 会话 token 预算用完时不再运行
+	if err := r.checkTokenBudget(); err != nil {
+		return r.failRun(err)
+	}
 
-	// 处理事件并发布消息
+	// 运行 Agent，受时间上限、取消和 token 预算控制
+	runCtx, endRun := r.startRun()
+	defer endRun()
+	runCtx, stopOverBudget := context.WithCancelCause(runCtx)
+	defer stopOverBudget(nil)
+	opts := []adk.AgentRunOption{adk.WithCallbacks(r.usageHandler(stopOverBudget))}
+
+	// 收集工具查阅的 URL 和知识库来源
+	var sources *tools.SourceCollector
+	if r.sourcesFooter || r.autoSaveAnswers {
+		sources = tools.NewSourceCollector()
+		runCtx = tools.WithSourceCollector(runCtx, sources)
+	}
+	// 同一轮内相同的知识库检索复用结果，不跨轮共享
+	runCtx = tools.WithKnowledgeSearchCache(runCtx, r.knowledgeCacheTTL)
+	// 重复工具调用的去重同样只在本轮内生效
+	runCtx = tools.WithToolCallDedup(runCtx)
+	runner := r.runner
+	if r.streaming.Load() {
+		runner = r.streamRunner
+	}
+	iter, err := start(runCtx, runner, history, opts)
+	if err != nil {
+		return r.failRun(fmt.Errorf("运行 Agent 失败: %w", err))
+	}
+
+	// 在后台读取事件，使超时或取消时即使 Agent 未响应 context 也能立即返回。
+	// 运行结束后不再转发事件，Agent 停止后 Next 返回，goroutine 随之退出
+	events := make(chan *adk.AgentEvent)
+	go func() {
+		defer close(events)
+		for runCtx.Err() == nil {
+			event, ok := iter.Next()
+			if !ok {
+				return
+			}
+			select {
+			case events <- event:
+			case <-runCtx.Done():
+				return
+			}
+		}
+	}()
+
+	// 处理事件并发布消息；收集来源时暂缓发布可能的最终回答，直到确定没有后续消息
+	var final adk.Message
+	var interrupted *adk.InterruptInfo
+loop:
 	for {
-		event, ok := iter.Next()
-		if !ok {
-			break
+		select {
+		case event, ok := <-events:
+			if !ok {
+				break loop
+			}
+			if event.Action != nil && event.Action.Interrupted != nil {
+				interrupted = event.Action.Interrupted
+			}
+			msg := r.eventMessage(runCtx, event)
+			if msg == nil {
+				continue
+			}
+			if final != nil {
+				r.publishMessage(final)
+				final = nil
+			}
+			if sources != nil && isFinalAnswer(msg) {
+				final = msg
+				continue
+			}
+			r.publishMessage(msg)
+		case <-runCtx.Done():
+			break loop
+		}
+	}
+
+	cause := context.Cause(runCtx)
+	if final != nil {
+		answer := final
+		if cause == nil && r.sourcesFooter {
+			final = withSourcesFooter(final, sources.Sources())
+		}
+		r.publishMessage(final)
+		if cause == nil && r.autoSaveAnswers {
+			r.autoSaveAnswer(history, answer, sources.Sources())
+		}
+	}
+
+	if stoppedEarly(cause) {
+		return r.finishStopped(cause)
+	}
+
+	if interrupted != nil {
+		if requests := approvalRequests(interrupted); len(requests) > 0 {
+			r.approvalMu.Lock()
+			r.approval = &pendingApproval{checkPointID: checkPointID, requests: requests}
+			r.approvalMu.Unlock()
+			r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
+				Role:    schema.System,
+				Content: approvalPrompt(requests),
+			})
 		}
-		r.handleAgentEvent(event)
 	}
 	r.broker.Publish(pubsub.FinishedEvent, nil)
 
 	return nil
 }
 
-// handleAgentEvent 处理 ADK Agent 事件
-func (r *Runtime) handleAgentEvent(event *adk.AgentEvent) {
+// eventMessage 从 ADK Agent 事件中取出消息，没有消息时返回 nil。
+// 流式的助手回复会边读取边发布增量内容。
+func (r *Runtime) eventMessage(ctx context.Context, event *adk.AgentEvent) adk.Message {
 	if event.Output == nil {
-		return
+		return nil
 	}
 
 	output := event.Output.MessageOutput
 	if output == nil {
-		return
+		return nil
 	}
 
 	// 获取消息
-	msg, err := output.GetMessage()
+	var msg adk.Message
+	var err error
+	if output.IsStreaming && output.Role == schema.Assistant {
+		msg, err = r.streamMessage(ctx, output.MessageStream)
+	} else {
+		msg, err = output.GetMessage()
+	}
 	if err != nil {
-		log.Printf("获取消息失败: %v", err)
+		if context.Cause(ctx) != nil {
+			// 超时或取消由 execute 统一报告
+			return nil
+		}
+		slog.Error("获取消息失败", "err", err)
 		// 发布错误消息
 		r.broker.Publish(pubsub.UpdatedEvent, &schema.Message{
 			Role:    schema.System,
 			Content: fmt.Sprintf("错误: %v", err),
 		})
-		return
+		return nil
 	}
+	return msg
+}
 
+// publishMessage 存储消息并发布到 Broker
+func (r *Runtime) publishMessage(msg adk.Message) {
 	// 添加到存储
 	if err := r.store.Add(r.ctx, msg); err != nil {
-		log.Printf("存储消息失败: %v", err)
+		slog.Error("存储消息失败", "err", err)
 	}
 
 	// 发布消息到 Broker（处理中的更新事件）
@@ -146,20 +530,41 @@ func (r *Runtime) Store() ConversationStore {
 	return r.store
 }
 
+// WorkDir 获取会话工作目录（未启用隔离时为空）
+func (r *Runtime) WorkDir() string {
+	return r.workDir
+}
+
 // Close 关闭运行时
 func (r *Runtime) Close() {
 	r.cancelFunc()
 	r.broker.Shutdown()
+	// 清理会话工作目录
+	if err := tools.RemoveSessionWorkDir(r.workDir); err != nil {
+		slog.Warn("清理会话工作目录失败", "err", err)
+	}
+	// 关闭对话存储的连接
+	if closer, ok := r.store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			slog.Error("关闭对话存储失败", "err", err)
+		}
+	}
 	// 关闭向量存储
 	if r.vectorStore != nil {
 		if err := r.vectorStore.Close(); err != nil {
-			log.Printf("关闭向量存储失败: %v", err)
+			slog.Error("关闭向量存储失败", "err", err)
 		}
 	}
 	// 关闭 Coze Loop 客户端
 	if r.cozeClient != nil {
 		r.cozeClient.Close(r.ctx)
 	}
+	// 写完剩余的追踪记录
+	if r.trace != nil {
+		if err := r.trace.Close(); err != nil {
+			slog.Error("关闭追踪文件失败", "err", err)
+		}
+	}
 }
 
 // SetupRuntime 设置 Runtime（从 main.go 调用）
@@ -167,6 +572,12 @@ func SetupRuntime(ctx context.Context) (*Runtime, error) {
 	// 初始化 Coze Loop 观测
 	cozeClient := initCozeLoop(ctx)
 
+	// 本地调用追踪，失败时不影响运行
+	trace, err := initTrace()
+	if err != nil {
+		slog.Warn("初始化调用追踪失败", "err", err)
+	}
+
 	// 创建 ChatModel
 	chatModel, err := providers.CreateChatModel(ctx)
 	if err != nil {
@@ -174,11 +585,11 @@ func SetupRuntime(ctx context.Context) (*Runtime, error) {
 	}
 
 	// 初始化向量存储
-	vectorStore, embedder, err := initVectorStore(ctx)
-	if err != nil {
-		log.Printf("初始化向量存储失败: %v (知识库功能将被禁用)", err)
+	vectorStore, embedder, vectorStoreErr := initVectorStore(ctx)
+	if vectorStoreErr != nil {
+		slog.Warn("初始化向量存储失败，知识库功能将被禁用", "err", vectorStoreErr)
 	} else {
-		log.Println("向量存储已启用")
+		slog.Info("向量存储已启用")
 	}
 
 	// 创建工具列表
@@ -196,30 +607,55 @@ func SetupRuntime(ctx context.Context) (*Runtime, error) {
 		return nil, err
 	}
 	runtime.cozeClient = cozeClient
+	runtime.trace = trace
 	runtime.vectorStore = vectorStore
+	runtime.embedder = embedder
+	runtime.vectorStoreErr = vectorStoreErr
+
+	// 对话存储：持久化存储不可用时退回内存存储
+	store, err := ConversationStoreFromEnv(ctx)
+	if err != nil {
+		slog.Warn("创建对话存储失败，对话历史将不会保存", "err", err)
+	} else {
+		if ms, ok := store.(*MemoryStore); ok && ms.summarizer != nil {
+			// 历史摘要同样计入 token 用量；摘要不因超出预算而中断，由下一轮运行前的检查拒绝
+			ms.summarizer = &usageModel{BaseChatModel: ms.summarizer, name: "history_summary", handler: runtime.usageHandler(func(error) {})}
+		}
+		runtime.store = store
+	}
 
 	return runtime, nil
 }
 
-// initVectorStore 初始化向量存储
+// initVectorStore 初始化向量存储：配置了 REDIS_ADDR 时使用 Redis，否则退回本地文件存储
 func initVectorStore(ctx context.Context) (vector.VectorStore, embedding.Embedder, error) {
-	// 检查是否启用 Redis 向量存储
-	redisAddr := os.Getenv("REDIS_ADDR")
-	if redisAddr == "" {
-		return nil, nil, fmt.Errorf("REDIS_ADDR not set")
-	}
-
 	// 创建 embedding 模型
 	embedder, err := providers.CreateEmbeddingModel(ctx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("创建 embedding 模型失败: %w", err)
 	}
 
-	// 创建 Redis 向量存储
-	redisConfig := vector.DefaultRedisConfig()
-	vectorStore, err := vector.NewRedisStore(ctx, embedder, redisConfig)
-	if err != nil {
-		return nil, nil, fmt.Errorf("创建 Redis 向量存储失败: %w", err)
+	var vectorStore vector.VectorStore
+	if os.Getenv("REDIS_ADDR") != "" {
+		// 创建 Redis 向量存储
+		vectorStore, err = vector.NewRedisStore(ctx, embedder, vector.DefaultRedisConfig())
+		if err != nil {
+			return nil, nil, fmt.Errorf("创建 Redis 向量存储失败: %w", err)
+		}
+	} else {
+		// 未配置 Redis：使用本地 JSON 文件存储
+		localConfig := vector.DefaultLocalConfig()
+		if localConfig.VectorDim == 0 {
+			// 未设置 VECTOR_DIM：维度随 embedding 模型而定，探测得到
+			if localConfig.VectorDim, err = vector.ProbeDimension(ctx, embedder); err != nil {
+				return nil, nil, fmt.Errorf("创建本地向量存储失败: %w", err)
+			}
+		}
+		vectorStore, err = vector.NewLocalStore(embedder, localConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("创建本地向量存储失败: %w", err)
+		}
+		slog.Info("REDIS_ADDR 未设置，使用本地向量存储", "path", localConfig.Path)
 	}
 
 	// 初始化解析器注册表
@@ -237,7 +673,7 @@ func initCozeLoop(ctx context.Context) cozeloop.Client {
 	cozeloopWorkspaceID := os.Getenv("COZELOOP_WORKSPACE_ID")
 
 	if cozeloopApiToken == "" || cozeloopWorkspaceID == "" {
-		log.Println("Coze Loop 未配置（需要 COZE_LOOP_API_TOKEN 和 COZELOOP_WORKSPACE_ID 环境变量）")
+		slog.Info("Coze Loop 未配置（需要 COZE_LOOP_API_TOKEN 和 COZELOOP_WORKSPACE_ID 环境变量）")
 		return nil
 	}
 
@@ -246,11 +682,11 @@ func initCozeLoop(ctx context.Context) cozeloop.Client {
 		cozeloop.WithWorkspaceID(cozeloopWorkspaceID),
 	)
 	if err != nil {
-		log.Printf("创建 Coze Loop 客户端失败: %v", err)
+		slog.Error("创建 Coze Loop 客户端失败", "err", err)
 		return nil
 	}
 
-	log.Println("Coze Loop 观测已启用")
+	slog.Info("Coze Loop 观测已启用")
 
 	// 注册全局回调处理器
 	handler := clc.NewLoopHandler(client)
@@ -259,36 +695,105 @@ func initCozeLoop(ctx context.Context) cozeloop.Client {
 	return client
 }
 
-// createTools 创建所有工具
+// toolConstructor 创建单个工具
+type toolConstructor func() (tool.InvokableTool, error)
+
+// buildTools 依次创建工具，跳过创建失败的工具，返回创建成功的工具和失败的构造函数名
+func buildTools(constructors ...toolConstructor) ([]tool.BaseTool, []string) {
+	var built []tool.BaseTool
+	var failed []string
+	for _, newTool := range constructors {
+		t, err := newTool()
+		if err != nil {
+			name := constructorName(newTool)
+			slog.Warn("创建工具失败，已跳过", "tool", name, "err", err)
+			failed = append(failed, name)
+			continue
+		}
+		built = append(built, t)
+	}
+	return built, failed
+}
+
+// constructorName 返回构造函数的函数名（不含包路径），用于日志
+func constructorName(c toolConstructor) string {
+	name := runtime.FuncForPC(reflect.ValueOf(c).Pointer()).Name()
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// createTools 创建所有工具。单个工具创建失败时记录警告并跳过，其余工具照常可用；
+// 只有配置的代理不可用时返回错误
 func createTools(ctx context.Context, vs vector.VectorStore, emb embedding.Embedder) ([]tool.BaseTool, error) {
 	var toolsList []tool.BaseTool
+	var failed []string
+	add := func(constructors ...toolConstructor) {
+		built, f := buildTools(constructors...)
+		toolsList = append(toolsList, built...)
+		failed = append(failed, f...)
+	}
 
-	// 文件操作工具
-	toolsList = append(toolsList, tools.GetReadFileTool())
-	toolsList = append(toolsList, tools.GetWriteFileTool())
-	toolsList = append(toolsList, tools.GetEditFileTool())
-	toolsList = append(toolsList, tools.GetDeleteFileTool())
-	toolsList = append(toolsList, tools.GetListDirTool())
-
-	// 搜索工具
-	toolsList = append(toolsList, tools.GetGrepTool())
-	toolsList = append(toolsList, tools.GetGlobTool())
+	// 文件操作工具、搜索工具和 Bash 工具
+	add(
+		tools.GetReadFileTool,
+		tools.GetWriteFileTool,
+		tools.GetEditFileTool,
+		tools.GetDeleteFileTool,
+		tools.GetRestoreFileTool,
+		tools.GetMoveFileTool,
+		tools.GetCopyFileTool,
+		tools.GetMakeDirTool,
+		tools.GetHashTool,
+		tools.GetStatFileTool,
+		tools.GetListDirTool,
+		tools.GetGrepTool,
+		tools.GetGlobTool,
+		tools.GetBashTool,
+	)
 
-	// Bash 工具
-	toolsList = append(toolsList, tools.GetBashTool())
+	// 网络工具，配置了代理时先确认代理可用
+	if err := tools.CheckProxy(ctx); err != nil {
+		return nil, err
+	}
+	add(tools.GetSearchTool, tools.GetCheckURLsTool, tools.GetFetchTableTool)
+	// 摘要工具按长度注册多个变体：批量 URL 用 brief，主要来源用 detailed
+	for _, config := range tools.SummaryToolConfigs(tools.SummaryLengthFromEnv()) {
+		summaryTool, err := tools.GetContentSummaryTool(ctx, config)
+		if err != nil {
+			slog.Warn("创建工具失败，已跳过", "tool", config.Name, "err", err)
+			failed = append(failed, config.Name)
+			continue
+		}
+		toolsList = append(toolsList, summaryTool)
+	}
 
-	// 网络工具
-	toolsList = append(toolsList, tools.GetSearchTool())
-	toolsList = append(toolsList, tools.GetContentSummaryTool(ctx))
+	// 超大网页由摘要模型压缩后再返回，知识库检索按需由摘要模型重排
+	if summaryModel, err := providers.CreateSummaryModel(ctx); err != nil {
+		slog.Warn("创建摘要模型失败，超大网页将原样返回，知识库检索不重排", "err", err)
+	} else {
+		tools.SetPageSummarizer(tools.NewModelPageSummarizer(summaryModel))
+		tools.SetKnowledgeReranker(tools.NewModelKnowledgeReranker(summaryModel))
+	}
 
 	// 知识库工具 (只在向量存储可用时添加)
 	if vs != nil {
-		toolsList = append(toolsList, tools.GetKnowledgeTool())
-		toolsList = append(toolsList, tools.GetIngestDocumentTool())
-		toolsList = append(toolsList, tools.GetListDocumentsTool())
-		toolsList = append(toolsList, tools.GetDeleteDocumentTool())
-		log.Println("知识库工具已启用")
+		add(
+			tools.GetKnowledgeTool,
+			tools.GetIngestDocumentTool,
+			tools.GetIngestDirectoryTool,
+			tools.GetIngestURLTool,
+			tools.GetListDocumentsTool,
+			tools.GetGetDocumentTool,
+			tools.GetDeleteDocumentTool,
+			tools.GetListCollectionsTool,
+			tools.GetEvalRetrievalTool,
+		)
+		slog.Info("知识库工具已启用")
+	}
+
+	if len(failed) > 0 {
+		slog.Warn("部分工具不可用", "tools", failed)
 	}
 
-	return toolsList, nil
+	// 在工具描述中声明输出格式
+	return tools.WithOutputSchemas(ctx, toolsList), nil
 }
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"compass/llm/tools"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// TestRetryDropsLastAssistantTurn 验证重试会删除最后一轮助手回复并基于前一条用户消息重新生成
+func TestRetryDropsLastAssistantTurn(t *testing.T) {
+	fake := &scriptedModel{reply: textReply("answer")}
+	rt, _ := newTestRuntime(t, fake)
+	ctx := context.Background()
+
+	if err := rt.Run("first question"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.Run("second question"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟最后一轮带工具调用的回复
+	rt.store.Add(ctx, schema.ToolMessage("tool output", "call_1"))
+	rt.store.Add(ctx, schema.AssistantMessage("final answer", nil))
+
+	if err := rt.Retry(); err != nil {
+		t.Fatal(err)
+	}
+
+	input := fake.lastInput()
+	last := input[len(input)-1]
+	if last.Role != schema.User || last.Content != "second question" {
+		t.Errorf("重新生成应基于前一条用户消息，实际最后输入: %+v", last)
+	}
+	for _, msg := range input {
+		if msg.Content == "final answer" || msg.Content == "tool output" {
+			t.Errorf("重试输入中不应包含被删除的回复: %+v", msg)
+		}
+	}
+
+	history, _ := rt.store.List(ctx)
+	if len(history) != 4 {
+		t.Fatalf("期望历史为 4 条消息, 实际 %d", len(history))
+	}
+	if history[3].Role != schema.Assistant || history[2].Content != "second question" {
+		t.Errorf("历史中最后一轮应为新生成的回复: %+v", history[2:])
+	}
+}
+
+// TestRetryWithoutUserMessage 验证没有用户消息时重试返回错误
+func TestRetryWithoutUserMessage(t *testing.T) {
+	fake := &scriptedModel{reply: textReply("answer")}
+	rt, _ := newTestRuntime(t, fake)
+	if err := rt.Retry(); err == nil {
+		t.Error("没有用户消息时重试应返回错误")
+	}
+	if fake.calls() != 0 {
+		t.Error("没有用户消息时不应调用模型")
+	}
+}
+
+// TestRetryAfterUserMessage 验证最后一条为用户消息时直接重新运行
+func TestRetryAfterUserMessage(t *testing.T) {
+	fake := &scriptedModel{reply: textReply("answer")}
+	rt, _ := newTestRuntime(t, fake)
+	rt.store.Add(context.Background(), schema.UserMessage("pending question"))
+
+	if err := rt.Retry(); err != nil {
+		t.Fatal(err)
+	}
+	input := fake.lastInput()
+	if input[len(input)-1].Content != "pending question" {
+		t.Errorf("期望基于待回复的用户消息重新运行, 实际: %+v", input[len(input)-1])
+	}
+}
+
+// brokenTool 模拟创建失败的工具
+func brokenTool() (tool.InvokableTool, error) {
+	return nil, errors.New("failed to create broken tool")
+}
+
+// TestBuildToolsSkipsFailures 验证单个工具创建失败时跳过该工具，其余工具照常创建
+func TestBuildToolsSkipsFailures(t *testing.T) {
+	built, failed := buildTools(tools.GetReadFileTool, brokenTool, tools.GetGrepTool)
+	if len(built) != 2 {
+		t.Errorf("应创建 2 个工具, 实际 %d", len(built))
+	}
+	if strings.Join(failed, ",") != "brokenTool" {
+		t.Errorf("失败的工具不正确: %v", failed)
+	}
+}
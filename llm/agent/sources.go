@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"os"
+	"strings"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// sourcesHeading 来源脚注的标题
+const sourcesHeading = "**Sources**"
+
+// SourcesFooterFromEnv 读取 COMPASS_SOURCES_FOOTER："true" 时在最终回答后附加本轮查阅的来源
+func SourcesFooterFromEnv() bool {
+	return os.Getenv("COMPASS_SOURCES_FOOTER") == "true"
+}
+
+// isFinalAnswer 判断消息是否可能是本轮的最终回答（不含工具调用的助手消息）
+func isFinalAnswer(msg adk.Message) bool {
+	return msg.Role == schema.Assistant && len(msg.ToolCalls) == 0
+}
+
+// formatSourcesFooter 将来源列表格式化为 markdown 脚注，没有来源时返回空字符串
+func formatSourcesFooter(sources []string) string {
+	if len(sources) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n---\n")
+	sb.WriteString(sourcesHeading)
+	sb.WriteString("\n")
+	for _, s := range sources {
+		sb.WriteString("- " + s + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// withSourcesFooter 返回附加了来源脚注的消息副本，不修改原消息
+func withSourcesFooter(msg adk.Message, sources []string) adk.Message {
+	footer := formatSourcesFooter(sources)
+	if footer == "" {
+		return msg
+	}
+	out := *msg
+	out.Content = strings.TrimRight(msg.Content, "\n") + footer
+	return &out
+}
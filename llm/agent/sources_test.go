@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"compass/llm/tools"
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// sourcesReply 第一轮并行调用 fetch 和 web_search，第二轮再次 fetch，随后回答
+func sourcesReply(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+	// 只统计最后一条用户消息之后的工具调用轮次
+	rounds := 0
+	for _, msg := range input[lastUserIndex(input)+1:] {
+		if msg.Role == schema.Assistant && len(msg.ToolCalls) > 0 {
+			rounds++
+		}
+	}
+	switch rounds {
+	case 0:
+		return []*schema.Message{schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "call_1", Function: schema.FunctionCall{Name: "fetch", Arguments: `{"url": "https://go.dev/doc/"}`}},
+			{ID: "call_2", Function: schema.FunctionCall{Name: "web_search", Arguments: `{}`}},
+		})}, nil
+	case 1:
+		return []*schema.Message{schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "call_3", Function: schema.FunctionCall{Name: "fetch", Arguments: `{"url": "https://go.dev/blog/"}`}},
+		})}, nil
+	}
+	return []*schema.Message{schema.AssistantMessage("Go is great.", nil)}, nil
+}
+
+// sourceTool 记录固定来源的假网络工具
+type sourceTool struct {
+	name    string
+	sources []string
+}
+
+func (t *sourceTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: t.name, Desc: "Fake " + t.name}, nil
+}
+
+func (t *sourceTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+	tools.RecordSources(ctx, t.sources...)
+	return "ok", nil
+}
+
+// newSourcesRuntime 创建调用假 fetch/web_search 工具的 Runtime
+func newSourcesRuntime(t *testing.T, footer bool) (*Runtime, <-chan pubsub.Event[adk.Message]) {
+	t.Helper()
+	rt, events := newTestRuntime(t, &scriptedModel{reply: sourcesReply},
+		// fetch 每次都记录 go.dev/doc，与搜索结果重复
+		&sourceTool{name: "fetch", sources: []string{"https://go.dev/doc/"}},
+		&sourceTool{name: "web_search", sources: []string{"https://go.dev/doc/", "https://go.dev/blog/", " "}},
+	)
+	rt.sourcesFooter = footer
+	return rt, events
+}
+
+// TestSourcesFooterAggregates 验证来源脚注汇总多次工具调用的来源并去重
+func TestSourcesFooterAggregates(t *testing.T) {
+	rt, events := newSourcesRuntime(t, true)
+
+	if err := rt.Run("tell me about go"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+	last := msgs[len(msgs)-1]
+
+	want := "Go is great.\n\n---\n**Sources**\n- https://go.dev/doc/\n- https://go.dev/blog/"
+	if last.Content != want {
+		t.Errorf("最终回答 = %q, want %q", last.Content, want)
+	}
+	for _, msg := range msgs[:len(msgs)-1] {
+		if strings.Contains(msg.Content, sourcesHeading) {
+			t.Errorf("只有最终回答应带来源脚注: %+v", msg)
+		}
+	}
+
+	history, _ := rt.store.List(context.Background())
+	if stored := history[len(history)-1]; stored.Content != want {
+		t.Errorf("存储的最终回答 = %q, want %q", stored.Content, want)
+	}
+}
+
+// TestSourcesFooterDisabled 验证关闭时最终回答保持原样
+func TestSourcesFooterDisabled(t *testing.T) {
+	rt, events := newSourcesRuntime(t, false)
+
+	if err := rt.Run("tell me about go"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+	if last := msgs[len(msgs)-1]; last.Content != "Go is great." {
+		t.Errorf("关闭脚注时最终回答 = %q", last.Content)
+	}
+}
+
+// TestFormatSourcesFooter 验证没有来源时不附加脚注
+func TestFormatSourcesFooter(t *testing.T) {
+	msg := schema.AssistantMessage("answer\n", nil)
+	if got := withSourcesFooter(msg, nil); got.Content != "answer\n" {
+		t.Errorf("没有来源时不应修改消息: %q", got.Content)
+	}
+	got := withSourcesFooter(msg, []string{"./manual.md"})
+	if got.Content != "answer\n\n---\n**Sources**\n- ./manual.md" {
+		t.Errorf("脚注格式错误: %q", got.Content)
+	}
+	if msg.Content != "answer\n" {
+		t.Error("不应修改原消息")
+	}
+}
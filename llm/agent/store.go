@@ -3,9 +3,16 @@ package agent
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
 	"sync"
 
+	"compass/llm/providers"
+	"compass/llm/vector"
+
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -17,49 +24,243 @@ type ConversationStore interface {
 	List(ctx context.Context) ([]adk.Message, error)
 	// Clear 清空消息历史
 	Clear(ctx context.Context) error
+	// Truncate 只保留前 n 条消息
+	Truncate(ctx context.Context, n int) error
+}
+
+const (
+	// DefaultMaxTokens 对话历史默认的 token 预算（估算值）
+	DefaultMaxTokens = 16000
+	// messageTokenOverhead 每条消息除内容外的估算开销（角色、分隔符等）
+	messageTokenOverhead = 4
+	// defaultMaxToolResponse 工具响应默认最大长度（字符数）
+	defaultMaxToolResponse = 2000
+)
+
+// ConversationStoreFromEnv 根据 CONVERSATION_STORE 创建对话存储：
+// "memory"（默认，CONVERSATION_SUMMARIZE=true 时淘汰的历史由摘要模型压缩）、"file"（CONVERSATION_FILE 指定的 JSONL 文件）或 "redis"（复用 REDIS_* 配置）
+func ConversationStoreFromEnv(ctx context.Context) (ConversationStore, error) {
+	switch kind := os.Getenv("CONVERSATION_STORE"); kind {
+	case "", "memory":
+		var opts []MemoryStoreOption
+		if HistorySummaryFromEnv() {
+			if m, err := providers.CreateSummaryModel(ctx); err != nil {
+				slog.Warn("创建摘要模型失败，淘汰的历史将直接丢弃", "err", err)
+			} else {
+				opts = append(opts, WithSummarizer(m))
+			}
+		}
+		return NewMemoryStore(opts...), nil
+	case "file":
+		path := os.Getenv("CONVERSATION_FILE")
+		if path == "" {
+			path = DefaultConversationFile
+		}
+		return NewFileStore(path)
+	case "redis":
+		key := os.Getenv("CONVERSATION_REDIS_KEY")
+		if key == "" {
+			key = DefaultConversationRedisKey
+		}
+		return NewRedisStore(ctx, vector.DefaultRedisConfig(), key)
+	default:
+		return nil, fmt.Errorf("未知的对话存储类型: %q (可选 memory/file/redis)", kind)
+	}
+}
+
+// MaxTokensFromEnv 从 CONVERSATION_MAX_TOKENS 读取对话历史的 token 预算
+func MaxTokensFromEnv() int {
+	if n, err := strconv.Atoi(os.Getenv("CONVERSATION_MAX_TOKENS")); err == nil && n > 0 {
+		return n
+	}
+	return DefaultMaxTokens
 }
 
 // MemoryStore 内存实现的对话存储
 type MemoryStore struct {
 	mu              sync.RWMutex
 	msgs            []adk.Message
-	maxMessages     int // 最大保留消息数
+	maxTokens       int // 历史的 token 预算（估算值）
 	maxToolResponse int // 工具响应最大长度（字符数）
+
+	summarizer model.BaseChatModel // 淘汰消息时生成摘要的模型，nil 表示直接丢弃
+	summaryMu  sync.Mutex          // 串行化摘要，调用摘要模型期间不持有 mu
+	gen        int                 // 历史被清空或替换的次数，用于丢弃过期的摘要
+}
+
+// MemoryStoreOption MemoryStore 的可选配置
+type MemoryStoreOption func(*MemoryStore)
+
+// WithSummarizer 淘汰旧消息时由 m 将其压缩为摘要，作为一条系统消息保留在窗口开头
+func WithSummarizer(m model.BaseChatModel) MemoryStoreOption {
+	return func(s *MemoryStore) {
+		s.summarizer = m
+	}
 }
 
 // NewMemoryStore 创建一个新的内存存储
-func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{
+func NewMemoryStore(opts ...MemoryStoreOption) *MemoryStore {
+	s := &MemoryStore{
 		msgs:            make([]adk.Message, 0),
-		maxMessages:     20,   // 默认保留最近20条消息
-		maxToolResponse: 2000, // 工具响应最大2000字符
+		maxTokens:       MaxTokensFromEnv(),     // 超出预算时淘汰最旧的消息
+		maxToolResponse: defaultMaxToolResponse, // 工具响应最大2000字符
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Add 添加一条消息（带滑动窗口和工具结果压缩）
+// Add 添加一条消息（带 token 预算窗口和工具结果压缩）
 func (s *MemoryStore) Add(ctx context.Context, msg adk.Message) error {
+	s.push(ctx, msg)
+	return nil
+}
+
+// push 压缩并追加消息，返回实际存储的消息
+func (s *MemoryStore) push(ctx context.Context, msg adk.Message) adk.Message {
+	if s.summarizer != nil {
+		return s.pushSummarized(ctx, msg)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	// 压缩工具响应！
-	if msg.Role == schema.Tool {
-		msg = s.compressToolResponse(msg)
-	}
+	msg = compressToolResponse(msg, s.maxToolResponse)
 
 	// 添加压缩后的消息
 	s.msgs = append(s.msgs, msg)
 
-	// 滑动窗口：超过限制时删除最旧的消息
-	if len(s.msgs) > s.maxMessages {
-		s.msgs = s.msgs[len(s.msgs)-s.maxMessages:]
+	// 滑动窗口：超出 token 预算时删除最旧的消息
+	s.msgs = fitTokenBudget(s.msgs, s.maxTokens)
+
+	return msg
+}
+
+// pushSummarized 与 push 相同，但淘汰的消息由摘要模型压缩为摘要。
+// 淘汰在锁内完成，摘要模型调用期间不持有 mu，生成后再替换窗口中的旧摘要
+func (s *MemoryStore) pushSummarized(ctx context.Context, msg adk.Message) adk.Message {
+	s.summaryMu.Lock()
+	defer s.summaryMu.Unlock()
+
+	s.mu.Lock()
+	msg = compressToolResponse(msg, s.maxToolResponse)
+	s.msgs = append(s.msgs, msg)
+	var prev adk.Message
+	var evicted []adk.Message
+	s.msgs, prev, evicted = s.evictForSummary(s.msgs)
+	gen := s.gen
+	s.mu.Unlock()
+
+	if len(evicted) == 0 {
+		return msg
+	}
+	summary, err := summarizeHistory(ctx, s.summarizer, prev, evicted)
+	if err != nil {
+		slog.Warn("生成历史摘要失败，淘汰的消息将被丢弃", "err", err)
+		return msg
 	}
 
-	return nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 摘要期间历史被清空或替换时丢弃该摘要
+	if s.gen == gen {
+		s.msgs = replaceHistorySummary(s.msgs, summary)
+	}
+	return msg
+}
+
+// restore 用已存储的消息替换当前历史，不再重复压缩
+func (s *MemoryStore) restore(msgs []adk.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = fitTokenBudget(msgs, s.maxTokens)
+	s.gen++
 }
 
-// compressToolResponse 压缩工具响应消息
-func (s *MemoryStore) compressToolResponse(msg adk.Message) adk.Message {
-	// 如果内容不大，直接返回
-	if len(msg.Content) <= s.maxToolResponse {
+// len 返回当前保留的消息数
+func (s *MemoryStore) len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.msgs)
+}
+
+// estimateTokens 粗略估算消息占用的 token 数（约 3 字符 1 token）
+func estimateTokens(msg adk.Message) int {
+	n := len(msg.Content) + len(msg.ReasoningContent)
+	for _, tc := range msg.ToolCalls {
+		n += len(tc.Function.Name) + len(tc.Function.Arguments)
+	}
+	return n/3 + messageTokenOverhead
+}
+
+// fitTokenBudget 从最旧的消息开始淘汰，直到估算的 token 总数不超过 maxTokens。
+// 第一条系统消息始终保留；带 ToolCalls 的助手消息与紧随其后的工具结果作为整体淘汰，
+// 避免留下不完整的调用/结果对；最新的一组消息即使超出预算也会保留。
+func fitTokenBudget(msgs []adk.Message, maxTokens int) []adk.Message {
+	kept, _ := splitTokenBudget(msgs, maxTokens)
+	return kept
+}
+
+// splitTokenBudget 与 fitTokenBudget 相同，同时按原顺序返回被淘汰的消息
+func splitTokenBudget(msgs []adk.Message, maxTokens int) (kept, evicted []adk.Message) {
+	pinned := -1
+	total := 0
+	for i, msg := range msgs {
+		if pinned == -1 && msg.Role == schema.System && !isHistorySummary(msg) {
+			pinned = i
+		}
+		total += estimateTokens(msg)
+	}
+	if total <= maxTokens {
+		return msgs, nil
+	}
+
+	// 按淘汰单位切分：工具调用与其结果为一组，其余消息各自一组
+	type group struct{ start, end, tokens int }
+	var groups []group
+	for i := 0; i < len(msgs); {
+		if i == pinned {
+			i++
+			continue
+		}
+		g := group{start: i, end: i + 1, tokens: estimateTokens(msgs[i])}
+		if msgs[i].Role == schema.Assistant && len(msgs[i].ToolCalls) > 0 {
+			for g.end < len(msgs) && g.end != pinned && msgs[g.end].Role == schema.Tool {
+				g.tokens += estimateTokens(msgs[g.end])
+				g.end++
+			}
+		}
+		groups = append(groups, g)
+		i = g.end
+	}
+
+	// 从最旧的一组开始淘汰，至少保留最新的一组
+	drop := 0
+	for drop < len(groups)-1 && total > maxTokens {
+		total -= groups[drop].tokens
+		drop++
+	}
+	if drop == 0 {
+		return msgs, nil
+	}
+
+	keepFrom := groups[drop].start
+	kept = make([]adk.Message, 0, len(msgs)-keepFrom+1)
+	for i, msg := range msgs[:keepFrom] {
+		if i == pinned {
+			kept = append(kept, msg)
+		} else {
+			evicted = append(evicted, msg)
+		}
+	}
+	return append(kept, msgs[keepFrom:]...), evicted
+}
+
+// compressToolResponse 压缩超过 maxLen 的工具响应消息，其他消息原样返回
+func compressToolResponse(msg adk.Message, maxLen int) adk.Message {
+	// 如果不是工具响应或内容不大，直接返回
+	if msg.Role != schema.Tool || len(msg.Content) <= maxLen {
 		return msg
 	}
 
@@ -67,14 +268,14 @@ func (s *MemoryStore) compressToolResponse(msg adk.Message) adk.Message {
 	originalLen := len(msg.Content)
 
 	// 智能截断：尝试在句号、换行符处截断
-	truncated := msg.Content[:s.maxToolResponse]
+	truncated := msg.Content[:maxLen]
 
 	// 寻找合适的截断点
 	breakPoints := []string{"。\n", ".\n", "。", ". ", "\n\n", "\n"}
-	cutoff := s.maxToolResponse
+	cutoff := maxLen
 
 	for _, bp := range breakPoints {
-		if idx := findLastIndex(truncated, bp); idx > s.maxToolResponse/2 {
+		if idx := findLastIndex(truncated, bp); idx > maxLen/2 {
 			cutoff = idx + len(bp)
 			break
 		}
@@ -91,11 +292,10 @@ func (s *MemoryStore) compressToolResponse(msg adk.Message) adk.Message {
 		float64(originalLen-cutoff)/float64(originalLen)*100,
 	)
 
-	// 返回压缩后的消息
-	return &schema.Message{
-		Role:    msg.Role,
-		Content: compressed,
-	}
+	// 返回压缩后的消息副本，保留 ToolCallID 等字段以便与工具调用配对
+	out := *msg
+	out.Content = compressed
+	return &out
 }
 
 // List 获取所有消息
@@ -113,6 +313,20 @@ func (s *MemoryStore) Clear(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.msgs = nil
+	s.gen++
+	return nil
+}
+
+// Truncate 只保留前 n 条消息
+func (s *MemoryStore) Truncate(ctx context.Context, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	if n < len(s.msgs) {
+		s.msgs = s.msgs[:n]
+	}
 	return nil
 }
 
@@ -0,0 +1,238 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// newBudgetStore 创建指定 token 预算的内存存储
+func newBudgetStore(maxTokens int) *MemoryStore {
+	s := NewMemoryStore()
+	s.maxTokens = maxTokens
+	return s
+}
+
+// sized 创建估算约为 tokens 个 token 的消息内容
+func sized(tokens int) string {
+	return strings.Repeat("x", (tokens-messageTokenOverhead)*3)
+}
+
+// contents 返回消息内容的前缀，便于比较
+func contents(msgs []adk.Message) []string {
+	var out []string
+	for _, m := range msgs {
+		c := m.Content
+		if len(c) > 8 {
+			c = c[:8]
+		}
+		out = append(out, string(m.Role)+":"+c)
+	}
+	return out
+}
+
+// TestMemoryStoreEvictsByTokenBudget 验证按 token 预算而不是消息条数淘汰
+func TestMemoryStoreEvictsByTokenBudget(t *testing.T) {
+	ctx := context.Background()
+	s := newBudgetStore(100)
+
+	// 很多小消息都在预算内
+	for i := 0; i < 30; i++ {
+		s.Add(ctx, schema.UserMessage("hi"))
+	}
+	if history, _ := s.List(ctx); len(history) != 25 {
+		t.Errorf("30 条约 4 token 的消息应保留在 100 token 预算内的 %d 条, 实际 %d", 25, len(history))
+	}
+
+	// 大消息会挤掉旧消息
+	s.Clear(ctx)
+	s.Add(ctx, schema.UserMessage("old"+sized(40)))
+	s.Add(ctx, schema.UserMessage("mid"+sized(40)))
+	s.Add(ctx, schema.UserMessage("new"+sized(40)))
+	history, _ := s.List(ctx)
+	if len(history) != 2 || !strings.HasPrefix(history[0].Content, "mid") {
+		t.Errorf("超出预算时应淘汰最旧的消息: %v", contents(history))
+	}
+
+	// 单条超出预算的最新消息仍然保留
+	s.Add(ctx, schema.UserMessage("huge"+sized(500)))
+	if history, _ := s.List(ctx); len(history) != 1 || !strings.HasPrefix(history[0].Content, "huge") {
+		t.Errorf("最新消息即使超出预算也应保留: %v", contents(history))
+	}
+}
+
+// TestMemoryStoreKeepsFirstSystemMessage 验证第一条系统消息始终保留
+func TestMemoryStoreKeepsFirstSystemMessage(t *testing.T) {
+	ctx := context.Background()
+	s := newBudgetStore(100)
+
+	s.Add(ctx, schema.SystemMessage("rules"))
+	for i := 0; i < 5; i++ {
+		s.Add(ctx, schema.UserMessage(sized(40)))
+	}
+	history, _ := s.List(ctx)
+	if history[0].Role != schema.System || history[0].Content != "rules" {
+		t.Errorf("第一条系统消息应被保留: %v", contents(history))
+	}
+	if len(history) != 3 {
+		t.Errorf("系统消息之外应只保留预算内的消息: %v", contents(history))
+	}
+}
+
+// TestMemoryStoreEvictsToolPairsTogether 验证工具调用和对应结果一起淘汰
+func TestMemoryStoreEvictsToolPairsTogether(t *testing.T) {
+	ctx := context.Background()
+	s := newBudgetStore(100)
+
+	s.Add(ctx, schema.UserMessage("question"))
+	s.Add(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "call_1", Function: schema.FunctionCall{Name: "fetch", Arguments: "{}"}},
+		{ID: "call_2", Function: schema.FunctionCall{Name: "fetch", Arguments: "{}"}},
+	}))
+	s.Add(ctx, schema.ToolMessage(sized(20), "call_1"))
+	s.Add(ctx, schema.ToolMessage(sized(20), "call_2"))
+	s.Add(ctx, schema.AssistantMessage("answer", nil))
+
+	// 只淘汰用户消息仍超出预算，调用与结果需要整体淘汰
+	s.Add(ctx, schema.UserMessage(sized(60)))
+	history, _ := s.List(ctx)
+	for _, msg := range history {
+		if msg.Role == schema.Tool {
+			t.Fatalf("工具结果不应在其调用被淘汰后单独保留: %v", contents(history))
+		}
+	}
+	if len(history) != 2 || history[0].Content != "answer" {
+		t.Errorf("期望保留最终回答和最新的用户消息: %v", contents(history))
+	}
+
+	// 淘汰更早的消息时，最新的调用与结果作为整体保留
+	s = newBudgetStore(100)
+	s.Add(ctx, schema.UserMessage(sized(80)))
+	s.Add(ctx, schema.AssistantMessage("", []schema.ToolCall{{ID: "call_1", Function: schema.FunctionCall{Name: "fetch"}}}))
+	s.Add(ctx, schema.ToolMessage(sized(20), "call_1"))
+	history, _ = s.List(ctx)
+	if len(history) != 2 || history[0].Role != schema.Assistant || history[1].ToolCallID != "call_1" {
+		t.Errorf("应整体保留工具调用及其结果: %v", contents(history))
+	}
+}
+
+// TestCompressToolResponseKeepsToolCallID 验证压缩后的工具结果仍能与调用配对
+func TestCompressToolResponseKeepsToolCallID(t *testing.T) {
+	msg := compressToolResponse(schema.ToolMessage(strings.Repeat("x", 5000), "call_1"), defaultMaxToolResponse)
+	if msg.ToolCallID != "call_1" || len(msg.Content) >= 5000 {
+		t.Errorf("压缩后应保留 ToolCallID 并缩短内容: id=%q len=%d", msg.ToolCallID, len(msg.Content))
+	}
+}
+
+// summaryReply 返回按调用序号编号的固定摘要，fail 时模拟模型不可用
+func summaryReply(fail bool) replyFunc {
+	return func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		if fail {
+			return nil, errors.New("model unavailable")
+		}
+		return []*schema.Message{schema.AssistantMessage(fmt.Sprintf("summary #%d", call), nil)}, nil
+	}
+}
+
+// TestMemoryStoreSummarizesEvicted 验证配置摘要模型后淘汰的消息被压缩为窗口开头的系统消息
+func TestMemoryStoreSummarizesEvicted(t *testing.T) {
+	ctx := context.Background()
+	summarizer := &scriptedModel{reply: summaryReply(false)}
+	s := NewMemoryStore(WithSummarizer(summarizer))
+	s.maxTokens = 100
+
+	s.Add(ctx, schema.SystemMessage("rules"))
+	s.Add(ctx, schema.UserMessage("first question"+sized(40)))
+	s.Add(ctx, schema.AssistantMessage("first answer"+sized(40), nil))
+	if summarizer.calls() != 0 {
+		t.Fatal("未超出预算时不应调用摘要模型")
+	}
+
+	s.Add(ctx, schema.UserMessage("second question"+sized(40)))
+	history, _ := s.List(ctx)
+	if history[0].Content != "rules" || !isHistorySummary(history[1]) || history[1].Role != schema.System {
+		t.Fatalf("摘要应作为系统消息放在第一条系统消息之后: %v", contents(history))
+	}
+	if !strings.HasSuffix(history[1].Content, "summary #1") {
+		t.Errorf("摘要内容错误: %q", history[1].Content)
+	}
+	if input := summarizer.lastInput()[1].Content; !strings.Contains(input, "user: first question") {
+		t.Errorf("摘要输入应包含被淘汰的消息: %q", input[:40])
+	}
+
+	// 再次淘汰时与已有摘要合并，窗口中只有一条摘要
+	s.Add(ctx, schema.AssistantMessage("second answer"+sized(40), nil))
+	history, _ = s.List(ctx)
+	summaries := 0
+	for _, msg := range history {
+		if isHistorySummary(msg) {
+			summaries++
+		}
+	}
+	if summaries != 1 || !strings.HasSuffix(history[1].Content, "summary #2") {
+		t.Errorf("应合并为一条新摘要: %v", contents(history))
+	}
+	if input := summarizer.lastInput()[1].Content; !strings.Contains(input, "Previous summary:\nsummary #1") {
+		t.Errorf("再次摘要应包含已有摘要: %q", input)
+	}
+}
+
+// TestMemoryStoreSummarizerFailure 验证摘要失败时退回直接淘汰
+func TestMemoryStoreSummarizerFailure(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(WithSummarizer(&scriptedModel{reply: summaryReply(true)}))
+	s.maxTokens = 100
+
+	for i := 0; i < 4; i++ {
+		s.Add(ctx, schema.UserMessage(sized(40)))
+	}
+	history, _ := s.List(ctx)
+	if len(history) != 2 {
+		t.Errorf("摘要失败时应直接淘汰旧消息, 实际保留 %d 条", len(history))
+	}
+	for _, msg := range history {
+		if isHistorySummary(msg) {
+			t.Error("摘要失败时不应插入摘要")
+		}
+	}
+}
+
+// TestMemoryStoreSummarizesWithoutLock 验证生成摘要期间不阻塞读取，期间清空的历史不会被摘要写回
+func TestMemoryStoreSummarizesWithoutLock(t *testing.T) {
+	ctx := context.Background()
+	// 摘要请求在 release 关闭前阻塞
+	started, release := make(chan struct{}), make(chan struct{})
+	summarizer := &scriptedModel{reply: func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		close(started)
+		<-release
+		return summaryReply(false)(ctx, input, call)
+	}}
+	s := NewMemoryStore(WithSummarizer(summarizer))
+	s.maxTokens = 100
+
+	s.Add(ctx, schema.UserMessage("first question"+sized(40)))
+	s.Add(ctx, schema.AssistantMessage("first answer"+sized(40), nil))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Add(ctx, schema.UserMessage("second question"+sized(40)))
+	}()
+	<-started
+
+	history, _ := s.List(ctx)
+	if len(history) != 2 || !strings.HasPrefix(history[1].Content, "second question") {
+		t.Errorf("摘要期间应能读取淘汰后的窗口: %v", contents(history))
+	}
+	s.Clear(ctx)
+	close(release)
+	<-done
+
+	if history, _ := s.List(ctx); len(history) != 0 {
+		t.Errorf("清空后不应写回摘要: %v", contents(history))
+	}
+}
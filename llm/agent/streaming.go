@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// partialExtraKey 标记流式生成中的临时消息，存放在 Message.Extra 中
+const partialExtraKey = "compass_partial"
+
+// streamPublishInterval 两次发布增量消息的最小间隔，避免每个 token 都触发一次重绘
+var streamPublishInterval = 30 * time.Millisecond
+
+// StreamingFromEnv 读取 COMPASS_STREAMING：默认启用流式输出，"false" 时等待完整回复后再显示
+func StreamingFromEnv() bool {
+	return os.Getenv("COMPASS_STREAMING") != "false"
+}
+
+// IsPartial 判断消息是否为流式生成中的临时消息。
+// 临时消息不会写入对话存储，同一条回复的后续增量或完整消息会取代它。
+func IsPartial(msg adk.Message) bool {
+	if msg == nil || msg.Extra == nil {
+		return false
+	}
+	partial, _ := msg.Extra[partialExtraKey].(bool)
+	return partial
+}
+
+// partialMessage 创建携带当前已生成内容的临时消息
+func partialMessage(content string) adk.Message {
+	return &schema.Message{
+		Role:    schema.Assistant,
+		Content: content,
+		Extra:   map[string]any{partialExtraKey: true},
+	}
+}
+
+// RunStreaming 与 Run 相同，但以流式方式运行 Agent：
+// 助手回复生成过程中持续发布带 IsPartial 标记的 UpdatedEvent，完整消息生成后再发布并存储。
+// 之后的 Retry 和 ApprovePlan 沿用最近一次运行的模式。
+func (r *Runtime) RunStreaming(userPrompt string) error {
+	r.streaming.Store(true)
+	return r.run(userPrompt)
+}
+
+// streamMessage 读取助手回复的消息流，按间隔发布增量内容，返回拼接后的完整消息
+func (r *Runtime) streamMessage(ctx context.Context, stream *schema.StreamReader[adk.Message]) (adk.Message, error) {
+	defer stream.Close()
+
+	var (
+		chunks      []*schema.Message
+		content     strings.Builder
+		lastPublish time.Time
+		published   int
+	)
+	for {
+		if err := context.Cause(ctx); err != nil {
+			return nil, err
+		}
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if chunk == nil {
+			continue
+		}
+		chunks = append(chunks, chunk)
+		content.WriteString(chunk.Content)
+
+		if content.Len() > published && time.Since(lastPublish) >= streamPublishInterval {
+			r.broker.Publish(pubsub.UpdatedEvent, partialMessage(content.String()))
+			published = content.Len()
+			lastPublish = time.Now()
+		}
+	}
+
+	if len(chunks) == 0 {
+		return nil, errors.New("消息流为空")
+	}
+	return schema.ConcatMessages(chunks)
+}
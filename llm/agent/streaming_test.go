@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// chunkReply 按片段返回回复；toolCall 为 true 时首次调用先返回一次工具调用
+func chunkReply(chunks []string, toolCall bool) replyFunc {
+	return func(ctx context.Context, input []*schema.Message, call int) ([]*schema.Message, error) {
+		if toolCall && call == 1 {
+			return []*schema.Message{echoToolCall()}, nil
+		}
+		var msgs []*schema.Message
+		for _, c := range chunks {
+			msgs = append(msgs, schema.AssistantMessage(c, nil))
+		}
+		return msgs, nil
+	}
+}
+
+// publishEveryChunk 让每个片段都立即发布增量
+func publishEveryChunk(t *testing.T) {
+	old := streamPublishInterval
+	streamPublishInterval = 0
+	t.Cleanup(func() { streamPublishInterval = old })
+}
+
+// TestRunStreamingPublishesPartials 验证流式运行逐步发布增量内容，最后发布并存储完整消息
+func TestRunStreamingPublishesPartials(t *testing.T) {
+	publishEveryChunk(t)
+	rt, events := newTestRuntime(t, &scriptedModel{reply: chunkReply([]string{"Hel", "lo ", "wor", "ld"}, false)})
+
+	if err := rt.RunStreaming("question"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+
+	var partials []string
+	for _, msg := range msgs[:len(msgs)-1] {
+		if !IsPartial(msg) {
+			t.Fatalf("完整消息之前应只有增量消息, 实际: %+v", msg)
+		}
+		partials = append(partials, msg.Content)
+	}
+	want := []string{"Hel", "Hello ", "Hello wor", "Hello world"}
+	if strings.Join(partials, "|") != strings.Join(want, "|") {
+		t.Errorf("增量内容应逐步增长, 期望 %q, 实际 %q", want, partials)
+	}
+
+	final := msgs[len(msgs)-1]
+	if IsPartial(final) || final.Content != "Hello world" {
+		t.Errorf("最后应发布完整消息, 实际: %+v", final)
+	}
+
+	history, _ := rt.store.List(context.Background())
+	if len(history) != 2 || history[1].Content != "Hello world" || IsPartial(history[1]) {
+		t.Errorf("存储中应只有用户消息和完整回复: %+v", history)
+	}
+}
+
+// TestRunStreamingWithToolCall 验证流式运行中工具调用和后续回复都能正常完成
+func TestRunStreamingWithToolCall(t *testing.T) {
+	publishEveryChunk(t)
+	rt, events := newTestRuntime(t, &scriptedModel{reply: chunkReply([]string{"done", "!"}, true)}, &echoTool{log: &planLog{}})
+
+	if err := rt.RunStreaming("question"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := collectUntilFinished(t, events)
+
+	var complete []string
+	for _, msg := range msgs {
+		if !IsPartial(msg) {
+			complete = append(complete, string(msg.Role))
+		}
+	}
+	if strings.Join(complete, ",") != "assistant,tool,assistant" {
+		t.Errorf("完整消息应为工具调用、工具结果和最终回答, 实际: %v", complete)
+	}
+	if last := msgs[len(msgs)-1]; last.Content != "done!" {
+		t.Errorf("最终回答应为完整内容, 实际: %q", last.Content)
+	}
+}
+
+// TestRunDoesNotPublishPartials 验证非流式运行只发布完整消息
+func TestRunDoesNotPublishPartials(t *testing.T) {
+	publishEveryChunk(t)
+	rt, events := newTestRuntime(t, &scriptedModel{reply: chunkReply([]string{"Hel", "lo"}, false)})
+
+	if err := rt.RunStreaming("first"); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+
+	// Run 切回非流式，之后的 Retry 沿用该模式
+	if err := rt.Run("second"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rt.Retry(); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range append(collectUntilFinished(t, events), collectUntilFinished(t, events)...) {
+		if IsPartial(msg) {
+			t.Errorf("非流式运行不应发布增量消息: %+v", msg)
+		}
+	}
+}
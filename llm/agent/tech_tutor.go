@@ -3,7 +3,9 @@ package agent
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
+
+	"compass/llm/tools"
 
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/components/model"
@@ -18,6 +20,8 @@ You are an intelligent learning assistant specializing in technology and program
 # TOOLSET
 | web_search | Latest info (versions, APIs, news) |
 | fetch | Full web content (use format="markdown") |
+| check_urls | Verify links are reachable before fetching |
+| fetch_table | Extract data tables from a page as CSV/markdown |
 | search_knowledge | Search local knowledge base |
 | ingest_document | Store docs for future retrieval |
 | grep/glob/read_file | Search/read local code |
@@ -41,6 +45,7 @@ Knowledge cutoff: 2025. Tech evolves rapidly. Always cite sources.
 # KNOWLEDGE BASE
 After generating valuable content, ask: "是否需要将此内容存入知识库以便后续检索？"
 If yes: write_file → ingest_document.
+Documents can live in separate collections (e.g. "work-docs"); use list_collections and pass collection when the user names one.
 
 # STYLE
 Concise, practical, high info density. Markdown format. Code examples preferred. Chinese explanations, English code/terms.
@@ -50,6 +55,12 @@ Concise, practical, high info density. Markdown format. Code examples preferred.
 type TechTutorConfig struct {
 	ChatModel model.ToolCallingChatModel
 	Tools     []tool.BaseTool
+
+	// ApprovalTools 执行前需要用户批准的工具名，为空时不拦截
+	ApprovalTools []string
+
+	// ReadOnly 只读模式：修改文件或执行命令的工具只返回预期改动，不实际执行
+	ReadOnly bool
 }
 
 // NewTechTutorAgent creates the TechTutor agent using the provided configuration.
@@ -58,6 +69,23 @@ func NewTechTutorAgent(ctx context.Context, config *TechTutorConfig) (adk.Agent,
 		return nil, errors.New("config is nil")
 	}
 
+	// 拦截本轮内紧接着重复的只读工具调用，避免模型陷入循环
+	middlewares := []compose.ToolMiddleware{
+		tools.DedupToolCalls(tools.DedupWindowFromEnv()),
+	}
+	if config.ReadOnly {
+		middlewares = append(middlewares, tools.ReadOnlyTools())
+	}
+	// 工具错误转为错误结果交给模型；超时放在最内层，只限制工具本身的执行时间
+	middlewares = append(middlewares,
+		tools.ErrorHandler(),
+		tools.ToolTimeouts(tools.ToolTimeoutFromEnv(), tools.DefaultToolTimeouts),
+	)
+	// 破坏性工具先中断等待批准，放在最外层使去重不缓存未批准的调用；只读模式下无需批准
+	if len(config.ApprovalTools) > 0 && !config.ReadOnly {
+		middlewares = append([]compose.ToolMiddleware{ApprovalMiddleware(config.ApprovalTools)}, middlewares...)
+	}
+
 	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
 		Name:        "TechTutor",
 		Description: "An intelligent learning assistant with web search and synthesis capabilities.",
@@ -65,13 +93,14 @@ func NewTechTutorAgent(ctx context.Context, config *TechTutorConfig) (adk.Agent,
 		Model:       config.ChatModel,
 		ToolsConfig: adk.ToolsConfig{
 			ToolsNodeConfig: compose.ToolsNodeConfig{
-				Tools: config.Tools,
+				Tools:               config.Tools,
+				ToolCallMiddlewares: middlewares,
 			},
 		},
 		MaxIterations: 200,
 	})
 	if err != nil {
-		log.Printf("Failed to create TechTutor agent: %v", err)
+		slog.Error("failed to create TechTutor agent", "err", err)
 		return nil, err
 	}
 
@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	ucb "github.com/cloudwego/eino/utils/callbacks"
+)
+
+// ErrTokenBudget 会话的 token 用量超出预算
+var ErrTokenBudget = errors.New("session token budget exceeded")
+
+// TokenUsage 模型调用的 token 用量
+type TokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	Estimated        bool // 至少有一次调用未返回用量，按字符数估算
+}
+
+// Total 返回 prompt 与 completion 之和
+func (u TokenUsage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// add 累加另一次调用的用量
+func (u TokenUsage) add(o TokenUsage) TokenUsage {
+	return TokenUsage{
+		PromptTokens:     u.PromptTokens + o.PromptTokens,
+		CompletionTokens: u.CompletionTokens + o.CompletionTokens,
+		Estimated:        u.Estimated || o.Estimated,
+	}
+}
+
+// MaxTokensPerSessionFromEnv 从 COMPASS_MAX_TOKENS_PER_SESSION 读取会话 token 预算，0 或未设置表示不限制
+func MaxTokensPerSessionFromEnv() int {
+	if val := os.Getenv("COMPASS_MAX_TOKENS_PER_SESSION"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// TokenUsage 返回本会话累计的 token 用量
+func (r *Runtime) TokenUsage() TokenUsage {
+	r.usageMu.Lock()
+	defer r.usageMu.Unlock()
+	return r.usage
+}
+
+// MaxTokensPerSession 返回会话 token 预算，0 表示不限制
+func (r *Runtime) MaxTokensPerSession() int {
+	return r.maxTokensPerSession
+}
+
+// ResetTokenUsage 清零累计用量，开始新的会话预算
+func (r *Runtime) ResetTokenUsage() {
+	r.usageMu.Lock()
+	defer r.usageMu.Unlock()
+	r.usage = TokenUsage{}
+}
+
+// addTokenUsage 累加一次调用的用量，返回累计值是否超出预算
+func (r *Runtime) addTokenUsage(u TokenUsage) bool {
+	r.usageMu.Lock()
+	defer r.usageMu.Unlock()
+	r.usage = r.usage.add(u)
+	return r.maxTokensPerSession > 0 && r.usage.Total() > r.maxTokensPerSession
+}
+
+// checkTokenBudget 运行开始前检查预算是否已用完
+func (r *Runtime) checkTokenBudget() error {
+	if r.maxTokensPerSession <= 0 {
+		return nil
+	}
+	if used := r.TokenUsage().Total(); used >= r.maxTokensPerSession {
+		return tokenBudgetError(used, r.maxTokensPerSession)
+	}
+	return nil
+}
+
+// tokenBudgetError 说明预算用量并提示如何继续
+func tokenBudgetError(used, budget int) error {
+	return fmt.Errorf("%w: 已使用 %d / %d tokens，使用 /clear 开始新会话或调高 COMPASS_MAX_TOKENS_PER_SESSION",
+		ErrTokenBudget, used, budget)
+}
+
+type usageInputKey struct{}
+
+// usageHandler 创建统计本轮模型调用用量的回调；累计用量超出预算时以 ErrTokenBudget 调用 stop 中断运行
+func (r *Runtime) usageHandler(stop context.CancelCauseFunc) callbacks.Handler {
+	record := func(ctx context.Context, msg *schema.Message, usage *model.TokenUsage) {
+		u, ok := reportedUsage(msg, usage)
+		if !ok {
+			input, _ := ctx.Value(usageInputKey{}).([]*schema.Message)
+			u = estimateUsage(input, msg)
+		}
+		if r.addTokenUsage(u) {
+			stop(tokenBudgetError(r.TokenUsage().Total(), r.maxTokensPerSession))
+		}
+	}
+
+	return ucb.NewHandlerHelper().
+		ChatModel(&ucb.ModelCallbackHandler{
+			OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *model.CallbackInput) context.Context {
+				return context.WithValue(ctx, usageInputKey{}, input.Messages)
+			},
+			OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+				record(ctx, output.Message, output.TokenUsage)
+				return ctx
+			},
+			OnEndWithStreamOutput: func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+				// 用量通常在流的末尾返回，读完后再统计
+				go func() {
+					msg, usage, _ := concatModelStream(output)
+					record(ctx, msg, usage)
+				}()
+				return ctx
+			},
+		}).
+		Handler()
+}
+
+// usageModel 包装在 Agent 之外直接调用的模型（生成计划、历史摘要），
+// 以 handler 触发模型回调，使这些调用同样计入 token 用量并写入追踪记录
+type usageModel struct {
+	model.BaseChatModel
+	name    string
+	handler callbacks.Handler
+}
+
+func (m *usageModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: m.name, Component: components.ComponentOfChatModel}, m.handler)
+	if components.IsCallbacksEnabled(m.BaseChatModel) {
+		// 模型自行触发回调
+		return m.BaseChatModel.Generate(ctx, input, opts...)
+	}
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: input})
+	resp, err := m.BaseChatModel.Generate(ctx, input, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	callbacks.OnEnd(ctx, &model.CallbackOutput{Message: resp})
+	return resp, nil
+}
+
+// reportedUsage 返回模型报告的用量：优先使用回调输出中的用量，其次是消息的 ResponseMeta
+func reportedUsage(msg *schema.Message, usage *model.TokenUsage) (TokenUsage, bool) {
+	if usage != nil && usage.TotalTokens > 0 {
+		return TokenUsage{PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens}, true
+	}
+	if msg != nil && msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil && msg.ResponseMeta.Usage.TotalTokens > 0 {
+		u := msg.ResponseMeta.Usage
+		return TokenUsage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}, true
+	}
+	return TokenUsage{}, false
+}
+
+// estimateUsage 模型未返回用量时按字符数估算
+func estimateUsage(input []*schema.Message, output *schema.Message) TokenUsage {
+	u := TokenUsage{Estimated: true}
+	for _, msg := range input {
+		u.PromptTokens += estimateTokens(msg)
+	}
+	if output != nil {
+		u.CompletionTokens = estimateTokens(output)
+	}
+	return u
+}
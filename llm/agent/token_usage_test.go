@@ -0,0 +1,151 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// newUsageRuntime 创建每次模型调用报告固定用量的 Runtime
+func newUsageRuntime(t *testing.T, budget int) (*Runtime, *planLog, <-chan pubsub.Event[adk.Message]) {
+	t.Helper()
+	log := &planLog{}
+	m := &scriptedModel{
+		reply: planReply(log),
+		usage: &schema.TokenUsage{PromptTokens: 100, CompletionTokens: 10, TotalTokens: 110},
+	}
+	rt, events := newTestRuntime(t, m, &echoTool{log: log})
+	rt.maxTokensPerSession = budget
+	return rt, log, events
+}
+
+// TestTokenUsageAccumulates 验证每次模型调用的用量累加到会话总量
+func TestTokenUsageAccumulates(t *testing.T) {
+	rt, _, events := newUsageRuntime(t, 0)
+
+	if err := rt.Run("question"); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+	want := TokenUsage{PromptTokens: 200, CompletionTokens: 20}
+	if got := rt.TokenUsage(); got != want {
+		t.Errorf("一轮两次模型调用, got %+v, want %+v", got, want)
+	}
+
+	rt.Run("again")
+	collectUntilFinished(t, events)
+	if got := rt.TokenUsage().Total(); got != 440 {
+		t.Errorf("用量应跨轮累计, got %d", got)
+	}
+
+	rt.ResetTokenUsage()
+	if got := rt.TokenUsage().Total(); got != 0 {
+		t.Errorf("重置后用量应为 0, got %d", got)
+	}
+}
+
+// TestTokenUsageEstimated 验证模型未返回用量时按字符数估算
+func TestTokenUsageEstimated(t *testing.T) {
+	rt, _, events := newPlanRuntime(t, PlanOff)
+	rt.Run("question")
+	collectUntilFinished(t, events)
+	if got := rt.TokenUsage(); !got.Estimated || got.Total() == 0 {
+		t.Errorf("应估算用量, got %+v", got)
+	}
+}
+
+// TestTokenBudgetInterruptsRun 验证超出预算时中断运行，之后的运行直接拒绝，重置后恢复
+func TestTokenBudgetInterruptsRun(t *testing.T) {
+	rt, log, events := newUsageRuntime(t, 100)
+
+	err := rt.Run("question")
+	if !errors.Is(err, ErrTokenBudget) {
+		t.Fatalf("期望 ErrTokenBudget, 实际: %v", err)
+	}
+	msgs := collectContents(t, events)
+	if len(msgs) == 0 || !strings.Contains(msgs[len(msgs)-1], "110 / 100 tokens") {
+		t.Errorf("应发布预算说明, 实际: %v", msgs)
+	}
+	if steps := log.list(); strings.Join(steps, ",") != "call" {
+		t.Errorf("超出预算后不应继续执行, 实际: %v", steps)
+	}
+
+	if err := rt.Run("again"); !errors.Is(err, ErrTokenBudget) {
+		t.Errorf("预算用完后应拒绝运行, 实际: %v", err)
+	}
+	collectUntilFinished(t, events)
+	if steps := log.list(); len(steps) != 1 {
+		t.Errorf("预算用完后不应调用模型, 实际: %v", steps)
+	}
+
+	rt.ResetTokenUsage()
+	rt.maxTokensPerSession = 1000
+	if err := rt.Run("again"); err != nil {
+		t.Fatalf("重置后应可以运行: %v", err)
+	}
+	collectUntilFinished(t, events)
+}
+
+// TestTokenUsageIncludesPlan 验证生成计划的模型调用计入用量，预算用完时不再生成计划
+func TestTokenUsageIncludesPlan(t *testing.T) {
+	rt, log, events := newUsageRuntime(t, 0)
+	rt.planMode = PlanShow
+
+	if err := rt.Run("question"); err != nil {
+		t.Fatal(err)
+	}
+	collectUntilFinished(t, events)
+	if got := rt.TokenUsage().Total(); got != 330 {
+		t.Errorf("计划和两次执行调用都应计入用量, got %d", got)
+	}
+
+	rt.maxTokensPerSession = 300
+	if err := rt.Run("again"); !errors.Is(err, ErrTokenBudget) {
+		t.Fatalf("预算用完后应拒绝生成计划, 实际: %v", err)
+	}
+	collectUntilFinished(t, events)
+	if steps := log.list(); strings.Join(steps, ",") != "plan,call,tool,answer" {
+		t.Errorf("预算用完后不应调用模型, 实际: %v", steps)
+	}
+}
+
+// TestTokenUsageIncludesHistorySummary 验证历史摘要的模型调用计入用量
+func TestTokenUsageIncludesHistorySummary(t *testing.T) {
+	rt, _, _ := newUsageRuntime(t, 0)
+	s := NewMemoryStore(WithSummarizer(&usageModel{
+		BaseChatModel: &scriptedModel{reply: summaryReply(false)},
+		name:          "history_summary",
+		handler:       rt.usageHandler(func(error) {}),
+	}))
+	s.maxTokens = 100
+
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		s.Add(ctx, schema.UserMessage(sized(40)))
+	}
+	if got := rt.TokenUsage(); !got.Estimated || got.Total() == 0 {
+		t.Errorf("摘要调用应计入用量, got %+v", got)
+	}
+}
+
+// TestMaxTokensPerSessionFromEnv 验证 COMPASS_MAX_TOKENS_PER_SESSION 的解析
+func TestMaxTokensPerSessionFromEnv(t *testing.T) {
+	for val, want := range map[string]int{
+		"":        0,
+		"50000":   50000,
+		"0":       0,
+		"-1":      0,
+		"invalid": 0,
+	} {
+		t.Setenv("COMPASS_MAX_TOKENS_PER_SESSION", val)
+		if got := MaxTokensPerSessionFromEnv(); got != want {
+			t.Errorf("COMPASS_MAX_TOKENS_PER_SESSION=%q: got %d, want %d", val, got, want)
+		}
+	}
+}
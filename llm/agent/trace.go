@@ -0,0 +1,267 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	ucb "github.com/cloudwego/eino/utils/callbacks"
+)
+
+// 追踪记录的类型
+const (
+	traceKindLLM  = "llm"
+	traceKindTool = "tool"
+)
+
+// traceRecord JSONL 追踪文件中的一行：一次模型调用或工具调用
+type traceRecord struct {
+	Time      time.Time    `json:"time"` // 调用开始时间
+	Kind      string       `json:"kind"`
+	Name      string       `json:"name"`
+	LatencyMS int64        `json:"latency_ms"`
+	Input     any          `json:"input,omitempty"`
+	Output    any          `json:"output,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	Tokens    *traceTokens `json:"tokens,omitempty"`
+}
+
+// traceTokens 模型调用的 token 用量；模型未返回用量时为估算值
+type traceTokens struct {
+	Prompt     int  `json:"prompt"`
+	Completion int  `json:"completion"`
+	Total      int  `json:"total"`
+	Estimated  bool `json:"estimated,omitempty"`
+}
+
+// traceLLMInput 模型调用的输入，工具只记录名称以控制文件大小
+type traceLLMInput struct {
+	Messages []*schema.Message `json:"messages"`
+	Tools    []string          `json:"tools,omitempty"`
+}
+
+// traceSpan 调用开始时记录的信息，经 context 传给结束回调
+type traceSpan struct {
+	start time.Time
+	input any
+	model *model.CallbackInput // 模型调用的原始输入，用于估算 token
+}
+
+type traceSpanKey struct{}
+
+// traceWriter 串行写入追踪记录；流式输出在后台读取，Close 时等待其写完
+type traceWriter struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	closer  io.Closer
+	pending sync.WaitGroup
+}
+
+// newTraceWriter 创建写入 w 的追踪记录器；w 实现 io.Closer 时由 Close 关闭
+func newTraceWriter(w io.Writer) *traceWriter {
+	tw := &traceWriter{enc: json.NewEncoder(w)}
+	if c, ok := w.(io.Closer); ok {
+		tw.closer = c
+	}
+	return tw
+}
+
+// write 追加一条记录，失败时只记录日志，不影响 Agent 运行
+func (tw *traceWriter) write(rec traceRecord) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if err := tw.enc.Encode(rec); err != nil {
+		slog.Warn("写入追踪记录失败", "err", err)
+	}
+}
+
+// Close 等待后台记录写完后关闭文件
+func (tw *traceWriter) Close() error {
+	tw.pending.Wait()
+	if tw.closer != nil {
+		return tw.closer.Close()
+	}
+	return nil
+}
+
+// handler 创建记录模型和工具调用的回调处理器
+func (tw *traceWriter) handler() callbacks.Handler {
+	return ucb.NewHandlerHelper().
+		ChatModel(&ucb.ModelCallbackHandler{
+			OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *model.CallbackInput) context.Context {
+				return startSpan(ctx, traceLLMInputOf(input), input)
+			},
+			OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+				tw.endLLM(ctx, info, output.Message, output.TokenUsage, nil)
+				return ctx
+			},
+			OnEndWithStreamOutput: func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+				// 流必须读完并关闭，在后台拼接完整回复后再记录
+				tw.pending.Add(1)
+				go func() {
+					defer tw.pending.Done()
+					msg, usage, err := concatModelStream(output)
+					tw.endLLM(ctx, info, msg, usage, err)
+				}()
+				return ctx
+			},
+			OnError: func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+				tw.endLLM(ctx, info, nil, nil, err)
+				return ctx
+			},
+		}).
+		Tool(&ucb.ToolCallbackHandler{
+			OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *tool.CallbackInput) context.Context {
+				return startSpan(ctx, rawJSONOrString(input.ArgumentsInJSON), nil)
+			},
+			OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *tool.CallbackOutput) context.Context {
+				tw.endTool(ctx, info, output.Response, nil)
+				return ctx
+			},
+			OnError: func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+				tw.endTool(ctx, info, "", err)
+				return ctx
+			},
+		}).
+		Handler()
+}
+
+// startSpan 在 context 中记录调用开始的时间和输入
+func startSpan(ctx context.Context, input any, modelInput *model.CallbackInput) context.Context {
+	return context.WithValue(ctx, traceSpanKey{}, &traceSpan{start: time.Now(), input: input, model: modelInput})
+}
+
+// newRecord 根据 context 中的开始信息创建记录
+func newRecord(ctx context.Context, kind string, info *callbacks.RunInfo, err error) (traceRecord, *traceSpan) {
+	rec := traceRecord{Time: time.Now(), Kind: kind, Name: runName(info)}
+	span, _ := ctx.Value(traceSpanKey{}).(*traceSpan)
+	if span != nil {
+		rec.Time = span.start
+		rec.LatencyMS = time.Since(span.start).Milliseconds()
+		rec.Input = span.input
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	return rec, span
+}
+
+// endLLM 记录一次模型调用
+func (tw *traceWriter) endLLM(ctx context.Context, info *callbacks.RunInfo, msg *schema.Message, usage *model.TokenUsage, err error) {
+	rec, span := newRecord(ctx, traceKindLLM, info, err)
+	if msg != nil {
+		rec.Output = msg
+	}
+	u, ok := reportedUsage(msg, usage)
+	if !ok && span != nil && span.model != nil {
+		u, ok = estimateUsage(span.model.Messages, msg), true
+	}
+	if ok {
+		rec.Tokens = &traceTokens{Prompt: u.PromptTokens, Completion: u.CompletionTokens, Total: u.Total(), Estimated: u.Estimated}
+	}
+	tw.write(rec)
+}
+
+// endTool 记录一次工具调用
+func (tw *traceWriter) endTool(ctx context.Context, info *callbacks.RunInfo, response string, err error) {
+	rec, _ := newRecord(ctx, traceKindTool, info, err)
+	if err == nil {
+		rec.Output = response
+	}
+	tw.write(rec)
+}
+
+// concatModelStream 读完模型的流式输出，返回拼接后的消息和最后报告的 token 用量。
+// 回调收到的分片与 Agent 共享，Agent 会并发写入其 Extra，因此只拼接其余字段的副本。
+func concatModelStream(stream *schema.StreamReader[*model.CallbackOutput]) (*schema.Message, *model.TokenUsage, error) {
+	defer stream.Close()
+	var chunks []*schema.Message
+	var usage *model.TokenUsage
+	for {
+		out, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, usage, err
+		}
+		if out == nil {
+			continue
+		}
+		if m := out.Message; m != nil {
+			chunks = append(chunks, &schema.Message{
+				Role:             m.Role,
+				Content:          m.Content,
+				Name:             m.Name,
+				ToolCalls:        m.ToolCalls,
+				ToolCallID:       m.ToolCallID,
+				ToolName:         m.ToolName,
+				ResponseMeta:     m.ResponseMeta,
+				ReasoningContent: m.ReasoningContent,
+			})
+		}
+		if out.TokenUsage != nil {
+			usage = out.TokenUsage
+		}
+	}
+	if len(chunks) == 0 {
+		return nil, usage, nil
+	}
+	msg, err := schema.ConcatMessages(chunks)
+	return msg, usage, err
+}
+
+// traceLLMInputOf 提取模型调用的消息和工具名
+func traceLLMInputOf(input *model.CallbackInput) traceLLMInput {
+	in := traceLLMInput{Messages: input.Messages}
+	for _, t := range input.Tools {
+		in.Tools = append(in.Tools, t.Name)
+	}
+	return in
+}
+
+// rawJSONOrString 合法的 JSON 参数原样嵌入记录，否则作为字符串记录
+func rawJSONOrString(s string) any {
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	return s
+}
+
+// runName 返回组件名，未命名时使用组件类型
+func runName(info *callbacks.RunInfo) string {
+	if info == nil {
+		return ""
+	}
+	if info.Name != "" {
+		return info.Name
+	}
+	return info.Type
+}
+
+// initTrace 设置了 TRACE_FILE 时注册全局回调，将模型和工具调用追加到该 JSONL 文件。
+// 返回的 Closer 在运行时关闭时写完剩余记录并关闭文件，未启用时为 nil。
+func initTrace() (io.Closer, error) {
+	path := os.Getenv("TRACE_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开追踪文件失败: %w", err)
+	}
+	tw := newTraceWriter(f)
+	callbacks.AppendGlobalHandlers(tw.handler())
+	slog.Info("调用追踪已启用", "path", path)
+	return tw, nil
+}
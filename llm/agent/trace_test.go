@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// TestTraceWriter 验证模型和工具调用（含流式输出和错误）各写入一行 JSONL 记录
+func TestTraceWriter(t *testing.T) {
+	var buf bytes.Buffer
+	tw := newTraceWriter(&buf)
+	h := tw.handler()
+	ctx := context.Background()
+
+	toolInfo := &callbacks.RunInfo{Name: "echo", Component: components.ComponentOfTool}
+	toolCtx := h.OnStart(ctx, toolInfo, &tool.CallbackInput{ArgumentsInJSON: `{"text":"hi"}`})
+	h.OnEnd(toolCtx, toolInfo, &tool.CallbackOutput{Response: "echoed"})
+
+	// 流式模型回复，未返回用量时估算 token
+	modelInfo := &callbacks.RunInfo{Type: "Fake", Component: components.ComponentOfChatModel}
+	modelCtx := h.OnStart(ctx, modelInfo, &model.CallbackInput{
+		Messages: []*schema.Message{schema.UserMessage("question")},
+		Tools:    []*schema.ToolInfo{{Name: "echo"}},
+	})
+	reader, writer := schema.Pipe[callbacks.CallbackOutput](2)
+	writer.Send(&model.CallbackOutput{Message: schema.AssistantMessage("ans", nil)}, nil)
+	writer.Send(&model.CallbackOutput{Message: schema.AssistantMessage("wer", nil)}, nil)
+	writer.Close()
+	h.OnEndWithStreamOutput(modelCtx, modelInfo, reader)
+
+	failCtx := h.OnStart(ctx, modelInfo, &model.CallbackInput{})
+	h.OnError(failCtx, modelInfo, errors.New("rate limited"))
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var rec map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("记录不是合法 JSON: %s", scanner.Text())
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 3 {
+		t.Fatalf("期望 3 条记录, 实际 %d: %s", len(records), buf.String())
+	}
+
+	// 流式记录在后台写入，按类型查找
+	var toolRec, streamRec, errRec map[string]any
+	for _, rec := range records {
+		switch {
+		case rec["kind"] == traceKindTool:
+			toolRec = rec
+		case rec["error"] != nil:
+			errRec = rec
+		default:
+			streamRec = rec
+		}
+	}
+	if toolRec["name"] != "echo" || toolRec["output"] != "echoed" || toolRec["input"].(map[string]any)["text"] != "hi" {
+		t.Errorf("工具记录不正确: %v", toolRec)
+	}
+	if streamRec["name"] != "Fake" || streamRec["output"].(map[string]any)["content"] != "answer" {
+		t.Errorf("流式回复应拼接为完整消息: %v", streamRec)
+	}
+	if tokens := streamRec["tokens"].(map[string]any); tokens["estimated"] != true || tokens["total"].(float64) <= 0 {
+		t.Errorf("未返回用量时应估算 token: %v", tokens)
+	}
+	if tools := streamRec["input"].(map[string]any)["tools"].([]any); len(tools) != 1 || tools[0] != "echo" {
+		t.Errorf("输入应记录工具名: %v", tools)
+	}
+	if errRec["error"] != "rate limited" {
+		t.Errorf("错误记录不正确: %v", errRec)
+	}
+}
+
+// TestTraceWriterTokenUsage 验证优先使用模型返回的 token 用量
+func TestTraceWriterTokenUsage(t *testing.T) {
+	var buf bytes.Buffer
+	tw := newTraceWriter(&buf)
+	h := tw.handler()
+	info := &callbacks.RunInfo{Name: "chat", Component: components.ComponentOfChatModel}
+
+	ctx := h.OnStart(context.Background(), info, &model.CallbackInput{Messages: []*schema.Message{schema.UserMessage("q")}})
+	h.OnEnd(ctx, info, &model.CallbackOutput{
+		Message:    schema.AssistantMessage("a", nil),
+		TokenUsage: &model.TokenUsage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10},
+	})
+	tw.Close()
+
+	var rec traceRecord
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Tokens == nil || *rec.Tokens != (traceTokens{Prompt: 7, Completion: 3, Total: 10}) {
+		t.Errorf("token 用量不正确: %+v", rec.Tokens)
+	}
+}
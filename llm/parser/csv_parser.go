@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// CSVParser handles comma-separated data files
+type CSVParser struct{}
+
+// NewCSVParser creates a new CSV parser
+func NewCSVParser() *CSVParser {
+	return &CSVParser{}
+}
+
+// Parse reads and parses CSV from the reader
+func (p *CSVParser) Parse(ctx context.Context, r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	return p.parse(string(data), "")
+}
+
+// ParseFile reads and parses a CSV file
+func (p *CSVParser) ParseFile(ctx context.Context, filePath string) (*Document, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return p.parse(string(data), filePath)
+}
+
+// parse renders each data row as one "column: value | column: value" line
+// using the header row for column names. Empty cells are left out.
+func (p *CSVParser) parse(data, filePath string) (*Document, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv: %w", err)
+	}
+
+	var header []string
+	var rows []string
+	for _, record := range records {
+		if isBlankRecord(record) {
+			continue
+		}
+		if header == nil {
+			header = record
+			continue
+		}
+
+		var cells []string
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			cells = append(cells, csvColumnName(header, i)+": "+value)
+		}
+		rows = append(rows, strings.Join(cells, " | "))
+	}
+
+	title := "Untitled"
+	if filePath != "" {
+		title = extractFileName(filePath)
+	}
+
+	return &Document{
+		Content: strings.Join(rows, "\n\n"),
+		Title:   title,
+		Metadata: map[string]interface{}{
+			"file_size":    len(data),
+			"row_count":    len(rows),
+			"column_count": len(header),
+		},
+	}, nil
+}
+
+// csvColumnName returns the header name of column i, or "column N" when the
+// header is blank or shorter than the row
+func csvColumnName(header []string, i int) string {
+	if i < len(header) {
+		if name := strings.TrimSpace(header[i]); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("column %d", i+1)
+}
+
+// isBlankRecord reports whether every field of a record is empty
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// FileType returns the file type this parser handles
+func (p *CSVParser) FileType() FileType {
+	return FileTypeCSV
+}
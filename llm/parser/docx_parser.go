@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// wordNamespace is the WordprocessingML namespace of document.xml elements
+	wordNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+	// dcNamespace is the Dublin Core namespace of the title in core properties
+	dcNamespace = "http://purl.org/dc/elements/1.1/"
+
+	// maxDocxPartSize bounds the decompressed size of a part read from the archive
+	maxDocxPartSize = 64 * 1024 * 1024
+)
+
+// DocxParser handles Word .docx files
+type DocxParser struct{}
+
+// NewDocxParser creates a new docx parser
+func NewDocxParser() *DocxParser {
+	return &DocxParser{}
+}
+
+// Parse reads and parses a docx archive from the reader
+func (p *DocxParser) Parse(ctx context.Context, r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docx: %w", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docx: %w", err)
+	}
+	return p.parse(zr, "", len(data))
+}
+
+// ParseFile reads and parses a docx file
+func (p *DocxParser) ParseFile(ctx context.Context, filePath string) (*Document, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docx: %w", err)
+	}
+	defer zr.Close()
+
+	size := 0
+	if info, err := os.Stat(filePath); err == nil {
+		size = int(info.Size())
+	}
+	return p.parse(&zr.Reader, filePath, size)
+}
+
+// parse extracts paragraphs from word/document.xml and the title from
+// docProps/core.xml when present
+func (p *DocxParser) parse(zr *zip.Reader, filePath string, size int) (*Document, error) {
+	body, err := readZipPart(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	paragraphs, err := docxParagraphs(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse word/document.xml: %w", err)
+	}
+	content := strings.Join(paragraphs, "\n\n")
+
+	title := ""
+	if core, err := readZipPart(zr, "docProps/core.xml"); err == nil {
+		title = docxTitle(core)
+	}
+	if title == "" {
+		title = ExtractTitle(content, filePath)
+	}
+
+	return &Document{
+		Content: content,
+		Title:   title,
+		Metadata: map[string]interface{}{
+			"file_size":       size,
+			"paragraph_count": len(paragraphs),
+		},
+	}, nil
+}
+
+// readZipPart returns the decompressed content of the named archive entry
+func readZipPart(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(io.LimitReader(rc, maxDocxPartSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if len(data) > maxDocxPartSize {
+			return nil, fmt.Errorf("%s exceeds %d bytes", name, maxDocxPartSize)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("not a docx file: missing %s", name)
+}
+
+// docxParagraphs returns the text of each non-empty w:p paragraph. Runs are
+// concatenated; w:tab becomes a tab and w:br / w:cr a line break.
+func docxParagraphs(data []byte) ([]string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var paragraphs []string
+	var current strings.Builder
+	inText := false
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				current.Reset()
+			case "t":
+				inText = true
+			case "tab":
+				current.WriteString("\t")
+			case "br", "cr":
+				current.WriteString("\n")
+			}
+		case xml.EndElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if text := strings.TrimSpace(current.String()); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+				current.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+	return paragraphs, nil
+}
+
+// docxTitle returns dc:title from the core properties part, or "" if unset
+func docxTitle(data []byte) string {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Space == dcNamespace && start.Name.Local == "title" {
+			var title string
+			if err := dec.DecodeElement(&title, &start); err != nil {
+				return ""
+			}
+			return strings.TrimSpace(title)
+		}
+	}
+}
+
+// FileType returns the file type this parser handles
+func (p *DocxParser) FileType() FileType {
+	return FileTypeDOCX
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// JSONParser handles JSON data files
+type JSONParser struct{}
+
+// NewJSONParser creates a new JSON parser
+func NewJSONParser() *JSONParser {
+	return &JSONParser{}
+}
+
+// Parse reads and parses JSON from the reader
+func (p *JSONParser) Parse(ctx context.Context, r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read json: %w", err)
+	}
+	return p.parse(data, "")
+}
+
+// ParseFile reads and parses a JSON file
+func (p *JSONParser) ParseFile(ctx context.Context, filePath string) (*Document, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return p.parse(data, filePath)
+}
+
+// parse flattens the document into "dot.path: value" lines. Each element of a
+// top-level array, or each top-level key of an object, becomes one paragraph.
+func (p *JSONParser) parse(data []byte, filePath string) (*Document, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var root interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+
+	var paragraphs []string
+	elements := 1
+	switch v := root.(type) {
+	case []interface{}:
+		elements = len(v)
+		for _, elem := range v {
+			paragraphs = append(paragraphs, strings.Join(flattenJSON("", elem, nil), "\n"))
+		}
+	case map[string]interface{}:
+		elements = len(v)
+		for _, key := range sortedKeys(v) {
+			paragraphs = append(paragraphs, strings.Join(flattenJSON(key, v[key], nil), "\n"))
+		}
+	default:
+		paragraphs = flattenJSON("", v, nil)
+	}
+
+	title := "Untitled"
+	if obj, ok := root.(map[string]interface{}); ok {
+		if t, ok := obj["title"].(string); ok && strings.TrimSpace(t) != "" {
+			title = strings.TrimSpace(t)
+		}
+	}
+	if title == "Untitled" && filePath != "" {
+		title = extractFileName(filePath)
+	}
+
+	return &Document{
+		Content: strings.Join(paragraphs, "\n\n"),
+		Title:   title,
+		Metadata: map[string]interface{}{
+			"file_size":     len(data),
+			"element_count": elements,
+		},
+	}, nil
+}
+
+// flattenJSON appends one "path: value" line per leaf of v. Object keys join
+// with dots and array elements use [i]; keys are sorted for stable output.
+func flattenJSON(path string, v interface{}, lines []string) []string {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			return append(lines, jsonLine(path, "{}"))
+		}
+		for _, key := range sortedKeys(val) {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			lines = flattenJSON(child, val[key], lines)
+		}
+		return lines
+	case []interface{}:
+		if len(val) == 0 {
+			return append(lines, jsonLine(path, "[]"))
+		}
+		for i, elem := range val {
+			lines = flattenJSON(fmt.Sprintf("%s[%d]", path, i), elem, lines)
+		}
+		return lines
+	case nil:
+		return append(lines, jsonLine(path, "null"))
+	default:
+		return append(lines, jsonLine(path, fmt.Sprint(val)))
+	}
+}
+
+// jsonLine formats a leaf, omitting the path for top-level scalars
+func jsonLine(path, value string) string {
+	if path == "" {
+		return value
+	}
+	return path + ": " + value
+}
+
+// sortedKeys returns the keys of an object in lexical order
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FileType returns the file type this parser handles
+func (p *JSONParser) FileType() FileType {
+	return FileTypeJSON
+}
@@ -7,12 +7,16 @@ import (
 	"os"
 	"regexp"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // MarkdownParser handles markdown files
 type MarkdownParser struct {
 	// stripCodeBlocks whether to remove code blocks from content
 	stripCodeBlocks bool
+	// keepHeadings whether to keep header markers, which heading-aware chunking splits on
+	keepHeadings bool
 }
 
 // NewMarkdownParser creates a new markdown parser
@@ -22,6 +26,12 @@ func NewMarkdownParser() *MarkdownParser {
 	}
 }
 
+// NewHeadingMarkdownParser creates a markdown parser that keeps header
+// markers, for documents chunked by heading
+func NewHeadingMarkdownParser() *MarkdownParser {
+	return &MarkdownParser{keepHeadings: true}
+}
+
 // Parse reads and parses markdown from the reader
 func (p *MarkdownParser) Parse(ctx context.Context, r io.Reader) (*Document, error) {
 	data, err := io.ReadAll(r)
@@ -74,38 +84,34 @@ func (p *MarkdownParser) parse(content, filePath string) *Document {
 	}
 }
 
-// extractFrontmatter extracts YAML frontmatter from content
+// extractFrontmatter unmarshals the YAML frontmatter block into a map,
+// keeping lists and nested maps. Malformed YAML yields an empty map so the
+// document still parses.
 func (p *MarkdownParser) extractFrontmatter(content string) map[string]interface{} {
 	metadata := make(map[string]interface{})
 
-	if !hasFrontmatter(content) {
+	block, ok := frontmatterBlock(content)
+	if !ok {
 		return metadata
 	}
-
-	// Find the closing ---
-	lines := strings.Split(content, "\n")
-	if len(lines) < 2 {
-		return metadata
+	if err := yaml.Unmarshal([]byte(block), &metadata); err != nil || metadata == nil {
+		return make(map[string]interface{})
 	}
+	return metadata
+}
 
-	// Skip first line (opening ---)
+// frontmatterBlock returns the text between the opening and closing --- fences
+func frontmatterBlock(content string) (string, bool) {
+	if !hasFrontmatter(content) {
+		return "", false
+	}
+	lines := strings.Split(content, "\n")
 	for i := 1; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "---" {
-			break
-		}
-
-		// Parse simple key: value pairs
-		if idx := strings.Index(line, ":"); idx > 0 {
-			key := strings.TrimSpace(line[:idx])
-			value := strings.TrimSpace(line[idx+1:])
-			// Remove quotes if present
-			value = strings.Trim(value, `"`)
-			metadata[key] = value
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], "\n"), true
 		}
 	}
-
-	return metadata
+	return "", false
 }
 
 // removeFrontmatter removes YAML frontmatter from content
@@ -145,8 +151,10 @@ func (p *MarkdownParser) removeCodeBlocks(content string) string {
 // cleanMarkdown cleans up markdown formatting for better embedding
 func (p *MarkdownParser) cleanMarkdown(content string) string {
 	// Remove markdown headers but keep the text
-	re := regexp.MustCompile(`^#+\s+(.*)$`)
-	content = re.ReplaceAllString(content, "$1")
+	if !p.keepHeadings {
+		re := regexp.MustCompile(`^#+\s+(.*)$`)
+		content = re.ReplaceAllString(content, "$1")
+	}
 
 	// Remove bold/italic markers
 	content = strings.ReplaceAll(content, "**", "")
@@ -155,7 +163,7 @@ func (p *MarkdownParser) cleanMarkdown(content string) string {
 	content = strings.ReplaceAll(content, "_", "")
 
 	// Remove links but keep the text
-	re = regexp.MustCompile(`\[([^\]]+)\]\([^\)]+\)`)
+	re := regexp.MustCompile(`\[([^\]]+)\]\([^\)]+\)`)
 	content = re.ReplaceAllString(content, "$1")
 
 	// Remove image references
@@ -15,6 +15,9 @@ type FileType string
 const (
 	FileTypeMD      FileType = "md"
 	FileTypeTXT     FileType = "txt"
+	FileTypeDOCX    FileType = "docx"
+	FileTypeCSV     FileType = "csv"
+	FileTypeJSON    FileType = "json"
 	FileTypeUnknown FileType = "unknown"
 )
 
@@ -84,11 +87,23 @@ func FileTypeFromExt(ext string) FileType {
 		return FileTypeMD
 	case "txt":
 		return FileTypeTXT
+	case "docx":
+		return FileTypeDOCX
+	case "csv":
+		return FileTypeCSV
+	case "json":
+		return FileTypeJSON
 	default:
 		return FileTypeUnknown
 	}
 }
 
+// IsStructured reports whether the file type holds records rather than prose.
+// Their repeated "field: value" lines are data, not boilerplate.
+func (ft FileType) IsStructured() bool {
+	return ft == FileTypeCSV || ft == FileTypeJSON
+}
+
 // String returns the string representation of the FileType
 func (ft FileType) String() string {
 	return string(ft)
@@ -99,6 +114,9 @@ func DefaultRegistry() *Registry {
 	reg := NewRegistry()
 	reg.Register(NewTxtParser())
 	reg.Register(NewMarkdownParser())
+	reg.Register(NewDocxParser())
+	reg.Register(NewCSVParser())
+	reg.Register(NewJSONParser())
 	return reg
 }
 
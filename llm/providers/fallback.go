@@ -0,0 +1,240 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// FallbackConfig defines the retry behaviour of a FallbackChatModel.
+type FallbackConfig struct {
+	// MaxRetries is the number of extra attempts per model on retryable errors.
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry; it doubles on every further retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultFallbackConfig returns the retry settings used when LLM_MAX_RETRIES and
+// LLM_RETRY_BACKOFF are unset.
+func DefaultFallbackConfig() *FallbackConfig {
+	return &FallbackConfig{
+		MaxRetries:     2,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// FallbackConfigFromEnv reads the retry settings from environment variables:
+//   - LLM_MAX_RETRIES: retries per model on retryable errors (default: 2)
+//   - LLM_RETRY_BACKOFF: initial backoff, Go duration (default: 1s)
+func FallbackConfigFromEnv() *FallbackConfig {
+	config := DefaultFallbackConfig()
+	if val := os.Getenv("LLM_MAX_RETRIES"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			config.MaxRetries = n
+		}
+	}
+	if val := os.Getenv("LLM_RETRY_BACKOFF"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			config.InitialBackoff = d
+		}
+	}
+	return config
+}
+
+// FallbackChatModel wraps an ordered list of chat models. Each call is retried with
+// exponential backoff while a model returns retryable errors (rate limits, 5xx,
+// network failures), then falls through to the next model in the list.
+type FallbackChatModel struct {
+	models []model.ToolCallingChatModel
+	config FallbackConfig
+}
+
+// NewFallbackChatModel creates a FallbackChatModel trying models in order.
+// A nil config uses DefaultFallbackConfig.
+func NewFallbackChatModel(models []model.ToolCallingChatModel, config *FallbackConfig) (*FallbackChatModel, error) {
+	if len(models) == 0 {
+		return nil, fmt.Errorf("at least one chat model is required")
+	}
+	if config == nil {
+		config = DefaultFallbackConfig()
+	}
+	cfg := *config
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultFallbackConfig().MaxBackoff
+	}
+	return &FallbackChatModel{
+		models: models,
+		config: cfg,
+	}, nil
+}
+
+// Generate returns the first successful response of the wrapped models.
+func (f *FallbackChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return callWithFallback(ctx, f, func(m model.ToolCallingChatModel) (*schema.Message, error) {
+		return m.Generate(ctx, input, opts...)
+	})
+}
+
+// Stream returns the stream of the first model whose stream starts without error.
+// The first chunk is read before committing to a model, since most providers report
+// rate limits and server errors there; errors after the first chunk are passed through.
+func (f *FallbackChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return callWithFallback(ctx, f, func(m model.ToolCallingChatModel) (*schema.StreamReader[*schema.Message], error) {
+		sr, err := m.Stream(ctx, input, opts...)
+		if err != nil {
+			return nil, err
+		}
+		first, err := sr.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			sr.Close()
+			return nil, err
+		}
+		if errors.Is(err, io.EOF) {
+			sr.Close()
+			return schema.StreamReaderFromArray([]*schema.Message{}), nil
+		}
+		return prependChunk(first, sr), nil
+	})
+}
+
+// WithTools binds tools to every wrapped model and returns a new FallbackChatModel.
+func (f *FallbackChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	models := make([]model.ToolCallingChatModel, len(f.models))
+	for i, m := range f.models {
+		withTools, err := m.WithTools(tools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind tools to model %d: %w", i, err)
+		}
+		models[i] = withTools
+	}
+	return &FallbackChatModel{models: models, config: f.config}, nil
+}
+
+// GetType implements components.Typer.
+func (f *FallbackChatModel) GetType() string {
+	return "Fallback"
+}
+
+// IsCallbacksEnabled reports that callbacks are handled by the wrapped models,
+// so the framework does not report each call twice.
+func (f *FallbackChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+// callWithFallback runs call against each model in order, retrying retryable errors
+// with backoff. Non-retryable errors move on to the next model immediately.
+func callWithFallback[T any](ctx context.Context, f *FallbackChatModel, call func(model.ToolCallingChatModel) (T, error)) (T, error) {
+	var zero T
+	var lastErr error
+	for i, m := range f.models {
+		backoff := f.config.InitialBackoff
+		for attempt := 0; attempt <= f.config.MaxRetries; attempt++ {
+			if attempt > 0 {
+				if err := sleepContext(ctx, backoff); err != nil {
+					return zero, err
+				}
+				backoff = min(backoff*2, f.config.MaxBackoff)
+			}
+
+			result, err := call(m)
+			if err == nil {
+				return result, nil
+			}
+			if ctx.Err() != nil {
+				return zero, err
+			}
+			lastErr = err
+			if !IsRetryableError(err) {
+				break
+			}
+			slog.Warn("model attempt failed", "model", i, "attempt", attempt+1, "err", err)
+		}
+		if i < len(f.models)-1 {
+			slog.Warn("model failed, falling back", "model", i, "fallback", i+1, "err", lastErr)
+		}
+	}
+	return zero, fmt.Errorf("all %d models failed: %w", len(f.models), lastErr)
+}
+
+// retryableStatus matches a 429 or 5xx status code reported as such in an error
+// message ("status code: 429", "HTTP 503", "error code: 529"), so that other
+// numbers like token counts do not count as status codes.
+var retryableStatus = regexp.MustCompile(`\b(?:status(?: code)?|http(?:/[\d.]+)?|error code)[\s:=]*(?:429|5\d\d)\b`)
+
+// retryableMarkers are error message fragments of transient failures.
+var retryableMarkers = []string{
+	"rate limit", "too many requests", "overloaded",
+	"internal server error", "bad gateway", "service unavailable", "gateway timeout",
+	"timeout", "connection reset", "connection refused", "unexpected eof",
+}
+
+// IsRetryableError reports whether err looks like a transient provider failure
+// worth retrying: rate limiting, server errors or network failures.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	if retryableStatus.MatchString(msg) {
+		return true
+	}
+	for _, marker := range retryableMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// prependChunk returns a stream yielding first followed by the rest of sr.
+func prependChunk(first *schema.Message, sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	out, w := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer w.Close()
+		defer sr.Close()
+		if w.Send(first, nil) {
+			return
+		}
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if w.Send(chunk, err) || err != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,218 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// scriptedChatModel returns the queued errors in order, then replies with its name.
+// A non-nil streamErr is returned from the first stream chunk instead of from Stream.
+type scriptedChatModel struct {
+	name      string
+	errs      []error
+	streamErr bool
+	calls     int
+	tools     []*schema.ToolInfo
+}
+
+func (m *scriptedChatModel) next() error {
+	m.calls++
+	if len(m.errs) == 0 {
+		return nil
+	}
+	err := m.errs[0]
+	m.errs = m.errs[1:]
+	return err
+}
+
+func (m *scriptedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if err := m.next(); err != nil {
+		return nil, err
+	}
+	return schema.AssistantMessage(m.name, nil), nil
+}
+
+func (m *scriptedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	err := m.next()
+	if err != nil && !m.streamErr {
+		return nil, err
+	}
+	sr, w := schema.Pipe[*schema.Message](2)
+	go func() {
+		defer w.Close()
+		if err != nil {
+			w.Send(nil, err)
+			return
+		}
+		w.Send(schema.AssistantMessage(m.name, nil), nil)
+		w.Send(schema.AssistantMessage("!", nil), nil)
+	}()
+	return sr, nil
+}
+
+func (m *scriptedChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	clone := *m
+	clone.tools = tools
+	return &clone, nil
+}
+
+func newTestFallback(t *testing.T, models ...model.ToolCallingChatModel) *FallbackChatModel {
+	t.Helper()
+	f, err := NewFallbackChatModel(models, &FallbackConfig{MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("NewFallbackChatModel: %v", err)
+	}
+	return f
+}
+
+func TestFallbackRetriesRetryableErrors(t *testing.T) {
+	primary := &scriptedChatModel{name: "primary", errs: []error{
+		errors.New("status code: 429, rate limited"),
+		errors.New("503 Service Unavailable"),
+	}}
+	secondary := &scriptedChatModel{name: "secondary"}
+	f := newTestFallback(t, primary, secondary)
+
+	msg, err := f.Generate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if msg.Content != "primary" || primary.calls != 3 || secondary.calls != 0 {
+		t.Errorf("got %q after %d primary / %d secondary calls, want primary after 3/0",
+			msg.Content, primary.calls, secondary.calls)
+	}
+}
+
+func TestFallbackFallsThroughWhenRetriesExhausted(t *testing.T) {
+	rateLimited := errors.New("429 Too Many Requests")
+	primary := &scriptedChatModel{name: "primary", errs: []error{rateLimited, rateLimited, rateLimited}}
+	secondary := &scriptedChatModel{name: "secondary"}
+	f := newTestFallback(t, primary, secondary)
+
+	msg, err := f.Generate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if msg.Content != "secondary" || primary.calls != 3 {
+		t.Errorf("got %q after %d primary calls, want secondary after 3", msg.Content, primary.calls)
+	}
+}
+
+func TestFallbackSkipsRetriesOnPermanentErrors(t *testing.T) {
+	primary := &scriptedChatModel{name: "primary", errs: []error{errors.New("401 invalid api key")}}
+	secondary := &scriptedChatModel{name: "secondary"}
+	f := newTestFallback(t, primary, secondary)
+
+	msg, err := f.Generate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if msg.Content != "secondary" || primary.calls != 1 {
+		t.Errorf("got %q after %d primary calls, want secondary after 1", msg.Content, primary.calls)
+	}
+}
+
+func TestFallbackReturnsLastErrorWhenAllFail(t *testing.T) {
+	overloaded := errors.New("529 overloaded")
+	f := newTestFallback(t,
+		&scriptedChatModel{name: "a", errs: []error{overloaded, overloaded, overloaded}},
+		&scriptedChatModel{name: "b", errs: []error{errors.New("bad request")}},
+	)
+
+	_, err := f.Generate(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "all 2 models failed: bad request") {
+		t.Errorf("err = %v, want all 2 models failed: bad request", err)
+	}
+}
+
+func TestFallbackStreamRetriesFirstChunkError(t *testing.T) {
+	primary := &scriptedChatModel{name: "primary", streamErr: true, errs: []error{errors.New("500 internal server error")}}
+	f := newTestFallback(t, primary)
+
+	sr, err := f.Stream(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	defer sr.Close()
+
+	var content strings.Builder
+	for {
+		chunk, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		content.WriteString(chunk.Content)
+	}
+	if content.String() != "primary!" || primary.calls != 2 {
+		t.Errorf("streamed %q after %d calls, want primary! after 2", content.String(), primary.calls)
+	}
+}
+
+func TestFallbackWithToolsBindsEveryModel(t *testing.T) {
+	f := newTestFallback(t, &scriptedChatModel{name: "a"}, &scriptedChatModel{name: "b"})
+	tools := []*schema.ToolInfo{{Name: "echo"}}
+
+	bound, err := f.WithTools(tools)
+	if err != nil {
+		t.Fatalf("WithTools: %v", err)
+	}
+	for i, m := range bound.(*FallbackChatModel).models {
+		if got := m.(*scriptedChatModel).tools; len(got) != 1 || got[0].Name != "echo" {
+			t.Errorf("model %d tools = %v, want [echo]", i, got)
+		}
+	}
+	for i, m := range f.models {
+		if m.(*scriptedChatModel).tools != nil {
+			t.Errorf("original model %d was modified", i)
+		}
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	cases := map[string]bool{
+		"status code: 429":                     true,
+		"HTTP/1.1 503":                         true,
+		"error code: 529":                      true,
+		"POST /v1/messages: 502 Bad Gateway":   true,
+		"read tcp: connection reset by peer":   true,
+		"400 invalid request: max_tokens 4500": false,
+		"400 max_tokens 500 exceeds the limit": false,
+		"prompt has 429 tokens too many":       false,
+		"401 unauthorized":                     false,
+	}
+	for msg, want := range cases {
+		if got := IsRetryableError(errors.New(msg)); got != want {
+			t.Errorf("IsRetryableError(%q) = %v, want %v", msg, got, want)
+		}
+	}
+	if IsRetryableError(context.Canceled) {
+		t.Error("context.Canceled should not be retryable")
+	}
+}
+
+func TestCreateChatModelBuildsFallbackFromModelList(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "openai")
+	t.Setenv("API_KEY", "test-key")
+	t.Setenv("MODEL", "glm-4-plus, glm-4-flash")
+
+	m, err := CreateChatModel(context.Background())
+	if err != nil {
+		t.Fatalf("CreateChatModel: %v", err)
+	}
+	f, ok := m.(*FallbackChatModel)
+	if !ok {
+		t.Fatalf("CreateChatModel returned %T, want *FallbackChatModel", m)
+	}
+	if len(f.models) != 2 {
+		t.Errorf("fallback has %d models, want 2", len(f.models))
+	}
+}
@@ -1,11 +1,20 @@
 package providers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	openaiEmbed "github.com/cloudwego/eino-ext/components/embedding/openai"
+	"github.com/cloudwego/eino-ext/components/model/claude"
+	"github.com/cloudwego/eino-ext/components/model/ollama"
 	openaiModel "github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino-ext/components/model/qwen"
 	einoEmbedding "github.com/cloudwego/eino/components/embedding"
@@ -17,6 +26,68 @@ type ChatModelConfig struct {
 	APIKey  string
 	BaseURL string
 	Model   string
+	SamplingConfig
+}
+
+// SamplingConfig holds optional generation settings. Nil fields keep the
+// provider's defaults.
+type SamplingConfig struct {
+	Temperature *float32
+	MaxTokens   *int
+	TopP        *float32
+}
+
+// defaultSummaryTemperature keeps summaries close to the source text.
+const defaultSummaryTemperature = 0.2
+
+// ChatSamplingFromEnv reads the main chat model's generation settings from
+// MODEL_TEMPERATURE, MODEL_MAX_TOKENS and MODEL_TOP_P.
+func ChatSamplingFromEnv() (SamplingConfig, error) {
+	return samplingFromEnv("MODEL_")
+}
+
+// SummarySamplingFromEnv reads the summary model's generation settings from
+// SUMMARY_MODEL_TEMPERATURE (default: 0.2), SUMMARY_MODEL_MAX_TOKENS and
+// SUMMARY_MODEL_TOP_P.
+func SummarySamplingFromEnv() (SamplingConfig, error) {
+	sampling, err := samplingFromEnv("SUMMARY_MODEL_")
+	if err != nil {
+		return sampling, err
+	}
+	if sampling.Temperature == nil {
+		t := float32(defaultSummaryTemperature)
+		sampling.Temperature = &t
+	}
+	return sampling, nil
+}
+
+// samplingFromEnv reads <prefix>TEMPERATURE, <prefix>MAX_TOKENS and <prefix>TOP_P.
+func samplingFromEnv(prefix string) (SamplingConfig, error) {
+	var sampling SamplingConfig
+	if v := strings.TrimSpace(os.Getenv(prefix + "TEMPERATURE")); v != "" {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil || f < 0 || f > 2 {
+			return sampling, fmt.Errorf("invalid %sTEMPERATURE %q: must be a number between 0 and 2", prefix, v)
+		}
+		t := float32(f)
+		sampling.Temperature = &t
+	}
+	if v := strings.TrimSpace(os.Getenv(prefix + "MAX_TOKENS")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return sampling, fmt.Errorf("invalid %sMAX_TOKENS %q: must be a positive integer", prefix, v)
+		}
+		sampling.MaxTokens = &n
+	}
+	if v := strings.TrimSpace(os.Getenv(prefix + "TOP_P")); v != "" {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil || f <= 0 || f > 1 {
+			return sampling, fmt.Errorf("invalid %sTOP_P %q: must be a number in (0, 1]", prefix, v)
+		}
+		p := float32(f)
+		sampling.TopP = &p
+	}
+	return sampling, nil
 }
 
 // NewChatModel creates an OpenAI-compatible chat model from specific configuration.
@@ -36,42 +107,343 @@ func NewChatModel(ctx context.Context, config *ChatModelConfig) (model.ToolCalli
 	}
 
 	return openaiModel.NewChatModel(ctx, &openaiModel.ChatModelConfig{
-		APIKey:  config.APIKey,
-		BaseURL: baseURL,
-		Model:   modelName,
+		APIKey:      config.APIKey,
+		BaseURL:     baseURL,
+		Model:       modelName,
+		Temperature: config.Temperature,
+		MaxTokens:   config.MaxTokens,
+		TopP:        config.TopP,
 	})
 }
 
-// CreateChatModel creates an OpenAI-compatible chat model from environment variables.
+// LLM provider names accepted by LLM_PROVIDER.
+const (
+	ProviderOpenAI    = "openai"
+	ProviderAnthropic = "anthropic"
+	ProviderQwen      = "qwen"
+	ProviderOllama    = "ollama"
+)
+
+// CreateChatModel creates the main chat model from environment variables.
+// LLM_PROVIDER selects the provider (default: openai):
+//   - openai: OpenAI-compatible API, see CreateOpenAIChatModel
+//   - anthropic: Anthropic Claude API, see CreateAnthropicChatModel
+//   - qwen: DashScope Qwen API, see CreateQwenChatModel
+//   - ollama: local Ollama server, see CreateOllamaChatModel
+//
+// The model is always wrapped in a FallbackChatModel configured by
+// FallbackConfigFromEnv, so transient errors are retried. A comma-separated
+// model list (e.g. MODEL=glm-4-plus,glm-4-flash) tries the models in order.
+//
+// Every provider applies the generation settings read by ChatSamplingFromEnv.
+func CreateChatModel(ctx context.Context) (model.ToolCallingChatModel, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("LLM_PROVIDER")))
+	if provider == "" {
+		provider = ProviderOpenAI
+	}
+	modelEnv, err := chatModelEnv(provider)
+	if err != nil {
+		return nil, err
+	}
+	sampling, err := ChatSamplingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	names := splitModelList(os.Getenv(modelEnv))
+	if len(names) == 0 {
+		names = []string{""} // The provider's default model
+	}
+
+	models := make([]model.ToolCallingChatModel, 0, len(names))
+	for _, name := range names {
+		m, err := newProviderChatModel(ctx, provider, name, sampling)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create model %q: %w", name, err)
+		}
+		models = append(models, m)
+	}
+	return NewFallbackChatModel(models, FallbackConfigFromEnv())
+}
+
+// chatModelEnv returns the environment variable holding the model name of a provider.
+func chatModelEnv(provider string) (string, error) {
+	switch provider {
+	case ProviderOpenAI, ProviderQwen:
+		return "MODEL", nil
+	case ProviderAnthropic:
+		return "ANTHROPIC_MODEL", nil
+	case ProviderOllama:
+		return "OLLAMA_MODEL", nil
+	default:
+		return "", fmt.Errorf("unknown LLM_PROVIDER %q (expected %s, %s, %s or %s)",
+			provider, ProviderOpenAI, ProviderAnthropic, ProviderQwen, ProviderOllama)
+	}
+}
+
+// newProviderChatModel creates a chat model of the given provider and model name.
+func newProviderChatModel(ctx context.Context, provider, modelName string, sampling SamplingConfig) (model.ToolCallingChatModel, error) {
+	switch provider {
+	case ProviderAnthropic:
+		return newAnthropicChatModel(ctx, modelName, sampling)
+	case ProviderQwen:
+		return newQwenChatModel(ctx, modelName, sampling)
+	case ProviderOllama:
+		return newOllamaChatModel(ctx, modelName, sampling)
+	default:
+		return newOpenAIChatModel(ctx, modelName, sampling)
+	}
+}
+
+// splitModelList splits a comma-separated model list, dropping empty entries.
+func splitModelList(val string) []string {
+	var names []string
+	for _, name := range strings.Split(val, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CreateOpenAIChatModel creates an OpenAI-compatible chat model from environment variables.
 // Required environment variables:
 //   - API_KEY: API key for the LLM provider
 //
 // Optional environment variables:
 //   - BASE_URL: Base URL for OpenAI-compatible API (default: https://open.bigmodel.cn/api/paas/v4)
 //   - MODEL: Model name (default: glm-4-flash)
-func CreateChatModel(ctx context.Context) (model.ToolCallingChatModel, error) {
+func CreateOpenAIChatModel(ctx context.Context) (model.ToolCallingChatModel, error) {
+	sampling, err := ChatSamplingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return newOpenAIChatModel(ctx, os.Getenv("MODEL"), sampling)
+}
+
+func newOpenAIChatModel(ctx context.Context, modelName string, sampling SamplingConfig) (model.ToolCallingChatModel, error) {
 	apiKey := os.Getenv("API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("API_KEY environment variable is required")
 	}
 
 	return NewChatModel(ctx, &ChatModelConfig{
-		APIKey:  apiKey,
-		BaseURL: os.Getenv("BASE_URL"),
-		Model:   os.Getenv("MODEL"),
+		APIKey:         apiKey,
+		BaseURL:        os.Getenv("BASE_URL"),
+		Model:          modelName,
+		SamplingConfig: sampling,
+	})
+}
+
+// defaultAnthropicMaxTokens is the response token limit used when ANTHROPIC_MAX_TOKENS is unset.
+// The Anthropic API requires max_tokens on every request.
+const defaultAnthropicMaxTokens = 4096
+
+// CreateAnthropicChatModel creates an Anthropic Claude chat model from environment variables.
+// Required environment variables:
+//   - ANTHROPIC_API_KEY: Anthropic API key
+//
+// Optional environment variables:
+//   - ANTHROPIC_MODEL: Model name (default: claude-sonnet-4-5)
+//   - ANTHROPIC_BASE_URL: Base URL override for proxies or gateways
+//   - ANTHROPIC_MAX_TOKENS: Maximum tokens per response (default: MODEL_MAX_TOKENS or 4096)
+func CreateAnthropicChatModel(ctx context.Context) (model.ToolCallingChatModel, error) {
+	sampling, err := ChatSamplingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return newAnthropicChatModel(ctx, os.Getenv("ANTHROPIC_MODEL"), sampling)
+}
+
+func newAnthropicChatModel(ctx context.Context, modelName string, sampling SamplingConfig) (model.ToolCallingChatModel, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable is required")
+	}
+
+	if modelName == "" {
+		modelName = "claude-sonnet-4-5"
+	}
+
+	maxTokens := defaultAnthropicMaxTokens
+	if sampling.MaxTokens != nil {
+		maxTokens = *sampling.MaxTokens
+	}
+	if v := strings.TrimSpace(os.Getenv("ANTHROPIC_MAX_TOKENS")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid ANTHROPIC_MAX_TOKENS %q: must be a positive integer", v)
+		}
+		maxTokens = n
+	}
+
+	config := &claude.Config{
+		APIKey:      apiKey,
+		Model:       modelName,
+		MaxTokens:   maxTokens,
+		Temperature: sampling.Temperature,
+		TopP:        sampling.TopP,
+	}
+	if baseURL := os.Getenv("ANTHROPIC_BASE_URL"); baseURL != "" {
+		config.BaseURL = &baseURL
+	}
+
+	return claude.NewChatModel(ctx, config)
+}
+
+// CreateQwenChatModel creates a Qwen chat model from the API_KEY, BASE_URL and MODEL
+// environment variables.
+func CreateQwenChatModel(ctx context.Context) (model.ToolCallingChatModel, error) {
+	sampling, err := ChatSamplingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return newQwenChatModel(ctx, os.Getenv("MODEL"), sampling)
+}
+
+func newQwenChatModel(ctx context.Context, modelName string, sampling SamplingConfig) (model.ToolCallingChatModel, error) {
+	apiKey := os.Getenv("API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("API_KEY environment variable is required")
+	}
+
+	return qwen.NewChatModel(ctx, &qwen.ChatModelConfig{
+		APIKey:      apiKey,
+		BaseURL:     os.Getenv("BASE_URL"),
+		Model:       modelName,
+		Temperature: sampling.Temperature,
+		MaxTokens:   sampling.MaxTokens,
+		TopP:        sampling.TopP,
 	})
 }
 
+// defaultOllamaHost is the address of a local Ollama server.
+const defaultOllamaHost = "http://localhost:11434"
+
+// ollamaHost returns OLLAMA_HOST without a trailing slash, defaulting to the local server.
+func ollamaHost() string {
+	host := strings.TrimSpace(os.Getenv("OLLAMA_HOST"))
+	if host == "" {
+		return defaultOllamaHost
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+// CreateOllamaChatModel creates a chat model served by a local Ollama instance.
+// Required environment variables:
+//   - OLLAMA_MODEL: Model name, e.g. qwen3:8b (must support tool calling)
+//
+// Optional environment variables:
+//   - OLLAMA_HOST: Ollama server address (default: http://localhost:11434)
+func CreateOllamaChatModel(ctx context.Context) (model.ToolCallingChatModel, error) {
+	sampling, err := ChatSamplingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return newOllamaChatModel(ctx, os.Getenv("OLLAMA_MODEL"), sampling)
+}
+
+func newOllamaChatModel(ctx context.Context, modelName string, sampling SamplingConfig) (model.ToolCallingChatModel, error) {
+	if modelName == "" {
+		return nil, fmt.Errorf("OLLAMA_MODEL environment variable is required")
+	}
+
+	config := &ollama.ChatModelConfig{
+		BaseURL: ollamaHost(),
+		Model:   modelName,
+		Options: ollamaOptions(sampling),
+	}
+	if sampling.Temperature != nil && *sampling.Temperature == 0 {
+		config.HTTPClient = &http.Client{Transport: &ollamaZeroTemperature{base: http.DefaultTransport}}
+	}
+	return ollama.NewChatModel(ctx, config)
+}
+
+// ollamaOptions converts sampling settings to Ollama options, or nil when none
+// are set. Zero fields are omitted from the request, so the server keeps its
+// defaults for them; ollamaZeroTemperature sends a temperature of 0.
+func ollamaOptions(sampling SamplingConfig) *ollama.Options {
+	if sampling == (SamplingConfig{}) {
+		return nil
+	}
+	opts := &ollama.Options{}
+	if sampling.Temperature != nil {
+		opts.Temperature = *sampling.Temperature
+	}
+	if sampling.MaxTokens != nil {
+		opts.NumPredict = *sampling.MaxTokens
+	}
+	if sampling.TopP != nil {
+		opts.TopP = *sampling.TopP
+	}
+	return opts
+}
+
+// ollamaZeroTemperature sets "temperature": 0 in the options of Ollama chat
+// requests. Options.Temperature is tagged omitempty, so without it a
+// configured temperature of 0 is dropped and the server default applies.
+type ollamaZeroTemperature struct {
+	base http.RoundTripper
+}
+
+func (t *ollamaZeroTemperature) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || !strings.HasSuffix(req.URL.Path, "/api/chat") {
+		return t.base.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as written so integer options such as seed stay exact
+	var payload map[string]any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if dec.Decode(&payload) == nil {
+		options, _ := payload["options"].(map[string]any)
+		if options == nil {
+			options = make(map[string]any)
+		}
+		options["temperature"] = 0
+		payload["options"] = options
+		if data, err := json.Marshal(payload); err == nil {
+			body = data
+		}
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return t.base.RoundTrip(req)
+}
+
+// CreateSummaryModel creates the model used by the summarizer and reranker from
+// the SUMMARY_MODEL_* environment variables, with the generation settings read
+// by SummarySamplingFromEnv.
 func CreateSummaryModel(ctx context.Context) (model.ToolCallingChatModel, error) {
 	apiKey := os.Getenv("SUMMARY_MODEL_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("API_KEY environment variable is required")
 	}
+	sampling, err := SummarySamplingFromEnv()
+	if err != nil {
+		return nil, err
+	}
 
 	return qwen.NewChatModel(ctx, &qwen.ChatModelConfig{
-		APIKey:  apiKey,
-		BaseURL: os.Getenv("SUMMARY_MODEL_BASE_URL"),
-		Model:   os.Getenv("SUMMARY_MODEL"),
+		APIKey:      apiKey,
+		BaseURL:     os.Getenv("SUMMARY_MODEL_BASE_URL"),
+		Model:       os.Getenv("SUMMARY_MODEL"),
+		Temperature: sampling.Temperature,
+		MaxTokens:   sampling.MaxTokens,
+		TopP:        sampling.TopP,
 	})
 }
 
@@ -105,8 +477,23 @@ func NewEmbeddingModel(ctx context.Context, config *EmbeddingConfig) (einoEmbedd
 	})
 }
 
-// CreateEmbeddingModel creates an OpenAI-compatible embedding model from environment variables.
+// CreateEmbeddingModel creates the embedding model from environment variables.
+// EMBEDDING_PROVIDER selects the provider:
+//   - empty/openai: OpenAI-compatible API configured by EMBEDDING_MODEL_* variables
+//   - ollama: local Ollama server, see CreateOllamaEmbeddingModel
+//
+// VECTOR_DIM must match the dimension the selected model produces.
 func CreateEmbeddingModel(ctx context.Context) (einoEmbedding.Embedder, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("EMBEDDING_PROVIDER")))
+	switch provider {
+	case "", ProviderOpenAI:
+	case ProviderOllama:
+		return CreateOllamaEmbeddingModel(ctx)
+	default:
+		return nil, fmt.Errorf("unknown EMBEDDING_PROVIDER %q (expected %s or %s)",
+			provider, ProviderOpenAI, ProviderOllama)
+	}
+
 	apiKey := os.Getenv("EMBEDDING_MODEL_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("API_KEY environment variable is required")
@@ -118,3 +505,82 @@ func CreateEmbeddingModel(ctx context.Context) (einoEmbedding.Embedder, error) {
 		Model:   os.Getenv("EMBEDDING_MODEL"),
 	})
 }
+
+// CreateOllamaEmbeddingModel creates an embedding model served by a local Ollama instance
+// through its OpenAI-compatible /v1 endpoint.
+// Optional environment variables:
+//   - OLLAMA_HOST: Ollama server address (default: http://localhost:11434)
+//   - OLLAMA_EMBEDDING_MODEL: Model name (default: nomic-embed-text, 768 dimensions)
+func CreateOllamaEmbeddingModel(ctx context.Context) (einoEmbedding.Embedder, error) {
+	modelName := os.Getenv("OLLAMA_EMBEDDING_MODEL")
+	if modelName == "" {
+		modelName = "nomic-embed-text"
+	}
+
+	// Ollama ignores the key, but the OpenAI client requires one
+	return NewEmbeddingModel(ctx, &EmbeddingConfig{
+		APIKey:  "ollama",
+		BaseURL: ollamaHost() + "/v1",
+		Model:   modelName,
+	})
+}
+
+// DefaultEmbeddingProvider names the embedding provider configured by EMBEDDING_MODEL_* variables
+const DefaultEmbeddingProvider = "default"
+
+// embeddingProviderEnvPrefix returns the environment variable prefix of a provider:
+// EMBEDDING_MODEL_ for the default provider, EMBEDDING_<NAME>_ otherwise
+func embeddingProviderEnvPrefix(provider string) string {
+	provider = strings.TrimSpace(provider)
+	if provider == "" || strings.EqualFold(provider, DefaultEmbeddingProvider) {
+		return "EMBEDDING_MODEL_"
+	}
+	return "EMBEDDING_" + strings.ToUpper(provider) + "_"
+}
+
+// EmbeddingProviders lists the configured embedding providers: "default" when
+// EMBEDDING_MODEL_API_KEY is set, plus every <name> with EMBEDDING_<NAME>_API_KEY set.
+func EmbeddingProviders() []string {
+	var names []string
+	for _, kv := range os.Environ() {
+		key, val, _ := strings.Cut(kv, "=")
+		if val == "" || !strings.HasPrefix(key, "EMBEDDING_") || !strings.HasSuffix(key, "_API_KEY") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "EMBEDDING_"), "_API_KEY")
+		if name == "" || name == "MODEL" {
+			continue
+		}
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	if os.Getenv("EMBEDDING_MODEL_API_KEY") != "" {
+		names = append([]string{DefaultEmbeddingProvider}, names...)
+	}
+	return names
+}
+
+// CreateEmbeddingModelFor creates an OpenAI-compatible embedding model for a named provider.
+// The provider reads EMBEDDING_<NAME>_API_KEY and EMBEDDING_<NAME>_BASE_URL
+// ("default" reads EMBEDDING_MODEL_API_KEY and EMBEDDING_MODEL_BASE_URL).
+// An empty model name falls back to the provider's EMBEDDING_<NAME>_MODEL.
+func CreateEmbeddingModelFor(ctx context.Context, provider, modelName string) (einoEmbedding.Embedder, error) {
+	prefix := embeddingProviderEnvPrefix(provider)
+	apiKey := os.Getenv(prefix + "API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("embedding provider %q is not configured (%sAPI_KEY is empty)", provider, prefix)
+	}
+	if modelName == "" {
+		if prefix == "EMBEDDING_MODEL_" {
+			modelName = os.Getenv("EMBEDDING_MODEL")
+		} else {
+			modelName = os.Getenv(prefix + "MODEL")
+		}
+	}
+
+	return NewEmbeddingModel(ctx, &EmbeddingConfig{
+		APIKey:  apiKey,
+		BaseURL: os.Getenv(prefix + "BASE_URL"),
+		Model:   modelName,
+	})
+}
@@ -0,0 +1,220 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/components/model/claude"
+	"github.com/cloudwego/eino-ext/components/model/ollama"
+	openaiModel "github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino-ext/components/model/qwen"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// clearProviderEnv unsets the variables read by CreateChatModel and CreateEmbeddingModel
+func clearProviderEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"LLM_PROVIDER", "API_KEY", "BASE_URL", "MODEL",
+		"ANTHROPIC_API_KEY", "ANTHROPIC_MODEL", "ANTHROPIC_BASE_URL", "ANTHROPIC_MAX_TOKENS",
+		"OLLAMA_HOST", "OLLAMA_MODEL", "OLLAMA_EMBEDDING_MODEL",
+		"EMBEDDING_PROVIDER", "EMBEDDING_MODEL_API_KEY",
+		"MODEL_TEMPERATURE", "MODEL_MAX_TOKENS", "MODEL_TOP_P",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+// TestSamplingFromEnv verifies parsing, validation and the summary temperature default
+func TestSamplingFromEnv(t *testing.T) {
+	t.Setenv("MODEL_TEMPERATURE", "0.7")
+	t.Setenv("MODEL_MAX_TOKENS", "2048")
+	t.Setenv("MODEL_TOP_P", "")
+	for _, key := range []string{"SUMMARY_MODEL_TEMPERATURE", "SUMMARY_MODEL_MAX_TOKENS", "SUMMARY_MODEL_TOP_P"} {
+		t.Setenv(key, "")
+	}
+	sampling, err := ChatSamplingFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sampling.Temperature == nil || *sampling.Temperature != 0.7 || sampling.MaxTokens == nil || *sampling.MaxTokens != 2048 {
+		t.Errorf("unexpected chat sampling: %+v", sampling)
+	}
+	if sampling.TopP != nil {
+		t.Errorf("unset MODEL_TOP_P should keep the provider default, got %v", *sampling.TopP)
+	}
+
+	sampling, err = SummarySamplingFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sampling.Temperature == nil || *sampling.Temperature != defaultSummaryTemperature || sampling.MaxTokens != nil {
+		t.Errorf("summary sampling should default to a low temperature only: %+v", sampling)
+	}
+
+	for key, val := range map[string]string{
+		"MODEL_TEMPERATURE": "3",
+		"MODEL_MAX_TOKENS":  "-1",
+		"MODEL_TOP_P":       "abc",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, val)
+			if _, err := ChatSamplingFromEnv(); err == nil {
+				t.Errorf("%s=%s should be rejected", key, val)
+			}
+		})
+	}
+}
+
+// TestOllamaOptions verifies only configured settings are sent to Ollama
+func TestOllamaOptions(t *testing.T) {
+	if opts := ollamaOptions(SamplingConfig{}); opts != nil {
+		t.Errorf("no settings should leave options nil, got %+v", opts)
+	}
+	temp, maxTokens := float32(0.3), 512
+	opts := ollamaOptions(SamplingConfig{Temperature: &temp, MaxTokens: &maxTokens})
+	if opts == nil || opts.Temperature != 0.3 || opts.NumPredict != 512 || opts.TopP != 0 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
+
+// TestOllamaZeroTemperature verifies a configured temperature of 0 reaches
+// the Ollama server instead of being dropped as an empty option
+func TestOllamaZeroTemperature(t *testing.T) {
+	var options map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Options map[string]any `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		options = body.Options
+		w.Write([]byte(`{"model":"m","message":{"role":"assistant","content":"hi"},"done":true}`))
+	}))
+	defer srv.Close()
+	t.Setenv("OLLAMA_HOST", srv.URL)
+
+	temp, maxTokens := float32(0), 64
+	m, err := newOllamaChatModel(context.Background(), "m", SamplingConfig{Temperature: &temp, MaxTokens: &maxTokens})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Generate(context.Background(), []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := options["temperature"]; !ok || v != float64(0) {
+		t.Errorf("temperature 0 should be sent, got options %v", options)
+	}
+	if options["num_predict"] != float64(64) {
+		t.Errorf("other options should be kept, got %v", options)
+	}
+}
+
+// TestCreateChatModelSelectsProvider verifies LLM_PROVIDER dispatches to the
+// matching client, reading that provider's model variable, and that a single
+// model is still wrapped for retries
+func TestCreateChatModelSelectsProvider(t *testing.T) {
+	tests := []struct {
+		provider string
+		env      map[string]string
+		check    func(model.ToolCallingChatModel) bool
+	}{
+		{"", map[string]string{"API_KEY": "key"}, func(m model.ToolCallingChatModel) bool {
+			_, ok := m.(*openaiModel.ChatModel)
+			return ok
+		}},
+		{"OpenAI", map[string]string{"API_KEY": "key", "MODEL": "glm-4-plus"}, func(m model.ToolCallingChatModel) bool {
+			_, ok := m.(*openaiModel.ChatModel)
+			return ok
+		}},
+		{"anthropic", map[string]string{"ANTHROPIC_API_KEY": "key", "ANTHROPIC_MODEL": "claude-haiku-4-5"}, func(m model.ToolCallingChatModel) bool {
+			_, ok := m.(*claude.ChatModel)
+			return ok
+		}},
+		{"qwen", map[string]string{"API_KEY": "key", "MODEL": "qwen-plus"}, func(m model.ToolCallingChatModel) bool {
+			_, ok := m.(*qwen.ChatModel)
+			return ok
+		}},
+		{"ollama", map[string]string{"OLLAMA_MODEL": "qwen3:8b"}, func(m model.ToolCallingChatModel) bool {
+			_, ok := m.(*ollama.ChatModel)
+			return ok
+		}},
+	}
+	for _, tt := range tests {
+		t.Run("provider="+tt.provider, func(t *testing.T) {
+			clearProviderEnv(t)
+			t.Setenv("LLM_PROVIDER", tt.provider)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			m, err := CreateChatModel(context.Background())
+			if err != nil {
+				t.Fatalf("CreateChatModel: %v", err)
+			}
+			f, ok := m.(*FallbackChatModel)
+			if !ok || len(f.models) != 1 {
+				t.Fatalf("CreateChatModel returned %T, want a FallbackChatModel with one model", m)
+			}
+			if !tt.check(f.models[0]) {
+				t.Errorf("LLM_PROVIDER=%q created %T", tt.provider, f.models[0])
+			}
+		})
+	}
+}
+
+// TestCreateChatModelErrors verifies unknown providers and missing settings are reported
+func TestCreateChatModelErrors(t *testing.T) {
+	tests := []struct {
+		provider string
+		env      map[string]string
+		want     string
+	}{
+		{"gemini", nil, `unknown LLM_PROVIDER "gemini"`},
+		{"openai", nil, "API_KEY environment variable is required"},
+		{"anthropic", map[string]string{"API_KEY": "key"}, "ANTHROPIC_API_KEY environment variable is required"},
+		{"anthropic", map[string]string{"ANTHROPIC_API_KEY": "key", "ANTHROPIC_MAX_TOKENS": "0"}, "invalid ANTHROPIC_MAX_TOKENS"},
+		{"ollama", nil, "OLLAMA_MODEL environment variable is required"},
+		{"openai", map[string]string{"API_KEY": "key", "MODEL_TEMPERATURE": "5"}, "invalid MODEL_TEMPERATURE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			clearProviderEnv(t)
+			t.Setenv("LLM_PROVIDER", tt.provider)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if _, err := CreateChatModel(context.Background()); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestCreateEmbeddingModelSelectsProvider verifies EMBEDDING_PROVIDER dispatch
+func TestCreateEmbeddingModelSelectsProvider(t *testing.T) {
+	clearProviderEnv(t)
+	ctx := context.Background()
+
+	if _, err := CreateEmbeddingModel(ctx); err == nil {
+		t.Error("the default provider should require EMBEDDING_MODEL_API_KEY")
+	}
+	t.Setenv("EMBEDDING_MODEL_API_KEY", "key")
+	if _, err := CreateEmbeddingModel(ctx); err != nil {
+		t.Errorf("default provider: %v", err)
+	}
+
+	t.Setenv("EMBEDDING_MODEL_API_KEY", "")
+	t.Setenv("EMBEDDING_PROVIDER", "ollama")
+	if _, err := CreateEmbeddingModel(ctx); err != nil {
+		t.Errorf("ollama needs no API key: %v", err)
+	}
+
+	t.Setenv("EMBEDDING_PROVIDER", "cohere")
+	if _, err := CreateEmbeddingModel(ctx); err == nil || !strings.Contains(err.Error(), `unknown EMBEDDING_PROVIDER "cohere"`) {
+		t.Errorf("unknown provider error = %v", err)
+	}
+}
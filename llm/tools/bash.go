@@ -5,7 +5,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"os/exec"
 	"strings"
 	"time"
@@ -88,6 +87,12 @@ func BashToolFunc(ctx context.Context, params BashToolParams) (string, error) {
 		}
 	}
 
+	if IsReadOnly(ctx) {
+		return Simulated(fmt.Sprintf("Command not executed: %s", command), &Metadata{
+			Command: command,
+		})
+	}
+
 	// Validate and set timeout
 	timeoutMs := params.TimeoutMs
 	if timeoutMs == 0 {
@@ -104,6 +109,7 @@ func BashToolFunc(ctx context.Context, params BashToolParams) (string, error) {
 	defer cancel()
 
 	cmd := exec.CommandContext(cmdCtx, "powershell", "-NoProfile", "-Command", command)
+	cmd.Dir = WorkDirFromContext(ctx)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -121,24 +127,32 @@ func BashToolFunc(ctx context.Context, params BashToolParams) (string, error) {
 
 	// Check for timeout
 	if errors.Is(cmdCtx.Err(), context.DeadlineExceeded) {
+		partial, omitted := truncateOutput(stdoutStr)
 		return Partial(fmt.Sprintf("Command timed out after %v\n\nPartial output:\n%s",
-			timeout, truncateOutput(stdoutStr)), &Metadata{
-			Command:  command,
-			Duration: duration.Milliseconds(),
-			Timeout:  true,
+			timeout, partial), &Metadata{
+			Command:      command,
+			Duration:     duration.Milliseconds(),
+			Timeout:      true,
+			Truncated:    omitted > 0,
+			OmittedBytes: omitted,
 		})
 	}
 
 	// Build result content
+	omittedBytes := 0
 	if stdoutStr != "" {
-		output = append(output, truncateOutput(stdoutStr))
+		out, omitted := truncateOutput(stdoutStr)
+		output = append(output, out)
+		omittedBytes += omitted
 	}
 
 	exitCode := 0
 	if err != nil {
 		exitCode = 1
 		if stderrStr != "" {
-			output = append(output, fmt.Sprintf("stderr: %s", truncateOutput(stderrStr)))
+			out, omitted := truncateOutput(stderrStr)
+			output = append(output, fmt.Sprintf("stderr: %s", out))
+			omittedBytes += omitted
 		}
 		// Don't include the error message for exit code, just metadata
 	}
@@ -153,29 +167,31 @@ func BashToolFunc(ctx context.Context, params BashToolParams) (string, error) {
 		command,
 		duration.Milliseconds(),
 		exitCode,
+		omittedBytes,
 	)
 }
 
-// truncateOutput truncates output if it exceeds MaxOutputLength
-func truncateOutput(s string) string {
+// truncateOutput keeps the head and tail of output exceeding MaxOutputLength
+// and returns it with the number of bytes omitted from the middle
+func truncateOutput(s string) (string, int) {
 	if len(s) <= MaxOutputLength {
-		return s
+		return s, 0
 	}
 	half := MaxOutputLength / 2
-	truncated := len(s) - MaxOutputLength
-	return fmt.Sprintf("%s\n\n... [~%d chars truncated] ...\n\n%s",
-		s[:half], truncated, s[len(s)-half:])
+	omitted := len(s) - 2*half
+	note := truncationNote(omitted, fmt.Sprintf("showing the first and last %d bytes of the output", half))
+	return fmt.Sprintf("%s\n\n%s\n\n%s", s[:half], note, s[len(s)-half:]), omitted
 }
 
 // GetBashTool returns the PowerShell tool with enhanced description.
-func GetBashTool() tool.InvokableTool {
+func GetBashTool() (tool.InvokableTool, error) {
 	bashTool, err := utils.InferTool(
 		BashToolName,
 		bashDescription,
 		BashToolFunc,
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", BashToolName, err)
 	}
-	return bashTool
+	return bashTool, nil
 }
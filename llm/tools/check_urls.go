@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const (
+	// CheckURLsToolName is the name of the URL availability tool
+	CheckURLsToolName = "check_urls"
+
+	// MaxCheckURLs is the maximum number of URLs checked per call
+	MaxCheckURLs = 20
+	// DefaultCheckTimeout is the default per-URL timeout in seconds
+	DefaultCheckTimeout = 10
+	// checkConcurrency limits the number of in-flight checks
+	checkConcurrency = 8
+)
+
+// CheckURLsParams defines the arguments for the check_urls tool.
+type CheckURLsParams struct {
+	URLs    []string `json:"urls" jsonschema:"description=List of URLs to check (max 20). Must start with http:// or https://"`
+	Timeout int      `json:"timeout,omitempty" jsonschema:"description=Optional per-URL timeout in seconds (default: 10, max: 120)"`
+}
+
+// URLStatus holds the availability metadata of a single URL.
+type URLStatus struct {
+	URL           string
+	StatusCode    int
+	FinalURL      string
+	ContentType   string
+	ContentLength int64
+	Err           string
+}
+
+// Reachable reports whether the URL answered with a 2xx/3xx status.
+func (s URLStatus) Reachable() bool {
+	return s.Err == "" && s.StatusCode >= 200 && s.StatusCode < 400
+}
+
+// checkURLsDescription is the detailed tool description for the AI
+const checkURLsDescription = `Check whether URLs are reachable without downloading their content.
+
+BEFORE USING:
+- Use this before fetching many URLs to skip dead links
+- Prefer fetch when you actually need the page content
+
+CAPABILITIES:
+- Checks up to 20 URLs concurrently
+- Sends HEAD requests, falling back to a 0-byte ranged GET
+- Follows redirects and reports the final URL
+- Reports status code, content type and content length
+- Private network addresses are blocked
+
+PARAMETERS:
+- urls (required): List of URLs to check
+- timeout (optional): Per-URL timeout in seconds (default: 10, max: 120)
+
+OUTPUT FORMAT:
+One line per URL with status code, final URL, content type and length.
+
+EXAMPLES:
+- Check links: {"urls": ["https://go.dev/doc", "https://pkg.go.dev"]}
+- With timeout: {"urls": ["https://example.com"], "timeout": 5}`
+
+// CheckURLsFunc checks the availability of the given URLs concurrently.
+func CheckURLsFunc(ctx context.Context, params CheckURLsParams) (string, error) {
+	if len(params.URLs) == 0 {
+		return Error("urls parameter is required")
+	}
+	if len(params.URLs) > MaxCheckURLs {
+		return Error(fmt.Sprintf("too many URLs: %d (max %d)", len(params.URLs), MaxCheckURLs))
+	}
+
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	if timeout > MaxTimeout {
+		timeout = MaxTimeout
+	}
+
+	client := newHTTPClient(time.Duration(timeout) * time.Second)
+	client.CheckRedirect = checkRedirectTarget
+
+	results := make([]URLStatus, len(params.URLs))
+	sem := make(chan struct{}, checkConcurrency)
+	var wg sync.WaitGroup
+
+	for i, rawURL := range params.URLs {
+		wg.Add(1)
+		go func(i int, rawURL string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = checkURL(ctx, client, strings.TrimSpace(rawURL))
+		}(i, rawURL)
+	}
+	wg.Wait()
+
+	var sb strings.Builder
+	reachable := 0
+	for _, r := range results {
+		if r.Reachable() {
+			reachable++
+		}
+		sb.WriteString(formatURLStatus(r))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(fmt.Sprintf("\n%d/%d URLs reachable", reachable, len(results)))
+
+	if reachable < len(results) {
+		return Partial(sb.String(), nil)
+	}
+	return Success(sb.String(), nil, TierCompact)
+}
+
+// checkURL probes a single URL with HEAD, falling back to a ranged GET.
+func checkURL(ctx context.Context, client *http.Client, rawURL string) URLStatus {
+	status := URLStatus{URL: rawURL}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		status.Err = fmt.Sprintf("invalid URL: %v", err)
+		return status
+	}
+	if err := validateURLTarget(ctx, u); err != nil {
+		status.Err = err.Error()
+		return status
+	}
+
+	resp, err := doProbe(ctx, client, http.MethodHead, rawURL)
+	if err != nil || resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		resp, err = doProbe(ctx, client, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		status.Err = err.Error()
+		return status
+	}
+
+	status.StatusCode = resp.StatusCode
+	status.FinalURL = resp.Request.URL.String()
+	status.ContentType = resp.Header.Get("Content-Type")
+	status.ContentLength = probeContentLength(resp)
+	return status
+}
+
+// doProbe sends a request without reading the body. GET requests ask for a
+// single byte so servers that reject HEAD don't send the whole document.
+func doProbe(ctx context.Context, client *http.Client, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "compass-fetch-tool/1.0")
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// probeContentLength returns the full content length, using Content-Range
+// for partial responses. Returns -1 when unknown.
+func probeContentLength(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusPartialContent {
+		if cr := resp.Header.Get("Content-Range"); cr != "" {
+			if idx := strings.LastIndex(cr, "/"); idx != -1 {
+				if n, err := strconv.ParseInt(cr[idx+1:], 10, 64); err == nil {
+					return n
+				}
+			}
+		}
+		return -1
+	}
+	if cl := resp.Header.Get("Content-Length"); cl != "" {
+		if n, err := strconv.ParseInt(cl, 10, 64); err == nil {
+			return n
+		}
+	}
+	return -1
+}
+
+// formatURLStatus formats a single check result line
+func formatURLStatus(s URLStatus) string {
+	if s.Err != "" {
+		return fmt.Sprintf("❌ %s\n   error: %s", s.URL, s.Err)
+	}
+
+	icon := "✅"
+	if !s.Reachable() {
+		icon = "❌"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s\n   status: %d", icon, s.URL, s.StatusCode))
+	if s.FinalURL != "" && s.FinalURL != s.URL {
+		sb.WriteString(fmt.Sprintf(" | final: %s", s.FinalURL))
+	}
+	if s.ContentType != "" {
+		sb.WriteString(fmt.Sprintf(" | type: %s", s.ContentType))
+	}
+	if s.ContentLength >= 0 {
+		sb.WriteString(fmt.Sprintf(" | length: %d", s.ContentLength))
+	}
+	return sb.String()
+}
+
+// GetCheckURLsTool returns the URL availability tool.
+func GetCheckURLsTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(
+		CheckURLsToolName,
+		checkURLsDescription,
+		CheckURLsFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", CheckURLsToolName, err)
+	}
+	return t, nil
+}
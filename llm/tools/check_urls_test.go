@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCheckURLsMetadata verifies status, final URL, content type and length reporting
+func TestCheckURLsMetadata(t *testing.T) {
+	allowPrivateNetworks = true
+	defer func() { allowPrivateNetworks = false }()
+
+	var getBodyRequested atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Content-Length", "1234")
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/nohead", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Range") != "bytes=0-0" {
+			getBodyRequested.Store(true)
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Range", "bytes 0-0/5000")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("x"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	s := checkURL(ctx, client, srv.URL+"/ok")
+	if s.StatusCode != 200 || s.ContentType != "text/html" || s.ContentLength != 1234 {
+		t.Errorf("unexpected /ok status: %+v", s)
+	}
+
+	s = checkURL(ctx, client, srv.URL+"/missing")
+	if s.StatusCode != 404 || s.Reachable() {
+		t.Errorf("unexpected /missing status: %+v", s)
+	}
+
+	s = checkURL(ctx, client, srv.URL+"/redirect")
+	if s.StatusCode != 200 || s.FinalURL != srv.URL+"/ok" {
+		t.Errorf("unexpected /redirect status: %+v", s)
+	}
+
+	s = checkURL(ctx, client, srv.URL+"/nohead")
+	if s.StatusCode != http.StatusPartialContent || s.ContentType != "application/pdf" || s.ContentLength != 5000 {
+		t.Errorf("unexpected /nohead status: %+v", s)
+	}
+	if getBodyRequested.Load() {
+		t.Error("GET fallback requested the full body")
+	}
+}
+
+// TestCheckURLsConcurrent verifies URLs are checked in parallel
+func TestCheckURLsConcurrent(t *testing.T) {
+	allowPrivateNetworks = true
+	defer func() { allowPrivateNetworks = false }()
+
+	var inFlight, maxInFlight atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	urls := make([]string, 5)
+	for i := range urls {
+		urls[i] = srv.URL + "/" + string(rune('a'+i))
+	}
+
+	out, _ := CheckURLsFunc(context.Background(), CheckURLsParams{URLs: urls})
+	if !strings.Contains(out, "5/5 URLs reachable") {
+		t.Errorf("expected all URLs reachable, got:\n%s", out)
+	}
+	if strings.Contains(out, "matches") {
+		t.Errorf("the reachable count is not a search match count:\n%s", out)
+	}
+	if maxInFlight.Load() < 2 {
+		t.Errorf("expected concurrent checks, max in flight was %d", maxInFlight.Load())
+	}
+}
+
+// TestCheckURLsBlocksPrivate verifies SSRF protection rejects loopback targets
+func TestCheckURLsBlocksPrivate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	s := checkURL(context.Background(), srv.Client(), srv.URL)
+	if s.Err == "" || s.StatusCode != 0 {
+		t.Errorf("expected loopback URL to be blocked, got %+v", s)
+	}
+
+	u, _ := url.Parse("ftp://example.com/file")
+	if err := validateURLTarget(context.Background(), u); err == nil {
+		t.Error("expected non-http scheme to be rejected")
+	}
+
+	blockedDomains = []string{"example.com"}
+	defer func() { blockedDomains = nil }()
+	u, _ = url.Parse("https://docs.example.com/")
+	if err := validateURLTarget(context.Background(), u); err == nil {
+		t.Error("expected blocked subdomain to be rejected")
+	}
+}
@@ -3,14 +3,83 @@ package tools
 import (
 	"compass/llm/providers"
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/cloudwego/eino/adk"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
 )
 
-// ContentSummarizerPrompt 定义了内容摘要 Agent 的系统提示词
+// SummaryLength 摘要的目标长度
+type SummaryLength string
+
+const (
+	SummaryBrief    SummaryLength = "brief"    // 适合批量摘要大量 URL
+	SummaryStandard SummaryLength = "standard" // 默认长度
+	SummaryDetailed SummaryLength = "detailed" // 适合精读主要来源
+)
+
+// summaryLengthSpecs 各长度对应的字数范围和要点数
+var summaryLengthSpecs = map[SummaryLength]struct{ words, bullets string }{
+	SummaryBrief:    {"50-100", "2-3"},
+	SummaryStandard: {"150-300", "3-7"},
+	SummaryDetailed: {"400-700", "7-12"},
+}
+
+// ParseSummaryLength 解析摘要长度，空字符串表示 standard
+func ParseSummaryLength(s string) (SummaryLength, error) {
+	if s == "" {
+		return SummaryStandard, nil
+	}
+	l := SummaryLength(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := summaryLengthSpecs[l]; !ok {
+		return "", fmt.Errorf("unknown summary length %q: use brief, standard or detailed", s)
+	}
+	return l, nil
+}
+
+// SummaryLengthFromEnv 读取 SUMMARY_LENGTH，无效值时使用 standard
+func SummaryLengthFromEnv() SummaryLength {
+	l, err := ParseSummaryLength(os.Getenv("SUMMARY_LENGTH"))
+	if err != nil {
+		slog.Warn("invalid SUMMARY_LENGTH, using standard", "err", err)
+		return SummaryStandard
+	}
+	return l
+}
+
+// ContentSummaryConfig 内容摘要工具的配置
+type ContentSummaryConfig struct {
+	Length SummaryLength // 摘要长度，默认 standard
+	Name   string        // 工具名，默认 summarize_url，非 standard 长度为 summarize_url_<length>
+}
+
+// SummaryToolConfigs 返回要注册的摘要工具：默认长度的 summarize_url，
+// 以及与默认长度不同的 brief 和 detailed 变体，使 Agent 可以按 URL 选择摘要长度
+func SummaryToolConfigs(defaultLength SummaryLength) []ContentSummaryConfig {
+	configs := []ContentSummaryConfig{{Length: defaultLength, Name: "summarize_url"}}
+	for _, length := range []SummaryLength{SummaryBrief, SummaryDetailed} {
+		if length != defaultLength {
+			configs = append(configs, ContentSummaryConfig{Length: length, Name: "summarize_url_" + string(length)})
+		}
+	}
+	return configs
+}
+
+// ContentSummarizerInstruction 返回指定长度的摘要 Agent 系统提示词
+func ContentSummarizerInstruction(length SummaryLength) string {
+	spec, ok := summaryLengthSpecs[length]
+	if !ok {
+		spec = summaryLengthSpecs[SummaryStandard]
+	}
+	return strings.NewReplacer("{words}", spec.words, "{bullets}", spec.bullets).Replace(ContentSummarizerPrompt)
+}
+
+// ContentSummarizerPrompt 定义了内容摘要 Agent 的系统提示词模板，
+// {words} 和 {bullets} 由 ContentSummarizerInstruction 按摘要长度填充
 const ContentSummarizerPrompt = `
 Role: Web Content Summarizer
 Profile:
@@ -42,12 +111,12 @@ Use the following structure for ALL summaries:
 - Point 1
 - Point 2
 - Point 3
-(Include 3-7 bullet points, rank by importance)
+(Include {bullets} bullet points, rank by importance)
 **Source:** [URL]
 **Date:** [extraction date]
 ---
 Guidelines:
-- **Be Concise**: Aim for 150-300 words total
+- **Be Concise**: Aim for {words} words total
 - **Be Accurate**: Don't hallucinate information
 - **Be Selective**: Skip navigation, ads, footers, sidebars
 - **Use Markdown**: Format with headers, bullets, emphasis
@@ -65,19 +134,34 @@ Tone: Professional, objective, information-dense.
 `
 
 // NewSummaryAgent 创建网页内容摘要 Agent
-func NewSummaryAgent(ctx context.Context) adk.Agent {
+func NewSummaryAgent(ctx context.Context, config ContentSummaryConfig) (adk.Agent, error) {
+	length, err := ParseSummaryLength(string(config.Length))
+	if err != nil {
+		return nil, err
+	}
+	name := config.Name
+	if name == "" {
+		name = "summarize_url"
+		if length != SummaryStandard {
+			name += "_" + string(length)
+		}
+	}
+
 	model, err := providers.CreateSummaryModel(ctx)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create summary model: %w", err)
 	}
 
 	// 获取工具
-	fetchTool := GetFetchTool()
+	fetchTool, err := GetFetchTool()
+	if err != nil {
+		return nil, err
+	}
 
 	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
-		Name:        "summarize_url",
-		Description: "Intelligent web content summarizer that fetches URLs and provides structured summaries",
-		Instruction: ContentSummarizerPrompt,
+		Name:        name,
+		Description: fmt.Sprintf("Intelligent web content summarizer that fetches URLs and provides structured %s summaries (%s words)", length, summaryLengthSpecs[length].words),
+		Instruction: ContentSummarizerInstruction(length),
 		Model:       model,
 		ToolsConfig: adk.ToolsConfig{
 			ToolsNodeConfig: compose.ToolsNodeConfig{
@@ -86,19 +170,30 @@ func NewSummaryAgent(ctx context.Context) adk.Agent {
 				},
 				ToolCallMiddlewares: []compose.ToolMiddleware{
 					ErrorHandler(), // 使用统一的错误处理中间件
+					ToolTimeouts(ToolTimeoutFromEnv(), DefaultToolTimeouts),
 				},
 			},
 			EmitInternalEvents: true,
 		},
 	})
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s agent: %w", name, err)
 	}
-	return agent
+	return agent, nil
 }
 
 // GetContentSummaryTool  将摘要 Agent 包装成 Tool (Agent-as-Tool 模式)
-func GetContentSummaryTool(ctx context.Context) tool.BaseTool {
-	summaryAgent := NewSummaryAgent(ctx)
-	return adk.NewAgentTool(ctx, summaryAgent)
+// 并发摘要数受 SUMMARY_MAX_CONCURRENCY 限制，超出的请求按先后顺序排队；
+// 不同长度的摘要工具可以同时注册，例如批量 URL 用 brief、主要来源用 detailed
+func GetContentSummaryTool(ctx context.Context, config ContentSummaryConfig) (tool.BaseTool, error) {
+	summaryAgent, err := NewSummaryAgent(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	agentTool := adk.NewAgentTool(ctx, summaryAgent)
+	invokable, ok := agentTool.(tool.InvokableTool)
+	if !ok {
+		return agentTool, nil
+	}
+	return &limitedTool{InvokableTool: invokable, limiter: summaryLimit}, nil
 }
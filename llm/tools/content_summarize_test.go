@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestContentSummarizerInstruction verifies each length fills in its word and bullet targets
+func TestContentSummarizerInstruction(t *testing.T) {
+	brief := ContentSummarizerInstruction(SummaryBrief)
+	if !strings.Contains(brief, "Aim for 50-100 words total") || !strings.Contains(brief, "Include 2-3 bullet points") {
+		t.Errorf("brief instruction missing its targets")
+	}
+	detailed := ContentSummarizerInstruction(SummaryDetailed)
+	if !strings.Contains(detailed, "Aim for 400-700 words total") || !strings.Contains(detailed, "Include 7-12 bullet points") {
+		t.Errorf("detailed instruction missing its targets")
+	}
+	if strings.Contains(detailed, "{words}") || strings.Contains(detailed, "{bullets}") {
+		t.Error("placeholders should be replaced")
+	}
+	if ContentSummarizerInstruction("") != ContentSummarizerInstruction(SummaryStandard) {
+		t.Error("unknown length should use the standard instruction")
+	}
+}
+
+// TestParseSummaryLength verifies lengths are parsed case-insensitively with standard as default
+func TestParseSummaryLength(t *testing.T) {
+	for in, want := range map[string]SummaryLength{"": SummaryStandard, "Brief": SummaryBrief, " detailed ": SummaryDetailed} {
+		if got, err := ParseSummaryLength(in); err != nil || got != want {
+			t.Errorf("ParseSummaryLength(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseSummaryLength("long"); err == nil {
+		t.Error("expected an error for an unknown length")
+	}
+}
+
+// TestSummaryToolConfigs verifies brief and detailed variants are registered next to summarize_url
+func TestSummaryToolConfigs(t *testing.T) {
+	names := func(configs []ContentSummaryConfig) string {
+		var out []string
+		for _, c := range configs {
+			out = append(out, c.Name+"="+string(c.Length))
+		}
+		return strings.Join(out, ",")
+	}
+	if got := names(SummaryToolConfigs(SummaryStandard)); got != "summarize_url=standard,summarize_url_brief=brief,summarize_url_detailed=detailed" {
+		t.Errorf("standard default: got %s", got)
+	}
+	if got := names(SummaryToolConfigs(SummaryBrief)); got != "summarize_url=brief,summarize_url_detailed=detailed" {
+		t.Errorf("brief default should not repeat the brief variant: got %s", got)
+	}
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+)
+
+// DefaultDedupWindow is how long an identical tool call reuses the prior result
+const DefaultDedupWindow = 30 * time.Second
+
+// DedupWindowFromEnv reads TOOL_DEDUP_WINDOW (Go duration, "0" disables)
+func DedupWindowFromEnv() time.Duration {
+	if val := os.Getenv("TOOL_DEDUP_WINDOW"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+	}
+	return DefaultDedupWindow
+}
+
+// dedupEntry is the result of the last tool call in a run
+type dedupEntry struct {
+	key     string
+	result  string
+	sources []string // Sources the call recorded, replayed on reuse
+	at      time.Time
+}
+
+// dedupRun holds the last reusable tool call of one run
+type dedupRun struct {
+	mu   sync.Mutex
+	last *dedupEntry
+}
+
+// dedupRunKey is the context key of the run's dedup state
+type dedupRunKey struct{}
+
+// WithToolCallDedup returns a context carrying fresh dedup state, so only
+// calls within the same run can reuse each other's results
+func WithToolCallDedup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dedupRunKey{}, &dedupRun{})
+}
+
+// dedupRunFromContext returns the run's dedup state, or nil if unset
+func dedupRunFromContext(ctx context.Context) *dedupRun {
+	r, _ := ctx.Value(dedupRunKey{}).(*dedupRun)
+	return r
+}
+
+// toolCallDeduper reuses the result of a read-only call repeated back to back
+type toolCallDeduper struct {
+	window time.Duration
+	now    func() time.Time
+}
+
+// DedupToolCalls 是重复工具调用去重中间件：同一轮运行中，只读工具以相同参数
+// 紧接着再次调用且在 window 内时不再执行，直接返回上次结果并提示模型不要重复调用。
+// 修改文件、执行命令或修改知识库的工具不去重，错误和不完整的结果不缓存。
+// 需要 WithToolCallDedup 提供的运行状态；window <= 0 时禁用。
+func DedupToolCalls(window time.Duration) compose.ToolMiddleware {
+	d := &toolCallDeduper{window: window, now: time.Now}
+	return d.middleware()
+}
+
+// middleware builds the invokable middleware
+func (d *toolCallDeduper) middleware() compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
+				run := dedupRunFromContext(ctx)
+				if d.window <= 0 || run == nil {
+					return next(ctx, in)
+				}
+				// A side effect may have changed what the next call would return
+				if IsMutatingTool(in.Name) {
+					run.set(nil)
+					return next(ctx, in)
+				}
+
+				key := in.Name + "\x00" + normalizeArguments(in.Arguments)
+				if entry := run.get(); entry != nil && entry.key == key && d.now().Sub(entry.at) <= d.window {
+					RecordSources(ctx, entry.sources...)
+					ago := d.now().Sub(entry.at).Round(time.Second)
+					return &compose.ToolOutput{
+						Result: fmt.Sprintf("⚠️ You already called %s with identical arguments %s ago; "+
+							"here is the prior result. Do not repeat the same call.\n\n%s",
+							in.Name, ago, entry.result),
+					}, nil
+				}
+
+				// Capture the sources this call records so a reuse can replay them
+				collector := NewSourceCollector()
+				output, err := next(WithSourceCollector(ctx, collector), in)
+				sources := collector.Sources()
+				RecordSources(ctx, sources...)
+
+				// A call cut short by cancellation or timeout, or one that failed or
+				// returned only part of its output, is not a result worth reusing
+				if err == nil && output != nil && ctx.Err() == nil && isCompleteResult(output.Result) {
+					run.set(&dedupEntry{key: key, result: output.Result, sources: sources, at: d.now()})
+				} else {
+					run.set(nil)
+				}
+				return output, err
+			}
+		},
+	}
+}
+
+// get returns the last reusable call, or nil
+func (r *dedupRun) get() *dedupEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// set replaces the last reusable call; nil forgets it
+func (r *dedupRun) set(entry *dedupEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = entry
+}
+
+// normalizeArguments compacts JSON arguments so whitespace differences don't matter
+func normalizeArguments(args string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(args)); err == nil {
+		return buf.String()
+	}
+	return strings.TrimSpace(args)
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+)
+
+// newTestDeduper returns a deduper with a controllable clock
+func newTestDeduper(window time.Duration, now *time.Time) *toolCallDeduper {
+	return &toolCallDeduper{
+		window: window,
+		now:    func() time.Time { return *now },
+	}
+}
+
+// countingEndpoint wraps a result function with the deduper and counts executions
+func countingEndpoint(d *toolCallDeduper, result func(ctx context.Context, in *compose.ToolInput) string) (compose.InvokableToolEndpoint, *int) {
+	calls := 0
+	endpoint := d.middleware().Invokable(func(ctx context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
+		calls++
+		return &compose.ToolOutput{Result: result(ctx, in)}, nil
+	})
+	return endpoint, &calls
+}
+
+// TestDedupToolCallsCachesRepeat verifies a call repeated back to back returns
+// the cached result without re-executing and replays its sources
+func TestDedupToolCallsCachesRepeat(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduper(30*time.Second, &now)
+	endpoint, calls := countingEndpoint(d, func(ctx context.Context, in *compose.ToolInput) string {
+		RecordSources(ctx, "https://example.com")
+		return "result of " + in.Arguments
+	})
+
+	collector := NewSourceCollector()
+	ctx := WithSourceCollector(WithToolCallDedup(context.Background()), collector)
+	first, err := endpoint(ctx, &compose.ToolInput{Name: "read", Arguments: `{"path": "a.go"}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(5 * time.Second)
+	second, err := endpoint(ctx, &compose.ToolInput{Name: "read", Arguments: `{"path":"a.go"}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 1 {
+		t.Fatalf("identical call re-executed: calls = %d", *calls)
+	}
+	if !strings.Contains(second.Result, "already called read") || !strings.Contains(second.Result, first.Result) {
+		t.Errorf("expected nudge and cached result, got:\n%s", second.Result)
+	}
+
+	// A reuse in a fresh collector still reports the call's sources
+	replay := NewSourceCollector()
+	endpoint(WithSourceCollector(ctx, replay), &compose.ToolInput{Name: "read", Arguments: `{"path":"a.go"}`})
+	if got := replay.Sources(); len(got) != 1 || got[0] != "https://example.com" {
+		t.Errorf("cache hit should replay sources, got %v", got)
+	}
+	if got := collector.Sources(); len(got) != 1 {
+		t.Errorf("executed call should record sources, got %v", got)
+	}
+
+	endpoint(ctx, &compose.ToolInput{Name: "read", Arguments: `{"path":"b.go"}`})
+	if *calls != 2 {
+		t.Errorf("different arguments should execute: calls = %d", *calls)
+	}
+}
+
+// TestDedupToolCallsOnlyConsecutive verifies only a back-to-back repeat is
+// reused, and that a mutating call in between forces re-execution
+func TestDedupToolCallsOnlyConsecutive(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduper(30*time.Second, &now)
+	endpoint, calls := countingEndpoint(d, func(context.Context, *compose.ToolInput) string { return "ok" })
+	ctx := WithToolCallDedup(context.Background())
+
+	readA := &compose.ToolInput{Name: "read", Arguments: `{"path":"a.go"}`}
+	readB := &compose.ToolInput{Name: "read", Arguments: `{"path":"b.go"}`}
+	endpoint(ctx, readA)
+	endpoint(ctx, readB)
+	endpoint(ctx, readA)
+	if *calls != 3 {
+		t.Errorf("non-consecutive repeat should execute: calls = %d", *calls)
+	}
+
+	edit := &compose.ToolInput{Name: EditToolName, Arguments: `{"path":"a.go"}`}
+	endpoint(ctx, edit)
+	endpoint(ctx, edit)
+	endpoint(ctx, readA)
+	if *calls != 6 {
+		t.Errorf("mutating calls and reads after them should execute: calls = %d", *calls)
+	}
+}
+
+// TestDedupToolCallsScopedToRun verifies results are not shared across runs
+// and that calls outside a run are never deduplicated
+func TestDedupToolCallsScopedToRun(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduper(30*time.Second, &now)
+	endpoint, calls := countingEndpoint(d, func(context.Context, *compose.ToolInput) string { return "ok" })
+
+	in := &compose.ToolInput{Name: "glob", Arguments: `{"pattern":"*.go"}`}
+	endpoint(WithToolCallDedup(context.Background()), in)
+	endpoint(WithToolCallDedup(context.Background()), in)
+	endpoint(context.Background(), in)
+	endpoint(context.Background(), in)
+	if *calls != 4 {
+		t.Errorf("calls in different runs should execute: calls = %d", *calls)
+	}
+}
+
+// TestDedupToolCallsWindowExpiry verifies calls outside the window execute again
+func TestDedupToolCallsWindowExpiry(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduper(10*time.Second, &now)
+	endpoint, calls := countingEndpoint(d, func(context.Context, *compose.ToolInput) string { return "ok" })
+
+	ctx := WithToolCallDedup(context.Background())
+	in := &compose.ToolInput{Name: "grep", Arguments: `{"pattern":"x"}`}
+	endpoint(ctx, in)
+	now = now.Add(11 * time.Second)
+	endpoint(ctx, in)
+	if *calls != 2 {
+		t.Errorf("call after window should execute: calls = %d", *calls)
+	}
+}
+
+// TestDedupToolCallsSkipsIncomplete verifies canceled, failed and partial
+// calls are not reused
+func TestDedupToolCallsSkipsIncomplete(t *testing.T) {
+	now := time.Now()
+	d := newTestDeduper(30*time.Second, &now)
+	var result string
+	endpoint, calls := countingEndpoint(d, func(context.Context, *compose.ToolInput) string { return result })
+
+	run := WithToolCallDedup(context.Background())
+	in := &compose.ToolInput{Name: "fetch", Arguments: `{"url":"https://example.com"}`}
+
+	result = "ok"
+	canceled, cancel := context.WithCancel(run)
+	cancel()
+	endpoint(canceled, in)
+	endpoint(run, in)
+	if *calls != 2 {
+		t.Errorf("canceled call should not be reused: calls = %d", *calls)
+	}
+
+	for _, r := range []string{mustResult(Error("boom")), mustResult(Partial("half", nil))} {
+		result = r
+		before := *calls
+		run := WithToolCallDedup(context.Background())
+		endpoint(run, in)
+		endpoint(run, in)
+		if *calls != before+2 {
+			t.Errorf("result %q should not be reused: calls = %d", r, *calls-before)
+		}
+	}
+}
+
+// TestDedupToolCallsDisabled verifies a zero window disables dedup
+func TestDedupToolCallsDisabled(t *testing.T) {
+	calls := 0
+	endpoint := DedupToolCalls(0).Invokable(func(_ context.Context, _ *compose.ToolInput) (*compose.ToolOutput, error) {
+		calls++
+		return &compose.ToolOutput{Result: "ok"}, nil
+	})
+
+	ctx := WithToolCallDedup(context.Background())
+	in := &compose.ToolInput{Name: "grep", Arguments: `{"pattern":"x"}`}
+	endpoint(ctx, in)
+	endpoint(ctx, in)
+	if calls != 2 {
+		t.Errorf("disabled dedup should execute every call: calls = %d", calls)
+	}
+}
+
+// mustResult returns the formatted result of a result constructor
+func mustResult(s string, _ error) string {
+	return s
+}
@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContextLines is the number of unchanged lines around each hunk
+	diffContextLines = 3
+	// MaxDiffHunks caps the number of hunks included in a diff
+	MaxDiffHunks = 10
+	// maxDiffCells bounds the LCS table; larger changes are shown as a full replacement
+	maxDiffCells = 4_000_000
+)
+
+// diffOp is a single line of an edit script
+type diffOp struct {
+	kind byte // ' ', '-', '+'
+	text string
+	a, b int // 0-based line numbers in old and new
+}
+
+// unifiedDiff returns a unified diff between oldText and newText, limited to
+// MaxDiffHunks hunks. It returns "" when the texts are equal.
+func unifiedDiff(name, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitDiffLines(oldText), splitDiffLines(newText))
+	hunks := groupHunks(ops)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", name, name))
+	for i, h := range hunks {
+		if i == MaxDiffHunks {
+			sb.WriteString(fmt.Sprintf("... %d more hunk(s) not shown\n", len(hunks)-MaxDiffHunks))
+			break
+		}
+		writeHunk(&sb, h)
+	}
+	return sb.String()
+}
+
+// splitDiffLines splits text into lines without their terminators
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes an edit script using the longest common subsequence of
+// the lines between the common prefix and suffix
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: ' ', text: a[i], a: i, b: i})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(midA), len(midB)
+	if n*m > maxDiffCells {
+		for i, line := range midA {
+			ops = append(ops, diffOp{kind: '-', text: line, a: prefix + i, b: prefix})
+		}
+		for j, line := range midB {
+			ops = append(ops, diffOp{kind: '+', text: line, a: prefix + n, b: prefix + j})
+		}
+	} else {
+		// lcs[i][j] is the LCS length of midA[i:] and midB[j:]
+		lcs := make([][]int, n+1)
+		for i := range lcs {
+			lcs[i] = make([]int, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n || j < m {
+			switch {
+			case i < n && j < m && midA[i] == midB[j]:
+				ops = append(ops, diffOp{kind: ' ', text: midA[i], a: prefix + i, b: prefix + j})
+				i++
+				j++
+			case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+				ops = append(ops, diffOp{kind: '+', text: midB[j], a: prefix + i, b: prefix + j})
+				j++
+			default:
+				ops = append(ops, diffOp{kind: '-', text: midA[i], a: prefix + i, b: prefix + j})
+				i++
+			}
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		ai, bi := len(a)-suffix+k, len(b)-suffix+k
+		ops = append(ops, diffOp{kind: ' ', text: a[ai], a: ai, b: bi})
+	}
+	return ops
+}
+
+// groupHunks splits an edit script into hunks with surrounding context
+func groupHunks(ops []diffOp) [][]diffOp {
+	var hunks [][]diffOp
+	start, end := -1, -1
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		lo := max(i-diffContextLines, 0)
+		if start >= 0 && lo > end {
+			hunks = append(hunks, ops[start:end])
+			start = -1
+		}
+		if start < 0 {
+			start = lo
+		}
+		end = min(i+diffContextLines+1, len(ops))
+	}
+	if start >= 0 {
+		hunks = append(hunks, ops[start:end])
+	}
+	return hunks
+}
+
+// writeHunk writes a hunk header and its lines
+func writeHunk(sb *strings.Builder, h []diffOp) {
+	oldCount, newCount := 0, 0
+	for _, op := range h {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	oldStart, newStart := h[0].a+1, h[0].b+1
+	// Unified diff numbers an empty range by the line before it
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+	sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount))
+	for _, op := range h {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.text)
+		sb.WriteByte('\n')
+	}
+}
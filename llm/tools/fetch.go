@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -25,17 +25,21 @@ const (
 	MaxTimeout = 120
 	// MaxReadSize is the maximum response size (5MB)
 	MaxReadSize = int64(5 * 1024 * 1024)
+
+	// fetchUserAgent identifies the fetch tool to servers
+	fetchUserAgent = robotsAgent + "/1.0"
 )
 
 // FetchToolParams defines the arguments for the FetchTool.
 type FetchToolParams struct {
 	URL     string `json:"url" jsonschema:"description=The URL to fetch content from. Must start with http:// or https://"`
-	Format  string `json:"format,omitempty" jsonschema:"description=The format to return the content in (text, markdown, or html). Default is text.,enum=text,enum=markdown,enum=html"`
+	Format  string `json:"format,omitempty" jsonschema:"description=The format to return the content in (text, markdown, html, or json). Default is text.,enum=text,enum=markdown,enum=html,enum=json"`
 	Timeout int    `json:"timeout,omitempty" jsonschema:"description=Optional timeout in seconds (default: 30, max: 120)"`
+	Raw     bool   `json:"raw,omitempty" jsonschema:"description=Return the full content even if the page is very large (default: false)"`
 }
 
 // fetchDescription is the detailed tool description for the AI
-const fetchDescription = `Fetch content from a URL and convert it to text, markdown, or HTML.
+const fetchDescription = `Fetch content from a URL and convert it to text, markdown, HTML, or JSON.
 
 BEFORE USING:
 - Verify the URL is accessible
@@ -45,26 +49,35 @@ BEFORE USING:
 CAPABILITIES:
 - Fetch web pages and extract content
 - Convert HTML to readable text or markdown
+- Pretty-print JSON API responses with a summary of their top-level structure
 - Handle redirects automatically
-- Size limit: 5MB
+- Size limit: 5MB (larger responses are cut and marked [TRUNCATED: ...])
+- Very large pages are summarized automatically (use raw=true for full content)
+- When RESPECT_ROBOTS is enabled, paths disallowed by the site's robots.txt are refused
 
 SUPPORTED FORMATS:
 - text:     Plain text extraction (default)
 - markdown: HTML converted to markdown
 - html:     Raw HTML content
+- json:     Validated, compacted JSON (error if the response is not valid JSON)
 
 PARAMETERS:
 - url (required): The URL to fetch (must start with http:// or https://)
-- format (optional): Output format - text, markdown, or html (default: text)
+- format (optional): Output format - text, markdown, html, or json (default: text)
 - timeout (optional): Timeout in seconds (default: 30, max: 120)
+- raw (optional): Skip automatic summarization of very large pages (default: false)
 
 OUTPUT FORMAT:
-Returns the fetched and formatted content.
+Returns the fetched and formatted content. Pages above the size threshold
+are returned as a summary followed by a note; fetch again with raw=true
+only if the summary lacks what you need.
 
 EXAMPLES:
 - Fetch as markdown: {"url": "https://example.com", "format": "markdown"}
 - Quick text: {"url": "https://example.com", "format": "text"}
-- With timeout: {"url": "https://example.com", "timeout": 60}`
+- With timeout: {"url": "https://example.com", "timeout": 60}
+- Full large page: {"url": "https://example.com/spec", "format": "markdown", "raw": true}
+- REST endpoint: {"url": "https://api.example.com/v1/items", "format": "json"}`
 
 // FetchToolFunc implements the logic for fetching and converting web content.
 func FetchToolFunc(ctx context.Context, params FetchToolParams) (string, error) {
@@ -80,11 +93,11 @@ func FetchToolFunc(ctx context.Context, params FetchToolParams) (string, error)
 	if format == "" {
 		format = "text"
 	}
-	if format != "text" && format != "markdown" && format != "html" {
-		return Error("format must be one of: text, markdown, html")
+	if format != "text" && format != "markdown" && format != "html" && format != "json" {
+		return Error("format must be one of: text, markdown, html, json")
 	}
 
-	// 2. Setup Client with Timeout
+	// 2. Timeout
 	timeout := params.Timeout
 	if timeout <= 0 {
 		timeout = DefaultTimeout
@@ -93,39 +106,35 @@ func FetchToolFunc(ctx context.Context, params FetchToolParams) (string, error)
 		timeout = MaxTimeout
 	}
 
-	client := &http.Client{
-		Timeout: time.Duration(timeout) * time.Second,
+	// robots.txt is checked before the cache so a cached copy never bypasses it
+	if err := checkRobots(ctx, params.URL); err != nil {
+		return Error(err.Error())
 	}
 
-	// 3. Prepare Request
-	req, err := http.NewRequestWithContext(ctx, "GET", params.URL, nil)
-	if err != nil {
-		return Error(fmt.Sprintf("failed to create request: %v", err))
+	// Pages fetched successfully in an earlier run are served from the disk cache
+	cacheInput := FetchToolParams{URL: params.URL, Format: format, Raw: params.Raw}
+	var cached string
+	if globalToolCache.Get(FetchToolName, cacheInput, &cached) {
+		RecordSources(ctx, params.URL)
+		return cached, nil
 	}
-	req.Header.Set("User-Agent", "compass-fetch-tool/1.0")
 
-	// 4. Execute Request
-	startTime := time.Now()
-	resp, err := client.Do(req)
+	// 3. Download with size limit
+	page, err := fetchPage(ctx, params.URL, timeout)
 	if err != nil {
-		return Error(fmt.Sprintf("failed to fetch URL: %v", err))
+		return Error(err.Error())
 	}
-	defer resp.Body.Close()
+	content, contentType := page.body, page.contentType
+	truncated, omittedBytes := page.truncated, page.omittedBytes
 
-	// 5. Read Body with Size Limit
-	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, MaxReadSize))
-	if err != nil {
-		return Error(fmt.Sprintf("failed to read response: %v", err))
-	}
-
-	content := string(bodyBytes)
-	truncated := int64(len(content)) >= MaxReadSize
-
-	// 6. Format Conversion
-	contentType := resp.Header.Get("Content-Type")
+	// 4. Format Conversion
 	switch format {
 	case "text":
-		if strings.Contains(contentType, "text/html") {
+		if isJSONContentType(contentType) {
+			if structure, pretty, ok := prettyJSON(content); ok {
+				content = structure + "\n\n" + pretty
+			}
+		} else if strings.Contains(contentType, "text/html") {
 			text, err := extractTextFromHTML(content)
 			if err != nil {
 				return Error(fmt.Sprintf("failed to extract text: %v", err))
@@ -134,7 +143,11 @@ func FetchToolFunc(ctx context.Context, params FetchToolParams) (string, error)
 		}
 
 	case "markdown":
-		if strings.Contains(contentType, "text/html") {
+		if isJSONContentType(contentType) {
+			if structure, pretty, ok := prettyJSON(content); ok {
+				content = structure + "\n\n```json\n" + pretty + "\n```"
+			}
+		} else if strings.Contains(contentType, "text/html") {
 			markdown, err := convertHTMLToMarkdown(content)
 			if err != nil {
 				return Error(fmt.Sprintf("failed to convert to markdown: %v", err))
@@ -153,23 +166,104 @@ func FetchToolFunc(ctx context.Context, params FetchToolParams) (string, error)
 				content = "<html>\n<body>\n" + body + "\n</body>\n</html>"
 			}
 		}
+
+	case "json":
+		if truncated {
+			return Error(fmt.Sprintf("response exceeds the %d byte limit and cannot be returned as JSON", MaxReadSize))
+		}
+		compact, err := compactJSON(content)
+		if err != nil {
+			return Error(err.Error())
+		}
+		content = compact
 	}
 
+	// 5. Summarize oversized pages instead of overwhelming the model; JSON output stays parseable
+	if format != "json" && shouldSummarizePage(content, params.Raw) {
+		summary, err := pageSummarizer(ctx, params.URL, content)
+		if err != nil {
+			slog.Warn("failed to summarize page, returning full content", "url", params.URL, "err", err)
+		} else {
+			content = summary + fmt.Sprintf("\n\n[Page content is %d bytes (limit %d), so this is a summary. "+
+				"Call fetch with raw=true for the full content.]", len(content), fetchSummarizeThreshold)
+		}
+	}
+
+	// Added after summarizing so the note survives; a summary of a cut page is still partial
 	if truncated {
-		content += fmt.Sprintf("\n\n[Content truncated to %d bytes]", MaxReadSize)
+		content += "\n\n" + truncationNote(omittedBytes,
+			fmt.Sprintf("only the first %d bytes of the response were read", MaxReadSize))
 	}
 
-	duration := time.Since(startTime)
+	RecordSources(ctx, params.URL)
 
-	if resp.StatusCode != http.StatusOK {
+	if page.statusCode != http.StatusOK {
 		return Partial(content, &Metadata{
-			URL:        params.URL,
-			StatusCode: resp.StatusCode,
-			Duration:   duration.Milliseconds(),
+			URL:          params.URL,
+			StatusCode:   page.statusCode,
+			Duration:     page.duration.Milliseconds(),
+			Truncated:    truncated,
+			OmittedBytes: omittedBytes,
 		})
 	}
 
-	return FetchSuccess(content, params.URL, resp.StatusCode)
+	out, err := FetchSuccess(content, params.URL, page.statusCode, truncated, omittedBytes)
+	globalToolCache.Put(FetchToolName, cacheInput, out)
+	return out, err
+}
+
+// fetchedPage is a downloaded response body, cut to MaxReadSize
+type fetchedPage struct {
+	body         string
+	contentType  string
+	statusCode   int
+	truncated    bool
+	omittedBytes int // Bytes beyond MaxReadSize, when the server reported a length
+	duration     time.Duration
+}
+
+// fetchPage downloads rawURL with a timeout in seconds, reading at most MaxReadSize bytes.
+// With RESPECT_ROBOTS=1 it refuses paths disallowed by the host's robots.txt.
+func fetchPage(ctx context.Context, rawURL string, timeout int) (*fetchedPage, error) {
+	if err := checkRobots(ctx, rawURL); err != nil {
+		return nil, err
+	}
+
+	client := newHTTPClient(time.Duration(timeout) * time.Second)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("User-Agent", fetchUserAgent)
+
+	startTime := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Read one extra byte to tell a body of exactly MaxReadSize from a cut one
+	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, MaxReadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	page := &fetchedPage{
+		contentType: resp.Header.Get("Content-Type"),
+		statusCode:  resp.StatusCode,
+	}
+	if int64(len(bodyBytes)) > MaxReadSize {
+		bodyBytes = bodyBytes[:MaxReadSize]
+		page.truncated = true
+		if resp.ContentLength > MaxReadSize {
+			page.omittedBytes = int(resp.ContentLength - MaxReadSize)
+		}
+	}
+	page.body = string(bodyBytes)
+	page.duration = time.Since(startTime)
+	return page, nil
 }
 
 func extractTextFromHTML(html string) (string, error) {
@@ -203,14 +297,14 @@ func convertHTMLToMarkdown(html string) (string, error) {
 }
 
 // GetFetchTool returns the fetch tool with enhanced description.
-func GetFetchTool() tool.InvokableTool {
+func GetFetchTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(
 		FetchToolName,
 		fetchDescription,
 		FetchToolFunc,
 	)
 	if err != nil {
-		log.Fatalf("failed to create fetch tool: %v", err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", FetchToolName, err)
 	}
-	return t
+	return t, nil
 }
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+)
+
+// maxStructureKeys caps the top-level keys listed in a JSON structure summary
+const maxStructureKeys = 20
+
+// isJSONContentType reports whether a Content-Type header denotes JSON,
+// including vendor types such as application/problem+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// compactJSON validates body and removes insignificant whitespace
+func compactJSON(body string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(body)); err != nil {
+		return "", fmt.Errorf("response is not valid JSON: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// prettyJSON indents body and prefixes a one-line summary of its top-level
+// structure. Invalid JSON is returned unchanged with ok false.
+func prettyJSON(body string) (structure, pretty string, ok bool) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(body), "", "  "); err != nil {
+		return "", body, false
+	}
+	return jsonStructure([]byte(body)), buf.String(), true
+}
+
+// jsonStructure describes the top-level value: the keys of an object, the
+// length and element kind of an array, or the kind of a scalar
+func jsonStructure(data []byte) string {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return ""
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		desc := make([]string, 0, min(len(keys), maxStructureKeys))
+		for _, k := range keys[:min(len(keys), maxStructureKeys)] {
+			desc = append(desc, fmt.Sprintf("%s (%s)", k, jsonKind(v[k])))
+		}
+		if len(keys) > maxStructureKeys {
+			desc = append(desc, fmt.Sprintf("... %d more", len(keys)-maxStructureKeys))
+		}
+		return fmt.Sprintf("JSON object with %d keys: %s", len(keys), strings.Join(desc, ", "))
+	case []any:
+		if len(v) == 0 {
+			return "JSON array with 0 items"
+		}
+		return fmt.Sprintf("JSON array with %d items of %s", len(v), jsonKind(v[0]))
+	default:
+		return "JSON " + jsonKind(v)
+	}
+}
+
+// jsonKind names the kind of a decoded JSON value
+func jsonKind(v any) string {
+	switch v := v.(type) {
+	case map[string]any:
+		return fmt.Sprintf("object, %d keys", len(v))
+	case []any:
+		return fmt.Sprintf("array, %d items", len(v))
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
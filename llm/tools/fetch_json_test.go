@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newJSONServer serves body with a JSON content type
+func newJSONServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestFetchJSONPretty verifies JSON responses are indented with a structure summary
+func TestFetchJSONPretty(t *testing.T) {
+	srv := newJSONServer(t, `{"items":[{"id":1},{"id":2}],"total":2,"next":null}`)
+
+	out, _ := FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL + "/pretty", Format: "markdown"})
+	if !strings.Contains(out, "JSON object with 3 keys: items (array, 2 items), next (null), total (number)") {
+		t.Errorf("missing structure summary:\n%s", out)
+	}
+	if !strings.Contains(out, "```json\n{\n  \"items\": [") {
+		t.Errorf("JSON should be indented in a code block:\n%s", out)
+	}
+
+	out, _ = FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL + "/pretty"})
+	if strings.Contains(out, "```") || !strings.Contains(out, "  \"total\": 2") {
+		t.Errorf("text format should indent without a code block:\n%s", out)
+	}
+}
+
+// TestFetchJSONFormat verifies format=json compacts valid JSON and rejects anything else
+func TestFetchJSONFormat(t *testing.T) {
+	srv := newJSONServer(t, "{\n  \"ok\": true,\n  \"tags\": [\"a\", \"b\"]\n}")
+	out, _ := FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL + "/compact", Format: "json"})
+	if !strings.Contains(out, `{"ok":true,"tags":["a","b"]}`) {
+		t.Errorf("expected compact JSON:\n%s", out)
+	}
+
+	bad := newJSONServer(t, "<html>not json</html>")
+	out, _ = FetchToolFunc(context.Background(), FetchToolParams{URL: bad.URL + "/bad", Format: "json"})
+	if !strings.Contains(out, "ERROR") || !strings.Contains(out, "not valid JSON") {
+		t.Errorf("invalid JSON should be an error:\n%s", out)
+	}
+}
+
+// TestJSONStructure verifies arrays and scalars are described
+func TestJSONStructure(t *testing.T) {
+	tests := map[string]string{
+		`[{"a":1},{"a":2}]`: "JSON array with 2 items of object, 1 keys",
+		`[]`:                "JSON array with 0 items",
+		`"hi"`:              "JSON string",
+	}
+	for in, want := range tests {
+		if got := jsonStructure([]byte(in)); got != want {
+			t.Errorf("jsonStructure(%s) = %q, want %q", in, got, want)
+		}
+	}
+	if !isJSONContentType("application/problem+json") || isJSONContentType("text/html") {
+		t.Error("isJSONContentType misclassified a content type")
+	}
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// DefaultFetchSummarizeThreshold is the content size above which fetched
+	// pages are summarized instead of returned verbatim (100KB)
+	DefaultFetchSummarizeThreshold = 100 * 1024
+
+	// maxSummarizeInput caps the text sent to the summary model
+	maxSummarizeInput = 200 * 1024
+)
+
+// PageSummarizer condenses oversized fetched content
+type PageSummarizer func(ctx context.Context, url, content string) (string, error)
+
+var (
+	// pageSummarizer summarizes oversized pages, nil disables summarization
+	pageSummarizer PageSummarizer
+	// fetchSummarizeThreshold is read from FETCH_SUMMARIZE_THRESHOLD (bytes, 0 disables)
+	fetchSummarizeThreshold = getFetchSummarizeThreshold()
+)
+
+// SetPageSummarizer installs the summarizer used by fetch for oversized pages
+func SetPageSummarizer(s PageSummarizer) {
+	pageSummarizer = s
+}
+
+// getFetchSummarizeThreshold reads the summarize threshold from environment
+func getFetchSummarizeThreshold() int {
+	if val := os.Getenv("FETCH_SUMMARIZE_THRESHOLD"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			return n
+		}
+	}
+	return DefaultFetchSummarizeThreshold
+}
+
+// pageSummaryPrompt instructs the summary model for oversized pages
+const pageSummaryPrompt = `You condense long web pages for another assistant that cannot read them in full.
+Summarize the page below in markdown, preserving:
+- The main topic and purpose
+- Key facts, figures, versions, commands, API names and configuration options
+- Section structure (use the page's headings)
+Skip navigation, ads and boilerplate. Do not invent information. Aim for 300-600 words.`
+
+// NewModelPageSummarizer returns a PageSummarizer backed by a chat model
+func NewModelPageSummarizer(m model.BaseChatModel) PageSummarizer {
+	return func(ctx context.Context, url, content string) (string, error) {
+		if len(content) > maxSummarizeInput {
+			content = content[:maxSummarizeInput]
+		}
+		resp, err := m.Generate(ctx, []*schema.Message{
+			schema.SystemMessage(pageSummaryPrompt),
+			schema.UserMessage(fmt.Sprintf("URL: %s\n\n%s", url, content)),
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Content, nil
+	}
+}
+
+// shouldSummarizePage reports whether fetched content should be summarized
+func shouldSummarizePage(content string, raw bool) bool {
+	return !raw && pageSummarizer != nil && fetchSummarizeThreshold > 0 && len(content) > fetchSummarizeThreshold
+}
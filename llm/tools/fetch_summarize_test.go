@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// useStubSummarizer installs a summarizer that records its calls
+func useStubSummarizer(t *testing.T, threshold int, err error) *int {
+	t.Helper()
+	calls := 0
+	prevSummarizer, prevThreshold := pageSummarizer, fetchSummarizeThreshold
+	SetPageSummarizer(func(ctx context.Context, url, content string) (string, error) {
+		calls++
+		return "STUB SUMMARY", err
+	})
+	fetchSummarizeThreshold = threshold
+	t.Cleanup(func() {
+		pageSummarizer, fetchSummarizeThreshold = prevSummarizer, prevThreshold
+	})
+	return &calls
+}
+
+// newPageServer serves a text page of the given size
+func newPageServer(t *testing.T, size int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestFetchSummarizesOversizedPage verifies the summarized path above the threshold
+func TestFetchSummarizesOversizedPage(t *testing.T) {
+	calls := useStubSummarizer(t, 1000, nil)
+	srv := newPageServer(t, 50000)
+
+	out, _ := FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL})
+	if *calls != 1 {
+		t.Fatalf("summarizer calls = %d, want 1", *calls)
+	}
+	if !strings.Contains(out, "STUB SUMMARY") || !strings.Contains(out, "raw=true") {
+		t.Errorf("expected summary with raw hint:\n%s", out)
+	}
+	if strings.Contains(out, strings.Repeat("a", 2000)) {
+		t.Error("summarized output should not include the raw page")
+	}
+
+	out, _ = FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL, Raw: true})
+	if *calls != 1 || !strings.Contains(out, strings.Repeat("a", 50000)) {
+		t.Errorf("raw mode should return full content without summarizing (calls = %d)", *calls)
+	}
+}
+
+// TestFetchBelowThreshold verifies small pages are returned verbatim
+func TestFetchBelowThreshold(t *testing.T) {
+	calls := useStubSummarizer(t, 1000, nil)
+	srv := newPageServer(t, 500)
+
+	out, _ := FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL})
+	if *calls != 0 || !strings.Contains(out, strings.Repeat("a", 500)) {
+		t.Errorf("small page should not be summarized (calls = %d):\n%s", *calls, out)
+	}
+}
+
+// TestFetchSummarizeFailure verifies the full content is returned when summarizing fails
+func TestFetchSummarizeFailure(t *testing.T) {
+	useStubSummarizer(t, 1000, errors.New("model unavailable"))
+	srv := newPageServer(t, 5000)
+
+	out, _ := FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL})
+	if !strings.Contains(out, strings.Repeat("a", 5000)) {
+		t.Errorf("expected full content fallback:\n%.200s", out)
+	}
+}
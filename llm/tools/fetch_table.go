@@ -0,0 +1,308 @@
+package tools
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const (
+	// FetchTableToolName is the name of the table extraction tool
+	FetchTableToolName = "fetch_table"
+
+	// maxTableColspan caps colspan expansion for malformed tables
+	maxTableColspan = 50
+)
+
+// FetchTableParams defines the arguments for the fetch_table tool.
+type FetchTableParams struct {
+	URL     string `json:"url" jsonschema:"description=The URL of the page containing the table. Must start with http:// or https://"`
+	Index   int    `json:"index,omitempty" jsonschema:"description=1-based index of the table to extract (default: all tables)"`
+	Heading string `json:"heading,omitempty" jsonschema:"description=Select tables whose nearest preceding heading contains this text (case-insensitive)"`
+	Format  string `json:"format,omitempty" jsonschema:"description=Output format: csv or markdown (default: markdown),enum=csv,enum=markdown"`
+	Timeout int    `json:"timeout,omitempty" jsonschema:"description=Optional timeout in seconds (default: 30, max: 120)"`
+}
+
+// HTMLTable is a table extracted from an HTML page
+type HTMLTable struct {
+	Index   int        // 1-based position among all tables on the page
+	Heading string     // Nearest preceding heading text
+	Rows    [][]string // Cell text, padded to the same column count
+}
+
+// Columns returns the number of columns in the table
+func (t HTMLTable) Columns() int {
+	if len(t.Rows) == 0 {
+		return 0
+	}
+	return len(t.Rows[0])
+}
+
+// fetchTableDescription is the detailed tool description for the AI
+const fetchTableDescription = `Fetch a web page and extract its <table> elements as CSV or markdown.
+
+BEFORE USING:
+- Use this when you need tabular data (versions, benchmarks, pricing, specs)
+- Use fetch for prose content
+
+CAPABILITIES:
+- Extract all tables, or one table by index
+- Select tables by the text of their nearest preceding heading
+- Output as markdown tables or CSV
+- Reports row and column counts
+
+PARAMETERS:
+- url (required): The page URL (must start with http:// or https://)
+- index (optional): 1-based table index (default: all tables)
+- heading (optional): Match tables under a heading containing this text
+- format (optional): csv or markdown (default: markdown)
+- timeout (optional): Timeout in seconds (default: 30, max: 120)
+
+OUTPUT FORMAT:
+Each table is preceded by "Table N" and its heading, followed by the data.
+
+EXAMPLES:
+- All tables: {"url": "https://go.dev/doc/devel/release"}
+- Second table as CSV: {"url": "https://example.com/stats", "index": 2, "format": "csv"}
+- By heading: {"url": "https://example.com/pricing", "heading": "Enterprise"}`
+
+// FetchTableFunc fetches a page and extracts its tables.
+func FetchTableFunc(ctx context.Context, params FetchTableParams) (string, error) {
+	if params.URL == "" {
+		return Error("URL parameter is required")
+	}
+	u, err := url.Parse(params.URL)
+	if err != nil {
+		return Error(fmt.Sprintf("invalid URL: %v", err))
+	}
+	if err := validateURLTarget(ctx, u); err != nil {
+		return Error(err.Error())
+	}
+	if err := checkRobots(ctx, params.URL); err != nil {
+		return Error(err.Error())
+	}
+
+	format := strings.ToLower(params.Format)
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "csv" && format != "markdown" {
+		return Error("format must be one of: csv, markdown")
+	}
+
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if timeout > MaxTimeout {
+		timeout = MaxTimeout
+	}
+
+	client := newHTTPClient(time.Duration(timeout) * time.Second)
+	client.CheckRedirect = checkRedirectTarget
+	req, err := http.NewRequestWithContext(ctx, "GET", params.URL, nil)
+	if err != nil {
+		return Error(fmt.Sprintf("failed to create request: %v", err))
+	}
+	req.Header.Set("User-Agent", "compass-fetch-tool/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Error(fmt.Sprintf("failed to fetch URL: %v", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Error(fmt.Sprintf("fetch failed with status code: %d", resp.StatusCode))
+	}
+
+	tables, err := extractHTMLTables(io.LimitReader(resp.Body, MaxReadSize))
+	if err != nil {
+		return Error(fmt.Sprintf("failed to parse HTML: %v", err))
+	}
+	if len(tables) == 0 {
+		return Success("No tables found on the page", &Metadata{URL: params.URL}, TierCompact)
+	}
+
+	selected := selectTables(tables, params.Index, params.Heading)
+	if len(selected) == 0 {
+		return Error(fmt.Sprintf("no table matches the selection (page has %d tables)", len(tables)))
+	}
+
+	var sb strings.Builder
+	rows, cols := 0, 0
+	for i, t := range selected {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("Table %d", t.Index))
+		if t.Heading != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", t.Heading))
+		}
+		sb.WriteString(fmt.Sprintf(": %d rows x %d columns\n", len(t.Rows), t.Columns()))
+
+		if format == "csv" {
+			sb.WriteString(tableToCSV(t))
+		} else {
+			sb.WriteString(tableToMarkdown(t))
+		}
+
+		rows += len(t.Rows)
+		if t.Columns() > cols {
+			cols = t.Columns()
+		}
+	}
+
+	md := &Metadata{
+		URL:         params.URL,
+		StatusCode:  resp.StatusCode,
+		MatchCount:  len(selected),
+		RowCount:    rows,
+		ColumnCount: cols,
+	}
+	recordMetadataSources(ctx, md)
+
+	return Success(sb.String(), md, TierCompact)
+}
+
+// extractHTMLTables parses all tables from an HTML document, recording the
+// nearest preceding heading of each.
+func extractHTMLTables(r io.Reader) ([]HTMLTable, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []HTMLTable
+	heading := ""
+	doc.Find("h1, h2, h3, h4, h5, h6, table").Each(func(_ int, s *goquery.Selection) {
+		if goquery.NodeName(s) != "table" {
+			heading = cellText(s)
+			return
+		}
+		tables = append(tables, HTMLTable{
+			Index:   len(tables) + 1,
+			Heading: heading,
+			Rows:    parseTableRows(s),
+		})
+	})
+
+	return tables, nil
+}
+
+// parseTableRows reads the rows of a table, ignoring rows of nested tables
+func parseTableRows(table *goquery.Selection) [][]string {
+	var rows [][]string
+	width := 0
+
+	table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		if !tr.Closest("table").IsSelection(table) {
+			return
+		}
+		var row []string
+		tr.ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+			text := cellText(cell)
+			span, _ := strconv.Atoi(cell.AttrOr("colspan", "1"))
+			if span < 1 {
+				span = 1
+			}
+			if span > maxTableColspan {
+				span = maxTableColspan
+			}
+			for i := 0; i < span; i++ {
+				row = append(row, text)
+			}
+		})
+		if len(row) == 0 {
+			return
+		}
+		if len(row) > width {
+			width = len(row)
+		}
+		rows = append(rows, row)
+	})
+
+	// Pad ragged rows
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], "")
+		}
+	}
+	return rows
+}
+
+// cellText returns the whitespace-normalized text of a node
+func cellText(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// selectTables filters tables by 1-based index and/or heading text
+func selectTables(tables []HTMLTable, index int, heading string) []HTMLTable {
+	heading = strings.ToLower(strings.TrimSpace(heading))
+
+	var selected []HTMLTable
+	for _, t := range tables {
+		if index > 0 && t.Index != index {
+			continue
+		}
+		if heading != "" && !strings.Contains(strings.ToLower(t.Heading), heading) {
+			continue
+		}
+		selected = append(selected, t)
+	}
+	return selected
+}
+
+// tableToCSV renders a table as CSV
+func tableToCSV(t HTMLTable) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.WriteAll(t.Rows)
+	return sb.String()
+}
+
+// tableToMarkdown renders a table as a markdown table, using the first row as header
+func tableToMarkdown(t HTMLTable) string {
+	if len(t.Rows) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for _, cell := range row {
+			sb.WriteString(" " + strings.ReplaceAll(cell, "|", "\\|") + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(t.Rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", t.Columns()) + "\n")
+	for _, row := range t.Rows[1:] {
+		writeRow(row)
+	}
+	return sb.String()
+}
+
+// GetFetchTableTool returns the table extraction tool.
+func GetFetchTableTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(
+		FetchTableToolName,
+		fetchTableDescription,
+		FetchTableFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", FetchTableToolName, err)
+	}
+	return t, nil
+}
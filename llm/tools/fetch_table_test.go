@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestExtractTableCSV verifies a specific table is extracted from the fixture as CSV
+func TestExtractTableCSV(t *testing.T) {
+	f, err := os.Open("testdata/tables.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tables, err := extractHTMLTables(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(tables))
+	}
+
+	selected := selectTables(tables, 0, "benchmarks")
+	if len(selected) != 1 || selected[0].Index != 2 {
+		t.Fatalf("expected heading selector to pick table 2, got %+v", selected)
+	}
+
+	want := "Name,ns/op,Notes\n" +
+		"Encode,\"1,204\",\"uses \"\"fast\"\" path\"\n" +
+		"Decode,987,\n" +
+		"Summary row,Summary row,Summary row\n"
+	if got := tableToCSV(selected[0]); got != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+	if selected[0].Columns() != 3 || len(selected[0].Rows) != 4 {
+		t.Errorf("unexpected dimensions: %d rows x %d columns", len(selected[0].Rows), selected[0].Columns())
+	}
+}
+
+// TestFetchTableFunc verifies fetching, index selection and row/column metadata
+func TestFetchTableFunc(t *testing.T) {
+	allowPrivateNetworks = true
+	defer func() { allowPrivateNetworks = false }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/tables.html")
+	}))
+	defer srv.Close()
+
+	out, _ := FetchTableFunc(context.Background(), FetchTableParams{URL: srv.URL, Index: 1})
+	for _, want := range []string{"Table 1 (Go Releases): 3 rows x 2 columns", "| Version | Date |", "| 1.23 | 2024-08-13 |"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Encode") {
+		t.Errorf("output should only contain table 1:\n%s", out)
+	}
+
+	out, _ = FetchTableFunc(context.Background(), FetchTableParams{URL: srv.URL, Index: 5})
+	if !strings.Contains(out, "ERROR") {
+		t.Errorf("expected error for out-of-range index:\n%s", out)
+	}
+}
+
+// TestFetchTableFuncRedirect verifies redirects are checked against the URL guard
+func TestFetchTableFuncRedirect(t *testing.T) {
+	allowPrivateNetworks = true
+	blockedDomains = []string{"blocked.example"}
+	defer func() {
+		allowPrivateNetworks = false
+		blockedDomains = nil
+	}()
+
+	srv := httptest.NewServer(http.RedirectHandler("http://blocked.example/tables", http.StatusFound))
+	defer srv.Close()
+
+	out, _ := FetchTableFunc(context.Background(), FetchTableParams{URL: srv.URL})
+	if !strings.Contains(out, "domain blocked.example is blocked") {
+		t.Errorf("redirect to a blocked domain should fail:\n%s", out)
+	}
+}
@@ -12,4 +12,14 @@ const (
 	EditToolName = "edit"
 	// DeleteToolName deletes files
 	DeleteToolName = "delete"
+	// MoveToolName moves or renames files
+	MoveToolName = "move"
+	// CopyToolName copies files
+	CopyToolName = "copy"
+	// RestoreToolName restores soft-deleted files from the trash
+	RestoreToolName = "restore"
+	// StatToolName shows file information
+	StatToolName = "stat"
+	// MakeDirToolName creates directories
+	MakeDirToolName = "mkdir"
 )
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// CopyFileParams defines parameters for copying a file.
+type CopyFileParams struct {
+	Source      string `json:"source" jsonschema:"description=The path of the file to copy"`
+	Destination string `json:"destination" jsonschema:"description=The path of the copy"`
+	Overwrite   bool   `json:"overwrite,omitempty" jsonschema:"description=Replace the destination if it already exists (default: false)"`
+}
+
+// copyDescription is the detailed tool description for the AI
+const copyDescription = `Copy a file to a new path.
+
+BEFORE USING:
+- Verify the source path is correct
+- Use this to back up a file before risky edits
+
+CAPABILITIES:
+- Copy a single file, preserving its permissions
+- Creates missing parent directories of the destination
+- Refuses to replace an existing destination unless overwrite is set
+- Cannot copy directories (use bash tool for that)
+- Protected files cannot be copied (.env, .git)
+
+PARAMETERS:
+- source (required): The path of the file to copy
+- destination (required): The path of the copy
+- overwrite (optional): Replace an existing destination file (default: false)
+
+OUTPUT FORMAT:
+Returns confirmation with the source and destination paths and bytes copied.
+
+EXAMPLES:
+- Backup: {"source": "main.go", "destination": "main.go.bak"}
+- Copy into directory: {"source": "config.yaml", "destination": "backup/config.yaml"}
+
+SECURITY:
+- Copying .env, .git files is blocked`
+
+// CopyFileFunc copies a file.
+func CopyFileFunc(ctx context.Context, params CopyFileParams) (string, error) {
+	if params.Source == "" || params.Destination == "" {
+		return Error("source and destination parameters are required")
+	}
+
+	// Security check
+	for _, p := range []string{params.Source, params.Destination} {
+		if base, ok := protectedFileName(p); ok {
+			return Error(fmt.Sprintf("copying %s is not allowed for security reasons", base))
+		}
+	}
+
+	src := resolvePath(ctx, params.Source)
+	dst := resolvePath(ctx, params.Destination)
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return Error(fmt.Sprintf("file not found: %v", err))
+	}
+	if info.IsDir() {
+		return Error(fmt.Sprintf("%s is a directory, use bash to copy directories", params.Source))
+	}
+
+	if dstInfo, err := os.Stat(dst); err == nil {
+		if os.SameFile(info, dstInfo) {
+			return Error("source and destination are the same file")
+		}
+		if !params.Overwrite {
+			return Error(fmt.Sprintf("destination already exists: %s (set overwrite to replace it)", params.Destination))
+		}
+	}
+
+	if IsReadOnly(ctx) {
+		absSrc, _ := filepath.Abs(src)
+		absDst, _ := filepath.Abs(dst)
+		return Simulated(fmt.Sprintf("File copied: %s -> %s (%d bytes)", absSrc, absDst, info.Size()), &Metadata{
+			FilePath:  absDst,
+			ByteCount: int(info.Size()),
+		})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return Error(fmt.Sprintf("failed to create destination directory: %v", err))
+	}
+
+	n, err := copyFile(src, dst, info.Mode())
+	if err != nil {
+		return Error(fmt.Sprintf("failed to copy file: %v", err))
+	}
+
+	absSrc, _ := filepath.Abs(src)
+	absDst, _ := filepath.Abs(dst)
+	return CopyFileSuccess(absSrc, absDst, int(n))
+}
+
+// copyFile copies src to dst with the given permissions and returns the bytes copied
+func copyFile(src, dst string, mode os.FileMode) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if err != nil {
+		out.Close()
+		os.Remove(dst)
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, err
+	}
+	// OpenFile only applies the mode to new files and is subject to umask
+	return n, os.Chmod(dst, mode.Perm())
+}
+
+// GetCopyFileTool returns the copy file tool.
+func GetCopyFileTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(CopyToolName, copyDescription, CopyFileFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", CopyToolName, err)
+	}
+	return t, nil
+}
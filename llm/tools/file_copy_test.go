@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestCopyFile verifies mode preservation, parent creation, byte count and overwrite protection
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	src := writeTestFile(t, dir, "run.sh", "#!/bin/sh\necho hi\n")
+	if err := os.Chmod(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(dir, "backup", "run.sh")
+
+	out, _ := CopyFileFunc(ctx, CopyFileParams{Source: src, Destination: dst})
+	if strings.Contains(out, "ERROR") {
+		t.Fatalf("copy failed:\n%s", out)
+	}
+	if !strings.Contains(out, "(18 bytes)") {
+		t.Errorf("expected byte count in output:\n%s", out)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "#!/bin/sh\necho hi\n" {
+		t.Errorf("copied content = %q", data)
+	}
+	if info, err := os.Stat(dst); err != nil || (runtime.GOOS != "windows" && info.Mode().Perm() != 0755) {
+		t.Errorf("file mode not preserved: %v %v", info.Mode(), err)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Error("source should still exist")
+	}
+
+	writeTestFile(t, dir, "run.sh", "changed\n")
+	out, _ = CopyFileFunc(ctx, CopyFileParams{Source: src, Destination: dst})
+	if !strings.Contains(out, "already exists") {
+		t.Errorf("expected overwrite protection:\n%s", out)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "#!/bin/sh\necho hi\n" {
+		t.Errorf("destination clobbered: %q", data)
+	}
+
+	out, _ = CopyFileFunc(ctx, CopyFileParams{Source: src, Destination: dst, Overwrite: true})
+	if data, _ := os.ReadFile(dst); strings.Contains(out, "ERROR") || string(data) != "changed\n" {
+		t.Errorf("overwrite failed: %q\n%s", data, out)
+	}
+
+	out, _ = CopyFileFunc(ctx, CopyFileParams{Source: src, Destination: src, Overwrite: true})
+	if !strings.Contains(out, "same file") {
+		t.Errorf("copying onto itself should be refused:\n%s", out)
+	}
+}
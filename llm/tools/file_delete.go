@@ -3,9 +3,9 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/components/tool/utils"
@@ -13,7 +13,8 @@ import (
 
 // DeleteFileParams defines parameters for deleting a file.
 type DeleteFileParams struct {
-	Path string `json:"path" jsonschema:"description=The path of the file to delete"`
+	Path       string `json:"path" jsonschema:"description=The path of the file to delete"`
+	SoftDelete *bool  `json:"soft_delete,omitempty" jsonschema:"description=Move the file to the .trash directory so it can be restored (default: true). Set false to delete permanently"`
 }
 
 // deleteDescription is the detailed tool description for the AI
@@ -25,18 +26,20 @@ BEFORE USING:
 
 CAPABILITIES:
 - Delete individual files
+- By default files are moved to the .trash directory and can be brought back with the restore tool
 - Cannot delete directories (use bash tool for that)
 - Protected files cannot be deleted (.env, .git)
 
 PARAMETERS:
 - path (required): The path of the file to delete
+- soft_delete (optional): Move to .trash instead of deleting permanently (default: true)
 
 OUTPUT FORMAT:
-Returns confirmation with the file path deleted.
+Returns confirmation with the file path deleted and, for soft deletes, the trash id.
 
 EXAMPLES:
 - Delete file: {"path": "temp.txt"}
-- Delete specific: {"path": "output.log"}
+- Delete permanently: {"path": "output.log", "soft_delete": false}
 
 SECURITY:
 - Deleting .env, .git files is blocked`
@@ -44,25 +47,82 @@ SECURITY:
 // DeleteFileFunc deletes a file.
 func DeleteFileFunc(ctx context.Context, params DeleteFileParams) (string, error) {
 	// Security check
-	base := filepath.Base(params.Path)
-	if base == ".env" || base == ".git" {
+	if base, ok := protectedFileName(params.Path); ok {
 		return Error(fmt.Sprintf("deleting %s is not allowed for security reasons", base))
 	}
 
-	err := os.Remove(params.Path)
+	path := resolvePath(ctx, params.Path)
+	absPath, _ := filepath.Abs(path)
+
+	soft := SoftDeleteFromEnv()
+	if params.SoftDelete != nil {
+		soft = *params.SoftDelete
+	}
+	if IsReadOnly(ctx) {
+		return simulateDelete(path, absPath, soft && !isInTrash(ctx, absPath))
+	}
+	// 回收站内的文件直接删除
+	if soft && !isInTrash(ctx, absPath) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to delete file: %v", err))
+		}
+		if info.IsDir() {
+			return Error(fmt.Sprintf("%s is a directory, use bash to delete directories", params.Path))
+		}
+		entry, err := moveToTrash(ctx, path, info)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to move file to trash: %v", err))
+		}
+		return SoftDeleteFileSuccess(absPath, entry.ID)
+	}
+
+	err := os.Remove(path)
 	if err != nil {
 		return Error(fmt.Sprintf("failed to delete file: %v", err))
 	}
-
-	absPath, _ := filepath.Abs(params.Path)
 	return DeleteFileSuccess(absPath)
 }
 
+// simulateDelete describes a delete in read-only mode
+func simulateDelete(path, absPath string, soft bool) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Error(fmt.Sprintf("failed to delete file: %v", err))
+	}
+	if info.IsDir() && soft {
+		return Error(fmt.Sprintf("%s is a directory, use bash to delete directories", path))
+	}
+	content := fmt.Sprintf("File deleted: %s", absPath)
+	if soft {
+		content += fmt.Sprintf(" (moved to %s)", TrashDirName)
+	}
+	return Simulated(content, &Metadata{
+		FilePath:    absPath,
+		ByteCount:   int(info.Size()),
+		SoftDeleted: soft,
+	})
+}
+
+// isInTrash reports whether absPath lies inside the session trash directory
+func isInTrash(ctx context.Context, absPath string) bool {
+	dir, _ := filepath.Abs(trashDir(ctx))
+	rel, err := filepath.Rel(dir, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// protectedFileName reports whether path names a sensitive file (.env, .git)
+// that file tools must not delete or move
+func protectedFileName(path string) (string, bool) {
+	base := filepath.Base(path)
+	return base, base == ".env" || base == ".git"
+}
+
 // GetDeleteFileTool returns the delete file tool.
-func GetDeleteFileTool() tool.InvokableTool {
+func GetDeleteFileTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(DeleteToolName, deleteDescription, DeleteFileFunc)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", DeleteToolName, err)
 	}
-	return t
+	return t, nil
 }
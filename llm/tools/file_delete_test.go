@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDeleteFileSoft verifies soft deletes move the file to the trash and restore brings it back
+func TestDeleteFileSoft(t *testing.T) {
+	dir := t.TempDir()
+	ctx := WithWorkDir(context.Background(), dir)
+	src := writeTestFile(t, dir, "a.txt", "hello")
+
+	out, _ := DeleteFileFunc(ctx, DeleteFileParams{Path: "a.txt"})
+	if strings.Contains(out, "ERROR") || !strings.Contains(out, "restore id") {
+		t.Fatalf("soft delete failed:\n%s", out)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("file should no longer exist at its original path")
+	}
+	entries, err := listTrash(ctx)
+	if err != nil || len(entries) != 1 || entries[0].OriginalPath != src {
+		t.Fatalf("trash manifest = %+v, %v", entries, err)
+	}
+
+	writeTestFile(t, dir, "a.txt", "new")
+	out, _ = RestoreFileFunc(ctx, RestoreFileParams{Path: "a.txt"})
+	if !strings.Contains(out, "already exists") {
+		t.Errorf("restore should refuse to overwrite:\n%s", out)
+	}
+	os.Remove(src)
+
+	out, _ = RestoreFileFunc(ctx, RestoreFileParams{ID: entries[0].ID})
+	if data, _ := os.ReadFile(src); strings.Contains(out, "ERROR") || string(data) != "hello" {
+		t.Errorf("restore failed: %q\n%s", data, out)
+	}
+	if out, _ = RestoreFileFunc(ctx, RestoreFileParams{}); !strings.Contains(out, "Trash is empty") {
+		t.Errorf("trash should be empty after restore:\n%s", out)
+	}
+}
+
+// TestDeleteFileHard verifies soft_delete=false removes the file permanently
+func TestDeleteFileHard(t *testing.T) {
+	dir := t.TempDir()
+	ctx := WithWorkDir(context.Background(), dir)
+	src := writeTestFile(t, dir, "a.txt", "hello")
+	hard := false
+
+	out, _ := DeleteFileFunc(ctx, DeleteFileParams{Path: src, SoftDelete: &hard})
+	if strings.Contains(out, "ERROR") || strings.Contains(out, "soft_deleted") || strings.Contains(out, "restore id") {
+		t.Fatalf("hard delete failed:\n%s", out)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("file should be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, TrashDirName)); !os.IsNotExist(err) {
+		t.Error("hard delete should not create the trash directory")
+	}
+}
+
+// TestDeleteFileProtected verifies .env cannot be deleted, soft or hard
+func TestDeleteFileProtected(t *testing.T) {
+	dir := t.TempDir()
+	ctx := WithWorkDir(context.Background(), dir)
+	writeTestFile(t, dir, ".env", "SECRET=1")
+
+	out, _ := DeleteFileFunc(ctx, DeleteFileParams{Path: ".env"})
+	if !strings.Contains(out, "not allowed") {
+		t.Errorf("deleting .env should be refused:\n%s", out)
+	}
+}
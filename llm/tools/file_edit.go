@@ -3,7 +3,6 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,13 +13,18 @@ import (
 
 // EditFileParams defines parameters for editing a file using search and replace.
 type EditFileParams struct {
-	Path    string `json:"path" jsonschema:"description=The path of the file to edit"`
-	Search  string `json:"search" jsonschema:"description=The string to search for (must be unique in the file)"`
-	Replace string `json:"replace" jsonschema:"description=The string to replace with"`
+	Path        string `json:"path" jsonschema:"description=The path of the file to edit"`
+	Search      string `json:"search,omitempty" jsonschema:"description=The string to search for (must be unique in the file unless occurrence is set)"`
+	Replace     string `json:"replace,omitempty" jsonschema:"description=The string to replace with"`
+	Occurrence  int    `json:"occurrence,omitempty" jsonschema:"description=Which occurrence to replace: 0 = search must be unique (default), -1 = replace all, N = replace only the Nth occurrence"`
+	StartLine   int    `json:"start_line,omitempty" jsonschema:"description=Line-range mode: first line to replace (1-indexed, used when search is empty)"`
+	EndLine     int    `json:"end_line,omitempty" jsonschema:"description=Line-range mode: last line to replace (1-indexed, inclusive)"`
+	Replacement string `json:"replacement,omitempty" jsonschema:"description=Line-range mode: new content for the line range (empty deletes the lines)"`
+	DryRun      bool   `json:"dry_run,omitempty" jsonschema:"description=Return the diff without writing the file (default: false)"`
 }
 
 // editDescription is the detailed tool description for the AI
-const editDescription = `Edit a file by replacing a specific search string with a replacement string.
+const editDescription = `Edit a file by replacing a specific search string, or a range of lines, with new content.
 
 BEFORE USING:
 - Use view tool to read the file first
@@ -29,53 +33,182 @@ BEFORE USING:
 
 CAPABILITIES:
 - Search and replace within a file
-- Replaces ALL occurrences of the search string
+- By default the search string must be unique; the edit fails if it appears more than once
+- Replace all occurrences with occurrence=-1, or only the Nth with occurrence=N
+- Line-range mode: replace lines start_line..end_line (inclusive) when search is empty
+- Preserves the file's line-ending style (LF or CRLF) in line-range mode
+- Returns a unified diff of the change; dry_run previews it without writing
 - Case-sensitive matching
 
 PARAMETERS:
 - path (required): The path of the file to edit
-- search (required): The string to search for
-- replace (required): The string to replace with
+- search: The string to search for (search/replace mode)
+- replace: The string to replace with (search/replace mode)
+- occurrence (optional): 0 = must be unique (default), -1 = replace all, N = replace the Nth occurrence (1-based)
+- start_line, end_line: Inclusive 1-indexed line range (line-range mode)
+- replacement: New content for the line range; empty deletes the lines (line-range mode)
+- dry_run (optional): Preview the diff without modifying the file (default: false)
 
 OUTPUT FORMAT:
-Returns confirmation with the file path edited and replacement count.
+Returns confirmation with the file path edited and replacement count,
+followed by a unified diff of the change (at most 10 hunks).
 
 EXAMPLES:
 - Simple replace: {"path": "main.go", "search": "oldFunc", "replace": "newFunc"}
 - Multi-line: {"path": "config.json", "search": "\"port\": 8080", "replace": "\"port\": 3000"}
+- Rename everywhere: {"path": "main.go", "search": "oldFunc", "replace": "newFunc", "occurrence": -1}
+- Second match only: {"path": "main.go", "search": "TODO", "replace": "DONE", "occurrence": 2}
+- Replace lines 10-12: {"path": "main.go", "start_line": 10, "end_line": 12, "replacement": "func main() {\n}"}
+- Preview only: {"path": "main.go", "search": "oldFunc", "replace": "newFunc", "dry_run": true}
 
 WARNINGS:
-- If search string appears multiple times, ALL occurrences will be replaced
+- If the search string is not unique and occurrence is unset, the edit fails with the match count
 - Search is case-sensitive
-- Search must match exactly, including whitespace`
+- Search must match exactly, including whitespace
+- Read the file first in line-range mode; line numbers shift after each edit`
 
-// EditFileFunc edits a file by replacing a string.
+// EditFileFunc edits a file by replacing a string or a line range.
 func EditFileFunc(ctx context.Context, params EditFileParams) (string, error) {
-	data, err := os.ReadFile(params.Path)
+	path := resolvePath(ctx, params.Path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return Error(fmt.Sprintf("file not found: %v", err))
 	}
 
 	content := string(data)
-	if !strings.Contains(content, params.Search) {
-		return Error(fmt.Sprintf("search string not found in file: %s", params.Path))
+	var newContent string
+	var replaced int
+	if params.Search == "" {
+		if params.StartLine <= 0 {
+			return Error("either search or start_line is required")
+		}
+		newContent, replaced, err = editLineRange(content, params)
+	} else {
+		newContent, replaced, err = editSearchReplace(content, params)
+	}
+	if err != nil {
+		return Error(err.Error())
 	}
 
-	newContent := strings.ReplaceAll(content, params.Search, params.Replace)
-	err = os.WriteFile(params.Path, []byte(newContent), 0644)
+	absPath, _ := filepath.Abs(path)
+	diff := unifiedDiff(filepath.ToSlash(params.Path), content, newContent)
+	if params.DryRun {
+		return EditFilePreview(absPath, replaced, diff)
+	}
+	if IsReadOnly(ctx) {
+		content := fmt.Sprintf("File edited: %s (%d replaced)", absPath, replaced)
+		if diff != "" {
+			content += "\n\n" + diff
+		}
+		return Simulated(content, &Metadata{
+			FilePath:  absPath,
+			LineCount: strings.Count(newContent, "\n") + 1,
+			Replaced:  replaced,
+		})
+	}
+
+	err = os.WriteFile(path, []byte(newContent), 0644)
 	if err != nil {
 		return Error(fmt.Sprintf("failed to write file: %v", err))
 	}
 
-	absPath, _ := filepath.Abs(params.Path)
-	return EditFileSuccess(absPath, strings.Count(newContent, "\n")+1)
+	return EditFileSuccess(absPath, strings.Count(newContent, "\n")+1, replaced, diff)
+}
+
+// editSearchReplace replaces the selected occurrence(s) of Search with Replace
+func editSearchReplace(content string, params EditFileParams) (string, int, error) {
+	count := strings.Count(content, params.Search)
+	if count == 0 {
+		return "", 0, fmt.Errorf("search string not found in file: %s", params.Path)
+	}
+
+	switch {
+	case params.Occurrence == -1:
+		return strings.ReplaceAll(content, params.Search, params.Replace), count, nil
+	case params.Occurrence == 0:
+		if count > 1 {
+			return "", 0, fmt.Errorf("search string appears %d times in %s; add more context to make it unique, or set occurrence (-1 for all, N for the Nth)", count, params.Path)
+		}
+		return strings.Replace(content, params.Search, params.Replace, 1), 1, nil
+	case params.Occurrence > 0:
+		if params.Occurrence > count {
+			return "", 0, fmt.Errorf("occurrence %d out of range: search string appears %d times", params.Occurrence, count)
+		}
+		return replaceNth(content, params.Search, params.Replace, params.Occurrence), 1, nil
+	default:
+		return "", 0, fmt.Errorf("occurrence must be -1, 0, or a positive number")
+	}
+}
+
+// editLineRange replaces the inclusive line range [StartLine, EndLine] with Replacement
+func editLineRange(content string, params EditFileParams) (string, int, error) {
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+
+	// A trailing newline terminates the last line rather than starting a new one
+	body := strings.TrimSuffix(content, eol)
+	trailing := len(body) < len(content)
+	lines := strings.Split(body, eol)
+	if content == "" {
+		lines = nil
+	}
+
+	start, end := params.StartLine, params.EndLine
+	if end <= 0 {
+		end = start
+	}
+	if start > len(lines) {
+		return "", 0, fmt.Errorf("start line %d exceeds file length %d", start, len(lines))
+	}
+	if end < start {
+		return "", 0, fmt.Errorf("end line %d is before start line %d", end, start)
+	}
+	if end > len(lines) {
+		return "", 0, fmt.Errorf("end line %d exceeds file length %d", end, len(lines))
+	}
+
+	var replacement []string
+	if params.Replacement != "" {
+		text := strings.ReplaceAll(params.Replacement, "\r\n", "\n")
+		text = strings.TrimSuffix(text, "\n")
+		replacement = strings.Split(text, "\n")
+	}
+
+	newLines := make([]string, 0, len(lines)-(end-start+1)+len(replacement))
+	newLines = append(newLines, lines[:start-1]...)
+	newLines = append(newLines, replacement...)
+	newLines = append(newLines, lines[end:]...)
+
+	newContent := strings.Join(newLines, eol)
+	if trailing && len(newLines) > 0 {
+		newContent += eol
+	}
+	return newContent, end - start + 1, nil
+}
+
+// replaceNth replaces only the nth (1-based) non-overlapping occurrence of search
+func replaceNth(content, search, replace string, n int) string {
+	offset := 0
+	for i := 1; ; i++ {
+		idx := strings.Index(content[offset:], search)
+		if idx < 0 {
+			return content
+		}
+		start := offset + idx
+		if i == n {
+			return content[:start] + replace + content[start+len(search):]
+		}
+		offset = start + len(search)
+	}
 }
 
 // GetEditFileTool returns the edit file tool.
-func GetEditFileTool() tool.InvokableTool {
+func GetEditFileTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(EditToolName, editDescription, EditFileFunc)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", EditToolName, err)
 	}
-	return t
+	return t, nil
 }
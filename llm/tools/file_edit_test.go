@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestEditFileOccurrence verifies uniqueness checking, replace-all and Nth replacement
+func TestEditFileOccurrence(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		occurrence int
+		want       string
+		wantErr    bool
+	}{
+		{name: "ambiguous", occurrence: 0, want: "a x a x a", wantErr: true},
+		{name: "all", occurrence: -1, want: "b x b x b"},
+		{name: "second", occurrence: 2, want: "a x b x a"},
+		{name: "out of range", occurrence: 4, want: "a x a x a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, dir, tt.name+".txt", "a x a x a")
+			out, _ := EditFileFunc(ctx, EditFileParams{
+				Path:       path,
+				Search:     "a",
+				Replace:    "b",
+				Occurrence: tt.occurrence,
+			})
+			if got := strings.Contains(out, "ERROR"); got != tt.wantErr {
+				t.Errorf("error = %v, want %v:\n%s", got, tt.wantErr, out)
+			}
+			data, _ := os.ReadFile(path)
+			if string(data) != tt.want {
+				t.Errorf("content = %q, want %q", data, tt.want)
+			}
+		})
+	}
+
+	path := writeTestFile(t, dir, "unique.txt", "foo bar")
+	out, _ := EditFileFunc(ctx, EditFileParams{Path: path, Search: "bar", Replace: "baz"})
+	if !strings.Contains(out, "1 replaced") {
+		t.Errorf("expected replacement count in output:\n%s", out)
+	}
+}
+
+// TestEditFileLineRange verifies line-range replacement, validation and CRLF preservation
+func TestEditFileLineRange(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	path := writeTestFile(t, dir, "lf.txt", "one\ntwo\nthree\nfour\n")
+	out, _ := EditFileFunc(ctx, EditFileParams{Path: path, StartLine: 2, EndLine: 3, Replacement: "TWO\nTHREE\nEXTRA\n"})
+	if strings.Contains(out, "ERROR") {
+		t.Fatalf("unexpected error:\n%s", out)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "one\nTWO\nTHREE\nEXTRA\nfour\n" {
+		t.Errorf("content = %q", data)
+	}
+
+	path = writeTestFile(t, dir, "crlf.txt", "a\r\nb\r\nc\r\n")
+	EditFileFunc(ctx, EditFileParams{Path: path, StartLine: 2, EndLine: 2, Replacement: "x\ny"})
+	if data, _ := os.ReadFile(path); string(data) != "a\r\nx\r\ny\r\nc\r\n" {
+		t.Errorf("CRLF content = %q", data)
+	}
+
+	EditFileFunc(ctx, EditFileParams{Path: path, StartLine: 1, EndLine: 1})
+	if data, _ := os.ReadFile(path); string(data) != "x\r\ny\r\nc\r\n" {
+		t.Errorf("deletion content = %q", data)
+	}
+
+	out, _ = EditFileFunc(ctx, EditFileParams{Path: path, StartLine: 2, EndLine: 9, Replacement: "z"})
+	if !strings.Contains(out, "exceeds file length 3") {
+		t.Errorf("expected range validation error:\n%s", out)
+	}
+}
+
+// TestEditFileDiff verifies the diff in the result and dry-run mode
+func TestEditFileDiff(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	original := "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"
+	path := writeTestFile(t, dir, "main.go", original)
+
+	out, _ := EditFileFunc(ctx, EditFileParams{Path: path, Search: `println("hi")`, Replace: `println("hello")`, DryRun: true})
+	for _, want := range []string{"Dry run", "@@ -1,5 +1,5 @@", "-\tprintln(\"hi\")", "+\tprintln(\"hello\")", " func main() {"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry run output missing %q:\n%s", want, out)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("dry run should not modify the file: %q", data)
+	}
+
+	out, _ = EditFileFunc(ctx, EditFileParams{Path: path, Search: `println("hi")`, Replace: `println("hello")`})
+	if !strings.Contains(out, "File edited") || !strings.Contains(out, "+\tprintln(\"hello\")") {
+		t.Errorf("edit output should include the diff:\n%s", out)
+	}
+}
+
+// TestUnifiedDiffHunks verifies hunk grouping and truncation
+func TestUnifiedDiffHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 300; i++ {
+		line := "line " + strconv.Itoa(i)
+		oldLines = append(oldLines, line)
+		if i%20 == 0 {
+			line += " changed"
+		}
+		newLines = append(newLines, line)
+	}
+
+	diff := unifiedDiff("a.txt", strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+	if got := strings.Count(diff, "@@ -"); got != MaxDiffHunks {
+		t.Errorf("hunks = %d, want %d:\n%s", got, MaxDiffHunks, diff)
+	}
+	if !strings.Contains(diff, "5 more hunk(s) not shown") {
+		t.Errorf("expected truncation note:\n%s", diff)
+	}
+	if !strings.Contains(diff, "@@ -1,4 +1,4 @@") || !strings.Contains(diff, "@@ -18,7 +18,7 @@") {
+		t.Errorf("unexpected hunk header:\n%s", diff)
+	}
+
+	if unifiedDiff("a.txt", "same\n", "same\n") != "" {
+		t.Error("equal texts should produce an empty diff")
+	}
+	if diff := unifiedDiff("a.txt", "", "new\n"); !strings.Contains(diff, "@@ -0,0 +1,1 @@\n+new") {
+		t.Errorf("unexpected diff for new content:\n%s", diff)
+	}
+}
@@ -3,7 +3,6 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -49,7 +48,7 @@ func ListDirFunc(ctx context.Context, params ListDirParams) (string, error) {
 		path = "."
 	}
 
-	absPath, err := filepath.Abs(path)
+	absPath, err := filepath.Abs(resolvePath(ctx, path))
 	if err != nil {
 		return Error(fmt.Sprintf("invalid path: %v", err))
 	}
@@ -97,10 +96,10 @@ func ListDirFunc(ctx context.Context, params ListDirParams) (string, error) {
 }
 
 // GetListDirTool returns the list directory tool.
-func GetListDirTool() tool.InvokableTool {
+func GetListDirTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(ListToolName, listDescription, ListDirFunc)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", ListToolName, err)
 	}
-	return t
+	return t, nil
 }
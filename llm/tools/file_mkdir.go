@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// MakeDirParams defines parameters for creating a directory.
+type MakeDirParams struct {
+	Path      string `json:"path" jsonschema:"description=The path of the directory to create"`
+	Recursive bool   `json:"recursive,omitempty" jsonschema:"description=Create missing parent directories too (default: false)"`
+}
+
+// mkdirDescription is the detailed tool description for the AI
+const mkdirDescription = `Create a directory.
+
+BEFORE USING:
+- write creates parent directories itself; use this for empty directories
+  or to scaffold a project layout before writing files
+
+CAPABILITIES:
+- Create a single directory, or a whole path with recursive=true
+- An existing directory is reported, not treated as an error
+- Protected paths cannot be created (.git)
+
+PARAMETERS:
+- path (required): The directory to create
+- recursive (optional): Also create missing parent directories (default: false)
+
+OUTPUT FORMAT:
+Returns 'Directory created: <path>' or 'Directory already exists: <path>'.
+
+EXAMPLES:
+- Single directory: {"path": "docs"}
+- Nested layout: {"path": "internal/api/handlers", "recursive": true}`
+
+// MakeDirFunc creates a directory.
+func MakeDirFunc(ctx context.Context, params MakeDirParams) (string, error) {
+	if params.Path == "" {
+		return Error("path parameter is required")
+	}
+
+	// Security check
+	if base, ok := protectedFileName(params.Path); ok {
+		return Error(fmt.Sprintf("creating %s is not allowed for security reasons", base))
+	}
+
+	path := resolvePath(ctx, params.Path)
+	absPath, _ := filepath.Abs(path)
+
+	if info, err := os.Stat(path); err == nil {
+		if !info.IsDir() {
+			return Error(fmt.Sprintf("%s already exists and is not a directory", params.Path))
+		}
+		return MakeDirSuccess(absPath, false)
+	}
+
+	if !params.Recursive {
+		if _, err := os.Stat(filepath.Dir(path)); errors.Is(err, os.ErrNotExist) {
+			return Error(fmt.Sprintf("parent directory of %s does not exist (set recursive to create it)", params.Path))
+		}
+	}
+
+	if IsReadOnly(ctx) {
+		return Simulated(fmt.Sprintf("Directory created: %s", absPath), &Metadata{
+			FilePath: absPath,
+			IsDir:    true,
+		})
+	}
+
+	mkdir := os.Mkdir
+	if params.Recursive {
+		mkdir = os.MkdirAll
+	}
+	if err := mkdir(path, 0755); err != nil {
+		return Error(fmt.Sprintf("failed to create directory: %v", err))
+	}
+	return MakeDirSuccess(absPath, true)
+}
+
+// GetMakeDirTool returns the mkdir tool.
+func GetMakeDirTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(MakeDirToolName, mkdirDescription, MakeDirFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", MakeDirToolName, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMakeDir verifies single and recursive creation and existing paths
+func TestMakeDir(t *testing.T) {
+	dir := t.TempDir()
+	ctx := WithWorkDir(context.Background(), dir)
+
+	out, _ := MakeDirFunc(ctx, MakeDirParams{Path: "docs"})
+	if !strings.HasPrefix(out, "Directory created: ") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	checkOutputSchema(t, MakeDirToolName, out)
+	if info, err := os.Stat(filepath.Join(dir, "docs")); err != nil || !info.IsDir() {
+		t.Errorf("docs was not created: %v", err)
+	}
+
+	out, _ = MakeDirFunc(ctx, MakeDirParams{Path: "a/b/c"})
+	if !strings.Contains(out, "ERROR") || !strings.Contains(out, "set recursive") {
+		t.Errorf("missing parents should fail without recursive:\n%s", out)
+	}
+	out, _ = MakeDirFunc(ctx, MakeDirParams{Path: "a/b/c", Recursive: true})
+	if _, err := os.Stat(filepath.Join(dir, "a", "b", "c")); err != nil {
+		t.Errorf("recursive mkdir failed: %v\n%s", err, out)
+	}
+
+	out, _ = MakeDirFunc(ctx, MakeDirParams{Path: "docs"})
+	if !strings.HasPrefix(out, "Directory already exists: ") {
+		t.Errorf("existing directory should be reported:\n%s", out)
+	}
+	checkOutputSchema(t, MakeDirToolName, out)
+
+	writeTestFile(t, dir, "file.txt", "x")
+	for _, path := range []string{"file.txt", ".git", ""} {
+		if out, _ := MakeDirFunc(ctx, MakeDirParams{Path: path}); !strings.Contains(out, "ERROR") {
+			t.Errorf("mkdir %q should fail:\n%s", path, out)
+		}
+	}
+}
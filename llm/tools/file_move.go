@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// MoveFileParams defines parameters for moving or renaming a file.
+type MoveFileParams struct {
+	Source      string `json:"source" jsonschema:"description=The path of the file to move"`
+	Destination string `json:"destination" jsonschema:"description=The new path of the file"`
+	Overwrite   bool   `json:"overwrite,omitempty" jsonschema:"description=Replace the destination if it already exists (default: false)"`
+}
+
+// moveDescription is the detailed tool description for the AI
+const moveDescription = `Move or rename a file.
+
+BEFORE USING:
+- Verify the source path is correct
+- Prefer this over bash mv so the move stays within the file tools
+
+CAPABILITIES:
+- Rename a file in place or move it to another directory
+- Creates missing parent directories of the destination
+- Works across filesystems (falls back to copy + delete)
+- Cannot move directories (use bash tool for that)
+- Protected files cannot be moved (.env, .git)
+
+PARAMETERS:
+- source (required): The path of the file to move
+- destination (required): The new path of the file
+- overwrite (optional): Replace an existing destination file (default: false)
+
+OUTPUT FORMAT:
+Returns confirmation with the source and destination paths.
+
+EXAMPLES:
+- Rename: {"source": "old_name.go", "destination": "new_name.go"}
+- Move: {"source": "notes.md", "destination": "docs/notes.md"}
+
+SECURITY:
+- Moving .env, .git files is blocked`
+
+// MoveFileFunc moves or renames a file.
+func MoveFileFunc(ctx context.Context, params MoveFileParams) (string, error) {
+	if params.Source == "" || params.Destination == "" {
+		return Error("source and destination parameters are required")
+	}
+
+	// Security check
+	for _, p := range []string{params.Source, params.Destination} {
+		if base, ok := protectedFileName(p); ok {
+			return Error(fmt.Sprintf("moving %s is not allowed for security reasons", base))
+		}
+	}
+
+	src := resolvePath(ctx, params.Source)
+	dst := resolvePath(ctx, params.Destination)
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return Error(fmt.Sprintf("file not found: %v", err))
+	}
+	if info.IsDir() {
+		return Error(fmt.Sprintf("%s is a directory, use bash to move directories", params.Source))
+	}
+
+	if _, err := os.Stat(dst); err == nil && !params.Overwrite {
+		return Error(fmt.Sprintf("destination already exists: %s (set overwrite to replace it)", params.Destination))
+	}
+
+	if IsReadOnly(ctx) {
+		absSrc, _ := filepath.Abs(src)
+		absDst, _ := filepath.Abs(dst)
+		return Simulated(fmt.Sprintf("File moved: %s -> %s", absSrc, absDst), &Metadata{
+			FilePath:  absDst,
+			ByteCount: int(info.Size()),
+		})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return Error(fmt.Sprintf("failed to create destination directory: %v", err))
+	}
+
+	if err := renameFile(src, dst, info.Mode()); err != nil {
+		return Error(err.Error())
+	}
+
+	absSrc, _ := filepath.Abs(src)
+	absDst, _ := filepath.Abs(dst)
+	return MoveFileSuccess(absSrc, absDst, int(info.Size()))
+}
+
+// GetMoveFileTool returns the move file tool.
+func GetMoveFileTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(MoveToolName, moveDescription, MoveFileFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", MoveToolName, err)
+	}
+	return t, nil
+}
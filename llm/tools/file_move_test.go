@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMoveFile verifies renames, parent directory creation and overwrite protection
+func TestMoveFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	src := writeTestFile(t, dir, "a.txt", "hello")
+	dst := filepath.Join(dir, "nested", "deeper", "b.txt")
+
+	out, _ := MoveFileFunc(ctx, MoveFileParams{Source: src, Destination: dst})
+	if strings.Contains(out, "ERROR") {
+		t.Fatalf("move failed:\n%s", out)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Error("source should no longer exist")
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "hello" {
+		t.Errorf("destination content = %q", data)
+	}
+
+	other := writeTestFile(t, dir, "c.txt", "other")
+	out, _ = MoveFileFunc(ctx, MoveFileParams{Source: other, Destination: dst})
+	if !strings.Contains(out, "already exists") {
+		t.Errorf("expected overwrite protection:\n%s", out)
+	}
+	out, _ = MoveFileFunc(ctx, MoveFileParams{Source: other, Destination: dst, Overwrite: true})
+	if data, _ := os.ReadFile(dst); strings.Contains(out, "ERROR") || string(data) != "other" {
+		t.Errorf("overwrite failed: %q\n%s", data, out)
+	}
+}
+
+// TestMoveFileProtected verifies .env and .git cannot be moved
+func TestMoveFileProtected(t *testing.T) {
+	dir := t.TempDir()
+	env := writeTestFile(t, dir, ".env", "SECRET=1")
+
+	out, _ := MoveFileFunc(context.Background(), MoveFileParams{Source: env, Destination: filepath.Join(dir, "env.bak")})
+	if !strings.Contains(out, "not allowed") {
+		t.Errorf("moving .env should be refused:\n%s", out)
+	}
+
+	src := writeTestFile(t, dir, "x.txt", "x")
+	out, _ = MoveFileFunc(context.Background(), MoveFileParams{Source: src, Destination: filepath.Join(dir, ".env")})
+	if !strings.Contains(out, "not allowed") {
+		t.Errorf("overwriting .env should be refused:\n%s", out)
+	}
+}
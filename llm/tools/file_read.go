@@ -3,9 +3,9 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/cloudwego/eino/components/tool"
@@ -17,6 +17,8 @@ type ReadFileParams struct {
 	Path      string `json:"path" jsonschema:"description=The path of the file to read"`
 	StartLine int    `json:"start_line,omitempty" jsonschema:"description=The starting line number (1-indexed) to read from"`
 	EndLine   int    `json:"end_line,omitempty" jsonschema:"description=The ending line number (1-indexed) to read to"`
+	// ShowLineNumbers prefixes each line with its line number
+	ShowLineNumbers bool `json:"show_line_numbers,omitempty" jsonschema:"description=Prefix each line with its 1-indexed line number (default: false)"`
 }
 
 // viewDescription is the detailed tool description for the AI
@@ -36,17 +38,22 @@ PARAMETERS:
 - path (required): The path of the file to read
 - start_line (optional): Starting line number (1-indexed, default: 1)
 - end_line (optional): Ending line number (1-indexed, default: end of file)
+- show_line_numbers (optional): Prefix each line with its number, useful
+  before editing a line range (default: false)
 
 OUTPUT FORMAT:
-Returns the file content as plain text.
+Returns the file content as plain text. With show_line_numbers, each line
+is '<right-aligned number><tab><line>'.
 
 EXAMPLES:
 - Read whole file: {"path": "main.go"}
-- Read specific range: {"path": "main.go", "start_line": 1, "end_line": 50}`
+- Read specific range: {"path": "main.go", "start_line": 1, "end_line": 50}
+- Read with line numbers: {"path": "main.go", "start_line": 40, "end_line": 60, "show_line_numbers": true}`
 
 // ReadFileFunc reads the content of a file.
 func ReadFileFunc(ctx context.Context, params ReadFileParams) (string, error) {
-	data, err := os.ReadFile(params.Path)
+	path := resolvePath(ctx, params.Path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return Error(fmt.Sprintf("file not found: %v", err))
 	}
@@ -65,17 +72,32 @@ func ReadFileFunc(ctx context.Context, params ReadFileParams) (string, error) {
 		return Error(fmt.Sprintf("start line %d exceeds file length %d", start, len(lines)))
 	}
 
-	content := strings.Join(lines[start-1:end], "\n")
+	shown := lines[start-1 : end]
+	if params.ShowLineNumbers {
+		shown = numberLines(shown, start, end)
+	}
+	content := strings.Join(shown, "\n")
 
-	absPath, _ := filepath.Abs(params.Path)
+	absPath, _ := filepath.Abs(path)
 	return ReadFileSuccess(content, absPath, len(lines), len(data))
 }
 
+// numberLines prefixes lines, which start at line number first, with their
+// numbers right-aligned to the width of last
+func numberLines(lines []string, first, last int) []string {
+	width := len(strconv.Itoa(last))
+	numbered := make([]string, len(lines))
+	for i, line := range lines {
+		numbered[i] = fmt.Sprintf("%*d\t%s", width, first+i, line)
+	}
+	return numbered
+}
+
 // GetReadFileTool returns the read file tool.
-func GetReadFileTool() tool.InvokableTool {
+func GetReadFileTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(ViewToolName, viewDescription, ReadFileFunc)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", ViewToolName, err)
 	}
-	return t
+	return t, nil
 }
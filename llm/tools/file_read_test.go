@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestReadFileLineNumbers verifies numbered output keeps windowing and the whole-file line count
+func TestReadFileLineNumbers(t *testing.T) {
+	dir := t.TempDir()
+	var sb strings.Builder
+	for i := 1; i <= 12; i++ {
+		sb.WriteString("line\n")
+	}
+	path := writeTestFile(t, dir, "a.txt", sb.String())
+
+	out, _ := ReadFileFunc(context.Background(), ReadFileParams{Path: path, StartLine: 8, EndLine: 10, ShowLineNumbers: true})
+	checkOutputSchema(t, ViewToolName, out)
+	if want := " 8\tline\n 9\tline\n10\tline"; !strings.Contains(out, want) {
+		t.Errorf("expected right-aligned numbers %q in:\n%s", want, out)
+	}
+	if !strings.Contains(out, "| 13 lines]") {
+		t.Errorf("line_count should cover the whole file:\n%s", out)
+	}
+
+	out, _ = ReadFileFunc(context.Background(), ReadFileParams{Path: path, EndLine: 2})
+	if !strings.Contains(out, "line\nline") || strings.Contains(out, "1\t") {
+		t.Errorf("lines should not be numbered by default:\n%s", out)
+	}
+}
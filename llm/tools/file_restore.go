@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// RestoreFileParams defines parameters for restoring a soft-deleted file.
+type RestoreFileParams struct {
+	ID   string `json:"id,omitempty" jsonschema:"description=The trash id reported by the delete tool"`
+	Path string `json:"path,omitempty" jsonschema:"description=The original path of the file; restores its most recent deletion"`
+}
+
+// restoreDescription is the detailed tool description for the AI
+const restoreDescription = `Restore a file that the delete tool moved to the .trash directory.
+
+CAPABILITIES:
+- Restore a file to its original path by trash id or by original path
+- List the files in the trash when called without parameters
+- Never overwrites: restoring fails if a file already exists at the original path
+
+PARAMETERS:
+- id (optional): The trash id reported by the delete tool
+- path (optional): The original path of the file; the most recent deletion is restored
+
+OUTPUT FORMAT:
+Returns confirmation with the restored path, or the trash listing.
+
+EXAMPLES:
+- List trash: {}
+- Restore by id: {"id": "20260101-120000.000000_temp.txt"}
+- Restore by path: {"path": "temp.txt"}`
+
+// RestoreFileFunc restores a soft-deleted file or lists the trash.
+func RestoreFileFunc(ctx context.Context, params RestoreFileParams) (string, error) {
+	if params.ID == "" && params.Path == "" {
+		entries, err := listTrash(ctx)
+		if err != nil {
+			return Error(err.Error())
+		}
+		return Success(formatTrashList(entries), &Metadata{FileCount: len(entries)}, TierCompact)
+	}
+
+	path := params.Path
+	if path != "" {
+		path = resolvePath(ctx, path)
+	}
+	if IsReadOnly(ctx) {
+		entry, err := findTrashEntry(ctx, params.ID, path)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to restore file: %v", err))
+		}
+		return Simulated(fmt.Sprintf("File restored: %s", entry.OriginalPath), &Metadata{
+			FilePath:  entry.OriginalPath,
+			ByteCount: int(entry.Size),
+		})
+	}
+	entry, err := restoreFromTrash(ctx, params.ID, path)
+	if err != nil {
+		return Error(fmt.Sprintf("failed to restore file: %v", err))
+	}
+	return RestoreFileSuccess(entry.OriginalPath, entry.Size)
+}
+
+// GetRestoreFileTool returns the restore file tool.
+func GetRestoreFileTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(RestoreToolName, restoreDescription, RestoreFileFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", RestoreToolName, err)
+	}
+	return t, nil
+}
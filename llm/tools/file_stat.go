@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// maxStatLineCountSize is the largest file whose lines stat counts (10MB)
+const maxStatLineCountSize = 10 * 1024 * 1024
+
+// StatFileParams defines parameters for inspecting a file.
+type StatFileParams struct {
+	Path string `json:"path" jsonschema:"description=The path of the file or directory to inspect"`
+}
+
+// statDescription is the detailed tool description for the AI
+const statDescription = `Show information about a file or directory without reading it.
+
+USE CASES:
+- Check a file exists and how large it is before reading it
+- Tell files from directories
+- See when a file was last modified
+
+PARAMETERS:
+- path (required): The file or directory to inspect
+
+OUTPUT FORMAT:
+One 'Key: value' line each for Path, Type (file, directory, or symlink -> target),
+Size, Mode, Modified (RFC 3339), then Lines for text files up to 10MB or Entries
+for directories.
+
+EXAMPLES:
+- Inspect a file: {"path": "main.go"}
+- Inspect a directory: {"path": "llm/tools"}`
+
+// StatFileFunc reports size, mode, modification time and type of a path.
+func StatFileFunc(ctx context.Context, params StatFileParams) (string, error) {
+	if params.Path == "" {
+		return Error("path parameter is required")
+	}
+
+	path := resolvePath(ctx, params.Path)
+	absPath, _ := filepath.Abs(path)
+
+	// Report symlinks themselves, then describe their target
+	fileType := "file"
+	if linfo, err := os.Lstat(path); err == nil && linfo.Mode()&os.ModeSymlink != 0 {
+		target, _ := os.Readlink(path)
+		fileType = "symlink -> " + target
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Error(fmt.Sprintf("file not found: %v", err))
+	}
+	if info.IsDir() && fileType == "file" {
+		fileType = "directory"
+	}
+
+	lines := []string{
+		"Path: " + absPath,
+		"Type: " + fileType,
+		fmt.Sprintf("Size: %d bytes", info.Size()),
+		"Mode: " + info.Mode().String(),
+		"Modified: " + info.ModTime().Format(time.RFC3339),
+	}
+	md := &Metadata{
+		FilePath:  absPath,
+		ByteCount: int(info.Size()),
+		Mode:      info.Mode().String(),
+		ModTime:   info.ModTime().Format(time.RFC3339),
+		IsDir:     info.IsDir(),
+	}
+
+	switch {
+	case info.IsDir():
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to read directory: %v", err))
+		}
+		lines = append(lines, fmt.Sprintf("Entries: %d", len(entries)))
+		md.FileCount = len(entries)
+	case !info.Mode().IsRegular():
+		// Devices and pipes have no meaningful line count
+	case info.Size() > maxStatLineCountSize:
+		lines = append(lines, "Lines: not counted (file larger than 10MB)")
+	case isBinaryFile(path):
+		lines = append(lines, "Lines: not counted (binary file)")
+	default:
+		n, err := countLines(path)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to read file: %v", err))
+		}
+		lines = append(lines, fmt.Sprintf("Lines: %d", n))
+		md.LineCount = n
+	}
+
+	return Success(strings.Join(lines, "\n"), md, TierMinimal)
+}
+
+// countLines counts the lines of a text file; a final line without a
+// trailing newline counts as a line
+func countLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 32*1024)
+	count, last := 0, byte('\n')
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			count += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if last != '\n' {
+		count++
+	}
+	return count, nil
+}
+
+// GetStatFileTool returns the stat tool.
+func GetStatFileTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(StatToolName, statDescription, StatFileFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", StatToolName, err)
+	}
+	return t, nil
+}
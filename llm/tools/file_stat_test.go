@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestStatFile verifies file and directory details and their metadata
+func TestStatFile(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	path := writeTestFile(t, dir, "notes.txt", "one\ntwo\nthree")
+
+	out, _ := StatFileFunc(ctx, StatFileParams{Path: path})
+	for _, want := range []string{"Type: file", "Size: 13 bytes", "Lines: 3", "Mode: -rw"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	writeTestFile(t, dir, "sub/a.txt", "a\n")
+	out, _ = StatFileFunc(ctx, StatFileParams{Path: dir})
+	if !strings.Contains(out, "Type: directory") || !strings.Contains(out, "Entries: 2") || strings.Contains(out, "Lines:") {
+		t.Errorf("unexpected directory info:\n%s", out)
+	}
+
+	bin := filepath.Join(dir, "blob.bin")
+	if err := os.WriteFile(bin, []byte{0, 1, 2}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, _ = StatFileFunc(ctx, StatFileParams{Path: bin})
+	if !strings.Contains(out, "Lines: not counted (binary file)") {
+		t.Errorf("binary files should not be line counted:\n%s", out)
+	}
+
+	out, _ = StatFileFunc(ctx, StatFileParams{Path: filepath.Join(dir, "missing")})
+	if !strings.Contains(out, "ERROR") {
+		t.Errorf("expected an error for a missing file:\n%s", out)
+	}
+}
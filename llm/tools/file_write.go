@@ -3,7 +3,6 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 
@@ -42,25 +41,45 @@ EXAMPLES:
 
 // WriteFileFunc writes content to a file.
 func WriteFileFunc(ctx context.Context, params WriteFileParams) (string, error) {
-	err := os.MkdirAll(filepath.Dir(params.Path), 0755)
+	path := resolvePath(ctx, params.Path)
+	if IsReadOnly(ctx) {
+		return simulateWrite(path, params)
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
 	if err != nil {
 		return Error(fmt.Sprintf("failed to create parent directories: %v", err))
 	}
 
-	err = os.WriteFile(params.Path, []byte(params.Content), 0644)
+	err = os.WriteFile(path, []byte(params.Content), 0644)
 	if err != nil {
 		return Error(fmt.Sprintf("failed to write file: %v", err))
 	}
 
-	absPath, _ := filepath.Abs(params.Path)
+	absPath, _ := filepath.Abs(path)
 	return WriteFileSuccess(absPath, len(params.Content))
 }
 
+// simulateWrite describes a write in read-only mode, with a diff when the file exists
+func simulateWrite(path string, params WriteFileParams) (string, error) {
+	absPath, _ := filepath.Abs(path)
+	content := fmt.Sprintf("File written: %s", absPath)
+	if old, err := os.ReadFile(path); err == nil {
+		if diff := unifiedDiff(filepath.ToSlash(params.Path), string(old), params.Content); diff != "" {
+			content += "\n\n" + diff
+		}
+	}
+	return Simulated(content, &Metadata{
+		FilePath:  absPath,
+		ByteCount: len(params.Content),
+	})
+}
+
 // GetWriteFileTool returns the write file tool.
-func GetWriteFileTool() tool.InvokableTool {
+func GetWriteFileTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(WriteToolName, writeDescription, WriteFileFunc)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", WriteToolName, err)
 	}
-	return t
+	return t, nil
 }
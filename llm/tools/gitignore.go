@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// ignoreRule is a single .gitignore pattern translated to doublestar syntax
+type ignoreRule struct {
+	base    string // directory containing the .gitignore
+	pattern string // doublestar pattern relative to base
+	negate  bool
+}
+
+// gitignoreMatcher evaluates .gitignore files from the repository root down
+// to each path's directory. Rule files are loaded lazily and cached.
+type gitignoreMatcher struct {
+	root  string
+	cache map[string][]ignoreRule
+}
+
+// newGitignoreMatcher creates a matcher for paths under dir. Rules from
+// ancestor directories up to the enclosing repository root are included.
+func newGitignoreMatcher(dir string) *gitignoreMatcher {
+	root := dir
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			root = d
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	return &gitignoreMatcher{
+		root:  root,
+		cache: make(map[string][]ignoreRule),
+	}
+}
+
+// Ignored reports whether path is ignored. The last matching rule wins, with
+// rules in deeper directories taking precedence over their ancestors.
+func (m *gitignoreMatcher) Ignored(path string) bool {
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".git" || strings.HasPrefix(rel, ".git/") || strings.Contains(rel, "/.git/") {
+		return true
+	}
+
+	// Collect directories from root down to the path's parent
+	dirs := []string{m.root}
+	parts := strings.Split(rel, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		dirs = append(dirs, filepath.Join(dirs[len(dirs)-1], parts[i]))
+	}
+
+	ignored := false
+	for _, dir := range dirs {
+		for _, rule := range m.rules(dir) {
+			r, err := filepath.Rel(rule.base, path)
+			if err != nil {
+				continue
+			}
+			if ok, _ := doublestar.Match(rule.pattern, filepath.ToSlash(r)); ok {
+				ignored = !rule.negate
+			}
+		}
+	}
+	return ignored
+}
+
+// rules returns the cached rules of dir's .gitignore
+func (m *gitignoreMatcher) rules(dir string) []ignoreRule {
+	if rules, ok := m.cache[dir]; ok {
+		return rules
+	}
+	rules := loadGitignore(dir)
+	m.cache[dir] = rules
+	return rules
+}
+
+// loadGitignore parses dir/.gitignore into doublestar rules
+func loadGitignore(dir string) []ignoreRule {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(line, "!")
+		line = strings.TrimPrefix(line, "!")
+
+		dirOnly := strings.HasSuffix(line, "/")
+		line = strings.TrimSuffix(line, "/")
+
+		// Patterns containing a slash are anchored to the .gitignore directory
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		if line == "" || line == "**/" {
+			continue
+		}
+
+		// A matched directory ignores everything beneath it
+		if !dirOnly {
+			rules = append(rules, ignoreRule{base: dir, pattern: line, negate: negate})
+		}
+		rules = append(rules, ignoreRule{base: dir, pattern: line + "/**", negate: negate})
+	}
+	return rules
+}
@@ -3,9 +3,10 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/bmatcuk/doublestar/v4"
@@ -28,6 +29,11 @@ type GlobToolParams struct {
 	Pattern    string `json:"pattern" jsonschema:"description=The glob pattern to match files (e.g., *.go, **/*.json)"`
 	Path       string `json:"path,omitempty" jsonschema:"description=The directory to search in (defaults to current working directory)"`
 	MaxResults int    `json:"max_results,omitempty" jsonschema:"description=Maximum number of results to return (default: 100, max: 1000)"`
+	// RespectGitignore is a pointer so that an omitted value defaults to true
+	RespectGitignore *bool    `json:"respect_gitignore,omitempty" jsonschema:"description=Skip paths ignored by .gitignore files (default: true)"`
+	Exclude          []string `json:"exclude,omitempty" jsonschema:"description=Additional glob patterns to exclude (e.g. **/vendor/**)"`
+	SortBy           string   `json:"sort_by,omitempty" jsonschema:"description=Result ordering: name (default), mtime (newest first) or size (largest first),enum=name,enum=mtime,enum=size"`
+	Reverse          bool     `json:"reverse,omitempty" jsonschema:"description=Reverse the sort order"`
 }
 
 // globDescription is the detailed tool description for the AI
@@ -42,6 +48,9 @@ CAPABILITIES:
 - Recursive search with ** pattern
 - Search in specific directories
 - Returns relative paths from the search directory
+- Skips .git and paths ignored by .gitignore (disable with respect_gitignore=false)
+- Exclude extra paths with ad-hoc patterns
+- Sort by name, modification time or size
 
 SUPPORTED PATTERNS:
 - *.go           - Match Go files in current directory
@@ -53,23 +62,31 @@ PARAMETERS:
 - pattern (required): The glob pattern to match files
 - path (optional): Directory to search in (default: current directory)
 - max_results (optional): Maximum results (default: 100, max: 1000)
+- respect_gitignore (optional): Filter out gitignored paths (default: true)
+- exclude (optional): Patterns to exclude, relative to path; patterns without
+  a slash match file names (e.g. ["*_test.go", "**/vendor/**"])
+- sort_by (optional): name (A-Z, default), mtime (newest first), size (largest first)
+- reverse (optional): Reverse the chosen order
 
 OUTPUT FORMAT:
-Returns a list of matching file paths, one per line.
+A header with the match count and ordering, then matching paths, one per line.
 
 EXAMPLES:
 - Find Go files: {"pattern": "*.go"}
 - Find all Markdown: {"pattern": "**/*.md"}
-- Find test files: {"pattern": "**/*_test.go"}`
+- Find test files: {"pattern": "**/*_test.go"}
+- Exclude vendor: {"pattern": "**/*.go", "exclude": ["**/vendor/**"]}
+- Include ignored files: {"pattern": "**/*.log", "respect_gitignore": false}
+- Recently changed: {"pattern": "**/*.go", "sort_by": "mtime", "max_results": 10}`
 
 // GlobToolFunc executes the glob search with structured response.
-func GlobToolFunc(_ context.Context, params GlobToolParams) (string, error) {
+func GlobToolFunc(ctx context.Context, params GlobToolParams) (string, error) {
 	searchPath := params.Path
 	if searchPath == "" {
 		searchPath = "."
 	}
 
-	absPath, err := filepath.Abs(searchPath)
+	absPath, err := filepath.Abs(resolvePath(ctx, searchPath))
 	if err != nil {
 		return Error(fmt.Sprintf("invalid path: %v", err))
 	}
@@ -88,10 +105,43 @@ func GlobToolFunc(_ context.Context, params GlobToolParams) (string, error) {
 		return Error(fmt.Sprintf("glob matching failed: %v", err))
 	}
 
+	sortBy := strings.ToLower(params.SortBy)
+	if sortBy == "" {
+		sortBy = "name"
+	}
+	if sortBy != "name" && sortBy != "mtime" && sortBy != "size" {
+		return Error("sort_by must be one of: name, mtime, size")
+	}
+
+	for _, ex := range params.Exclude {
+		if !doublestar.ValidatePattern(ex) {
+			return Error(fmt.Sprintf("invalid exclude pattern: %s", ex))
+		}
+	}
+
+	// Filter gitignored and excluded paths before truncation
+	var ignore *gitignoreMatcher
+	if params.RespectGitignore == nil || *params.RespectGitignore {
+		ignore = newGitignoreMatcher(absPath)
+	}
+	filtered := matches[:0]
+	for _, match := range matches {
+		if ignore != nil && ignore.Ignored(match) {
+			continue
+		}
+		if isExcluded(absPath, match, params.Exclude) {
+			continue
+		}
+		filtered = append(filtered, match)
+	}
+	matches = filtered
+
 	if len(matches) == 0 {
-		return GlobSuccess("No matches found", 0)
+		return GlobSuccess("No matches found", 0, false)
 	}
 
+	sortGlobMatches(matches, sortBy, params.Reverse)
+
 	// Apply max results limit
 	maxResults := params.MaxResults
 	if maxResults <= 0 {
@@ -101,6 +151,7 @@ func GlobToolFunc(_ context.Context, params GlobToolParams) (string, error) {
 		maxResults = MaxMaxResults
 	}
 
+	total := len(matches)
 	truncated := false
 	if len(matches) > maxResults {
 		matches = matches[:maxResults]
@@ -116,24 +167,104 @@ func GlobToolFunc(_ context.Context, params GlobToolParams) (string, error) {
 		relPaths = append(relPaths, rel)
 	}
 
-	content := strings.Join(relPaths, "\n")
+	order := globOrderLabel(sortBy, params.Reverse)
+	content := fmt.Sprintf("Found %d matches (sorted by %s):\n", total, order) + strings.Join(relPaths, "\n")
 	if truncated {
-		content += fmt.Sprintf("\n\n... (showing first %d of %d matches)",
-			maxResults, maxResults)
+		content += "\n\n" + truncationNote(0, fmt.Sprintf("showing first %d of %d matches", maxResults, total))
+	}
+
+	return GlobSuccess(content, len(matches), truncated)
+}
+
+// sortGlobMatches orders matches by name (A-Z), mtime (newest first) or size
+// (largest first). Files that cannot be stat'ed sort last.
+func sortGlobMatches(matches []string, sortBy string, reverse bool) {
+	var infos map[string]os.FileInfo
+	if sortBy != "name" {
+		infos = make(map[string]os.FileInfo, len(matches))
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil {
+				infos[m] = info
+			}
+		}
 	}
 
-	return GlobSuccess(content, len(matches))
+	less := func(a, b string) bool {
+		switch sortBy {
+		case "mtime", "size":
+			ia, ib := infos[a], infos[b]
+			if ia == nil || ib == nil {
+				return ia != nil
+			}
+			if sortBy == "mtime" && !ia.ModTime().Equal(ib.ModTime()) {
+				return ia.ModTime().After(ib.ModTime())
+			}
+			if sortBy == "size" && ia.Size() != ib.Size() {
+				return ia.Size() > ib.Size()
+			}
+		}
+		return a < b
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if reverse {
+			return less(matches[j], matches[i])
+		}
+		return less(matches[i], matches[j])
+	})
+}
+
+// globOrderLabel describes the ordering for the output header
+func globOrderLabel(sortBy string, reverse bool) string {
+	labels := map[string][2]string{
+		"name":  {"name", "name, descending"},
+		"mtime": {"mtime, newest first", "mtime, oldest first"},
+		"size":  {"size, largest first", "size, smallest first"},
+	}
+	if reverse {
+		return labels[sortBy][1]
+	}
+	return labels[sortBy][0]
+}
+
+// isExcluded reports whether path matches one of the exclude patterns.
+// Patterns without a slash match the file name, others the path relative to base.
+func isExcluded(base, path string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return false
+	}
+	for _, p := range patterns {
+		if matchesRelPattern(filepath.ToSlash(rel), p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRelPattern reports whether a slash-separated relative path matches a
+// doublestar pattern. Patterns without a slash match the file name.
+func matchesRelPattern(rel, pattern string) bool {
+	name := rel
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(rel)
+	}
+	ok, _ := doublestar.Match(pattern, name)
+	return ok
 }
 
 // GetGlobTool returns the glob tool with enhanced description.
-func GetGlobTool() tool.InvokableTool {
+func GetGlobTool() (tool.InvokableTool, error) {
 	globTool, err := utils.InferTool(
 		GlobToolName,
 		globDescription,
 		GlobToolFunc,
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", GlobToolName, err)
 	}
-	return globTool
+	return globTool, nil
 }
@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGlobRespectGitignore verifies gitignored paths and exclude patterns are filtered
+func TestGlobRespectGitignore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, dir, ".gitignore", "node_modules/\n*.log\n/build\n!keep.log\n")
+	writeTestFile(t, dir, "main.go", "")
+	writeTestFile(t, dir, "app.log", "")
+	writeTestFile(t, dir, "keep.log", "")
+	writeTestFile(t, dir, "node_modules/lib/index.js", "")
+	writeTestFile(t, dir, "build/out.go", "")
+	writeTestFile(t, dir, "src/build/gen.go", "")
+	writeTestFile(t, dir, "src/.gitignore", "gen_*.go\n")
+	writeTestFile(t, dir, "src/gen_types.go", "")
+	writeTestFile(t, dir, "vendor/dep/dep.go", "")
+	writeTestFile(t, dir, ".git/HEAD", "")
+
+	out, _ := GlobToolFunc(context.Background(), GlobToolParams{Pattern: "**/*", Path: dir})
+	for _, want := range []string{"main.go", "keep.log", filepath.Join("src", "build", "gen.go"), filepath.Join("vendor", "dep", "dep.go")} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"app.log", "index.js", "out.go", "gen_types.go", "HEAD"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output should not contain %q:\n%s", unwanted, out)
+		}
+	}
+
+	out, _ = GlobToolFunc(context.Background(), GlobToolParams{
+		Pattern: "**/*.go",
+		Path:    dir,
+		Exclude: []string{"**/vendor/**"},
+	})
+	if strings.Contains(out, "dep.go") {
+		t.Errorf("exclude pattern not applied:\n%s", out)
+	}
+
+	off := false
+	out, _ = GlobToolFunc(context.Background(), GlobToolParams{Pattern: "*.log", Path: dir, RespectGitignore: &off})
+	if !strings.Contains(out, "app.log") {
+		t.Errorf("expected ignored file when respect_gitignore=false:\n%s", out)
+	}
+}
+
+// TestGlobSortBy verifies mtime/size ordering is applied before truncation
+func TestGlobSortBy(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		path := writeTestFile(t, dir, name, strings.Repeat("x", (3-i)*10))
+		mtime := now.Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, _ := GlobToolFunc(context.Background(), GlobToolParams{Pattern: "*.txt", Path: dir, SortBy: "mtime", MaxResults: 2})
+	if !strings.HasPrefix(out, "Found 3 matches (sorted by mtime, newest first):\nc.txt\nb.txt") {
+		t.Errorf("unexpected mtime order:\n%s", out)
+	}
+
+	out, _ = GlobToolFunc(context.Background(), GlobToolParams{Pattern: "*.txt", Path: dir, SortBy: "size", Reverse: true})
+	if !strings.HasPrefix(out, "Found 3 matches (sorted by size, smallest first):\nc.txt\nb.txt\na.txt") {
+		t.Errorf("unexpected size order:\n%s", out)
+	}
+
+	out, _ = GlobToolFunc(context.Background(), GlobToolParams{Pattern: "*.txt", Path: dir})
+	if !strings.HasPrefix(out, "Found 3 matches (sorted by name):\na.txt\nb.txt\nc.txt") {
+		t.Errorf("unexpected default order:\n%s", out)
+	}
+}
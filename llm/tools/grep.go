@@ -2,14 +2,16 @@ package tools
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
-	"log"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/components/tool/utils"
 )
@@ -22,46 +24,84 @@ const (
 	DefaultMaxMatches = 100
 	// MaxMaxMatches is the maximum allowed matches
 	MaxMaxMatches = 500
+	// MaxContextLines is the maximum allowed before+after context lines
+	MaxContextLines = 20
+	// MaxGrepWalkFiles is the maximum number of files collected from a directory walk
+	MaxGrepWalkFiles = 10000
+	// DefaultGrepMaxOutputBytes is the default cap on the formatted output size
+	DefaultGrepMaxOutputBytes = 50000
+	// MaxGrepMaxOutputBytes is the largest output cap a caller may request
+	MaxGrepMaxOutputBytes = 200000
 )
 
+// grepSkipDirs are directories never descended into when walking
+var grepSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
 // GrepToolParams contains parameters for the grep tool.
 type GrepToolParams struct {
-	Pattern    string   `json:"pattern" jsonschema:"description=The regex pattern to search for in file contents"`
-	Files      []string `json:"files" jsonschema:"description=List of file paths to search in"`
-	MaxMatches int      `json:"max_matches,omitempty" jsonschema:"description=Maximum number of matches to return (default: 100)"`
+	Pattern     string   `json:"pattern" jsonschema:"description=The regex pattern to search for in file contents"`
+	Files       []string `json:"files,omitempty" jsonschema:"description=List of file paths to search in (optional when dir is set)"`
+	Dir         string   `json:"dir,omitempty" jsonschema:"description=Directory to search recursively"`
+	Include     string   `json:"include,omitempty" jsonschema:"description=Glob pattern to filter files when searching a directory (e.g. *.go)"`
+	MaxMatches  int      `json:"max_matches,omitempty" jsonschema:"description=Maximum number of matches to return (default: 100)"`
+	Before      int      `json:"before,omitempty" jsonschema:"description=Number of context lines to show before each match"`
+	After       int      `json:"after,omitempty" jsonschema:"description=Number of context lines to show after each match"`
+	IgnoreCase  bool     `json:"ignore_case,omitempty" jsonschema:"description=Match case-insensitively"`
+	InvertMatch bool     `json:"invert_match,omitempty" jsonschema:"description=Return lines that do NOT match the pattern"`
+	MaxOutput   int      `json:"max_output_bytes,omitempty" jsonschema:"description=Maximum size of the result in bytes (default: 50000)"`
 }
 
 // grepDescription is the detailed tool description for the AI
 const grepDescription = `Search file contents using regular expressions to find specific patterns.
 
 BEFORE USING:
-- Use the glob tool to find files first if you don't know the exact paths
-- For large codebases, consider limiting the search scope
+- Pass dir to search a whole directory tree, or files for specific paths
+- For large codebases, narrow the search with include
 
 CAPABILITIES:
 - Search for text patterns across multiple files
+- Walk a directory recursively (skips .git, node_modules and binary files)
 - Supports full regular expression syntax
 - Returns file path, line number, and matching content
-- Case-sensitive by default (use (?i) flag for case-insensitive)
+- Case-sensitive by default (set ignore_case for case-insensitive)
+- Invert matching to find lines that do NOT match (like grep -v)
+- Optional context lines around each match (like grep -B/-A)
 
 PARAMETERS:
 - pattern (required): The regex pattern to search for
-- files (required): List of file paths to search in
+- files (optional): List of file paths to search in
+- dir (optional): Directory to search recursively (files or dir is required)
+- include (optional): Glob filter for files found in dir (e.g. *.go, **/*.md)
 - max_matches (optional): Maximum number of matches (default: 100, max: 500)
+- before (optional): Context lines before each match
+- after (optional): Context lines after each match (before + after max: 20)
+- ignore_case (optional): Case-insensitive matching (like grep -i)
+- invert_match (optional): Return non-matching lines instead (like grep -v)
+- max_output_bytes (optional): Cap on the result size (default: 50000, max: 200000)
 
 OUTPUT FORMAT:
 Returns matching lines with file paths and line numbers, grouped by file.
+Matching lines use "NNNN:", context lines use "NNNN-", and "--" separates
+non-adjacent groups. Output beyond max_output_bytes is cut at a line boundary
+and ends with a truncation note; narrow the pattern or use include to see more.
 
 EXAMPLES:
 - Find function definitions: {"pattern": "func\s+\w+\(", "files": ["*.go"]}
-- Case-insensitive search: {"pattern": "(?i)error", "files": ["main.go"]}
-- Find TODO comments: {"pattern": "TODO|FIXME", "files": ["*.go", "*.js"]}`
+- Case-insensitive search: {"pattern": "error", "files": ["main.go"], "ignore_case": true}
+- Lines without a prefix: {"pattern": "^#", "files": ["config.ini"], "invert_match": true}
+- Find TODO comments: {"pattern": "TODO|FIXME", "files": ["*.go", "*.js"]}
+- Search a directory: {"pattern": "TODO", "dir": ".", "include": "*.go"}
+- With context: {"pattern": "func main", "files": ["main.go"], "before": 2, "after": 5}`
 
 // GrepMatch represents a single grep result.
 type GrepMatch struct {
-	File    string
-	Line    int
-	Content string
+	File      string
+	Line      int
+	Content   string
+	IsContext bool // true for surrounding context lines
 }
 
 // GrepToolFunc executes the grep search with structured response.
@@ -70,11 +110,23 @@ func GrepToolFunc(ctx context.Context, params GrepToolParams) (string, error) {
 		return Error("pattern parameter is required")
 	}
 
-	re, err := regexp.Compile(params.Pattern)
+	pattern := params.Pattern
+	if params.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return Error(fmt.Sprintf("invalid regex pattern: %v", err))
 	}
 
+	maxOutput := params.MaxOutput
+	if maxOutput <= 0 {
+		maxOutput = DefaultGrepMaxOutputBytes
+	}
+	if maxOutput > MaxGrepMaxOutputBytes {
+		maxOutput = MaxGrepMaxOutputBytes
+	}
+
 	maxMatches := params.MaxMatches
 	if maxMatches <= 0 {
 		maxMatches = DefaultMaxMatches
@@ -83,14 +135,25 @@ func GrepToolFunc(ctx context.Context, params GrepToolParams) (string, error) {
 		maxMatches = MaxMaxMatches
 	}
 
-	if len(params.Files) == 0 {
-		return Error("files parameter is required")
+	if len(params.Files) == 0 && params.Dir == "" {
+		return Error("files or dir parameter is required")
+	}
+
+	before, after := params.Before, params.After
+	if before < 0 {
+		before = 0
+	}
+	if after < 0 {
+		after = 0
+	}
+	if before+after > MaxContextLines {
+		return Error(fmt.Sprintf("before + after must not exceed %d lines", MaxContextLines))
 	}
 
 	// Convert to absolute paths and validate
 	absFiles := make([]string, 0, len(params.Files))
 	for _, f := range params.Files {
-		absPath, err := filepath.Abs(f)
+		absPath, err := filepath.Abs(resolvePath(ctx, f))
 		if err != nil {
 			continue
 		}
@@ -99,36 +162,57 @@ func GrepToolFunc(ctx context.Context, params GrepToolParams) (string, error) {
 		}
 	}
 
+	var walkDir string
+	if params.Dir != "" {
+		walkDir, err = filepath.Abs(resolvePath(ctx, params.Dir))
+		if err != nil {
+			return Error(fmt.Sprintf("invalid dir: %v", err))
+		}
+		walked, err := walkGrepFiles(ctx, walkDir, params.Include)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to walk directory: %v", err))
+		}
+		absFiles = append(absFiles, walked...)
+	}
+
 	if len(absFiles) == 0 {
 		return Error("no valid files to search")
 	}
 
 	// Search files
 	var matches []GrepMatch
+	matchCount := 0
 	for _, file := range absFiles {
-		if len(matches) >= maxMatches {
+		if matchCount >= maxMatches {
 			break
 		}
 
 		select {
 		case <-ctx.Done():
-			return Partial("search cancelled", &Metadata{MatchCount: len(matches)})
+			return Partial("search cancelled", &Metadata{MatchCount: matchCount})
 		default:
-			fileMatches, err := searchFile(file, re, maxMatches-len(matches))
+			fileMatches, n, err := searchFile(file, re, params.InvertMatch, maxMatches-matchCount, before, after)
 			if err == nil {
 				matches = append(matches, fileMatches...)
+				matchCount += n
 			}
 		}
 	}
 
-	if len(matches) == 0 {
-		return GrepSuccess(fmt.Sprintf("No matches found for pattern '%s'", params.Pattern), params.Pattern, 0, 0)
+	if matchCount == 0 {
+		return GrepSuccess(fmt.Sprintf("No matches found for pattern '%s'", params.Pattern), params.Pattern, 0, 0, false, 0)
 	}
 
 	// Format results
 	var sb strings.Builder
 	baseDir := findCommonDir(absFiles)
+	if walkDir != "" && len(params.Files) == 0 {
+		baseDir = walkDir
+	}
 	currentFile := ""
+	lastLine := 0
+	// Once the output cap is reached, remaining lines are only measured
+	shownMatches, omitted := 0, 0
 
 	for _, m := range matches {
 		relPath, _ := filepath.Rel(baseDir, m.File)
@@ -136,18 +220,40 @@ func GrepToolFunc(ctx context.Context, params GrepToolParams) (string, error) {
 			relPath = filepath.Base(m.File)
 		}
 
+		var chunk string
 		if relPath != currentFile {
 			if currentFile != "" {
-				sb.WriteString("\n")
+				chunk = "\n"
 			}
-			sb.WriteString(fmt.Sprintf("%s:\n", relPath))
+			chunk += fmt.Sprintf("%s:\n", relPath)
 			currentFile = relPath
+		} else if (before > 0 || after > 0) && m.Line > lastLine+1 {
+			chunk = "  --\n"
+		}
+		lastLine = m.Line
+
+		sep := ":"
+		if m.IsContext {
+			sep = "-"
+		}
+		chunk += fmt.Sprintf("  %4d%s %s\n", m.Line, sep, strings.TrimSpace(m.Content))
+
+		if omitted > 0 || sb.Len()+len(chunk) > maxOutput {
+			omitted += len(chunk)
+			continue
+		}
+		sb.WriteString(chunk)
+		if !m.IsContext {
+			shownMatches++
 		}
-		sb.WriteString(fmt.Sprintf("  %4d: %s\n", m.Line, strings.TrimSpace(m.Content)))
 	}
 
-	if len(matches) >= maxMatches {
-		sb.WriteString(fmt.Sprintf("\n... (showing first %d matches)\n", maxMatches))
+	truncated := omitted > 0 || matchCount >= maxMatches
+	switch {
+	case omitted > 0:
+		sb.WriteString("\n" + truncationNote(omitted, fmt.Sprintf("output limit of %d bytes reached; showing %d of %d matches", maxOutput, shownMatches, matchCount)) + "\n")
+	case truncated:
+		sb.WriteString("\n" + truncationNote(0, fmt.Sprintf("showing first %d matches; more may exist", maxMatches)) + "\n")
 	}
 
 	var files []string
@@ -155,7 +261,7 @@ func GrepToolFunc(ctx context.Context, params GrepToolParams) (string, error) {
 		files = append(files, filepath.Base(f))
 	}
 
-	return GrepSuccess(sb.String(), params.Pattern, len(matches), len(files))
+	return GrepSuccess(sb.String(), params.Pattern, matchCount, len(files), truncated, omitted)
 }
 
 // findCommonDir finds the common parent directory of multiple files.
@@ -177,42 +283,138 @@ func findCommonDir(files []string) string {
 	return common
 }
 
-// searchFile searches a single file for regex matches.
-func searchFile(path string, re *regexp.Regexp, limit int) ([]GrepMatch, error) {
-	file, err := os.Open(path)
+// walkGrepFiles collects searchable files under dir, optionally filtered by an
+// include glob. Patterns without a slash match the file name, others match
+// the path relative to dir.
+func walkGrepFiles(ctx context.Context, dir, include string) ([]string, error) {
+	info, err := os.Stat(dir)
 	if err != nil {
 		return nil, err
 	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	if include != "" && !doublestar.ValidatePattern(include) {
+		return nil, fmt.Errorf("invalid include pattern: %s", include)
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if p != dir && grepSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if include != "" {
+			name := d.Name()
+			if strings.Contains(include, "/") {
+				name, _ = filepath.Rel(dir, p)
+				name = filepath.ToSlash(name)
+			}
+			if ok, _ := doublestar.Match(include, name); !ok {
+				return nil
+			}
+		}
+		if isBinaryFile(p) {
+			return nil
+		}
+		files = append(files, p)
+		if len(files) >= MaxGrepWalkFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return files, err
+}
+
+// isBinaryFile reports whether the file looks binary (NUL byte in the first 8KB)
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return true
+	}
+	defer f.Close()
+
+	buf := make([]byte, 8192)
+	n, _ := f.Read(buf)
+	return bytes.IndexByte(buf[:n], 0) != -1
+}
+
+// searchFile searches a single file for regex matches, including up to
+// before/after context lines around each match. With invert set, lines that
+// fail the regex are treated as matches. It returns the collected lines and
+// the number of actual matches among them.
+func searchFile(path string, re *regexp.Regexp, invert bool, limit, before, after int) ([]GrepMatch, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
 	defer file.Close()
 
 	var matches []GrepMatch
+	var pending []GrepMatch // ring of recent lines for before-context
+	matchCount := 0
+	afterLeft := 0
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
 
-	for scanner.Scan() && len(matches) < limit {
+	for scanner.Scan() {
 		lineNum++
 		line := scanner.Text()
-		if re.MatchString(line) {
+
+		if matchCount < limit && re.MatchString(line) != invert {
+			matches = append(matches, pending...)
+			pending = pending[:0]
 			matches = append(matches, GrepMatch{
 				File:    path,
 				Line:    lineNum,
 				Content: line,
 			})
+			matchCount++
+			afterLeft = after
+			continue
+		}
+
+		if afterLeft > 0 {
+			matches = append(matches, GrepMatch{File: path, Line: lineNum, Content: line, IsContext: true})
+			afterLeft--
+			continue
+		}
+
+		if matchCount >= limit {
+			break
+		}
+
+		if before > 0 {
+			if len(pending) == before {
+				pending = pending[1:]
+			}
+			pending = append(pending, GrepMatch{File: path, Line: lineNum, Content: line, IsContext: true})
 		}
 	}
 
-	return matches, scanner.Err()
+	return matches, matchCount, scanner.Err()
 }
 
 // GetGrepTool returns the grep tool with enhanced description.
-func GetGrepTool() tool.InvokableTool {
+func GetGrepTool() (tool.InvokableTool, error) {
 	grepTool, err := utils.InferTool(
 		GrepToolName,
 		grepDescription,
 		GrepToolFunc,
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", GrepToolName, err)
 	}
-	return grepTool
+	return grepTool, nil
 }
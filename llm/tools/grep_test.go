@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestFile creates a file under dir with the given content
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestGrepContextLines verifies before/after context and group separators
+func TestGrepContextLines(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "a.txt", "one\ntwo\nMATCH three\nfour\nfive\nsix\nseven\nMATCH eight\nnine\n")
+
+	out, _ := GrepToolFunc(context.Background(), GrepToolParams{
+		Pattern: "MATCH",
+		Files:   []string{path},
+		Before:  1,
+		After:   1,
+	})
+
+	for _, want := range []string{"2- two", "3: MATCH three", "4- four", "  --\n", "7- seven", "8: MATCH eight", "9- nine"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "5- five") {
+		t.Errorf("output contains line outside context window:\n%s", out)
+	}
+	if !strings.Contains(out, "2 matches") {
+		t.Errorf("match count should exclude context lines:\n%s", out)
+	}
+
+	out, _ = GrepToolFunc(context.Background(), GrepToolParams{
+		Pattern: "MATCH",
+		Files:   []string{path},
+		Before:  15,
+		After:   15,
+	})
+	if !strings.Contains(out, "ERROR") {
+		t.Errorf("expected error when context exceeds limit:\n%s", out)
+	}
+}
+
+// TestGrepDirWalk verifies directory recursion, include filtering and skipped paths
+func TestGrepDirWalk(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.go", "package main // TODO main\n")
+	writeTestFile(t, dir, "pkg/util.go", "package pkg // TODO util\n")
+	writeTestFile(t, dir, "README.md", "TODO readme\n")
+	writeTestFile(t, dir, ".git/config", "TODO git\n")
+	writeTestFile(t, dir, "node_modules/lib/index.go", "TODO node\n")
+	writeTestFile(t, dir, "bin/tool.go", "TODO\x00binary\n")
+
+	out, _ := GrepToolFunc(context.Background(), GrepToolParams{
+		Pattern: "TODO",
+		Dir:     dir,
+		Include: "*.go",
+	})
+
+	for _, want := range []string{"main.go:", filepath.Join("pkg", "util.go") + ":"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"readme", "git", "node", "binary"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output should not contain %q:\n%s", unwanted, out)
+		}
+	}
+	if !strings.Contains(out, "2 matches") {
+		t.Errorf("expected 2 matches:\n%s", out)
+	}
+}
+
+// TestGrepIgnoreCaseInvert verifies case-insensitive and inverted matching
+func TestGrepIgnoreCaseInvert(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "a.txt", "Error one\nok two\nERROR three\n")
+
+	out, _ := GrepToolFunc(context.Background(), GrepToolParams{
+		Pattern:    "error",
+		Files:      []string{path},
+		IgnoreCase: true,
+	})
+	if !strings.Contains(out, "2 matches") {
+		t.Errorf("expected 2 case-insensitive matches:\n%s", out)
+	}
+
+	out, _ = GrepToolFunc(context.Background(), GrepToolParams{
+		Pattern:     "error",
+		Files:       []string{path},
+		IgnoreCase:  true,
+		InvertMatch: true,
+	})
+	if !strings.Contains(out, "2: ok two") || strings.Contains(out, "Error one") {
+		t.Errorf("expected only the non-matching line:\n%s", out)
+	}
+}
+
+// TestGrepMaxOutput verifies the output size cap and its truncation metadata
+func TestGrepMaxOutput(t *testing.T) {
+	dir := t.TempDir()
+	line := "MATCH " + strings.Repeat("x", 200) + "\n"
+	path := writeTestFile(t, dir, "wide.txt", strings.Repeat(line, 50))
+
+	out, _ := GrepToolFunc(context.Background(), GrepToolParams{
+		Pattern:   "MATCH",
+		Files:     []string{path},
+		MaxOutput: 1000,
+	})
+	content := out[:strings.Index(out, "\n\n[")]
+	if len(content) > 1100 {
+		t.Errorf("output not capped: %d bytes", len(content))
+	}
+	for _, want := range []string{"output limit of 1000 bytes reached; showing 4 of 50 matches", "50 matches", "truncated"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	checkOutputSchema(t, GrepToolName, out)
+
+	out, _ = GrepToolFunc(context.Background(), GrepToolParams{Pattern: "MATCH", Files: []string{path}})
+	if strings.Contains(out, TruncationMarker) {
+		t.Errorf("default cap should fit 50 matches:\n%s", out[len(out)-200:])
+	}
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// HashToolName is the name of the hashing tool
+const HashToolName = "hash"
+
+// HashParams defines parameters for hashing a file or content.
+type HashParams struct {
+	Path      string `json:"path,omitempty" jsonschema:"description=The path of the file to hash (use either path or content)"`
+	Content   string `json:"content,omitempty" jsonschema:"description=Text to hash instead of a file"`
+	Algorithm string `json:"algorithm,omitempty" jsonschema:"description=Hash algorithm: sha256 or md5 (default: sha256)"`
+	Expected  string `json:"expected,omitempty" jsonschema:"description=Optional expected hex digest; the result reports MATCH or MISMATCH"`
+}
+
+// hashDescription is the detailed tool description for the AI
+const hashDescription = `Compute the SHA-256 or MD5 hash of a file or text, optionally comparing it with an expected digest.
+
+USE CASES:
+- Verify a downloaded file against a published checksum
+- Detect whether a file changed between two points in time
+- Fingerprint a piece of text
+
+PARAMETERS:
+- path (optional): The file to hash
+- content (optional): Text to hash instead of a file (exactly one of path or content)
+- algorithm (optional): sha256 or md5 (default: sha256)
+- expected (optional): Expected hex digest to compare against (case-insensitive)
+
+OUTPUT FORMAT:
+'<algorithm> <hex digest>  <file path or (content)>', then 'MATCH' or 'MISMATCH: expected <digest>' when expected is given.
+
+EXAMPLES:
+- Hash a file: {"path": "release.tar.gz"}
+- Verify a download: {"path": "release.tar.gz", "expected": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+- MD5 of text: {"content": "hello", "algorithm": "md5"}
+
+SECURITY:
+- Hashing .env, .git files is blocked`
+
+// newHasher returns the hash for a supported algorithm name
+func newHasher(algorithm string) (hash.Hash, bool) {
+	switch algorithm {
+	case "sha256":
+		return sha256.New(), true
+	case "md5":
+		return md5.New(), true
+	default:
+		return nil, false
+	}
+}
+
+// HashFunc hashes a file or content and compares it with an expected digest.
+func HashFunc(ctx context.Context, params HashParams) (string, error) {
+	if (params.Path == "") == (params.Content == "") {
+		return Error("exactly one of path or content is required")
+	}
+
+	algorithm := strings.ToLower(strings.TrimSpace(params.Algorithm))
+	if algorithm == "" {
+		algorithm = "sha256"
+	}
+	h, ok := newHasher(algorithm)
+	if !ok {
+		return Error(fmt.Sprintf("unsupported algorithm %q (use sha256 or md5)", params.Algorithm))
+	}
+
+	expected := strings.ToLower(strings.TrimSpace(params.Expected))
+	if expected != "" {
+		if _, err := hex.DecodeString(expected); err != nil || len(expected) != 2*h.Size() {
+			return Error(fmt.Sprintf("expected hash must be %d hex characters for %s", 2*h.Size(), algorithm))
+		}
+	}
+
+	label := "(content)"
+	md := &Metadata{}
+	if params.Path != "" {
+		if base, ok := protectedFileName(params.Path); ok {
+			return Error(fmt.Sprintf("hashing %s is not allowed for security reasons", base))
+		}
+		path := resolvePath(ctx, params.Path)
+		n, err := hashFile(h, path)
+		if err != nil {
+			return Error(err.Error())
+		}
+		absPath, _ := filepath.Abs(path)
+		label = absPath
+		md.FilePath = absPath
+		md.ByteCount = int(n)
+	} else {
+		h.Write([]byte(params.Content))
+		md.ByteCount = len(params.Content)
+	}
+
+	digest := hex.EncodeToString(h.Sum(nil))
+	content := fmt.Sprintf("%s %s  %s", algorithm, digest, label)
+	if expected != "" {
+		if digest == expected {
+			content += "\nMATCH"
+		} else {
+			content += "\nMISMATCH: expected " + expected
+		}
+	}
+	return Success(content, md, TierCompact)
+}
+
+// hashFile streams a regular file into h and returns the bytes read
+func hashFile(h hash.Hash, path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("file not found: %v", err)
+	}
+	if info.IsDir() {
+		return 0, fmt.Errorf("%s is a directory", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %v", err)
+	}
+	return n, nil
+}
+
+// GetHashTool returns the hash tool.
+func GetHashTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(HashToolName, hashDescription, HashFunc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", HashToolName, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const (
+	// hashFixtureSHA256 and hashFixtureMD5 are the digests of testdata/hash_fixture.txt
+	hashFixtureSHA256 = "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447"
+	hashFixtureMD5    = "6f5902ac237024bdd0c176cb93063dc4"
+)
+
+// TestHashFixture verifies file and content digests for both algorithms
+func TestHashFixture(t *testing.T) {
+	ctx := context.Background()
+
+	out, _ := HashFunc(ctx, HashParams{Path: "testdata/hash_fixture.txt"})
+	if !strings.HasPrefix(out, "sha256 "+hashFixtureSHA256+"  ") {
+		t.Errorf("unexpected sha256 result:\n%s", out)
+	}
+	checkOutputSchema(t, HashToolName, out)
+
+	out, _ = HashFunc(ctx, HashParams{Content: "hello world\n", Algorithm: "MD5"})
+	if !strings.HasPrefix(out, "md5 "+hashFixtureMD5+"  (content)") {
+		t.Errorf("unexpected md5 result:\n%s", out)
+	}
+
+	out, _ = HashFunc(WithWorkDir(ctx, "testdata"), HashParams{Path: "hash_fixture.txt"})
+	if !strings.Contains(out, hashFixtureSHA256) {
+		t.Errorf("relative path should resolve against the work dir:\n%s", out)
+	}
+}
+
+// TestHashCompare verifies compare mode reports match and mismatch
+func TestHashCompare(t *testing.T) {
+	ctx := context.Background()
+
+	out, _ := HashFunc(ctx, HashParams{Path: "testdata/hash_fixture.txt", Expected: " " + strings.ToUpper(hashFixtureSHA256) + "\n"})
+	if !strings.Contains(out, "\nMATCH") {
+		t.Errorf("expected MATCH:\n%s", out)
+	}
+	checkOutputSchema(t, HashToolName, out)
+
+	wrong := strings.Repeat("0", 64)
+	out, _ = HashFunc(ctx, HashParams{Path: "testdata/hash_fixture.txt", Expected: wrong})
+	if !strings.Contains(out, "MISMATCH: expected "+wrong) {
+		t.Errorf("expected MISMATCH:\n%s", out)
+	}
+	checkOutputSchema(t, HashToolName, out)
+
+	for _, p := range []HashParams{
+		{Path: "testdata/hash_fixture.txt", Expected: hashFixtureMD5},
+		{Path: "testdata/hash_fixture.txt", Algorithm: "sha1"},
+		{Path: "testdata/hash_fixture.txt", Content: "both"},
+		{Path: ".env"},
+		{Path: "testdata"},
+	} {
+		if out, _ := HashFunc(ctx, p); !strings.Contains(out, "ERROR") {
+			t.Errorf("%+v: expected an error:\n%s", p, out)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransportConfig tunes connection reuse of the HTTP transport shared by the
+// network tools
+type TransportConfig struct {
+	MaxIdleConns        int           // Idle connections kept across all hosts
+	MaxIdleConnsPerHost int           // Idle connections kept per host
+	IdleConnTimeout     time.Duration // How long idle connections stay open
+	ForceAttemptHTTP2   bool          // Try HTTP/2 even with a custom dialer or TLS config
+	Proxy               *url.URL      // Proxy for all requests; nil uses the standard HTTP_PROXY/HTTPS_PROXY variables
+}
+
+// DefaultTransportConfig returns transport settings suited to parallel
+// fetching, overridable via HTTP_MAX_IDLE_CONNS, HTTP_MAX_IDLE_CONNS_PER_HOST,
+// HTTP_IDLE_CONN_TIMEOUT (Go duration), HTTP_FORCE_ATTEMPT_HTTP2 and the
+// proxy settings read by ProxyURLFromEnv
+func DefaultTransportConfig() TransportConfig {
+	cfg := TransportConfig{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+		ForceAttemptHTTP2:   true,
+	}
+	if n, err := strconv.Atoi(os.Getenv("HTTP_MAX_IDLE_CONNS")); err == nil && n >= 0 {
+		cfg.MaxIdleConns = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("HTTP_MAX_IDLE_CONNS_PER_HOST")); err == nil && n >= 0 {
+		cfg.MaxIdleConnsPerHost = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("HTTP_IDLE_CONN_TIMEOUT")); err == nil && d >= 0 {
+		cfg.IdleConnTimeout = d
+	}
+	if b, err := strconv.ParseBool(os.Getenv("HTTP_FORCE_ATTEMPT_HTTP2")); err == nil {
+		cfg.ForceAttemptHTTP2 = b
+	}
+	// An invalid proxy is reported by CheckProxy at startup
+	if proxy, err := ProxyURLFromEnv(); err == nil {
+		cfg.Proxy = proxy
+	}
+	return cfg
+}
+
+// ProxyURLFromEnv reads the proxy for the network tools from HTTP_PROXY_URL
+// (http:// or https://) or SOCKS_PROXY_URL (socks5:// or socks5h://, e.g. Tor
+// at socks5h://127.0.0.1:9050). It returns nil when neither is set.
+func ProxyURLFromEnv() (*url.URL, error) {
+	httpProxy, socksProxy := os.Getenv("HTTP_PROXY_URL"), os.Getenv("SOCKS_PROXY_URL")
+	switch {
+	case httpProxy != "" && socksProxy != "":
+		return nil, fmt.Errorf("set only one of HTTP_PROXY_URL and SOCKS_PROXY_URL")
+	case httpProxy != "":
+		return parseProxyURL("HTTP_PROXY_URL", httpProxy, "http", "https")
+	case socksProxy != "":
+		return parseProxyURL("SOCKS_PROXY_URL", socksProxy, "socks5", "socks5h")
+	}
+	return nil, nil
+}
+
+// parseProxyURL parses a proxy URL and checks it uses one of schemes
+func parseProxyURL(name, raw string, schemes ...string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %v", name, raw, err)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid %s %q: missing host", name, raw)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s %q: scheme must be %s", name, raw, strings.Join(schemes, " or "))
+}
+
+// proxyDialTimeout bounds the startup reachability check of the proxy
+const proxyDialTimeout = 5 * time.Second
+
+// CheckProxy validates the configured proxy and that it accepts connections.
+// It returns nil when no proxy is configured.
+func CheckProxy(ctx context.Context) error {
+	proxy, err := ProxyURLFromEnv()
+	if err != nil || proxy == nil {
+		return err
+	}
+	addr := proxy.Host
+	if proxy.Port() == "" {
+		addr = net.JoinHostPort(proxy.Hostname(), defaultProxyPort(proxy.Scheme))
+	}
+	dialer := net.Dialer{Timeout: proxyDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("proxy %s is unreachable: %v", proxy.Redacted(), err)
+	}
+	conn.Close()
+	slog.Info("network tools use proxy", "proxy", proxy.Redacted())
+	return nil
+}
+
+// defaultProxyPort is the port used by net/http for a proxy URL without one
+func defaultProxyPort(scheme string) string {
+	switch scheme {
+	case "https":
+		return "443"
+	case "socks5", "socks5h":
+		return "1080"
+	}
+	return "80"
+}
+
+// newTransport builds a transport from the default one with cfg applied
+func newTransport(cfg TransportConfig) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+	t.ForceAttemptHTTP2 = cfg.ForceAttemptHTTP2
+	if cfg.Proxy != nil {
+		t.Proxy = http.ProxyURL(cfg.Proxy)
+	}
+	return t
+}
+
+// sharedTransport pools connections across fetch, fetch_table, check_urls and web_search
+var sharedTransport = newTransport(DefaultTransportConfig())
+
+// newHTTPClient returns a client with the given timeout on the shared transport
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: sharedTransport,
+	}
+}
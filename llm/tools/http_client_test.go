@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTransportConfigFromEnv verifies env settings reach the constructed transport
+func TestTransportConfigFromEnv(t *testing.T) {
+	t.Setenv("HTTP_MAX_IDLE_CONNS", "250")
+	t.Setenv("HTTP_MAX_IDLE_CONNS_PER_HOST", "32")
+	t.Setenv("HTTP_IDLE_CONN_TIMEOUT", "45s")
+	t.Setenv("HTTP_FORCE_ATTEMPT_HTTP2", "false")
+
+	tr := newTransport(DefaultTransportConfig())
+	if tr.MaxIdleConns != 250 || tr.MaxIdleConnsPerHost != 32 {
+		t.Errorf("idle conns = %d/%d, want 250/32", tr.MaxIdleConns, tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != 45*time.Second {
+		t.Errorf("idle timeout = %v, want 45s", tr.IdleConnTimeout)
+	}
+	if tr.ForceAttemptHTTP2 {
+		t.Error("HTTP/2 should be disabled")
+	}
+	if tr.Proxy == nil {
+		t.Error("transport should keep the default proxy settings")
+	}
+}
+
+// TestTransportConfigDefaults verifies defaults and that invalid values are ignored
+func TestTransportConfigDefaults(t *testing.T) {
+	t.Setenv("HTTP_MAX_IDLE_CONNS", "many")
+	t.Setenv("HTTP_IDLE_CONN_TIMEOUT", "-1s")
+
+	cfg := DefaultTransportConfig()
+	want := TransportConfig{MaxIdleConns: 100, MaxIdleConnsPerHost: 16, IdleConnTimeout: 90 * time.Second, ForceAttemptHTTP2: true}
+	if cfg != want {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+
+	client := newHTTPClient(5 * time.Second)
+	if client.Transport != sharedTransport || client.Timeout != 5*time.Second {
+		t.Errorf("client should use the shared transport with its own timeout: %+v", client)
+	}
+}
+
+// TestProxyURLFromEnv verifies proxy URLs are validated per variable
+func TestProxyURLFromEnv(t *testing.T) {
+	tests := []struct {
+		httpProxy, socksProxy string
+		want, wantErr         string
+	}{
+		{"", "", "", ""},
+		{"http://proxy.corp:3128", "", "http://proxy.corp:3128", ""},
+		{"", "socks5h://127.0.0.1:9050", "socks5h://127.0.0.1:9050", ""},
+		{"socks5://127.0.0.1:9050", "", "", "scheme must be http or https"},
+		{"", "http://127.0.0.1:9050", "", "scheme must be socks5 or socks5h"},
+		{"http://", "", "", "missing host"},
+		{"http://a:1", "socks5://b:2", "", "set only one"},
+	}
+	for _, tt := range tests {
+		t.Setenv("HTTP_PROXY_URL", tt.httpProxy)
+		t.Setenv("SOCKS_PROXY_URL", tt.socksProxy)
+		u, err := ProxyURLFromEnv()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q/%q: err = %v, want %q", tt.httpProxy, tt.socksProxy, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || (u == nil) != (tt.want == "") || u != nil && u.String() != tt.want {
+			t.Errorf("%q/%q: got %v, %v; want %q", tt.httpProxy, tt.socksProxy, u, err, tt.want)
+		}
+	}
+}
+
+// TestTransportProxy verifies a configured proxy is used for every request
+func TestTransportProxy(t *testing.T) {
+	t.Setenv("SOCKS_PROXY_URL", "socks5h://127.0.0.1:9050")
+	tr := newTransport(DefaultTransportConfig())
+
+	req, _ := http.NewRequest("GET", "https://example.com/", nil)
+	proxy, err := tr.Proxy(req)
+	if err != nil || proxy == nil || proxy.String() != "socks5h://127.0.0.1:9050" {
+		t.Errorf("proxy = %v, %v; want the SOCKS proxy", proxy, err)
+	}
+}
+
+// TestCheckProxy verifies the startup check reports an unreachable proxy
+func TestCheckProxy(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	t.Setenv("HTTP_PROXY_URL", "http://"+addr)
+	if err := CheckProxy(context.Background()); err != nil {
+		t.Errorf("reachable proxy: %v", err)
+	}
+
+	ln.Close()
+	if err := CheckProxy(context.Background()); err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("err = %v, want an unreachable proxy error", err)
+	}
+
+	t.Setenv("HTTP_PROXY_URL", "")
+	if err := CheckProxy(context.Background()); err != nil {
+		t.Errorf("no proxy configured: %v", err)
+	}
+}
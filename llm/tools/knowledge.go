@@ -1,9 +1,9 @@
 package tools
 
 import (
+	"compass/llm"
 	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/cloudwego/eino/components/tool"
@@ -22,8 +22,12 @@ const (
 
 // KnowledgeToolParams defines parameters for knowledge base search
 type KnowledgeToolParams struct {
-	Query string `json:"query" jsonschema:"description=The query to search for in the knowledge base"`
-	TopK  int    `json:"top_k,omitempty" jsonschema:"description=Number of results to return (default: 5, max: 10)"`
+	Query      string `json:"query" jsonschema:"description=The query to search for in the knowledge base"`
+	TopK       int    `json:"top_k,omitempty" jsonschema:"description=Number of results to return (default: 5, max: 10)"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to search (default: default)"`
+	Source     string `json:"source,omitempty" jsonschema:"description=Optional: only search documents ingested from this source file path"`
+	FileType   string `json:"file_type,omitempty" jsonschema:"description=Optional: only search documents of this file type (pdf, docx, md, txt, html)"`
+	Rerank     bool   `json:"rerank,omitempty" jsonschema:"description=Optional: score more candidates with the language model and return the most relevant (slower; default: false)"`
 }
 
 // knowledgeDescription is the detailed tool description for the AI
@@ -42,14 +46,23 @@ CAPABILITIES:
 PARAMETERS:
 - query (required): The question or topic to search for
 - top_k (optional): Number of results (default: 5, max: 10)
+- collection (optional): Collection (namespace) to search; see list_collections (default: default)
+- source (optional): Only search documents ingested from this source path (see list_documents)
+- file_type (optional): Only search documents of this file type
+- rerank (optional): Fetch 3x top_k candidates and let the language model re-rank them by relevance.
+  Slower; use it when the plain results look off-topic for a nuanced query
 
 OUTPUT FORMAT:
-Returns ranked results with relevance scores and content.
+Returns ranked results with relevance scores and content. With rerank, scores are
+the model's relevance ratings (0-1) instead of vector similarity.
 
 EXAMPLES:
 - Search topic: {"query": "Go design patterns"}
 - Find concept: {"query": "singleton pattern implementation"}
-- Quick lookup: {"query": "goroutine best practices"}`
+- Quick lookup: {"query": "goroutine best practices"}
+- Search a collection: {"query": "deployment checklist", "collection": "work-docs"}
+- Search one document: {"query": "reset password", "source": "./manual.md"}
+- Nuanced question: {"query": "why retries can duplicate payments", "rerank": true}`
 
 // KnowledgeToolFunc searches the knowledge base for relevant information
 func KnowledgeToolFunc(ctx context.Context, params KnowledgeToolParams) (string, error) {
@@ -61,6 +74,11 @@ func KnowledgeToolFunc(ctx context.Context, params KnowledgeToolParams) (string,
 		return Error("query parameter is required")
 	}
 
+	store, collection, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
+
 	topK := params.TopK
 	if topK <= 0 {
 		topK = DefaultTopK
@@ -69,11 +87,25 @@ func KnowledgeToolFunc(ctx context.Context, params KnowledgeToolParams) (string,
 		topK = MaxTopK
 	}
 
-	// Search the knowledge base
-	results, err := globalKnowledgeVectorStore.Search(ctx, params.Query, topK)
+	// Search the knowledge base, reusing identical searches within the run
+	filter := llm.ListFilter{
+		Source:   params.Source,
+		FileType: params.FileType,
+	}
+	// Re-ranking needs a wider candidate pool than the results returned
+	searchK := topK
+	if params.Rerank && knowledgeReranker != nil {
+		searchK = topK * rerankCandidateFactor
+	}
+	results, err := cachedKnowledgeSearch(ctx, store, collection, params.Query, searchK, filter)
 	if err != nil {
 		return Error(fmt.Sprintf("knowledge base search failed: %v", err))
 	}
+	candidates := len(results)
+	reranked := false
+	if searchK > topK {
+		results, reranked = rerankKnowledgeResults(ctx, params.Query, results, topK)
+	}
 
 	if len(results) == 0 {
 		return Success("No relevant content found in the knowledge base. Try using web_search for current information.",
@@ -82,7 +114,12 @@ func KnowledgeToolFunc(ctx context.Context, params KnowledgeToolParams) (string,
 
 	// Format results
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Found %d relevant results in knowledge base:\n\n", len(results)))
+	if reranked {
+		sb.WriteString(fmt.Sprintf("Found %d relevant results in collection %s (re-ranked from %d candidates):\n\n",
+			len(results), collection, candidates))
+	} else {
+		sb.WriteString(fmt.Sprintf("Found %d relevant results in collection %s:\n\n", len(results), collection))
+	}
 
 	for i, result := range results {
 		sb.WriteString(fmt.Sprintf("--- Result %d (score: %.2f) ---\n", i+1, result.Score))
@@ -96,23 +133,30 @@ func KnowledgeToolFunc(ctx context.Context, params KnowledgeToolParams) (string,
 		if result.Document.Title != "" {
 			sb.WriteString(fmt.Sprintf(" [title: %s]", result.Document.Title))
 		}
+		if result.Document.ID != "" {
+			sb.WriteString(fmt.Sprintf(" [id: %s]", result.Document.ID))
+		}
 		sb.WriteString("\n")
 	}
 
-	return Success(sb.String(), &Metadata{
-		MatchCount: len(results),
-	}, TierCompact)
+	md := &Metadata{MatchCount: len(results)}
+	for _, result := range results {
+		md.Sources = append(md.Sources, result.Document.Source)
+	}
+	recordMetadataSources(ctx, md)
+
+	return Success(sb.String(), md, TierCompact)
 }
 
 // GetKnowledgeTool returns the knowledge base search tool with enhanced description
-func GetKnowledgeTool() tool.InvokableTool {
+func GetKnowledgeTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(
 		KnowledgeToolName,
 		knowledgeDescription,
 		KnowledgeToolFunc,
 	)
 	if err != nil {
-		log.Fatalf("failed to create knowledge tool: %v", err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", KnowledgeToolName, err)
 	}
-	return t
+	return t, nil
 }
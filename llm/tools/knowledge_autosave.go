@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"compass/llm"
+	"compass/llm/vector"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// SavedAnswerFileType marks knowledge base documents holding saved answers
+	SavedAnswerFileType = "answer"
+
+	// savedAnswerSourcePrefix prefixes the content-derived source of a saved answer
+	savedAnswerSourcePrefix = "answer://"
+
+	// maxSavedAnswerTitle bounds the query-derived title of a saved answer
+	maxSavedAnswerTitle = 80
+)
+
+// SavedAnswer is an assistant answer to store in the knowledge base
+type SavedAnswer struct {
+	Query   string    // User request the answer responds to
+	Answer  string    // Final assistant answer
+	Sources []string  // URLs and knowledge sources consulted while answering
+	SavedAt time.Time // When the answer was produced
+}
+
+// AutoSaveCollectionFromEnv returns the collection answers are saved to
+// (COMPASS_AUTO_SAVE_COLLECTION, default: default)
+func AutoSaveCollectionFromEnv() string {
+	return os.Getenv("COMPASS_AUTO_SAVE_COLLECTION")
+}
+
+// savedAnswerSource derives a stable source from the answer text so the same
+// answer is only stored once. Case and whitespace differences are ignored.
+func savedAnswerSource(answer string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(answer), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return savedAnswerSourcePrefix + hex.EncodeToString(sum[:8])
+}
+
+// SaveAnswer chunks an answer and ingests it into the knowledge base with its
+// query, sources and timestamp as metadata. Returns false without error when
+// the same answer was saved before or the knowledge base is not initialized.
+func SaveAnswer(ctx context.Context, collection string, ans SavedAnswer) (bool, error) {
+	if globalKnowledgeVectorStore == nil {
+		return false, nil
+	}
+	answer := strings.TrimSpace(ans.Answer)
+	if answer == "" {
+		return false, fmt.Errorf("answer is empty")
+	}
+
+	store, _, err := knowledgeStore(ctx, collection)
+	if err != nil {
+		return false, err
+	}
+
+	source := savedAnswerSource(answer)
+	existing, err := store.List(ctx, llm.ListFilter{Source: source, Limit: 1})
+	if err != nil {
+		return false, fmt.Errorf("failed to check saved answers: %w", err)
+	}
+	if len(existing) > 0 {
+		return false, nil
+	}
+
+	// Short answers fall below the minimum chunk size; keep them whole
+	chunks := vector.ChunkDocument(answer, vector.DefaultChunkConfig())
+	if len(chunks) == 0 {
+		chunks = []vector.Chunk{{Content: answer}}
+	}
+
+	title := strings.Join(strings.Fields(ans.Query), " ")
+	if runes := []rune(title); len(runes) > maxSavedAnswerTitle {
+		title = string(runes[:maxSavedAnswerTitle]) + "..."
+	}
+
+	savedAt := ans.SavedAt.Format(time.RFC3339)
+	docs := make([]llm.Document, len(chunks))
+	for i, chunk := range chunks {
+		docs[i] = llm.Document{
+			ID:         fmt.Sprintf("doc_%s_%d", strings.TrimPrefix(source, savedAnswerSourcePrefix), i),
+			Content:    chunk.Content,
+			Source:     source,
+			FileType:   SavedAnswerFileType,
+			Title:      title,
+			ChunkIndex: i,
+			CreatedAt:  savedAt,
+			Metadata: map[string]interface{}{
+				"chunk_count": len(chunks),
+				"chunk_index": i,
+				"query":       ans.Query,
+				"sources":     ans.Sources,
+				"saved_at":    savedAt,
+			},
+		}
+	}
+
+	if err := store.AddBatch(ctx, docs); err != nil {
+		return false, fmt.Errorf("failed to store answer: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSaveAnswer verifies answers are stored with metadata and deduplicated by content
+func TestSaveAnswer(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	ctx := context.Background()
+	savedAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	ans := SavedAnswer{
+		Query:   "What changed in Go 1.25?",
+		Answer:  "Go 1.25 adds container-aware GOMAXPROCS defaults.",
+		Sources: []string{"https://go.dev/doc/go1.25"},
+		SavedAt: savedAt,
+	}
+
+	saved, err := SaveAnswer(ctx, "", ans)
+	if err != nil || !saved {
+		t.Fatalf("SaveAnswer = %v, %v; want saved", saved, err)
+	}
+	if len(store.docs) != 1 {
+		t.Fatalf("stored %d chunks, want 1", len(store.docs))
+	}
+	doc := store.docs[0]
+	if doc.Content != ans.Answer || doc.FileType != SavedAnswerFileType || doc.Title != ans.Query {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+	if !strings.HasPrefix(doc.Source, savedAnswerSourcePrefix) {
+		t.Errorf("source = %q, want %s prefix", doc.Source, savedAnswerSourcePrefix)
+	}
+	if doc.Metadata["query"] != ans.Query || doc.Metadata["saved_at"] != "2026-10-15T09:30:00Z" ||
+		fmt.Sprint(doc.Metadata["sources"]) != fmt.Sprint(ans.Sources) {
+		t.Errorf("unexpected metadata: %+v", doc.Metadata)
+	}
+
+	// The same answer with different spacing and case is not saved again
+	ans.Answer = "  go 1.25 adds container-aware\nGOMAXPROCS defaults. "
+	if saved, err := SaveAnswer(ctx, "", ans); err != nil || saved {
+		t.Errorf("duplicate SaveAnswer = %v, %v; want skipped", saved, err)
+	}
+	if len(store.docs) != 1 {
+		t.Errorf("duplicate answer stored: %d chunks", len(store.docs))
+	}
+
+	if _, err := SaveAnswer(ctx, "", SavedAnswer{Query: "q", Answer: " "}); err == nil {
+		t.Error("expected error for empty answer")
+	}
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"compass/llm"
+	"compass/llm/vector"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultKnowledgeCacheTTL is how long a knowledge search result is reused within a run
+const DefaultKnowledgeCacheTTL = 5 * time.Minute
+
+// KnowledgeCacheTTLFromEnv reads KNOWLEDGE_SEARCH_CACHE_TTL (Go duration, "0" disables)
+func KnowledgeCacheTTLFromEnv() time.Duration {
+	if val := os.Getenv("KNOWLEDGE_SEARCH_CACHE_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+	}
+	return DefaultKnowledgeCacheTTL
+}
+
+type knowledgeCacheKey struct{}
+
+// knowledgeCacheEntry is a cached search result set
+type knowledgeCacheEntry struct {
+	results []llm.SearchResult
+	at      time.Time
+}
+
+// knowledgeSearchCache caches knowledge search results for a single run
+type knowledgeSearchCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]knowledgeCacheEntry
+}
+
+// WithKnowledgeSearchCache returns a context carrying a fresh knowledge search
+// cache, so identical searches within one run skip embedding and search.
+// Each run gets its own cache; ttl <= 0 disables caching.
+func WithKnowledgeSearchCache(ctx context.Context, ttl time.Duration) context.Context {
+	if ttl <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, knowledgeCacheKey{}, &knowledgeSearchCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]knowledgeCacheEntry),
+	})
+}
+
+// knowledgeCacheFromContext returns the run's search cache, or nil if unset
+func knowledgeCacheFromContext(ctx context.Context) *knowledgeSearchCache {
+	c, _ := ctx.Value(knowledgeCacheKey{}).(*knowledgeSearchCache)
+	return c
+}
+
+// knowledgeCacheKeyFor builds the cache key of a search
+func knowledgeCacheKeyFor(collection, query string, topK int, filter llm.ListFilter) string {
+	return fmt.Sprintf("%s\x00%s\x00%d\x00%s\x00%s", collection, query, topK, filter.Source, filter.FileType)
+}
+
+// lookup returns cached results still within the ttl
+func (c *knowledgeSearchCache) lookup(key string) ([]llm.SearchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.now().Sub(entry.at) > c.ttl {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.results, true
+}
+
+// store caches search results
+func (c *knowledgeSearchCache) store(key string, results []llm.SearchResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = knowledgeCacheEntry{results: results, at: c.now()}
+}
+
+// knowledgeToolCacheInput keys knowledge searches in the persistent tool cache
+type knowledgeToolCacheInput struct {
+	Collection string
+	Query      string
+	TopK       int
+	Source     string
+	FileType   string
+}
+
+// invalidateKnowledgeCache drops the run's and the persisted cached searches
+// after the knowledge base changed
+func invalidateKnowledgeCache(ctx context.Context) {
+	globalToolCache.Clear(KnowledgeToolName)
+	c := knowledgeCacheFromContext(ctx)
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// cachedKnowledgeSearch searches the store, reusing the run's cached results
+// for an identical collection, query, top k and filter
+func cachedKnowledgeSearch(ctx context.Context, store vector.VectorStore, collection, query string, topK int, filter llm.ListFilter) ([]llm.SearchResult, error) {
+	cache := knowledgeCacheFromContext(ctx)
+	key := knowledgeCacheKeyFor(collection, query, topK, filter)
+	if cache != nil {
+		if results, ok := cache.lookup(key); ok {
+			return results, nil
+		}
+	}
+
+	results, err := persistedKnowledgeSearch(ctx, store, collection, query, topK, filter)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		cache.store(key, results)
+	}
+	return results, nil
+}
+
+// persistedKnowledgeSearch searches the store through the persistent tool
+// cache, which outlives the run
+func persistedKnowledgeSearch(ctx context.Context, store vector.VectorStore, collection, query string, topK int, filter llm.ListFilter) ([]llm.SearchResult, error) {
+	input := knowledgeToolCacheInput{collection, query, topK, filter.Source, filter.FileType}
+	var results []llm.SearchResult
+	if globalToolCache.Get(KnowledgeToolName, input, &results) {
+		return results, nil
+	}
+	results, err := store.Search(ctx, query, topK, filter)
+	if err != nil {
+		return nil, err
+	}
+	globalToolCache.Put(KnowledgeToolName, input, results)
+	return results, nil
+}
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestKnowledgeSearchCache verifies identical searches within a run embed and search once
+func TestKnowledgeSearchCache(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	dir := t.TempDir()
+	manual := writeTestFile(t, dir, "manual.md", "To reset the password open the admin console and choose the reset option under account settings, then confirm the change by email.\n")
+
+	run := WithKnowledgeSearchCache(context.Background(), time.Minute)
+	if out, _ := IngestDocumentFunc(run, IngestDocumentParams{FilePath: manual}); strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+
+	first, _ := KnowledgeToolFunc(run, KnowledgeToolParams{Query: "reset the password"})
+	second, _ := KnowledgeToolFunc(run, KnowledgeToolParams{Query: "reset the password"})
+	if store.searches != 1 {
+		t.Fatalf("searches = %d, want 1 for a repeated query", store.searches)
+	}
+	if first != second {
+		t.Errorf("cached result differs:\n%s\n---\n%s", first, second)
+	}
+
+	// Different parameters are separate entries
+	KnowledgeToolFunc(run, KnowledgeToolParams{Query: "reset the password", TopK: 3})
+	if store.searches != 2 {
+		t.Errorf("searches = %d, want 2 after changing top_k", store.searches)
+	}
+
+	// A new run does not see the previous run's cache
+	next := WithKnowledgeSearchCache(context.Background(), time.Minute)
+	KnowledgeToolFunc(next, KnowledgeToolParams{Query: "reset the password"})
+	if store.searches != 3 {
+		t.Errorf("searches = %d, want 3 in a new run", store.searches)
+	}
+
+	// Ingesting invalidates the run's cache
+	IngestDocumentFunc(next, IngestDocumentParams{FilePath: manual})
+	KnowledgeToolFunc(next, KnowledgeToolParams{Query: "reset the password"})
+	if store.searches != 4 {
+		t.Errorf("searches = %d, want 4 after ingest", store.searches)
+	}
+
+	// Without a cache every search runs
+	KnowledgeToolFunc(context.Background(), KnowledgeToolParams{Query: "reset the password"})
+	KnowledgeToolFunc(context.Background(), KnowledgeToolParams{Query: "reset the password"})
+	if store.searches != 6 {
+		t.Errorf("searches = %d, want 6 without a cache", store.searches)
+	}
+}
+
+// TestKnowledgeSearchCacheExpiry verifies entries older than the ttl are not reused
+func TestKnowledgeSearchCacheExpiry(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	run := WithKnowledgeSearchCache(context.Background(), time.Minute)
+	cache := knowledgeCacheFromContext(run)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	KnowledgeToolFunc(run, KnowledgeToolParams{Query: "anything"})
+	now = now.Add(2 * time.Minute)
+	KnowledgeToolFunc(run, KnowledgeToolParams{Query: "anything"})
+	if store.searches != 2 {
+		t.Errorf("searches = %d, want 2 after the ttl expired", store.searches)
+	}
+
+	if WithKnowledgeSearchCache(context.Background(), 0) != context.Background() {
+		t.Error("ttl 0 should disable the cache")
+	}
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"compass/llm/vector"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const (
+	// ListCollectionsToolName is the name of the collection listing tool
+	ListCollectionsToolName = "list_collections"
+)
+
+// knowledgeStore returns the vector store scoped to a collection and the
+// normalized collection name. An empty collection selects the default one.
+func knowledgeStore(ctx context.Context, collection string) (vector.VectorStore, string, error) {
+	name, err := vector.NormalizeCollection(collection)
+	if err != nil {
+		return nil, "", err
+	}
+	if name == vector.DefaultCollection {
+		return globalKnowledgeVectorStore, name, nil
+	}
+
+	cs, ok := globalKnowledgeVectorStore.(vector.CollectionStore)
+	if !ok {
+		return nil, "", fmt.Errorf("the vector store does not support collections")
+	}
+	store, err := cs.Collection(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	return store, name, nil
+}
+
+// listCollectionsDescription is the detailed tool description for the AI
+const listCollectionsDescription = `List the collections (namespaces) in the knowledge base.
+
+USE CASES:
+- See which collections exist before searching or ingesting
+- Check how many documents each collection holds
+
+PARAMETERS:
+- None
+
+OUTPUT FORMAT:
+Returns each collection name with its document chunk count.
+The "default" collection is used when no collection is given.
+
+EXAMPLES:
+- List collections: {}`
+
+// ListCollectionsParams defines parameters for listing collections
+type ListCollectionsParams struct{}
+
+// ListCollectionsFunc lists the knowledge base collections
+func ListCollectionsFunc(ctx context.Context, _ ListCollectionsParams) (string, error) {
+	if globalKnowledgeVectorStore == nil {
+		return Error("vector store is not initialized")
+	}
+
+	names := []string{vector.DefaultCollection}
+	if cs, ok := globalKnowledgeVectorStore.(vector.CollectionStore); ok {
+		var err error
+		names, err = cs.ListCollections(ctx)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to list collections: %v", err))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d collection(s):\n\n", len(names)))
+	for _, name := range names {
+		store, _, err := knowledgeStore(ctx, name)
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("📚 %s (unavailable: %v)\n", name, err))
+			continue
+		}
+		count, _ := store.Count(ctx)
+		sb.WriteString(fmt.Sprintf("📚 %s: %d chunks\n", name, count))
+	}
+
+	return Success(sb.String(), &Metadata{
+		MatchCount: len(names),
+	}, TierCompact)
+}
+
+// GetListCollectionsTool returns the collection listing tool
+func GetListCollectionsTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(
+		ListCollectionsToolName,
+		listCollectionsDescription,
+		ListCollectionsFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", ListCollectionsToolName, err)
+	}
+	return t, nil
+}
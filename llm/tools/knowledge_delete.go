@@ -2,6 +2,7 @@ package tools
 
 import (
 	"compass/llm"
+	"compass/llm/vector"
 	"context"
 	"fmt"
 	"strings"
@@ -26,6 +27,7 @@ USE CASES:
 PARAMETERS (one required):
 - source: Delete all documents from this source file path
 - id: Delete a specific document by its ID
+- collection (optional): Collection (namespace) to delete from (default: default)
 
 WARNING:
 - This operation cannot be undone
@@ -42,8 +44,9 @@ SAFETY:
 
 // DeleteDocumentParams defines parameters for document deletion
 type DeleteDocumentParams struct {
-	Source string `json:"source,omitempty" jsonschema:"description=Source file path to delete all documents from"`
-	ID     string `json:"id,omitempty" jsonschema:"description=Specific document ID to delete"`
+	Source     string `json:"source,omitempty" jsonschema:"description=Source file path to delete all documents from"`
+	ID         string `json:"id,omitempty" jsonschema:"description=Specific document ID to delete"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to delete from (default: default)"`
 }
 
 // DeleteDocumentFunc deletes documents from the knowledge base
@@ -57,76 +60,77 @@ func DeleteDocumentFunc(ctx context.Context, params DeleteDocumentParams) (strin
 		return Error("either 'source' or 'id' parameter is required")
 	}
 
-	var deletedCount int
-	var err error
+	store, _, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
 
 	if params.ID != "" {
 		// Delete specific document by ID
-		err = globalKnowledgeVectorStore.Delete(ctx, params.ID)
+		deletedCount, err := store.DeleteBatch(ctx, []string{params.ID})
 		if err != nil {
 			return Error(fmt.Sprintf("failed to delete document: %v", err))
 		}
-		deletedCount = 1
-	} else {
-		// Delete all documents from source
-		source := strings.TrimSpace(params.Source)
-
-		// Check what we're about to delete
-		filter := llm.ListFilter{
-			Source: source,
-			Limit:  1000,
-		}
-		docs, checkErr := globalKnowledgeVectorStore.List(ctx, filter)
-		if checkErr == nil && len(docs) > 0 {
-			// Get the title before deleting
-			title := docs[0].Title
-			fileType := docs[0].FileType
-
-			err = globalKnowledgeVectorStore.DeleteBySource(ctx, source)
-			if err != nil {
-				return Error(fmt.Sprintf("failed to delete documents: %v", err))
-			}
-			deletedCount = len(docs)
-
-			// Get updated count
-			totalCount, _ := globalKnowledgeVectorStore.Count(ctx)
-
-			return Success(fmt.Sprintf("Deleted document:\n"+
-				"  Title: %s\n"+
-				"  Source: %s\n"+
-				"  Type: %s\n"+
-				"  Chunks removed: %d\n"+
-				"  Remaining documents: %d",
-				title, source, fileType, deletedCount, totalCount),
-				&Metadata{
-					FilePath:   source,
-					MatchCount: deletedCount,
-				}, TierCompact)
+		if deletedCount == 0 {
+			return Success(fmt.Sprintf("No document found with id: %s", params.ID), nil, TierCompact)
 		}
+		invalidateKnowledgeCache(ctx)
+
+		// Get updated count
+		totalCount, _ := store.Count(ctx)
+
+		return Success(fmt.Sprintf("Deleted %d document(s). Remaining: %d",
+			deletedCount, totalCount),
+			&Metadata{
+				MatchCount: deletedCount,
+			}, TierCompact)
+	}
 
+	// Delete all documents from source
+	source := strings.TrimSpace(params.Source)
+
+	// Get the title and type of the document before deleting
+	var first *llm.Document
+	err = store.ListAll(ctx, llm.ListFilter{Source: source}, func(doc llm.Document) error {
+		first = &doc
+		return vector.ErrStopIteration
+	})
+	if err != nil || first == nil {
 		return Success(fmt.Sprintf("No documents found for source: %s", source),
 			nil, TierCompact)
 	}
 
-	// Get updated count
-	totalCount, _ := globalKnowledgeVectorStore.Count(ctx)
+	deletedCount, err := store.DeleteBySource(ctx, source)
+	if err != nil {
+		return Error(fmt.Sprintf("failed to delete documents: %v", err))
+	}
+	invalidateKnowledgeCache(ctx)
 
-	return Success(fmt.Sprintf("Deleted %d document(s). Remaining: %d",
-		deletedCount, totalCount),
+	// Get updated count
+	totalCount, _ := store.Count(ctx)
+
+	return Success(fmt.Sprintf("Deleted document:\n"+
+		"  Title: %s\n"+
+		"  Source: %s\n"+
+		"  Type: %s\n"+
+		"  Chunks removed: %d\n"+
+		"  Remaining documents: %d",
+		first.Title, source, first.FileType, deletedCount, totalCount),
 		&Metadata{
+			FilePath:   source,
 			MatchCount: deletedCount,
 		}, TierCompact)
 }
 
 // GetDeleteDocumentTool returns the document deletion tool
-func GetDeleteDocumentTool() tool.InvokableTool {
+func GetDeleteDocumentTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(
 		DeleteDocumentToolName,
 		deleteKnowledgeDocumentDescription,
 		DeleteDocumentFunc,
 	)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("failed to create %s tool: %w", DeleteDocumentToolName, err)
 	}
-	return t
+	return t, nil
 }
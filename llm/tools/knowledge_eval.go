@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"compass/llm"
+	"compass/llm/vector"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const (
+	// EvalRetrievalToolName is the name of the retrieval evaluation tool
+	EvalRetrievalToolName = "eval_retrieval"
+
+	// maxEvalCases bounds the number of questions in one evaluation
+	maxEvalCases = 500
+)
+
+// RetrievalEvalCase is a question paired with the source expected to answer it
+type RetrievalEvalCase struct {
+	Question       string `json:"question"`
+	ExpectedSource string `json:"expected_source"`
+}
+
+// RetrievalReport holds the aggregate and per-question evaluation results
+type RetrievalReport struct {
+	TopK   int
+	Recall float64 // Fraction of questions with the expected source in the top k
+	MRR    float64 // Mean reciprocal rank of the first expected-source result
+	Ranks  []int   // 1-based rank per question, 0 when not retrieved
+}
+
+// evalRetrievalDescription is the detailed tool description for the AI
+const evalRetrievalDescription = `Evaluate knowledge base retrieval quality against a set of questions.
+
+USE CASES:
+- Measure retrieval quality after ingesting documents
+- Compare chunk size or embedding configurations
+- Find questions whose expected document is not retrieved
+
+PARAMETERS:
+- eval_file (required): Path to a JSON file with the evaluation set
+- top_k (optional): Number of results per question (default: 5, max: 10)
+- collection (optional): Collection (namespace) to evaluate (default: default)
+
+EVAL FILE FORMAT:
+A JSON array of {"question": "...", "expected_source": "..."} objects.
+expected_source is the ingested file path (as shown by list_documents) or its trailing part.
+
+OUTPUT FORMAT:
+Returns recall@k (share of questions whose expected source appears in the top k)
+and MRR (mean reciprocal rank of the first matching result), followed by the
+rank of each question.
+
+EXAMPLES:
+- Evaluate: {"eval_file": "./eval/questions.json"}
+- Evaluate top 10 in a collection: {"eval_file": "./eval/work.json", "top_k": 10, "collection": "work-docs"}`
+
+// EvalRetrievalParams defines parameters for retrieval evaluation
+type EvalRetrievalParams struct {
+	EvalFile   string `json:"eval_file" jsonschema:"description=Path to a JSON file containing an array of {question, expected_source} objects"`
+	TopK       int    `json:"top_k,omitempty" jsonschema:"description=Number of results per question (default: 5, max: 10)"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to evaluate (default: default)"`
+}
+
+// EvalRetrievalFunc runs the evaluation set against the knowledge base
+func EvalRetrievalFunc(ctx context.Context, params EvalRetrievalParams) (string, error) {
+	if globalKnowledgeVectorStore == nil {
+		return Error("vector store is not initialized")
+	}
+	if strings.TrimSpace(params.EvalFile) == "" {
+		return Error("eval_file parameter is required")
+	}
+
+	store, collection, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	cases, err := loadRetrievalEvalSet(resolvePath(ctx, params.EvalFile))
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	topK := params.TopK
+	if topK <= 0 {
+		topK = DefaultTopK
+	}
+	if topK > MaxTopK {
+		topK = MaxTopK
+	}
+
+	report, err := evaluateRetrieval(ctx, store, cases, topK)
+	if err != nil {
+		return Error(fmt.Sprintf("evaluation failed: %v", err))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Retrieval evaluation of %d questions in collection %s:\n", len(cases), collection))
+	sb.WriteString(fmt.Sprintf("  Recall@%d: %.3f\n", report.TopK, report.Recall))
+	sb.WriteString(fmt.Sprintf("  MRR: %.3f\n\n", report.MRR))
+	for i, c := range cases {
+		if rank := report.Ranks[i]; rank > 0 {
+			sb.WriteString(fmt.Sprintf("✅ rank %d: %s\n", rank, c.Question))
+		} else {
+			sb.WriteString(fmt.Sprintf("❌ not in top %d: %s (expected %s)\n", report.TopK, c.Question, c.ExpectedSource))
+		}
+	}
+
+	return Success(sb.String(), &Metadata{
+		FilePath:   params.EvalFile,
+		MatchCount: len(cases),
+	}, TierCompact)
+}
+
+// loadRetrievalEvalSet reads and validates an evaluation set
+func loadRetrievalEvalSet(path string) ([]RetrievalEvalCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read eval file: %v", err)
+	}
+
+	var cases []RetrievalEvalCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		return nil, fmt.Errorf("invalid eval file (expected a JSON array of {question, expected_source}): %v", err)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("eval file contains no questions")
+	}
+	if len(cases) > maxEvalCases {
+		return nil, fmt.Errorf("too many questions: %d (max %d)", len(cases), maxEvalCases)
+	}
+	for i, c := range cases {
+		if strings.TrimSpace(c.Question) == "" || strings.TrimSpace(c.ExpectedSource) == "" {
+			return nil, fmt.Errorf("entry %d needs both question and expected_source", i+1)
+		}
+	}
+	return cases, nil
+}
+
+// evaluateRetrieval searches each question and computes recall@k and MRR
+func evaluateRetrieval(ctx context.Context, store vector.VectorStore, cases []RetrievalEvalCase, topK int) (RetrievalReport, error) {
+	report := RetrievalReport{TopK: topK, Ranks: make([]int, len(cases))}
+	if len(cases) == 0 {
+		return report, nil
+	}
+
+	hits, reciprocal := 0, 0.0
+	for i, c := range cases {
+		results, err := store.Search(ctx, c.Question, topK, llm.ListFilter{})
+		if err != nil {
+			return report, fmt.Errorf("question %d: %w", i+1, err)
+		}
+		for j, r := range results {
+			if j >= topK {
+				break
+			}
+			if sourceMatches(r.Document.Source, c.ExpectedSource) {
+				report.Ranks[i] = j + 1
+				hits++
+				reciprocal += 1 / float64(j+1)
+				break
+			}
+		}
+	}
+
+	report.Recall = float64(hits) / float64(len(cases))
+	report.MRR = reciprocal / float64(len(cases))
+	return report, nil
+}
+
+// sourceMatches reports whether a stored source path is the expected one.
+// The expected source may be the full path or any trailing path segments.
+func sourceMatches(source, expected string) bool {
+	source = filepath.ToSlash(filepath.Clean(source))
+	expected = filepath.ToSlash(filepath.Clean(expected))
+	return source == expected || strings.HasSuffix(source, "/"+expected)
+}
+
+// GetEvalRetrievalTool returns the retrieval evaluation tool
+func GetEvalRetrievalTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(
+		EvalRetrievalToolName,
+		evalRetrievalDescription,
+		EvalRetrievalFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", EvalRetrievalToolName, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"compass/llm"
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+// rankedStore returns fixed source rankings per question
+type rankedStore struct {
+	fakeVectorStore
+	ranked map[string][]string
+}
+
+func (s *rankedStore) Search(ctx context.Context, query string, topK int, filter llm.ListFilter) ([]llm.SearchResult, error) {
+	var results []llm.SearchResult
+	for i, source := range s.ranked[query] {
+		if i >= topK {
+			break
+		}
+		results = append(results, llm.SearchResult{
+			Document: llm.Document{Source: source},
+			Score:    1 - float32(i)/10,
+		})
+	}
+	return results, nil
+}
+
+// newRankedStore ranks the fixture questions: first, third, second and missing
+func newRankedStore() *rankedStore {
+	return &rankedStore{ranked: map[string][]string{
+		"How do I reset my password?":    {"/kb/docs/manual.md", "/kb/docs/api.md"},
+		"What are the API rate limits?":  {"/kb/docs/manual.md", "/kb/docs/deploy.md", "/kb/docs/api.md"},
+		"How do I deploy to production?": {"/kb/docs/manual.md", "/kb/docs/deploy.md"},
+		"Which regions are supported?":   {"/kb/docs/api.md", "/kb/other/regions.md"},
+	}}
+}
+
+// TestEvaluateRetrieval verifies recall@k and MRR on the fixture eval set
+func TestEvaluateRetrieval(t *testing.T) {
+	cases, err := loadRetrievalEvalSet("testdata/retrieval_eval.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newRankedStore()
+
+	tests := []struct {
+		topK   int
+		recall float64
+		mrr    float64
+		ranks  []int
+	}{
+		{topK: 5, recall: 0.75, mrr: (1 + 1.0/3 + 1.0/2) / 4, ranks: []int{1, 3, 2, 0}},
+		{topK: 2, recall: 0.5, mrr: (1 + 1.0/2) / 4, ranks: []int{1, 0, 2, 0}},
+	}
+	for _, tt := range tests {
+		report, err := evaluateRetrieval(context.Background(), store, cases, tt.topK)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(report.Recall-tt.recall) > 1e-9 {
+			t.Errorf("recall@%d = %v, want %v", tt.topK, report.Recall, tt.recall)
+		}
+		if math.Abs(report.MRR-tt.mrr) > 1e-9 {
+			t.Errorf("MRR@%d = %v, want %v", tt.topK, report.MRR, tt.mrr)
+		}
+		for i, rank := range tt.ranks {
+			if report.Ranks[i] != rank {
+				t.Errorf("top %d: question %d rank = %d, want %d", tt.topK, i+1, report.Ranks[i], rank)
+			}
+		}
+	}
+}
+
+// TestEvalRetrievalTool verifies the tool reports metrics and per-question ranks
+func TestEvalRetrievalTool(t *testing.T) {
+	InitKnowledgeVectorStore(newRankedStore(), nil, nil)
+	t.Cleanup(func() { InitKnowledgeVectorStore(nil, nil, nil) })
+
+	out, _ := EvalRetrievalFunc(context.Background(), EvalRetrievalParams{EvalFile: "testdata/retrieval_eval.json"})
+	for _, want := range []string{
+		"Retrieval evaluation of 4 questions in collection default:",
+		"Recall@5: 0.750",
+		"MRR: 0.458",
+		"✅ rank 3: What are the API rate limits?",
+		"❌ not in top 5: Which regions are supported? (expected docs/regions.md)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	checkOutputSchema(t, EvalRetrievalToolName, out)
+
+	path := writeTestFile(t, t.TempDir(), "bad.json", `[{"question": "no source"}]`)
+	if out, _ := EvalRetrievalFunc(context.Background(), EvalRetrievalParams{EvalFile: path}); !strings.Contains(out, "ERROR") {
+		t.Errorf("expected an error for an entry without expected_source:\n%s", out)
+	}
+}
+
+// TestSourceMatches verifies full paths and trailing segments match
+func TestSourceMatches(t *testing.T) {
+	tests := []struct {
+		source, expected string
+		want             bool
+	}{
+		{"/kb/docs/api.md", "/kb/docs/api.md", true},
+		{"/kb/docs/api.md", "docs/api.md", true},
+		{"/kb/docs/api.md", "./api.md", true},
+		{"/kb/docs/myapi.md", "api.md", false},
+		{"/kb/docs/api.md", "other/api.md", false},
+	}
+	for _, tt := range tests {
+		if got := sourceMatches(tt.source, tt.expected); got != tt.want {
+			t.Errorf("sourceMatches(%q, %q) = %v, want %v", tt.source, tt.expected, got, tt.want)
+		}
+	}
+}
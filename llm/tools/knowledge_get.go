@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"compass/llm"
+	"compass/llm/vector"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const (
+	// GetDocumentToolName is the name of the full document retrieval tool
+	GetDocumentToolName = "get_document"
+)
+
+// getKnowledgeDocumentDescription is the detailed tool description for the AI
+const getKnowledgeDocumentDescription = `Read back the full stored content of a knowledge base document.
+
+USE CASES:
+- Read a whole document after search_knowledge returned one of its chunks
+- Check exactly what was saved by ingest_document or save_answer
+
+PARAMETERS (one required):
+- source: Source path or URL of the document
+- id: ID of any of its chunks, as shown by search_knowledge
+- collection (optional): Collection (namespace) to read from (default: default)
+
+OUTPUT FORMAT:
+'Document: <title>' followed by indented Source, Type and Chunks lines, a
+blank line, then the content of all chunks joined in chunk order.
+
+EXAMPLES:
+- By source: {"source": "./docs/api.md"}
+- By chunk ID: {"id": "a1b2c3..."}`
+
+// GetDocumentParams defines parameters for retrieving a document
+type GetDocumentParams struct {
+	Source     string `json:"source,omitempty" jsonschema:"description=Source file path or URL of the document"`
+	ID         string `json:"id,omitempty" jsonschema:"description=ID of any chunk of the document"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to read from (default: default)"`
+}
+
+// GetDocumentFunc returns a stored document reassembled from its chunks
+func GetDocumentFunc(ctx context.Context, params GetDocumentParams) (string, error) {
+	if globalKnowledgeVectorStore == nil {
+		return Error("vector store is not initialized")
+	}
+
+	source, id := strings.TrimSpace(params.Source), strings.TrimSpace(params.ID)
+	if source == "" && id == "" {
+		return Error("either 'source' or 'id' parameter is required")
+	}
+
+	store, _, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	// Resolve a chunk ID to the source of its document
+	if source == "" {
+		doc, err := findDocumentByID(ctx, store, id)
+		if err != nil {
+			return Error(fmt.Sprintf("failed to look up document: %v", err))
+		}
+		if doc == nil {
+			return Error(fmt.Sprintf("no document found with id: %s", id))
+		}
+		source = doc.Source
+	}
+
+	var chunks []llm.Document
+	err = store.ListAll(ctx, llm.ListFilter{Source: source}, func(doc llm.Document) error {
+		chunks = append(chunks, doc)
+		return nil
+	})
+	if err != nil {
+		return Error(fmt.Sprintf("failed to list document chunks: %v", err))
+	}
+	if len(chunks) == 0 {
+		return Error(fmt.Sprintf("no document found for source: %s", source))
+	}
+
+	sort.SliceStable(chunks, func(a, b int) bool {
+		return chunks[a].ChunkIndex < chunks[b].ChunkIndex
+	})
+	parts := make([]string, len(chunks))
+	for i, c := range chunks {
+		parts[i] = c.Content
+	}
+
+	first := chunks[0]
+	content := fmt.Sprintf("Document: %s\n  Source: %s\n  Type: %s\n  Chunks: %d\n\n%s",
+		first.Title, source, first.FileType, len(chunks), strings.Join(parts, "\n\n"))
+	md := &Metadata{
+		FilePath:   source,
+		MatchCount: len(chunks),
+		Sources:    []string{source},
+	}
+	recordMetadataSources(ctx, md)
+	return Success(content, md, TierCompact)
+}
+
+// findDocumentByID returns the stored document with the given ID, or nil.
+// The store has no lookup by ID, so documents are scanned page by page.
+func findDocumentByID(ctx context.Context, store vector.VectorStore, id string) (*llm.Document, error) {
+	var found *llm.Document
+	err := store.ListAll(ctx, llm.ListFilter{}, func(doc llm.Document) error {
+		if doc.ID == id {
+			found = &doc
+			return vector.ErrStopIteration
+		}
+		return nil
+	})
+	return found, err
+}
+
+// GetGetDocumentTool returns the full document retrieval tool
+func GetGetDocumentTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(
+		GetDocumentToolName,
+		getKnowledgeDocumentDescription,
+		GetDocumentFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", GetDocumentToolName, err)
+	}
+	return t, nil
+}
@@ -18,6 +18,10 @@ import (
 const (
 	// IngestDocumentToolName is the name of the document ingestion tool
 	IngestDocumentToolName = "ingest_document"
+
+	// minIngestChunkSize and maxIngestChunkSize bound per-ingest chunk size overrides
+	minIngestChunkSize = 200
+	maxIngestChunkSize = 8000
 )
 
 var (
@@ -36,10 +40,25 @@ func InitKnowledgeVectorStore(vs vector.VectorStore, p *parser.Registry, emb emb
 	globalKnowledgeEmbedder = emb
 }
 
+// SetKnowledgeEmbedder replaces the embedding model referenced by the knowledge tools
+func SetKnowledgeEmbedder(emb embedding.Embedder) {
+	globalKnowledgeEmbedder = emb
+	// Persisted searches were ranked with the previous model
+	globalToolCache.Clear(KnowledgeToolName)
+}
+
 // IngestDocumentParams defines parameters for document ingestion
 type IngestDocumentParams struct {
-	FilePath string `json:"file_path" jsonschema:"description=Path to the file to ingest into the knowledge base"`
-	Title    string `json:"title,omitempty" jsonschema:"description=Optional title for the document (defaults to filename)"`
+	FilePath   string `json:"file_path" jsonschema:"description=Path to the file to ingest into the knowledge base"`
+	Title      string `json:"title,omitempty" jsonschema:"description=Optional title for the document (defaults to filename)"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to ingest into (default: default)"`
+	TTL        string `json:"ttl,omitempty" jsonschema:"description=Optional expiry as a duration (e.g. 72h) after which the document is removed; default: VECTOR_DOCUMENT_TTL"`
+
+	// Per-ingest chunking overrides
+	ChunkSize        int   `json:"chunk_size,omitempty" jsonschema:"description=Optional chunk size in characters (200-8000, default from CHUNK_SIZE)"`
+	ChunkOverlap     *int  `json:"chunk_overlap,omitempty" jsonschema:"description=Optional overlap between chunks in characters (at most half the chunk size)"`
+	SplitByParagraph *bool `json:"split_by_paragraph,omitempty" jsonschema:"description=Optional: split on paragraph boundaries first (default: true)"`
+	ChunkByHeading   *bool `json:"chunk_by_heading,omitempty" jsonschema:"description=Optional: for markdown, keep each # / ## section together and prefix chunks with their heading trail (default from CHUNK_BY_HEADING)"`
 }
 
 // ingestDescription is the detailed tool description for the AI
@@ -48,6 +67,9 @@ const ingestDescription = `Ingest a document file into the knowledge base for se
 SUPPORTED FORMATS:
 - Text files (.txt)
 - Markdown files (.md, .markdown)
+- Word documents (.docx)
+- CSV files (.csv), one "column: value" line per row
+- JSON files (.json), flattened to "dot.path: value" lines
 - HTML files (.html, .htm)
 
 USE CASES:
@@ -58,6 +80,12 @@ USE CASES:
 PARAMETERS:
 - file_path (required): Path to the file to ingest
 - title (optional): Custom title for the document
+- collection (optional): Collection (namespace) to ingest into, e.g. "work-docs" (default: default)
+- chunk_size (optional): Chunk size in characters, clamped to 200-8000
+- chunk_overlap (optional): Overlap between chunks, clamped to half the chunk size
+- split_by_paragraph (optional): Split on paragraph boundaries first (default: true)
+- chunk_by_heading (optional): Markdown only. Keep each # / ## section in its own chunks, prefixed with the heading trail
+- ttl (optional): Expire the document after this duration, e.g. "72h" for cached web research
 
 PROCESS:
 1. File content is parsed according to its type
@@ -68,10 +96,17 @@ PROCESS:
 EXAMPLES:
 - Ingest markdown: {"file_path": "./docs/api.md"}
 - Ingest with title: {"file_path": "./reference.txt", "title": "API Reference"}
+- Ingest into a collection: {"file_path": "./notes.md", "collection": "personal-notes"}
+- Larger chunks for prose: {"file_path": "./guide.md", "chunk_size": 2000, "chunk_overlap": 300}
+- Smaller chunks for reference: {"file_path": "./api.md", "chunk_size": 400}
+- Section-aligned chunks: {"file_path": "./handbook.md", "chunk_by_heading": true}
+- Expiring research notes: {"file_path": "./research/pricing.md", "ttl": "168h"}
 
 NOTES:
 - Large files are automatically chunked for optimal retrieval
+- Use larger chunks for narrative prose, smaller ones for dense reference material
 - Existing documents with the same source path are replaced
+- Chunks whose exact content is already in the collection are skipped
 - Use list_documents to see what's in the knowledge base`
 
 // IngestDocumentFunc ingests a document into the knowledge base
@@ -88,45 +123,138 @@ func IngestDocumentFunc(ctx context.Context, params IngestDocumentParams) (strin
 		return Error("file_path parameter is required")
 	}
 
+	store, collection, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	addOpts, err := ingestAddOptions(params.TTL)
+	if err != nil {
+		return Error(err.Error())
+	}
+
 	// Clean the path
-	filePath = filepath.Clean(filePath)
+	filePath = filepath.Clean(resolvePath(ctx, filePath))
+
+	prepared, err := prepareIngest(ctx, filePath, params)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	added, skipped, err := storeIngest(ctx, store, filePath, prepared.docs, addOpts...)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	// Get updated count
+	count, _ := store.Count(ctx)
+
+	return Success(fmt.Sprintf("Document ingested successfully:\n"+
+		"  Collection: %s\n"+
+		"  Title: %s\n"+
+		"  Source: %s\n"+
+		"  Type: %s\n"+
+		"  Chunks: %d (%d added, %d duplicates skipped)\n"+
+		"  Chunking: size=%d overlap=%d split_by_paragraph=%t by_heading=%t\n"+
+		"  Total documents in collection: %d",
+		collection, prepared.title, filePath, prepared.fileType, len(prepared.docs), added, skipped,
+		prepared.chunkConfig.ChunkSize, prepared.chunkConfig.ChunkOverlap,
+		prepared.chunkConfig.SplitByParagraph, prepared.chunkConfig.ChunkByHeading, count),
+		&Metadata{
+			FilePath:   filePath,
+			MatchCount: added,
+		}, TierCompact)
+}
+
+// ingestAddOptions converts an optional ttl parameter into add options
+func ingestAddOptions(ttl string) ([]vector.AddOption, error) {
+	if ttl == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(ttl)
+	if err != nil || d <= 0 {
+		return nil, fmt.Errorf("invalid ttl %q: use a positive duration such as 72h", ttl)
+	}
+	return []vector.AddOption{vector.WithTTL(d)}, nil
+}
+
+// preparedIngest holds a parsed and chunked document ready to be stored
+type preparedIngest struct {
+	docs             []llm.Document
+	title            string
+	fileType         string
+	chunkConfig      vector.ChunkConfig
+	boilerplateLines int
+}
+
+// prepareIngest parses, cleans and chunks a file into knowledge base documents
+func prepareIngest(ctx context.Context, filePath string, params IngestDocumentParams) (*preparedIngest, error) {
+	// Get file type from extension
+	ext := strings.TrimPrefix(filepath.Ext(filePath), ".")
+	ft := parser.FileTypeFromExt(ext)
 
 	// Parse the file
-	parsedDoc, err := globalKnowledgeParser.ParseFile(ctx, filePath)
+	p, ok := knowledgeParser(ft, params)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse file: no parser found for file: %s", filePath)
+	}
+	parsedDoc, err := p.ParseFile(ctx, filePath)
 	if err != nil {
-		return Error(fmt.Sprintf("failed to parse file: %v", err))
+		return nil, fmt.Errorf("failed to parse file: %v", err)
 	}
 
+	return chunkIngest(parsedDoc, filePath, filepath.Base(filePath), ft, false, params)
+}
+
+// knowledgeParser returns the parser for ft. Markdown chunked by heading
+// keeps its header markers, which the default markdown parser strips.
+func knowledgeParser(ft parser.FileType, params IngestDocumentParams) (parser.Parser, bool) {
+	if ft == parser.FileTypeMD && ingestChunkConfig(params).ChunkByHeading {
+		return parser.NewHeadingMarkdownParser(), true
+	}
+	return globalKnowledgeParser.GetParser(ft)
+}
+
+// chunkIngest cleans and chunks a parsed document from source into knowledge
+// base documents with IDs derived from idBase. Boilerplate is only removed
+// from web pages; in local files repeated lines are usually real content.
+func chunkIngest(parsedDoc *parser.Document, source, idBase string, ft parser.FileType, webPage bool, params IngestDocumentParams) (*preparedIngest, error) {
 	// Use custom title if provided, otherwise use extracted title
 	title := params.Title
 	if title == "" {
 		title = parsedDoc.Title
 	}
+	fileType := ft.String()
 
-	// Get file type from extension
-	ext := strings.TrimPrefix(filepath.Ext(filePath), ".")
-	fileType := parser.FileTypeFromExt(ext).String()
+	// Strip cookie notices, newsletter prompts and other repeated page boilerplate
+	content, boilerplateLines := parsedDoc.Content, 0
+	if webPage {
+		content, boilerplateLines = vector.RemoveBoilerplate(content, vector.DefaultBoilerplateConfig())
+	}
 
-	// Chunk the document
-	chunkConfig := vector.DefaultChunkConfig()
-	chunks := vector.ChunkDocument(parsedDoc.Content, chunkConfig)
+	// Chunk the document; heading mode needs markdown structure
+	chunkConfig := ingestChunkConfig(params)
+	if fileType != parser.FileTypeMD.String() {
+		chunkConfig.ChunkByHeading = false
+	}
+	chunks := vector.ChunkDocument(content, chunkConfig)
 
 	if len(chunks) == 0 {
-		return Error("document content is too short to process")
+		return nil, fmt.Errorf("document content is too short to process")
 	}
 
-	// Create documents with embeddings
+	// Create documents
 	docs := make([]llm.Document, len(chunks))
 	now := time.Now().Format(time.RFC3339)
 
 	for i, chunk := range chunks {
 		// Generate document ID
-		docID := fmt.Sprintf("doc_%s_%d", filepath.Base(filePath), i)
+		docID := fmt.Sprintf("doc_%s_%d", idBase, i)
 
 		docs[i] = llm.Document{
 			ID:         docID,
 			Content:    chunk.Content,
-			Source:     filePath,
+			Source:     source,
 			FileType:   fileType,
 			Title:      title,
 			ChunkIndex: i,
@@ -139,45 +267,135 @@ func IngestDocumentFunc(ctx context.Context, params IngestDocumentParams) (strin
 			},
 		}
 
+		// Embed chunk with its document context, keep raw content for display
+		if chunkConfig.ContextPrefix {
+			docs[i].EmbeddingText = vector.ContextualText(title, chunk.Heading, chunk.Content)
+			if chunk.Heading != "" {
+				docs[i].Metadata["heading"] = chunk.Heading
+			}
+		}
+
 		// Copy parser metadata
 		for k, v := range parsedDoc.Metadata {
 			docs[i].Metadata[k] = v
 		}
 	}
 
+	return &preparedIngest{
+		docs:             docs,
+		title:            title,
+		fileType:         fileType,
+		chunkConfig:      chunkConfig,
+		boilerplateLines: boilerplateLines,
+	}, nil
+}
+
+// storeIngest replaces the stored documents of a source with docs, skipping
+// chunks whose content is already stored under another source. It returns the
+// number of chunks added and skipped.
+func storeIngest(ctx context.Context, store vector.VectorStore, source string, docs []llm.Document, opts ...vector.AddOption) (int, int, error) {
 	// Delete existing documents from the same source
-	_ = globalKnowledgeVectorStore.DeleteBySource(ctx, filePath)
+	_, _ = store.DeleteBySource(ctx, source)
+
+	added, err := dedupChunks(ctx, store, docs)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to check for duplicate chunks: %v", err)
+	}
 
 	// Add documents to vector store
-	if err := globalKnowledgeVectorStore.AddBatch(ctx, docs); err != nil {
-		return Error(fmt.Sprintf("failed to store documents: %v", err))
+	if len(added) > 0 {
+		if err := store.AddBatch(ctx, added, opts...); err != nil {
+			return 0, 0, fmt.Errorf("failed to store documents: %v", err)
+		}
 	}
+	invalidateKnowledgeCache(ctx)
+	return len(added), len(docs) - len(added), nil
+}
 
-	// Get updated count
-	count, _ := globalKnowledgeVectorStore.Count(ctx)
+// dedupBatchSize is the number of content hashes looked up per store query
+const dedupBatchSize = 100
 
-	return Success(fmt.Sprintf("Document ingested successfully:\n"+
-		"  Title: %s\n"+
-		"  Source: %s\n"+
-		"  Type: %s\n"+
-		"  Chunks: %d\n"+
-		"  Total documents in knowledge base: %d",
-		title, filePath, fileType, len(chunks), count),
-		&Metadata{
-			FilePath:   filePath,
-			MatchCount: len(chunks),
-		}, TierCompact)
+// dedupChunks sets each document's ContentHash and returns the documents whose
+// hash is neither stored yet nor repeats an earlier chunk of docs
+func dedupChunks(ctx context.Context, store vector.VectorStore, docs []llm.Document) ([]llm.Document, error) {
+	seen := make(map[string]bool)
+	for i := 0; i < len(docs); i += dedupBatchSize {
+		batch := docs[i:min(i+dedupBatchSize, len(docs))]
+		hashes := make([]string, len(batch))
+		for j := range batch {
+			if batch[j].ContentHash == "" {
+				batch[j].ContentHash = vector.ContentHash(batch[j].Content)
+			}
+			hashes[j] = batch[j].ContentHash
+		}
+		err := store.ListAll(ctx, llm.ListFilter{ContentHashes: hashes}, func(doc llm.Document) error {
+			seen[doc.ContentHash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var added []llm.Document
+	for _, doc := range docs {
+		if seen[doc.ContentHash] {
+			continue
+		}
+		seen[doc.ContentHash] = true
+		added = append(added, doc)
+	}
+	return added, nil
+}
+
+// ingestChunkConfig applies per-ingest overrides to the default chunk config,
+// clamping them to sane bounds
+func ingestChunkConfig(params IngestDocumentParams) vector.ChunkConfig {
+	cfg := vector.DefaultChunkConfig()
+
+	if params.ChunkSize > 0 {
+		cfg.ChunkSize = params.ChunkSize
+	}
+	if cfg.ChunkSize < minIngestChunkSize {
+		cfg.ChunkSize = minIngestChunkSize
+	}
+	if cfg.ChunkSize > maxIngestChunkSize {
+		cfg.ChunkSize = maxIngestChunkSize
+	}
+
+	if params.ChunkOverlap != nil {
+		cfg.ChunkOverlap = *params.ChunkOverlap
+	}
+	if cfg.ChunkOverlap < 0 {
+		cfg.ChunkOverlap = 0
+	}
+	if cfg.ChunkOverlap > cfg.ChunkSize/2 {
+		cfg.ChunkOverlap = cfg.ChunkSize / 2
+	}
+
+	if params.SplitByParagraph != nil {
+		cfg.SplitByParagraph = *params.SplitByParagraph
+	}
+	if params.ChunkByHeading != nil {
+		cfg.ChunkByHeading = *params.ChunkByHeading
+	}
+
+	// Small chunks must not be dropped by the minimum size filter
+	if cfg.MinChunkSize > cfg.ChunkSize/2 {
+		cfg.MinChunkSize = cfg.ChunkSize / 2
+	}
+	return cfg
 }
 
 // GetIngestDocumentTool returns the document ingestion tool
-func GetIngestDocumentTool() tool.InvokableTool {
+func GetIngestDocumentTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(
 		IngestDocumentToolName,
 		ingestDescription,
 		IngestDocumentFunc,
 	)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("failed to create %s tool: %w", IngestDocumentToolName, err)
 	}
-	return t
+	return t, nil
 }
@@ -0,0 +1,337 @@
+package tools
+
+import (
+	"compass/llm"
+	"compass/llm/vector"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+const (
+	// IngestDirectoryToolName is the name of the directory ingestion tool
+	IngestDirectoryToolName = "ingest_directory"
+
+	// DefaultIngestWorkers is the default number of files processed in parallel
+	DefaultIngestWorkers = 4
+	// maxIngestWorkers bounds per-call worker overrides
+	maxIngestWorkers = 16
+	// maxIngestDirectoryFiles bounds the number of files ingested in one call
+	maxIngestDirectoryFiles = 1000
+)
+
+// IngestWorkersFromEnv reads INGEST_WORKERS, falling back to
+// DefaultIngestWorkers when unset or not a positive integer
+func IngestWorkersFromEnv() int {
+	if val := os.Getenv("INGEST_WORKERS"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			return min(n, maxIngestWorkers)
+		}
+	}
+	return DefaultIngestWorkers
+}
+
+// IngestDirectoryParams defines parameters for directory ingestion
+type IngestDirectoryParams struct {
+	Directory  string `json:"directory" jsonschema:"description=Directory whose supported files are ingested into the knowledge base"`
+	Recursive  *bool  `json:"recursive,omitempty" jsonschema:"description=Optional: include subdirectories (default: true)"`
+	Include    string `json:"include,omitempty" jsonschema:"description=Optional glob selecting files relative to the directory (e.g. **/*.md); patterns without a slash match file names"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to ingest into (default: default)"`
+	Workers    int    `json:"workers,omitempty" jsonschema:"description=Optional number of files processed in parallel (1-16, default from INGEST_WORKERS)"`
+	TTL        string `json:"ttl,omitempty" jsonschema:"description=Optional expiry as a duration (e.g. 72h) after which the documents are removed; default: VECTOR_DOCUMENT_TTL"`
+}
+
+// ingestDirectoryDescription is the detailed tool description for the AI
+const ingestDirectoryDescription = `Ingest every supported file in a directory into the knowledge base.
+
+SUPPORTED FORMATS:
+- Text files (.txt)
+- Markdown files (.md, .markdown)
+- Word documents (.docx)
+- CSV files (.csv), one "column: value" line per row
+- JSON files (.json), flattened to "dot.path: value" lines
+
+USE CASES:
+- Import a folder of notes or documentation in one call
+- Refresh the knowledge base after a set of files changed
+
+PARAMETERS:
+- directory (required): Directory to ingest
+- recursive (optional): Include subdirectories (default: true)
+- include (optional): Only ingest files matching this glob, relative to the
+  directory (e.g. "**/*.md", "guides/*.txt"); patterns without a slash match
+  file names anywhere (e.g. "*.md")
+- collection (optional): Collection (namespace) to ingest into (default: default)
+- workers (optional): Files parsed and embedded in parallel, 1-16 (default: INGEST_WORKERS or 4)
+- ttl (optional): Expire the documents after this duration, e.g. "168h"
+
+PROCESS:
+1. Hidden directories, .gitignore'd paths, files not matching include and
+   unsupported files are skipped
+2. Files are parsed, chunked and embedded by parallel workers
+3. Each file's chunks replace earlier chunks from the same path, one file at a time
+
+OUTPUT FORMAT:
+A summary line, then one line per file: '✅ <path>: <n> chunks' or '❌ <path>: <error>'.
+
+EXAMPLES:
+- Ingest notes: {"directory": "./notes"}
+- Top level only: {"directory": "./docs", "recursive": false}
+- Markdown only: {"directory": "./docs", "include": "**/*.md"}
+- Into a collection with more workers: {"directory": "./handbook", "collection": "work-docs", "workers": 8}
+
+NOTES:
+- At most 1000 files are ingested per call
+- Use ingest_document for a single file or custom chunking`
+
+// ingestItemResult is the outcome of ingesting one file
+type ingestItemResult struct {
+	Path    string
+	Chunks  int // Chunks added
+	Skipped int // Duplicate chunks skipped
+	Err     error
+}
+
+// IngestDirectoryFunc ingests all supported files in a directory
+func IngestDirectoryFunc(ctx context.Context, params IngestDirectoryParams) (string, error) {
+	if globalKnowledgeParser == nil {
+		return Error("document parser is not initialized")
+	}
+	if globalKnowledgeVectorStore == nil {
+		return Error("vector store is not initialized")
+	}
+	if strings.TrimSpace(params.Directory) == "" {
+		return Error("directory parameter is required")
+	}
+
+	store, collection, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
+	addOpts, err := ingestAddOptions(params.TTL)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	dir := filepath.Clean(resolvePath(ctx, strings.TrimSpace(params.Directory)))
+	info, err := os.Stat(dir)
+	if err != nil {
+		return Error(fmt.Sprintf("failed to access directory: %v", err))
+	}
+	if !info.IsDir() {
+		return Error(fmt.Sprintf("%s is not a directory; use ingest_document for single files", dir))
+	}
+
+	include := strings.TrimSpace(params.Include)
+	if include != "" && !doublestar.ValidatePattern(include) {
+		return Error(fmt.Sprintf("invalid include pattern: %s", include))
+	}
+
+	recursive := params.Recursive == nil || *params.Recursive
+	files, err := collectIngestFiles(dir, recursive, include)
+	if err != nil {
+		return Error(fmt.Sprintf("failed to list directory: %v", err))
+	}
+	if len(files) == 0 {
+		return Success(fmt.Sprintf("No supported files found in %s%s", dir, includeLabel(include)),
+			&Metadata{FilePath: dir}, TierCompact)
+	}
+	if len(files) > maxIngestDirectoryFiles {
+		return Error(fmt.Sprintf("directory has %d supported files (max %d); ingest subdirectories separately",
+			len(files), maxIngestDirectoryFiles))
+	}
+
+	workers := params.Workers
+	if workers <= 0 {
+		workers = IngestWorkersFromEnv()
+	}
+	workers = min(workers, maxIngestWorkers, len(files))
+
+	results := ingestFiles(ctx, store, files, workers, addOpts...)
+
+	ingested, chunks, skipped := 0, 0, 0
+	var lines []string
+	for _, r := range results {
+		rel, err := filepath.Rel(dir, r.Path)
+		if err != nil {
+			rel = r.Path
+		}
+		if r.Err != nil {
+			lines = append(lines, fmt.Sprintf("❌ %s: %v", rel, r.Err))
+			continue
+		}
+		ingested++
+		chunks += r.Chunks
+		skipped += r.Skipped
+		line := fmt.Sprintf("✅ %s: %d chunks", rel, r.Chunks)
+		if r.Skipped > 0 {
+			line += fmt.Sprintf(" (%d duplicates skipped)", r.Skipped)
+		}
+		lines = append(lines, line)
+	}
+
+	content := fmt.Sprintf("Ingested %d of %d files from %s into collection %s (%d chunks, %d duplicates skipped, %d workers):\n%s",
+		ingested, len(files), dir, collection, chunks, skipped, workers, strings.Join(lines, "\n"))
+	md := &Metadata{
+		FilePath:   dir,
+		FileCount:  ingested,
+		MatchCount: chunks,
+	}
+	if ingested < len(files) {
+		return Partial(content, md)
+	}
+	return Success(content, md, TierCompact)
+}
+
+// includeLabel describes the include filter for messages, "" when unset
+func includeLabel(include string) string {
+	if include == "" {
+		return ""
+	}
+	return fmt.Sprintf(" matching %s", include)
+}
+
+// collectIngestFiles lists files under dir that have a registered parser and
+// match include (when set), skipping hidden directories and .gitignore'd paths
+func collectIngestFiles(dir string, recursive bool, include string) ([]string, error) {
+	ignore := newGitignoreMatcher(dir)
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == dir {
+				return nil
+			}
+			if !recursive || strings.HasPrefix(d.Name(), ".") || ignore.Ignored(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if include != "" {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil || !matchesRelPattern(filepath.ToSlash(rel), include) {
+				return nil
+			}
+		}
+		if _, ok := globalKnowledgeParser.GetParserForPath(path); ok && !ignore.Ignored(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// ingestFiles parses and embeds files on a bounded pool of workers while
+// serializing store writes. Files not started before ctx ends report ctx.Err().
+func ingestFiles(ctx context.Context, store vector.VectorStore, files []string, workers int, opts ...vector.AddOption) []ingestItemResult {
+	results := make([]ingestItemResult, len(files))
+	started := make([]bool, len(files))
+	jobs := make(chan int)
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < max(workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = ingestFile(ctx, store, files[i], &writeMu, opts...)
+			}
+		}()
+	}
+
+feed:
+	for i := range files {
+		select {
+		case jobs <- i:
+			started[i] = true
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, ok := range started {
+		if !ok {
+			results[i] = ingestItemResult{Path: files[i], Err: ctx.Err()}
+		}
+	}
+	return results
+}
+
+// ingestFile prepares and embeds one file, then stores it under writeMu
+func ingestFile(ctx context.Context, store vector.VectorStore, path string, writeMu *sync.Mutex, opts ...vector.AddOption) ingestItemResult {
+	result := ingestItemResult{Path: path}
+	if result.Err = ctx.Err(); result.Err != nil {
+		return result
+	}
+
+	prepared, err := prepareIngest(ctx, path, IngestDocumentParams{})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if err := embedDocuments(ctx, prepared.docs); err != nil {
+		result.Err = err
+		return result
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	if result.Err = ctx.Err(); result.Err != nil {
+		return result
+	}
+	result.Chunks, result.Skipped, result.Err = storeIngest(ctx, store, path, prepared.docs, opts...)
+	return result
+}
+
+// embedDocuments sets each document's Vector using the knowledge embedder so
+// the store does not embed them again. Without an embedder the store embeds.
+func embedDocuments(ctx context.Context, docs []llm.Document) error {
+	if globalKnowledgeEmbedder == nil {
+		return nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.Content
+		if doc.EmbeddingText != "" {
+			texts[i] = doc.EmbeddingText
+		}
+	}
+	vectors, err := vector.NewEmbeddingService(globalKnowledgeEmbedder, 0).EmbedBatch(ctx, texts)
+	if err != nil {
+		return err
+	}
+	for i := range docs {
+		docs[i].Vector = vectors[i]
+	}
+	return nil
+}
+
+// GetIngestDirectoryTool returns the directory ingestion tool
+func GetIngestDirectoryTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(
+		IngestDirectoryToolName,
+		ingestDirectoryDescription,
+		IngestDirectoryFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", IngestDirectoryToolName, err)
+	}
+	return t, nil
+}
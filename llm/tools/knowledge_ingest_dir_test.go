@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"compass/llm"
+	"compass/llm/parser"
+	"compass/llm/vector"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// concurrencyTracker records the peak number of concurrent calls
+type concurrencyTracker struct {
+	active atomic.Int32
+	peak   atomic.Int32
+}
+
+func (c *concurrencyTracker) enter() {
+	n := c.active.Add(1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			return
+		}
+	}
+}
+
+func (c *concurrencyTracker) leave() {
+	c.active.Add(-1)
+}
+
+// slowEmbedder returns 2-dimensional vectors after a short delay
+type slowEmbedder struct {
+	concurrencyTracker
+}
+
+func (e *slowEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	e.enter()
+	defer e.leave()
+	time.Sleep(5 * time.Millisecond)
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = []float64{1, 0}
+	}
+	return vectors, nil
+}
+
+// serialStore wraps fakeVectorStore to detect concurrent writes
+type serialStore struct {
+	*fakeVectorStore
+	writes concurrencyTracker
+	mu     sync.Mutex
+}
+
+func (s *serialStore) AddBatch(ctx context.Context, docs []llm.Document, opts ...vector.AddOption) error {
+	s.writes.enter()
+	defer s.writes.leave()
+	time.Sleep(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fakeVectorStore.AddBatch(ctx, docs, opts...)
+}
+
+// writeIngestFiles creates n markdown files plus files that must be skipped
+func writeIngestFiles(t *testing.T, n int) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < n; i++ {
+		sub := dir
+		if i%2 == 1 {
+			sub = dir + "/nested"
+		}
+		writeTestFile(t, sub, fmt.Sprintf("note%02d.md", i), fmt.Sprintf(
+			"Note %d describes how service %d is deployed, monitored and rolled back by the on-call engineer "+
+				"during a release window, including the dashboards checked before traffic is shifted.\n", i, i))
+	}
+	writeTestFile(t, dir, "image.png", "not a document")
+	writeTestFile(t, dir+"/.hidden", "secret.md", "Hidden notes should never be ingested into the knowledge base at all.\n")
+	return dir
+}
+
+// TestIngestDirectoryConcurrent verifies all files are stored, workers are capped and writes are serial
+func TestIngestDirectoryConcurrent(t *testing.T) {
+	store := &serialStore{fakeVectorStore: &fakeVectorStore{}}
+	emb := &slowEmbedder{}
+	InitKnowledgeVectorStore(store, parser.DefaultRegistry(), emb)
+	t.Cleanup(func() { InitKnowledgeVectorStore(nil, nil, nil) })
+
+	dir := writeIngestFiles(t, 20)
+	out, _ := IngestDirectoryFunc(context.Background(), IngestDirectoryParams{Directory: dir, Workers: 3})
+	if !strings.Contains(out, "Ingested 20 of 20 files") || !strings.Contains(out, "3 workers") {
+		t.Fatalf("unexpected result:\n%s", out)
+	}
+	checkOutputSchema(t, IngestDirectoryToolName, out)
+
+	sources := make(map[string]bool)
+	for _, d := range store.docs {
+		sources[d.Source] = true
+		if len(d.Vector) != 2 {
+			t.Errorf("document %s should be embedded by the worker", d.ID)
+		}
+	}
+	if len(sources) != 20 {
+		t.Errorf("stored %d sources, want 20", len(sources))
+	}
+	if peak := emb.peak.Load(); peak > 3 || peak < 2 {
+		t.Errorf("peak concurrent embeddings = %d, want 2-3 with 3 workers", peak)
+	}
+	if peak := store.writes.peak.Load(); peak != 1 {
+		t.Errorf("peak concurrent writes = %d, want 1", peak)
+	}
+	if strings.Contains(out, "secret.md") || strings.Contains(out, "image.png") {
+		t.Errorf("hidden and unsupported files should be skipped:\n%s", out)
+	}
+
+	recursive := false
+	out, _ = IngestDirectoryFunc(context.Background(), IngestDirectoryParams{Directory: dir, Recursive: &recursive})
+	if !strings.Contains(out, "Ingested 10 of 10 files") {
+		t.Errorf("non-recursive ingest should skip nested files:\n%s", out)
+	}
+}
+
+// TestIngestDirectoryReportsFailures verifies per-file failures and cancellation are reported
+func TestIngestDirectoryReportsFailures(t *testing.T) {
+	useFakeKnowledgeStore(t)
+	dir := writeIngestFiles(t, 2)
+	writeTestFile(t, dir, "short.md", "too short\n")
+
+	out, _ := IngestDirectoryFunc(context.Background(), IngestDirectoryParams{Directory: dir})
+	if !strings.Contains(out, "PARTIAL") || !strings.Contains(out, "Ingested 2 of 3 files") ||
+		!strings.Contains(out, "❌ short.md: document content is too short") {
+		t.Errorf("expected a partial result reporting short.md:\n%s", out)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	files, _ := collectIngestFiles(dir, true, "")
+	for _, r := range ingestFiles(ctx, globalKnowledgeVectorStore, files, 2) {
+		if r.Err != context.Canceled {
+			t.Errorf("%s: err = %v, want context.Canceled", r.Path, r.Err)
+		}
+	}
+}
+
+// TestIngestDirectoryInclude verifies the include glob selects files by relative path or name
+func TestIngestDirectoryInclude(t *testing.T) {
+	useFakeKnowledgeStore(t)
+	dir := writeIngestFiles(t, 4)
+	writeTestFile(t, dir+"/nested", "guide.txt", "The guide explains how to rotate credentials for every service account "+
+		"without downtime, step by step, including how to verify the rotation afterwards.\n")
+
+	for include, want := range map[string]string{
+		"nested/*.md":  "Ingested 2 of 2 files",
+		"*.txt":        "Ingested 1 of 1 files",
+		"**/note0*.md": "Ingested 4 of 4 files",
+	} {
+		out, _ := IngestDirectoryFunc(context.Background(), IngestDirectoryParams{Directory: dir, Include: include})
+		if !strings.Contains(out, want) {
+			t.Errorf("include %q: want %q in:\n%s", include, want, out)
+		}
+	}
+
+	out, _ := IngestDirectoryFunc(context.Background(), IngestDirectoryParams{Directory: dir, Include: "*.png"})
+	if !strings.Contains(out, "No supported files found") || !strings.Contains(out, "matching *.png") {
+		t.Errorf("unsupported matches should be skipped:\n%s", out)
+	}
+	if out, _ := IngestDirectoryFunc(context.Background(), IngestDirectoryParams{Directory: dir, Include: "[a-"}); !strings.Contains(out, "invalid include pattern") {
+		t.Errorf("expected an invalid pattern error:\n%s", out)
+	}
+}
@@ -0,0 +1,269 @@
+package tools
+
+import (
+	"compass/llm/parser"
+	"compass/llm/vector"
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+// IngestURLToolName is the name of the URL ingestion tool
+const IngestURLToolName = "ingest_url"
+
+// IngestURLParams defines parameters for ingesting a web page
+type IngestURLParams struct {
+	URL        string `json:"url" jsonschema:"description=The URL to fetch and ingest. Must start with http:// or https://"`
+	Title      string `json:"title,omitempty" jsonschema:"description=Optional title for the document (defaults to the page title)"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to ingest into (default: default)"`
+	TTL        string `json:"ttl,omitempty" jsonschema:"description=Optional expiry as a duration (e.g. 72h) after which the document is removed; default: VECTOR_DOCUMENT_TTL"`
+	Timeout    int    `json:"timeout,omitempty" jsonschema:"description=Optional timeout in seconds (default: 30, max: 120)"`
+
+	// Per-ingest chunking overrides
+	ChunkSize      int   `json:"chunk_size,omitempty" jsonschema:"description=Optional chunk size in characters (200-8000, default from CHUNK_SIZE)"`
+	ChunkOverlap   *int  `json:"chunk_overlap,omitempty" jsonschema:"description=Optional overlap between chunks in characters (at most half the chunk size)"`
+	ChunkByHeading *bool `json:"chunk_by_heading,omitempty" jsonschema:"description=Optional: for HTML and markdown, keep each # / ## section together and prefix chunks with their heading trail (default from CHUNK_BY_HEADING)"`
+}
+
+// ingestURLDescription is the detailed tool description for the AI
+const ingestURLDescription = `Fetch a URL and ingest its content into the knowledge base for semantic search.
+
+SUPPORTED CONTENT TYPES:
+- HTML pages, converted to markdown
+- Markdown and plain text
+- CSV, one "column: value" line per row
+- JSON, flattened to "dot.path: value" lines
+- Word documents (.docx)
+
+USE CASES:
+- Cache a web page found during research for later retrieval
+- Add online documentation to the knowledge base without saving it to disk first
+
+PARAMETERS:
+- url (required): The URL to ingest (must start with http:// or https://)
+- title (optional): Custom title for the document (default: the page <title>)
+- collection (optional): Collection (namespace) to ingest into (default: default)
+- ttl (optional): Expire the document after this duration, e.g. "168h" for cached web research
+- timeout (optional): Timeout in seconds (default: 30, max: 120)
+- chunk_size, chunk_overlap, chunk_by_heading (optional): Chunking overrides as in ingest_document
+
+NOTES:
+- Size limit: 5MB. Larger text pages are ingested up to the limit and reported as truncated;
+  larger binary or structured files (DOCX, CSV, JSON) are rejected
+- Pages that do not return HTTP 200 are not ingested
+- The document source is the URL; ingesting the same URL again replaces it
+- HTML pages are stripped of boilerplate (cookie notices, subscribe prompts, lines repeated throughout the page)
+- Chunks whose exact content is already in the collection are skipped
+
+EXAMPLES:
+- Ingest a page: {"url": "https://example.com/docs/auth"}
+- Expiring research: {"url": "https://example.com/pricing", "ttl": "168h", "collection": "research"}`
+
+// docxContentType is the media type of Word documents
+const docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// IngestURLFunc fetches a URL and ingests its content into the knowledge base
+func IngestURLFunc(ctx context.Context, params IngestURLParams) (string, error) {
+	if globalKnowledgeParser == nil {
+		return Error("document parser is not initialized")
+	}
+	if globalKnowledgeVectorStore == nil {
+		return Error("vector store is not initialized")
+	}
+
+	rawURL := strings.TrimSpace(params.URL)
+	if rawURL == "" {
+		return Error("url parameter is required")
+	}
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return Error("URL must start with http:// or https://")
+	}
+
+	timeout := params.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if timeout > MaxTimeout {
+		timeout = MaxTimeout
+	}
+
+	store, collection, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	addOpts, err := ingestAddOptions(params.TTL)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	page, err := fetchPage(ctx, rawURL, timeout)
+	if err != nil {
+		return Error(err.Error())
+	}
+	RecordSources(ctx, rawURL)
+	if page.statusCode != http.StatusOK {
+		return Error(fmt.Sprintf("server returned status %d for %s; nothing was ingested", page.statusCode, rawURL))
+	}
+
+	ft, isHTML, err := urlFileType(rawURL, page.contentType)
+	if err != nil {
+		return Error(err.Error())
+	}
+	// A cut text page is still readable; a cut binary or structured file is not
+	if page.truncated && (ft == parser.FileTypeDOCX || ft.IsStructured()) {
+		return Error(fmt.Sprintf("response exceeds the %d byte limit; a truncated %s file cannot be parsed", MaxReadSize, ft))
+	}
+
+	ingestParams := IngestDocumentParams{
+		Title:          params.Title,
+		ChunkSize:      params.ChunkSize,
+		ChunkOverlap:   params.ChunkOverlap,
+		ChunkByHeading: params.ChunkByHeading,
+	}
+	parsedDoc, err := parseURLContent(ctx, page.body, ft, isHTML, ingestParams)
+	if err != nil {
+		return Error(err.Error())
+	}
+	if parsedDoc.Title == "" || parsedDoc.Title == "." {
+		parsedDoc.Title = urlTitle(rawURL)
+	}
+	if parsedDoc.Metadata == nil {
+		parsedDoc.Metadata = make(map[string]interface{})
+	}
+	parsedDoc.Metadata["url"] = rawURL
+	parsedDoc.Metadata["content_type"] = page.contentType
+
+	prepared, err := chunkIngest(parsedDoc, rawURL, "url_"+vector.ContentHash(rawURL)[:12], ft, isHTML, ingestParams)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	added, skipped, err := storeIngest(ctx, store, rawURL, prepared.docs, addOpts...)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	// Get updated count
+	count, _ := store.Count(ctx)
+
+	content := fmt.Sprintf("URL ingested successfully:\n"+
+		"  Collection: %s\n"+
+		"  Title: %s\n"+
+		"  Source: %s\n"+
+		"  Content type: %s\n"+
+		"  Type: %s\n"+
+		"  Chunks: %d (%d added, %d duplicates skipped)\n"+
+		"  Chunking: size=%d overlap=%d split_by_paragraph=%t by_heading=%t\n"+
+		"  Boilerplate lines removed: %d\n"+
+		"  Total documents in collection: %d",
+		collection, prepared.title, rawURL, page.contentType, prepared.fileType, len(prepared.docs), added, skipped,
+		prepared.chunkConfig.ChunkSize, prepared.chunkConfig.ChunkOverlap,
+		prepared.chunkConfig.SplitByParagraph, prepared.chunkConfig.ChunkByHeading,
+		prepared.boilerplateLines, count)
+	if page.truncated {
+		content += "\n" + truncationNote(page.omittedBytes,
+			fmt.Sprintf("only the first %d bytes of the response were ingested", MaxReadSize))
+	}
+
+	return Success(content, &Metadata{
+		URL:          rawURL,
+		StatusCode:   page.statusCode,
+		MatchCount:   added,
+		Truncated:    page.truncated,
+		OmittedBytes: page.omittedBytes,
+	}, TierCompact)
+}
+
+// urlFileType maps a response content type to the parser for it, falling back
+// to the URL's file extension for generic types. isHTML reports that the body
+// must be converted to markdown first.
+func urlFileType(rawURL, contentType string) (ft parser.FileType, isHTML bool, err error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return parser.FileTypeMD, true, nil
+	case mediaType == "text/markdown" || mediaType == "text/x-markdown":
+		return parser.FileTypeMD, false, nil
+	case mediaType == "text/csv":
+		return parser.FileTypeCSV, false, nil
+	case isJSONContentType(contentType):
+		return parser.FileTypeJSON, false, nil
+	case mediaType == docxContentType:
+		return parser.FileTypeDOCX, false, nil
+	}
+
+	// Servers often send files as text/plain or application/octet-stream
+	if u, err := url.Parse(rawURL); err == nil {
+		if ft := parser.FileTypeFromExt(strings.TrimPrefix(path.Ext(u.Path), ".")); ft != parser.FileTypeUnknown {
+			return ft, false, nil
+		}
+	}
+	if mediaType == "" || strings.HasPrefix(mediaType, "text/") {
+		return parser.FileTypeTXT, false, nil
+	}
+	return "", false, fmt.Errorf("unsupported content type %q: only HTML, markdown, plain text, CSV, JSON and DOCX can be ingested", contentType)
+}
+
+// parseURLContent parses a downloaded body with the parser for ft, converting
+// HTML to markdown and taking the title from its <title> element
+func parseURLContent(ctx context.Context, body string, ft parser.FileType, isHTML bool, params IngestDocumentParams) (*parser.Document, error) {
+	p, ok := knowledgeParser(ft, params)
+	if !ok {
+		return nil, fmt.Errorf("no parser registered for %s content", ft)
+	}
+
+	var title string
+	if isHTML {
+		if doc, err := goquery.NewDocumentFromReader(strings.NewReader(body)); err == nil {
+			title = strings.TrimSpace(doc.Find("title").First().Text())
+		}
+		markdown, err := convertHTMLToMarkdown(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert to markdown: %v", err)
+		}
+		body = markdown
+	}
+
+	parsedDoc, err := p.Parse(ctx, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse content: %v", err)
+	}
+	if title != "" {
+		parsedDoc.Title = title
+	}
+	return parsedDoc, nil
+}
+
+// urlTitle derives a fallback title from the host and last path segment
+func urlTitle(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if base := path.Base(u.Path); base != "/" && base != "." {
+		return u.Host + ": " + base
+	}
+	return u.Host
+}
+
+// GetIngestURLTool returns the URL ingestion tool
+func GetIngestURLTool() (tool.InvokableTool, error) {
+	t, err := utils.InferTool(
+		IngestURLToolName,
+		ingestURLDescription,
+		IngestURLFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", IngestURLToolName, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"compass/llm/parser"
+)
+
+// TestIngestURLHTML verifies a page is converted to markdown and stored with the URL as source
+func TestIngestURLHTML(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Auth Guide</title></head><body>
+<h1>Authentication</h1>
+<p>Clients send a bearer token in the <b>Authorization</b> header on every request to the API server.</p>
+</body></html>`))
+	}))
+	defer srv.Close()
+
+	url := srv.URL + "/docs/auth"
+	out, _ := IngestURLFunc(context.Background(), IngestURLParams{URL: url})
+	if !strings.HasPrefix(out, "URL ingested successfully:") {
+		t.Fatalf("ingest failed:\n%s", out)
+	}
+	if len(store.docs) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(store.docs))
+	}
+	doc := store.docs[0]
+	if doc.Source != url || doc.Title != "Auth Guide" || doc.FileType != "md" {
+		t.Errorf("unexpected document: source=%q title=%q type=%q", doc.Source, doc.Title, doc.FileType)
+	}
+	if !strings.Contains(doc.Content, "# Authentication\n\nClients send") || strings.Contains(doc.Content, "<p>") {
+		t.Errorf("content should be markdown: %q", doc.Content)
+	}
+}
+
+// TestIngestURLRemovesBoilerplate verifies boilerplate is stripped from web
+// pages before chunking while local files keep every line
+func TestIngestURLRemovesBoilerplate(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+
+	var page, text strings.Builder
+	page.WriteString("<html><head><title>Pipeline</title></head><body>")
+	for i := 0; i < 6; i++ {
+		for _, p := range []string{
+			"Step " + strconv.Itoa(i) + " of the deployment pipeline builds artifacts and verifies rollbacks in detail.",
+			"We use cookies to personalise content. Accept all cookies to continue browsing the site.",
+			"Sign up for our newsletter and never miss an update from the engineering team again!",
+		} {
+			page.WriteString("<p>" + p + "</p>\n")
+			text.WriteString(p + "\n\n")
+		}
+	}
+	page.WriteString("</body></html>")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page.String()))
+	}))
+	defer srv.Close()
+
+	out, _ := IngestURLFunc(context.Background(), IngestURLParams{URL: srv.URL, ChunkSize: 300})
+	if !strings.Contains(out, "Boilerplate lines removed: 12") {
+		t.Fatalf("result should report removed lines:\n%s", out)
+	}
+	for _, doc := range store.docs {
+		if strings.Contains(doc.Content, "cookies") || strings.Contains(doc.Content, "newsletter") {
+			t.Errorf("chunk still contains boilerplate: %q", doc.Content)
+		}
+	}
+	if !strings.Contains(store.docs[0].Content, "Step 0") {
+		t.Errorf("unique content should be preserved: %q", store.docs[0].Content)
+	}
+
+	path := writeTestFile(t, t.TempDir(), "page.md", text.String())
+	if out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path, ChunkSize: 300}); strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+	kept := false
+	for _, doc := range store.docs {
+		kept = kept || doc.Source == path && strings.Contains(doc.Content, "Accept all cookies")
+	}
+	if !kept {
+		t.Error("local files should not be filtered")
+	}
+}
+
+// TestIngestURLRejects verifies error pages, unsupported types and cut structured files are not ingested
+func TestIngestURLRejects(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/logo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G'})
+		case "/huge.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data": "` + strings.Repeat("x", int(MaxReadSize)) + `"}`))
+		}
+	}))
+	defer srv.Close()
+
+	for path, want := range map[string]string{
+		"/missing":   "status 404",
+		"/logo.png":  "unsupported content type",
+		"/huge.json": "truncated json file cannot be parsed",
+	} {
+		out, _ := IngestURLFunc(context.Background(), IngestURLParams{URL: srv.URL + path})
+		if !strings.Contains(out, "ERROR") || !strings.Contains(out, want) {
+			t.Errorf("%s: want error containing %q, got:\n%s", path, want, out)
+		}
+	}
+	if len(store.docs) != 0 {
+		t.Errorf("nothing should be stored, got %d chunks", len(store.docs))
+	}
+}
+
+// TestURLFileType verifies content types and URL extensions select the parser
+func TestURLFileType(t *testing.T) {
+	tests := []struct {
+		url, contentType string
+		want             parser.FileType
+		html             bool
+	}{
+		{"https://x.test/a", "text/html; charset=utf-8", parser.FileTypeMD, true},
+		{"https://x.test/a", "application/problem+json", parser.FileTypeJSON, false},
+		{"https://x.test/data.csv", "application/octet-stream", parser.FileTypeCSV, false},
+		{"https://x.test/README.md", "text/plain", parser.FileTypeMD, false},
+		{"https://x.test/notes", "text/plain", parser.FileTypeTXT, false},
+		{"https://x.test/notes", "", parser.FileTypeTXT, false},
+	}
+	for _, tt := range tests {
+		ft, html, err := urlFileType(tt.url, tt.contentType)
+		if err != nil || ft != tt.want || html != tt.html {
+			t.Errorf("urlFileType(%q, %q) = %v, %v, %v; want %v, %v", tt.url, tt.contentType, ft, html, err, tt.want, tt.html)
+		}
+	}
+	if _, _, err := urlFileType("https://x.test/app", "application/octet-stream"); err == nil {
+		t.Error("expected an error for an unknown binary type")
+	}
+}
@@ -27,6 +27,7 @@ PARAMETERS:
 - file_type (optional): Filter by file type (pdf, docx, md, txt, html)
 - source (optional): Filter by source file path
 - limit (optional): Maximum results to return (default: 100)
+- collection (optional): Collection (namespace) to list (default: default)
 
 OUTPUT FORMAT:
 Returns a list of documents with their metadata:
@@ -41,13 +42,15 @@ EXAMPLES:
 - List all: {}
 - List markdown: {"file_type": "md"}
 - List from source: {"source": "./docs/api.md"}
-- Limited results: {"limit": 10}`
+- Limited results: {"limit": 10}
+- List a collection: {"collection": "work-docs"}`
 
 // ListDocumentsParams defines parameters for listing documents
 type ListDocumentsParams struct {
-	FileType string `json:"file_type,omitempty" jsonschema:"description=Filter by file type (pdf, docx, md, txt, html)"`
-	Source   string `json:"source,omitempty" jsonschema:"description=Filter by source file path"`
-	Limit    int    `json:"limit,omitempty" jsonschema:"description=Maximum number of documents to return (default: 100)"`
+	FileType   string `json:"file_type,omitempty" jsonschema:"description=Filter by file type (pdf, docx, md, txt, html)"`
+	Source     string `json:"source,omitempty" jsonschema:"description=Filter by source file path"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"description=Maximum number of documents to return (default: 100)"`
+	Collection string `json:"collection,omitempty" jsonschema:"description=Optional collection to list (default: default)"`
 }
 
 // ListDocumentsFunc lists documents in the knowledge base
@@ -56,6 +59,11 @@ func ListDocumentsFunc(ctx context.Context, params ListDocumentsParams) (string,
 		return Error("vector store is not initialized")
 	}
 
+	store, _, err := knowledgeStore(ctx, params.Collection)
+	if err != nil {
+		return Error(err.Error())
+	}
+
 	// Build filter
 	filter := llm.ListFilter{
 		Source:   params.Source,
@@ -70,14 +78,14 @@ func ListDocumentsFunc(ctx context.Context, params ListDocumentsParams) (string,
 	}
 
 	// List documents
-	docs, err := globalKnowledgeVectorStore.List(ctx, filter)
+	docs, err := store.List(ctx, filter)
 	if err != nil {
 		return Error(fmt.Sprintf("failed to list documents: %v", err))
 	}
 
 	if len(docs) == 0 {
 		// Check if knowledge base is empty
-		count, _ := globalKnowledgeVectorStore.Count(ctx)
+		count, _ := store.Count(ctx)
 		if count == 0 {
 			return Success("Knowledge base is empty. Use ingest_document to add documents.",
 				nil, TierCompact)
@@ -120,14 +128,14 @@ func ListDocumentsFunc(ctx context.Context, params ListDocumentsParams) (string,
 }
 
 // GetListDocumentsTool returns the document listing tool
-func GetListDocumentsTool() tool.InvokableTool {
+func GetListDocumentsTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(
 		ListDocumentsToolName,
 		listKnowledgeDocumentsDescription,
 		ListDocumentsFunc,
 	)
 	if err != nil {
-		return nil
+		return nil, fmt.Errorf("failed to create %s tool: %w", ListDocumentsToolName, err)
 	}
-	return t
+	return t, nil
 }
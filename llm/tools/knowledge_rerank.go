@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"compass/llm"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// rerankCandidateFactor is how many more candidates than top_k are fetched for re-ranking
+	rerankCandidateFactor = 3
+	// maxRerankChunk caps the characters of each candidate shown to the scoring model
+	maxRerankChunk = 1500
+	// maxRerankScore is the top of the scale the scoring model rates relevance on
+	maxRerankScore = 10.0
+)
+
+// KnowledgeReranker scores each candidate's relevance to the query, in
+// candidate order, on a scale from 0 to 1
+type KnowledgeReranker func(ctx context.Context, query string, candidates []llm.SearchResult) ([]float64, error)
+
+// knowledgeReranker re-ranks knowledge search results on request, nil disables re-ranking
+var knowledgeReranker KnowledgeReranker
+
+// SetKnowledgeReranker installs the re-ranker used by search_knowledge when rerank=true
+func SetKnowledgeReranker(r KnowledgeReranker) {
+	knowledgeReranker = r
+}
+
+// rerankPrompt instructs the scoring model
+const rerankPrompt = `You judge how relevant passages are to a search query.
+Rate every passage from 0 (unrelated) to 10 (directly answers the query).
+Judge only the passage text; do not reward passages for merely repeating query words.
+Reply with only a JSON array of numbers, one per passage, in passage order, e.g. [7, 0, 10].`
+
+// NewModelKnowledgeReranker returns a KnowledgeReranker that asks a chat model
+// to rate all candidates in a single call
+func NewModelKnowledgeReranker(m model.BaseChatModel) KnowledgeReranker {
+	return func(ctx context.Context, query string, candidates []llm.SearchResult) ([]float64, error) {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Query: %s\n", query)
+		for i, c := range candidates {
+			content := c.Document.Content
+			if len(content) > maxRerankChunk {
+				content = content[:maxRerankChunk]
+			}
+			fmt.Fprintf(&sb, "\n[Passage %d]\n%s\n", i+1, content)
+		}
+
+		resp, err := m.Generate(ctx, []*schema.Message{
+			schema.SystemMessage(rerankPrompt),
+			schema.UserMessage(sb.String()),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return parseRerankScores(resp.Content, len(candidates))
+	}
+}
+
+// parseRerankScores reads the JSON array of 0-10 ratings from a model reply
+// and scales them to 0-1
+func parseRerankScores(reply string, n int) ([]float64, error) {
+	start, end := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no score array in reply: %.100q", reply)
+	}
+	var scores []float64
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &scores); err != nil {
+		return nil, fmt.Errorf("invalid score array: %v", err)
+	}
+	if len(scores) != n {
+		return nil, fmt.Errorf("got %d scores for %d passages", len(scores), n)
+	}
+	for i, s := range scores {
+		scores[i] = min(max(s, 0), maxRerankScore) / maxRerankScore
+	}
+	return scores, nil
+}
+
+// rerankKnowledgeResults re-sorts candidates by re-ranker score and keeps the
+// best topK. Scores replace the vector similarities; on failure the vector
+// order is kept.
+func rerankKnowledgeResults(ctx context.Context, query string, candidates []llm.SearchResult, topK int) ([]llm.SearchResult, bool) {
+	if knowledgeReranker == nil || len(candidates) < 2 {
+		return candidates[:min(topK, len(candidates))], false
+	}
+
+	scores, err := knowledgeReranker(ctx, query, candidates)
+	if err != nil || len(scores) != len(candidates) {
+		slog.Warn("failed to re-rank knowledge results, keeping vector order", "err", err)
+		return candidates[:min(topK, len(candidates))], false
+	}
+
+	// Candidates may be shared with the search cache
+	reranked := make([]llm.SearchResult, len(candidates))
+	copy(reranked, candidates)
+	for i := range reranked {
+		reranked[i].Score = float32(scores[i])
+	}
+	// Stable, so ties keep vector order
+	sort.SliceStable(reranked, func(a, b int) bool {
+		return reranked[a].Score > reranked[b].Score
+	})
+	return reranked[:min(topK, len(reranked))], true
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"compass/llm"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useStubReranker installs a re-ranker returning scores computed by score
+func useStubReranker(t *testing.T, score func(i int, doc llm.Document) float64, err error) *int {
+	t.Helper()
+	calls := 0
+	prev := knowledgeReranker
+	SetKnowledgeReranker(func(ctx context.Context, query string, candidates []llm.SearchResult) ([]float64, error) {
+		calls++
+		scores := make([]float64, len(candidates))
+		for i, c := range candidates {
+			scores[i] = score(i, c.Document)
+		}
+		return scores, err
+	})
+	t.Cleanup(func() { knowledgeReranker = prev })
+	return &calls
+}
+
+// addRerankDocs stores n documents all matching the query "widget"
+func addRerankDocs(store *fakeVectorStore, n int) {
+	for i := 0; i < n; i++ {
+		store.docs = append(store.docs, llm.Document{
+			ID:      fmt.Sprintf("doc-%d", i),
+			Content: fmt.Sprintf("widget note %d", i),
+			Source:  fmt.Sprintf("note%d.md", i),
+		})
+	}
+}
+
+// TestKnowledgeRerank verifies rerank=true widens the candidate pool and re-sorts by model score
+func TestKnowledgeRerank(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	addRerankDocs(store, 9)
+	// The last vector candidate is the most relevant
+	calls := useStubReranker(t, func(i int, doc llm.Document) float64 { return float64(i) / 10 }, nil)
+
+	out, _ := KnowledgeToolFunc(context.Background(), KnowledgeToolParams{Query: "widget", TopK: 2, Rerank: true})
+	if *calls != 1 {
+		t.Fatalf("re-ranker calls = %d, want 1", *calls)
+	}
+	if !strings.Contains(out, "Found 2 relevant results in collection default (re-ranked from 6 candidates):") {
+		t.Errorf("unexpected header:\n%s", out)
+	}
+	first, second := strings.Index(out, "widget note 5"), strings.Index(out, "widget note 4")
+	if first < 0 || second < first || strings.Contains(out, "widget note 0") {
+		t.Errorf("results should be the two best re-ranked candidates in order:\n%s", out)
+	}
+	if !strings.Contains(out, "(score: 0.50)") {
+		t.Errorf("scores should be the re-ranker ratings:\n%s", out)
+	}
+
+	// Without rerank the vector order is returned and the model is not called
+	out, _ = KnowledgeToolFunc(context.Background(), KnowledgeToolParams{Query: "widget", TopK: 2})
+	if *calls != 1 || !strings.Contains(out, "widget note 0") || strings.Contains(out, "re-ranked") {
+		t.Errorf("rerank=false should keep vector order (calls = %d):\n%s", *calls, out)
+	}
+}
+
+// TestKnowledgeRerankFailure verifies a failing re-ranker falls back to vector order
+func TestKnowledgeRerankFailure(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	addRerankDocs(store, 6)
+	useStubReranker(t, func(i int, doc llm.Document) float64 { return 0 }, errors.New("model unavailable"))
+
+	out, _ := KnowledgeToolFunc(context.Background(), KnowledgeToolParams{Query: "widget", TopK: 2, Rerank: true})
+	if !strings.Contains(out, "Found 2 relevant results in collection default:") ||
+		!strings.Contains(out, "widget note 0") || !strings.Contains(out, "widget note 1") {
+		t.Errorf("expected the top vector results:\n%s", out)
+	}
+}
+
+// TestParseRerankScores verifies model replies are parsed and scaled to 0-1
+func TestParseRerankScores(t *testing.T) {
+	scores, err := parseRerankScores("Scores:\n```json\n[10, 0, 7.5, 12]\n```", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(scores, []float64{1, 0, 0.75, 1}) {
+		t.Errorf("scores = %v", scores)
+	}
+
+	for _, reply := range []string{"no scores", "[1, 2]", "[1, oops, 3]"} {
+		if _, err := parseRerankScores(reply, 3); err == nil {
+			t.Errorf("parseRerankScores(%q) should fail", reply)
+		}
+	}
+}
@@ -0,0 +1,699 @@
+package tools
+
+import (
+	"archive/zip"
+	"compass/llm"
+	"compass/llm/parser"
+	"compass/llm/vector"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeVectorStore records documents in memory for knowledge tool tests
+type fakeVectorStore struct {
+	docs        []llm.Document
+	collections map[string]*fakeVectorStore
+	lastTTL     time.Duration // TTL of the most recent add
+	searches    int           // Number of Search calls, each standing for one query embedding
+}
+
+func (s *fakeVectorStore) Collection(ctx context.Context, name string) (vector.VectorStore, error) {
+	if s.collections == nil {
+		s.collections = make(map[string]*fakeVectorStore)
+	}
+	if _, ok := s.collections[name]; !ok {
+		s.collections[name] = &fakeVectorStore{}
+	}
+	return s.collections[name], nil
+}
+
+func (s *fakeVectorStore) ListCollections(ctx context.Context) ([]string, error) {
+	names := []string{vector.DefaultCollection}
+	for name := range s.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names, nil
+}
+
+func (s *fakeVectorStore) Add(ctx context.Context, doc llm.Document, opts ...vector.AddOption) error {
+	return s.AddBatch(ctx, []llm.Document{doc}, opts...)
+}
+
+func (s *fakeVectorStore) AddBatch(ctx context.Context, docs []llm.Document, opts ...vector.AddOption) error {
+	s.docs = append(s.docs, docs...)
+	s.lastTTL = vector.ResolveAddOptions(vector.StoreConfig{}, opts...).TTL
+	return nil
+}
+
+func (s *fakeVectorStore) Search(ctx context.Context, query string, topK int, filter llm.ListFilter) ([]llm.SearchResult, error) {
+	s.searches++
+	var results []llm.SearchResult
+	for _, d := range s.docs {
+		if filter.Source != "" && d.Source != filter.Source || filter.FileType != "" && d.FileType != filter.FileType {
+			continue
+		}
+		if strings.Contains(d.Content, query) && len(results) < topK {
+			results = append(results, llm.SearchResult{Document: d, Score: 1})
+		}
+	}
+	return results, nil
+}
+
+func (s *fakeVectorStore) Update(ctx context.Context, doc llm.Document) error {
+	for i, d := range s.docs {
+		if d.ID == doc.ID {
+			s.docs[i] = doc
+			return nil
+		}
+	}
+	return fmt.Errorf("document not found: %s", doc.ID)
+}
+
+func (s *fakeVectorStore) Delete(ctx context.Context, id string) error {
+	for i, d := range s.docs {
+		if d.ID == id {
+			s.docs = append(s.docs[:i], s.docs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *fakeVectorStore) DeleteBatch(ctx context.Context, ids []string) (int, error) {
+	return s.deleteWhere(func(d llm.Document) bool { return slices.Contains(ids, d.ID) }), nil
+}
+
+func (s *fakeVectorStore) DeleteBySource(ctx context.Context, source string) (int, error) {
+	return s.deleteWhere(func(d llm.Document) bool { return d.Source == source }), nil
+}
+
+// deleteWhere removes the matching documents and returns how many were removed
+func (s *fakeVectorStore) deleteWhere(match func(llm.Document) bool) int {
+	n := len(s.docs)
+	s.docs = slices.DeleteFunc(s.docs, match)
+	return n - len(s.docs)
+}
+
+func (s *fakeVectorStore) List(ctx context.Context, filter llm.ListFilter) ([]llm.Document, error) {
+	var docs []llm.Document
+	for _, d := range s.docs {
+		if filter.Source != "" && d.Source != filter.Source {
+			continue
+		}
+		if len(filter.ContentHashes) > 0 && !slices.Contains(filter.ContentHashes, d.ContentHash) {
+			continue
+		}
+		docs = append(docs, d)
+	}
+	return docs, nil
+}
+
+func (s *fakeVectorStore) ListAll(ctx context.Context, filter llm.ListFilter, fn func(llm.Document) error) error {
+	docs, _ := s.List(ctx, filter)
+	for _, d := range docs {
+		if err := fn(d); err != nil {
+			if errors.Is(err, vector.ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeVectorStore) Count(ctx context.Context) (int64, error) {
+	return int64(len(s.docs)), nil
+}
+
+func (s *fakeVectorStore) Close() error {
+	return nil
+}
+
+// useFakeKnowledgeStore installs a fake vector store for the duration of a test
+func useFakeKnowledgeStore(t *testing.T) *fakeVectorStore {
+	t.Helper()
+	store := &fakeVectorStore{}
+	InitKnowledgeVectorStore(store, parser.DefaultRegistry(), nil)
+	t.Cleanup(func() { InitKnowledgeVectorStore(nil, nil, nil) })
+	return store
+}
+
+// TestIngestContextPrefix verifies chunks are embedded with context while display content stays raw
+func TestIngestContextPrefix(t *testing.T) {
+	t.Setenv("CHUNK_CONTEXT_PREFIX", "true")
+	store := useFakeKnowledgeStore(t)
+
+	dir := t.TempDir()
+	body := "Bearer tokens are sent in the Authorization header for every request to the API server."
+	path := writeTestFile(t, dir, "api.md", "# API Reference\n\n## Authentication\n\n"+body+"\n")
+
+	out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path})
+	if strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+	if len(store.docs) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(store.docs))
+	}
+
+	doc := store.docs[0]
+	if !strings.HasPrefix(doc.EmbeddingText, "From: API Reference > Authentication\n\n") {
+		t.Errorf("embedding text missing context prefix: %q", doc.EmbeddingText)
+	}
+	if strings.HasPrefix(doc.Content, "From:") || !strings.Contains(doc.Content, body) {
+		t.Errorf("display content should be the raw chunk: %q", doc.Content)
+	}
+}
+
+// TestIngestChunkByHeading verifies markdown sections are chunked by heading and other files are not
+func TestIngestChunkByHeading(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	dir := t.TempDir()
+	content := "# Handbook\n\n## Onboarding\n\nNew hires receive a laptop, badge and accounts during their first morning on site.\n\n" +
+		"## Expenses\n\nSubmit receipts within thirty days through the finance portal to be reimbursed on time.\n"
+	byHeading := true
+
+	md := writeTestFile(t, dir, "handbook.md", content)
+	out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: md, ChunkByHeading: &byHeading})
+	if !strings.Contains(out, "by_heading=true") {
+		t.Fatalf("ingest should report heading mode:\n%s", out)
+	}
+	if len(store.docs) != 2 {
+		t.Fatalf("expected one chunk per section, got %d", len(store.docs))
+	}
+	if !strings.HasPrefix(store.docs[1].Content, "## Handbook > Expenses\n\nSubmit receipts") {
+		t.Errorf("chunk should start with its heading trail: %q", store.docs[1].Content)
+	}
+
+	txt := writeTestFile(t, dir, "handbook.txt", content)
+	out, _ = IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: txt, ChunkByHeading: &byHeading})
+	if !strings.Contains(out, "by_heading=false") {
+		t.Errorf("heading mode should only apply to markdown:\n%s", out)
+	}
+}
+
+// TestIngestSkipsDuplicateChunks verifies chunks already stored under another source are skipped
+func TestIngestSkipsDuplicateChunks(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	dir := t.TempDir()
+	body := "Rotate the signing keys every ninety days, record each rotation in the audit log and notify the on-call team."
+
+	first := writeTestFile(t, dir, "keys.md", body+"\n")
+	copyPath := writeTestFile(t, dir, "keys-copy.md", "  "+body+"\n\n")
+
+	out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: first})
+	if !strings.Contains(out, "(1 added, 0 duplicates skipped)") {
+		t.Fatalf("first ingest should add its chunk:\n%s", out)
+	}
+	out, _ = IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: copyPath})
+	if !strings.Contains(out, "(0 added, 1 duplicates skipped)") {
+		t.Errorf("identical content should be skipped:\n%s", out)
+	}
+	if len(store.docs) != 1 {
+		t.Fatalf("expected 1 stored chunk, got %d", len(store.docs))
+	}
+	if store.docs[0].ContentHash != vector.ContentHash(body) {
+		t.Errorf("stored hash = %q, want hash of the chunk content", store.docs[0].ContentHash)
+	}
+
+	// Re-ingesting a source replaces its own chunks rather than skipping them
+	out, _ = IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: first})
+	if !strings.Contains(out, "(1 added, 0 duplicates skipped)") || len(store.docs) != 1 {
+		t.Errorf("re-ingest should replace the source's chunks (%d stored):\n%s", len(store.docs), out)
+	}
+}
+
+// TestKnowledgeParserHeadings verifies header markers are only kept for markdown chunked by heading
+func TestKnowledgeParserHeadings(t *testing.T) {
+	useFakeKnowledgeStore(t)
+	byHeading, plain := true, false
+	for mode, want := range map[*bool]string{&byHeading: "# Release checklist", &plain: "Release checklist"} {
+		p, ok := knowledgeParser(parser.FileTypeMD, IngestDocumentParams{ChunkByHeading: mode})
+		if !ok {
+			t.Fatal("no markdown parser")
+		}
+		doc, err := p.Parse(context.Background(), strings.NewReader("# Release checklist"))
+		if err != nil || doc.Content != want {
+			t.Errorf("chunk_by_heading=%t: content %q, %v; want %q", *mode, doc.Content, err, want)
+		}
+	}
+}
+
+// TestIngestChunkSizeOverride verifies per-ingest chunk size changes the chunk count
+func TestIngestChunkSizeOverride(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+
+	var sb strings.Builder
+	for i := 0; i < 20; i++ {
+		sb.WriteString("This paragraph describes step " + strconv.Itoa(i) + " of the deployment pipeline in enough detail to matter. ")
+		sb.WriteString("It mentions builds, artifacts, and rollbacks.\n\n")
+	}
+	path := writeTestFile(t, t.TempDir(), "guide.md", sb.String())
+
+	ingest := func(size int) int {
+		t.Helper()
+		out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path, ChunkSize: size})
+		if strings.Contains(out, "ERROR") {
+			t.Fatalf("ingest failed: %s", out)
+		}
+		if !strings.Contains(out, "size="+strconv.Itoa(size)) {
+			t.Errorf("result should report effective chunk size %d:\n%s", size, out)
+		}
+		return len(store.docs)
+	}
+
+	large := ingest(4000)
+	small := ingest(400)
+	if small <= large {
+		t.Errorf("smaller chunk size should produce more chunks: 400 -> %d, 4000 -> %d", small, large)
+	}
+
+	cfg := ingestChunkConfig(IngestDocumentParams{ChunkSize: 50})
+	if cfg.ChunkSize != minIngestChunkSize || cfg.ChunkOverlap > cfg.ChunkSize/2 {
+		t.Errorf("chunk config not clamped: %+v", cfg)
+	}
+}
+
+// TestIngestHugeParagraphUniqueIDs verifies a force-split paragraph yields unique document IDs and indexes
+func TestIngestHugeParagraphUniqueIDs(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+
+	content := "A short introduction to the runbook that explains who should read it and when.\n\n" +
+		strings.Repeat("Every step of the failover procedure must be logged with its timestamp. ", 80) +
+		"\n\nA closing paragraph that lists the owners of this runbook and their escalation contacts."
+	path := writeTestFile(t, t.TempDir(), "runbook.txt", content)
+
+	out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path, ChunkSize: 500})
+	if strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+	if len(store.docs) < 5 {
+		t.Fatalf("expected the huge paragraph to be split, got %d documents", len(store.docs))
+	}
+
+	ids := make(map[string]bool)
+	indexes := make(map[int]bool)
+	for _, d := range store.docs {
+		if ids[d.ID] || indexes[d.ChunkIndex] {
+			t.Errorf("duplicate document %s (chunk index %d)", d.ID, d.ChunkIndex)
+		}
+		ids[d.ID] = true
+		indexes[d.ChunkIndex] = true
+	}
+}
+
+// writeDocx creates a minimal .docx archive with the given parts
+func writeDocx(t *testing.T, dir, name string, parts map[string]string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for part, content := range parts {
+		w, err := zw.Create(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return path
+}
+
+// TestIngestDocx verifies .docx paragraphs, runs and core title are extracted
+func TestIngestDocx(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:r><w:t>Release </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>checklist</w:t></w:r></w:p>
+<w:p></w:p>
+<w:p><w:r><w:t xml:space="preserve">Freeze the branch, </w:t></w:r><w:r><w:t>tag the build &amp; notify the on-call engineer.</w:t></w:r></w:p>
+<w:p><w:r><w:t>Owner:</w:t><w:tab/><w:t>platform team</w:t><w:br/><w:t>Backup: release managers who approve every production rollout.</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+	core := `<?xml version="1.0" encoding="UTF-8"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:title>Release Runbook</dc:title>
+</cp:coreProperties>`
+	path := writeDocx(t, t.TempDir(), "runbook.docx", map[string]string{
+		"word/document.xml": body,
+		"docProps/core.xml": core,
+	})
+
+	doc, err := parser.DefaultRegistry().ParseFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	want := "Release checklist\n\nFreeze the branch, tag the build & notify the on-call engineer.\n\n" +
+		"Owner:\tplatform team\nBackup: release managers who approve every production rollout."
+	if doc.Content != want {
+		t.Errorf("content = %q, want %q", doc.Content, want)
+	}
+	if doc.Title != "Release Runbook" || doc.Metadata["paragraph_count"] != 3 {
+		t.Errorf("title = %q, paragraph_count = %v", doc.Title, doc.Metadata["paragraph_count"])
+	}
+
+	out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path})
+	if strings.Contains(out, "ERROR") || len(store.docs) == 0 {
+		t.Fatalf("ingest failed: %s", out)
+	}
+	if store.docs[0].FileType != "docx" || store.docs[0].Title != "Release Runbook" {
+		t.Errorf("unexpected document: type %q, title %q", store.docs[0].FileType, store.docs[0].Title)
+	}
+
+	bad := writeTestFile(t, t.TempDir(), "broken.docx", "not a zip archive")
+	if _, err := parser.DefaultRegistry().ParseFile(context.Background(), bad); err == nil {
+		t.Error("expected an error for an invalid docx")
+	}
+}
+
+// TestParseStructuredData verifies CSV rows and flattened JSON paths are readable and counted
+func TestParseStructuredData(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	reg := parser.DefaultRegistry()
+
+	csvPath := writeTestFile(t, dir, "services.csv", "\ufeffname,owner,,tier\nbilling,payments team,,1\n,,,\nsearch, ,extra,2,spare\n")
+	doc, err := reg.ParseFile(ctx, csvPath)
+	if err != nil {
+		t.Fatalf("csv parse failed: %v", err)
+	}
+	want := "name: billing | owner: payments team | tier: 1\n\nname: search | column 3: extra | tier: 2 | column 5: spare"
+	if doc.Content != want {
+		t.Errorf("csv content = %q, want %q", doc.Content, want)
+	}
+	if doc.Metadata["row_count"] != 2 || doc.Metadata["column_count"] != 4 {
+		t.Errorf("csv metadata = %v", doc.Metadata)
+	}
+
+	jsonPath := writeTestFile(t, dir, "services.json",
+		`[{"name": "billing", "owner": {"team": "payments", "oncall": ["ana", "li"]}, "tier": 1, "deprecated": null},
+		  {"name": "search", "tags": [], "tier": 2.5}]`)
+	doc, err = reg.ParseFile(ctx, jsonPath)
+	if err != nil {
+		t.Fatalf("json parse failed: %v", err)
+	}
+	want = "deprecated: null\nname: billing\nowner.oncall[0]: ana\nowner.oncall[1]: li\nowner.team: payments\ntier: 1\n\n" +
+		"name: search\ntags: []\ntier: 2.5"
+	if doc.Content != want {
+		t.Errorf("json content = %q, want %q", doc.Content, want)
+	}
+	if doc.Metadata["element_count"] != 2 {
+		t.Errorf("json metadata = %v", doc.Metadata)
+	}
+
+	objPath := writeTestFile(t, dir, "config.json", `{"title": "Service Config", "limits": {"rps": 100}}`)
+	doc, err = reg.ParseFile(ctx, objPath)
+	if err != nil || doc.Title != "Service Config" || doc.Content != "limits.rps: 100\n\ntitle: Service Config" {
+		t.Errorf("json object parsed as %+v (err %v)", doc, err)
+	}
+
+	bad := writeTestFile(t, dir, "broken.json", `{"name": `)
+	if _, err := reg.ParseFile(ctx, bad); err == nil {
+		t.Error("expected an error for invalid json")
+	}
+}
+
+// TestIngestStructuredKeepsRepeatedLines verifies data rows are not dropped as boilerplate
+func TestIngestStructuredKeepsRepeatedLines(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < 10; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf(`{"id": %d, "status": "active in the primary production region"}`, i))
+	}
+	sb.WriteString("]")
+	path := writeTestFile(t, t.TempDir(), "records.json", sb.String())
+
+	out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path})
+	if strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+	var all strings.Builder
+	for _, d := range store.docs {
+		all.WriteString(d.Content)
+	}
+	if n := strings.Count(all.String(), "status: active in the primary production region"); n < 10 {
+		t.Errorf("repeated record lines kept %d times, want 10", n)
+	}
+}
+
+// TestParseMarkdownFrontmatter verifies lists and nested maps survive frontmatter parsing
+func TestParseMarkdownFrontmatter(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	reg := parser.DefaultRegistry()
+
+	path := writeTestFile(t, dir, "post.md", `---
+title: "Deploying: a guide"
+tags: [ops, release]
+author:
+  name: Ana
+  teams:
+    - platform
+draft: false
+---
+# Heading
+
+Body text.
+`)
+	doc, err := reg.ParseFile(ctx, path)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if doc.Title != "Deploying: a guide" {
+		t.Errorf("title = %q", doc.Title)
+	}
+	tags, _ := doc.Metadata["tags"].([]interface{})
+	author, _ := doc.Metadata["author"].(map[string]interface{})
+	teams, _ := author["teams"].([]interface{})
+	if len(tags) != 2 || tags[1] != "release" || author["name"] != "Ana" || len(teams) != 1 || doc.Metadata["draft"] != false {
+		t.Errorf("frontmatter not preserved: %#v", doc.Metadata)
+	}
+	if doc.Metadata["has_frontmatter"] != true || strings.Contains(doc.Content, "tags") {
+		t.Errorf("frontmatter should be flagged and removed from content: %q", doc.Content)
+	}
+
+	bad := writeTestFile(t, dir, "bad.md", "---\ntitle: [unclosed\n---\n# Fallback Title\n\nBody.\n")
+	doc, err = reg.ParseFile(ctx, bad)
+	if err != nil {
+		t.Fatalf("malformed frontmatter should not fail parsing: %v", err)
+	}
+	if doc.Title != "Fallback Title" || doc.Metadata["has_frontmatter"] != true || strings.Contains(doc.Content, "unclosed") {
+		t.Errorf("unexpected fallback: title %q, metadata %v, content %q", doc.Title, doc.Metadata, doc.Content)
+	}
+}
+
+// TestKnowledgeCollections verifies ingest and search are scoped to the selected
+// collection, on the fake store and on the file-backed LocalStore
+func TestKnowledgeCollections(t *testing.T) {
+	t.Run("fake", func(t *testing.T) {
+		useFakeKnowledgeStore(t)
+		testKnowledgeCollections(t)
+	})
+	t.Run("local", func(t *testing.T) {
+		store, err := vector.NewLocalStore(&slowEmbedder{}, vector.LocalConfig{Path: filepath.Join(t.TempDir(), "store.json"), VectorDim: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		InitKnowledgeVectorStore(store, parser.DefaultRegistry(), nil)
+		t.Cleanup(func() { InitKnowledgeVectorStore(nil, nil, nil) })
+		testKnowledgeCollections(t)
+	})
+}
+
+// testKnowledgeCollections runs the collection checks against the current knowledge store
+func testKnowledgeCollections(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	work := writeTestFile(t, dir, "work.md", "The quarterly deployment checklist requires sign-off from the release manager before rollout to production servers.\n")
+	personal := writeTestFile(t, dir, "personal.md", "The sourdough recipe needs a mature starter and an overnight proof in the fridge for a deeper, tangier flavor.\n")
+
+	for path, collection := range map[string]string{work: "work-docs", personal: "personal-notes"} {
+		out, _ := IngestDocumentFunc(ctx, IngestDocumentParams{FilePath: path, Collection: collection})
+		if !strings.Contains(out, "Collection: "+collection) {
+			t.Fatalf("ingest into %s failed:\n%s", collection, out)
+		}
+	}
+	if n, _ := globalKnowledgeVectorStore.Count(ctx); n != 0 {
+		t.Errorf("default collection should stay empty, got %d docs", n)
+	}
+
+	out, _ := KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "deployment", Collection: "work-docs"})
+	if !strings.Contains(out, "release manager") {
+		t.Errorf("expected work document in work-docs:\n%s", out)
+	}
+	out, _ = KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "deployment", Collection: "personal-notes"})
+	if strings.Contains(out, "release manager") {
+		t.Errorf("personal-notes search should not see work documents:\n%s", out)
+	}
+	out, _ = KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "deployment"})
+	if strings.Contains(out, "release manager") {
+		t.Errorf("default collection search should not see work documents:\n%s", out)
+	}
+
+	out, _ = ListCollectionsFunc(ctx, ListCollectionsParams{})
+	for _, want := range []string{"default: 0 chunks", "personal-notes: 1 chunks", "work-docs: 1 chunks"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("list_collections missing %q:\n%s", want, out)
+		}
+	}
+
+	out, _ = KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "x", Collection: "Bad Name!"})
+	if !strings.Contains(out, "invalid collection name") {
+		t.Errorf("expected invalid collection error:\n%s", out)
+	}
+}
+
+// TestKnowledgeSearchFilter verifies search can be restricted to one source document
+func TestKnowledgeSearchFilter(t *testing.T) {
+	useFakeKnowledgeStore(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	manual := writeTestFile(t, dir, "manual.md", "To reset the password open the admin console and choose the reset option under account settings, then confirm the change by email.\n")
+	faq := writeTestFile(t, dir, "faq.md", "Customers often ask how to reset the password when the recovery email address is no longer valid or was never confirmed.\n")
+	for _, path := range []string{manual, faq} {
+		if out, _ := IngestDocumentFunc(ctx, IngestDocumentParams{FilePath: path}); strings.Contains(out, "ERROR") {
+			t.Fatalf("ingest failed: %s", out)
+		}
+	}
+
+	out, _ := KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "reset the password"})
+	if !strings.Contains(out, "Found 2 relevant results") {
+		t.Fatalf("unfiltered search should match both documents:\n%s", out)
+	}
+
+	out, _ = KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "reset the password", Source: manual})
+	if !strings.Contains(out, "Found 1 relevant results") || !strings.Contains(out, "admin console") {
+		t.Errorf("filtered search should only match manual.md:\n%s", out)
+	}
+}
+
+// TestIngestTTL verifies the ttl parameter is passed to the store and validated
+func TestIngestTTL(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	path := writeTestFile(t, t.TempDir(), "pricing.md", strings.Repeat("Cached pricing research for the enterprise plan, gathered from the vendor site. ", 3))
+
+	if out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path, TTL: "72h"}); strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+	if store.lastTTL != 72*time.Hour {
+		t.Errorf("TTL = %v, want 72h", store.lastTTL)
+	}
+
+	if out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path}); strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+	if store.lastTTL != 0 {
+		t.Errorf("ingest without ttl should not set one, got %v", store.lastTTL)
+	}
+
+	for _, bad := range []string{"soon", "-1h", "0s"} {
+		if out, _ := IngestDocumentFunc(context.Background(), IngestDocumentParams{FilePath: path, TTL: bad}); !strings.Contains(out, "invalid ttl") {
+			t.Errorf("ttl %q should be rejected:\n%s", bad, out)
+		}
+	}
+}
+
+// TestKnowledgeToolsLocalStore verifies ingest, search, list and delete against the file-backed store
+func TestKnowledgeToolsLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := vector.NewLocalStore(&slowEmbedder{}, vector.LocalConfig{Path: filepath.Join(dir, "store.json"), VectorDim: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	InitKnowledgeVectorStore(store, parser.DefaultRegistry(), nil)
+	t.Cleanup(func() { InitKnowledgeVectorStore(nil, nil, nil) })
+
+	ctx := context.Background()
+	path := writeTestFile(t, dir, "auth.md", "# Auth\n\nBearer tokens are sent in the Authorization header for every request to the API server. Tokens expire after one hour and must be refreshed.\n")
+	if out, _ := IngestDocumentFunc(ctx, IngestDocumentParams{FilePath: path}); strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed:\n%s", out)
+	}
+	if out, _ := KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "bearer tokens"}); !strings.Contains(out, "Authorization header") {
+		t.Errorf("search should find the ingested chunk:\n%s", out)
+	}
+	if out, _ := ListDocumentsFunc(ctx, ListDocumentsParams{}); !strings.Contains(out, "auth.md") {
+		t.Errorf("list should show the source:\n%s", out)
+	}
+	if out, _ := DeleteDocumentFunc(ctx, DeleteDocumentParams{Source: path}); strings.Contains(out, "ERROR") {
+		t.Fatalf("delete failed:\n%s", out)
+	}
+	if n, _ := store.Count(ctx); n != 0 {
+		t.Errorf("count after delete = %d, want 0", n)
+	}
+}
+
+// TestGetDocument verifies chunks are reassembled in chunk order by source or by chunk ID
+func TestGetDocument(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	store.docs = []llm.Document{
+		{ID: "c2", Source: "guide.md", Title: "Guide", FileType: "md", ChunkIndex: 2, Content: "third"},
+		{ID: "c0", Source: "guide.md", Title: "Guide", FileType: "md", ChunkIndex: 0, Content: "first"},
+		{ID: "x0", Source: "other.md", ChunkIndex: 0, Content: "unrelated"},
+		{ID: "c1", Source: "guide.md", Title: "Guide", FileType: "md", ChunkIndex: 1, Content: "second"},
+	}
+	ctx := context.Background()
+
+	bySource, _ := GetDocumentFunc(ctx, GetDocumentParams{Source: "guide.md"})
+	checkOutputSchema(t, GetDocumentToolName, bySource)
+	if !strings.Contains(bySource, "Chunks: 3\n\nfirst\n\nsecond\n\nthird") || strings.Contains(bySource, "unrelated") {
+		t.Errorf("unexpected document:\n%s", bySource)
+	}
+	if byID, _ := GetDocumentFunc(ctx, GetDocumentParams{ID: "c1"}); byID != bySource {
+		t.Errorf("lookup by chunk ID should return the whole document:\n%s", byID)
+	}
+
+	for _, params := range []GetDocumentParams{{}, {ID: "missing"}, {Source: "missing.md"}} {
+		if out, _ := GetDocumentFunc(ctx, params); !strings.Contains(out, "ERROR") {
+			t.Errorf("%+v should fail:\n%s", params, out)
+		}
+	}
+}
+
+// TestDeleteDocumentCounts verifies delete_document reports the chunks actually removed
+func TestDeleteDocumentCounts(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	store.docs = []llm.Document{
+		{ID: "a0", Source: "a.md", Title: "A", FileType: "md"},
+		{ID: "a1", Source: "a.md", Title: "A", FileType: "md"},
+		{ID: "b0", Source: "b.md", Title: "B", FileType: "md"},
+	}
+	ctx := context.Background()
+
+	out, _ := DeleteDocumentFunc(ctx, DeleteDocumentParams{Source: "a.md"})
+	if !strings.Contains(out, "Chunks removed: 2") || !strings.Contains(out, "Remaining documents: 1") {
+		t.Errorf("unexpected source delete result:\n%s", out)
+	}
+
+	out, _ = DeleteDocumentFunc(ctx, DeleteDocumentParams{ID: "missing"})
+	if !strings.Contains(out, "No document found with id: missing") {
+		t.Errorf("deleting a missing ID should say so:\n%s", out)
+	}
+	out, _ = DeleteDocumentFunc(ctx, DeleteDocumentParams{ID: "b0"})
+	if !strings.Contains(out, "Deleted 1 document(s). Remaining: 0") {
+		t.Errorf("unexpected ID delete result:\n%s", out)
+	}
+}
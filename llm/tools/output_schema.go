@@ -0,0 +1,244 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// OutputSchemaExtraKey is the ToolInfo.Extra key holding a tool's OutputSchema
+const OutputSchemaExtraKey = "output_schema"
+
+// OutputSchema describes the shape of a tool's result. Results are plain text
+// produced by ToolResult.String(), so the schema documents the content layout
+// with regular expressions rather than JSON fields.
+type OutputSchema struct {
+	Content  string   `json:"content"`            // What the content section contains
+	Header   string   `json:"header,omitempty"`   // Regexp matched by the first content line
+	Item     string   `json:"item,omitempty"`     // Regexp matched by every other non-empty content line
+	Metadata []string `json:"metadata,omitempty"` // Metadata fields the tool reports
+}
+
+// outputSchemaEnvelope explains the result envelope shared by all tools
+const outputSchemaEnvelope = `
+
+OUTPUT SCHEMA:
+Results are plain text, not JSON: an optional status prefix ("❌ ERROR: ", "⚠️  PARTIAL: " or
+"🧪 SIMULATED: "), then the content, then an optional metadata summary line in brackets.
+In read-only mode mutating tools change nothing; their "🧪 SIMULATED: " result describes the
+change they would have made and carries the "simulated" metadata flag.
+Content cut short carries a "[TRUNCATED: ...]" note and the "truncated" metadata flag.
+Content layout:
+`
+
+// outputSchemas holds the output schema of each tool by name
+var outputSchemas = map[string]OutputSchema{
+	ViewToolName: {
+		Content:  "The requested lines of the file, verbatim; with show_line_numbers each line is '<number>\\t<line>'",
+		Metadata: []string{"file_path", "line_count", "byte_count"},
+	},
+	WriteToolName: {
+		Content:  "File written: <absolute path>; simulated overwrites are followed by a unified diff",
+		Header:   `^File written: .+$`,
+		Metadata: []string{"file_path", "byte_count", "simulated"},
+	},
+	EditToolName: {
+		Content:  "File edited: <absolute path> (<n> replaced), or a dry-run notice, followed by a unified diff",
+		Header:   `^(File edited: .+ \(\d+ replaced\)|Dry run, file not modified: .+ \(\d+ would be replaced\))$`,
+		Item:     `^(--- a/.*|\+\+\+ b/.*|@@ -\d+,\d+ \+\d+,\d+ @@|[ +-].*|\.\.\. \d+ more hunk\(s\) not shown)$`,
+		Metadata: []string{"file_path", "line_count", "replaced", "simulated"},
+	},
+	DeleteToolName: {
+		Content:  "File deleted: <absolute path>, followed by the trash location and restore id for soft deletes",
+		Header:   `^File deleted: .+$`,
+		Metadata: []string{"file_path", "soft_deleted", "trash_id", "simulated"},
+	},
+	RestoreToolName: {
+		Content:  "File restored: <absolute path>, or the trash listing with one line per trashed file",
+		Header:   `^(File restored: .+|Trash is empty|\d+ file\(s\) in trash:)$`,
+		Item:     `^\S+  .+  \(deleted .+, \d+ bytes\)$`,
+		Metadata: []string{"file_path", "byte_count", "file_count", "simulated"},
+	},
+	MoveToolName: {
+		Content:  "File moved: <source> -> <destination>",
+		Header:   `^File moved: .+ -> .+$`,
+		Metadata: []string{"file_path", "byte_count", "simulated"},
+	},
+	MakeDirToolName: {
+		Content:  "Directory created: <path>, or Directory already exists: <path>",
+		Header:   `^Directory (created|already exists): .+$`,
+		Metadata: []string{"file_path", "is_dir", "simulated"},
+	},
+	CopyToolName: {
+		Content:  "File copied: <source> -> <destination> (<n> bytes)",
+		Header:   `^File copied: .+ -> .+ \(\d+ bytes\)$`,
+		Metadata: []string{"file_path", "byte_count", "simulated"},
+	},
+	HashToolName: {
+		Content:  "'<algorithm> <hex digest>  <path or (content)>', then MATCH or 'MISMATCH: expected <digest>' when an expected digest was given",
+		Header:   `^(sha256|md5) [0-9a-f]+  .+$`,
+		Item:     `^(MATCH|MISMATCH: expected [0-9a-f]+)$`,
+		Metadata: []string{"file_path", "byte_count"},
+	},
+	StatToolName: {
+		Content:  "'Key: value' lines for Path, Type, Size, Mode and Modified, then Lines for text files or Entries for directories",
+		Header:   `^Path: .+$`,
+		Item:     `^(Type|Size|Mode|Modified|Lines|Entries): .+$`,
+		Metadata: []string{"file_path", "byte_count", "mode", "mod_time", "is_dir", "line_count", "file_count"},
+	},
+	ListToolName: {
+		Content:  "One entry per line, relative to the directory; directories end with /",
+		Item:     `^.+$`,
+		Metadata: []string{"file_path", "file_count"},
+	},
+	GlobToolName: {
+		Content:  "A header line, then one matching path per line relative to the search directory",
+		Header:   `^(Found \d+ matches \(sorted by .+\):|No matches found)$`,
+		Item:     `^(\S.*|\[TRUNCATED: showing first \d+ of \d+ matches\])$`,
+		Metadata: []string{"file_count", "truncated"},
+	},
+	GrepToolName: {
+		Content:  "Matches grouped by file: a '<path>:' line, then '  <line>: <text>' for matches and '  <line>- <text>' for context, '  --' between groups",
+		Header:   `^(.+:|No matches found for pattern '.*')$`,
+		Item:     `^(.+:|  +\d+[:-] .*|  --|\[TRUNCATED: (showing first \d+ matches; more may exist|\d+ bytes omitted; output limit of \d+ bytes reached; showing \d+ of \d+ matches)\])$`,
+		Metadata: []string{"pattern", "match_count", "file_count", "truncated", "omitted_bytes"},
+	},
+	BashToolName: {
+		Content:  "Command stdout, then 'stderr: <text>' on failure; long output keeps its head and tail around a truncation note. Simulated results read 'Command not executed: <command>'",
+		Metadata: []string{"command", "duration", "exit_code", "timeout", "truncated", "omitted_bytes", "simulated"},
+	},
+	SearchToolName: {
+		Content:  "A header line, then for each result a '- **<title>**' line followed by '  URL: <link>' and '  Snippet: <text>'; optionally a 'FETCH PLAN' line followed by '<n>. <url> (<reasons>)' lines",
+		Header:   `^(Found \d+ search results for '.*':|No results found for '.*')$`,
+		Item:     `^(- \*\*.*\*\*|  URL: \S*|  Snippet: .*|FETCH PLAN .*:|\d+\. \S+ \(.+\))$`,
+		Metadata: []string{"match_count", "sources"},
+	},
+	FetchToolName: {
+		Content:  "The page content in the requested format, or a summary plus a note for very large pages; JSON responses in text/markdown start with a 'JSON object with <n> keys: ...' or 'JSON array with <n> items ...' line; format=json returns compact JSON; responses over 5MB end with a truncation note",
+		Metadata: []string{"url", "status_code", "truncated", "omitted_bytes"},
+	},
+	CheckURLsToolName: {
+		Content:  "For each URL an '<icon> <url>' line followed by '   status: <code> | final: <url> | type: <mime> | length: <n>' or '   error: <text>', then '<n>/<total> URLs reachable'",
+		Item:     `^(✅|⚠️|❌) \S+$|^   (status: \d+.*|error: .*)$|^\d+/\d+ URLs reachable$`,
+		Metadata: []string{"match_count"},
+	},
+	FetchTableToolName: {
+		Content:  "For each table a 'Table <n> (<heading>): <rows> rows x <cols> columns' line followed by the table as markdown or CSV",
+		Header:   `^(Table \d+( \(.*\))?: \d+ rows x \d+ columns|No tables found on the page)$`,
+		Metadata: []string{"url", "status_code", "match_count", "row_count", "column_count"},
+	},
+	KnowledgeToolName: {
+		Content:  "A header line, then for each result a '--- Result <n> (score: <s>) ---' line, the chunk text and a '[source: <path>] [title: <title>] [id: <chunk id>]' line",
+		Header:   `^(Found \d+ relevant results in collection \S+( \(re-ranked from \d+ candidates\))?:|No relevant content found.*)$`,
+		Metadata: []string{"match_count", "sources"},
+	},
+	IngestDocumentToolName: {
+		Content:  "Document ingested successfully, followed by indented 'Key: value' lines (Collection, Title, Source, Type, Chunks with added and skipped duplicate counts, Chunking, Total documents in collection)",
+		Header:   `^Document ingested successfully:$`,
+		Item:     `^  [A-Z][A-Za-z ]+: .*$`,
+		Metadata: []string{"file_path", "match_count"},
+	},
+	IngestURLToolName: {
+		Content:  "URL ingested successfully, followed by indented 'Key: value' lines (Collection, Title, Source, Content type, Type, Chunks with added and skipped duplicate counts, Chunking, Boilerplate lines removed, Total documents in collection) and a truncation note when the page exceeded the size limit",
+		Header:   `^URL ingested successfully:$`,
+		Item:     `^(  [A-Z][A-Za-z ]+: .*|\[TRUNCATED: .*\])$`,
+		Metadata: []string{"url", "status_code", "match_count", "truncated", "omitted_bytes"},
+	},
+	IngestDirectoryToolName: {
+		Content:  "A summary line, then per file '✅ <path>: <n> chunks' (with ' (<n> duplicates skipped)' when chunks were already stored) or '❌ <path>: <error>' with paths relative to the directory",
+		Header:   `^(Ingested \d+ of \d+ files from .+ into collection \S+ \(\d+ chunks, \d+ duplicates skipped, \d+ workers\):|No supported files found in .+)$`,
+		Item:     `^(✅ .+: \d+ chunks( \(\d+ duplicates skipped\))?|❌ .+: .+)$`,
+		Metadata: []string{"file_path", "file_count", "match_count"},
+	},
+	ListDocumentsToolName: {
+		Content:  "A header line, then per source a '📄 <path>' line followed by indented Title, Type, Chunks and Preview lines",
+		Metadata: []string{"file_count", "match_count"},
+	},
+	GetDocumentToolName: {
+		Content:  "'Document: <title>' followed by indented Source, Type and Chunks lines, a blank line, then all chunk contents in chunk order",
+		Header:   `^Document: .*$`,
+		Metadata: []string{"file_path", "match_count", "sources"},
+	},
+	DeleteDocumentToolName: {
+		Content:  "A summary of the deleted chunks and the remaining document count",
+		Metadata: []string{"file_path", "match_count"},
+	},
+	EvalRetrievalToolName: {
+		Content:  "A header line, indented Recall@k and MRR lines, then per question '✅ rank <n>: <question>' or '❌ not in top <k>: <question> (expected <source>)'",
+		Header:   `^Retrieval evaluation of \d+ questions in collection \S+:$`,
+		Item:     `^(  Recall@\d+: \d+\.\d{3}|  MRR: \d+\.\d{3}|✅ rank \d+: .*|❌ not in top \d+: .*)$`,
+		Metadata: []string{"file_path", "match_count"},
+	},
+	ListCollectionsToolName: {
+		Content:  "A header line, then one '📚 <name>: <n> chunks' line per collection",
+		Header:   `^Found \d+ collection\(s\):$`,
+		Item:     `^📚 \S+(: \d+ chunks| \(unavailable: .*\))$`,
+		Metadata: []string{"match_count"},
+	},
+}
+
+// OutputSchemaFor returns the output schema registered for a tool
+func OutputSchemaFor(name string) (OutputSchema, bool) {
+	s, ok := outputSchemas[name]
+	return s, ok
+}
+
+// RegisterOutputSchema sets or replaces the output schema of a tool
+func RegisterOutputSchema(name string, s OutputSchema) {
+	outputSchemas[name] = s
+}
+
+// schemaTool wraps a tool to advertise its output schema in Info
+type schemaTool struct {
+	tool.InvokableTool
+	schema OutputSchema
+}
+
+// Info appends the output schema to the description and records it in Extra
+func (t *schemaTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	info, err := t.InvokableTool.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(t.schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	out := *info
+	out.Desc = info.Desc + outputSchemaEnvelope + string(data)
+	out.Extra = make(map[string]any, len(info.Extra)+1)
+	for k, v := range info.Extra {
+		out.Extra[k] = v
+	}
+	out.Extra[OutputSchemaExtraKey] = t.schema
+	return &out, nil
+}
+
+// WithOutputSchemas wraps every tool with a registered output schema so its
+// Info advertises the result shape. Disabled when TOOL_OUTPUT_SCHEMA=false.
+func WithOutputSchemas(ctx context.Context, toolsList []tool.BaseTool) []tool.BaseTool {
+	if os.Getenv("TOOL_OUTPUT_SCHEMA") == "false" {
+		return toolsList
+	}
+
+	wrapped := make([]tool.BaseTool, len(toolsList))
+	for i, t := range toolsList {
+		wrapped[i] = t
+		it, ok := t.(tool.InvokableTool)
+		if !ok {
+			continue
+		}
+		info, err := it.Info(ctx)
+		if err != nil {
+			continue
+		}
+		if s, ok := outputSchemas[info.Name]; ok {
+			wrapped[i] = &schemaTool{InvokableTool: it, schema: s}
+		}
+	}
+	return wrapped
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// checkOutputSchema verifies a tool result matches the content layout of its schema
+func checkOutputSchema(t *testing.T, name, out string) {
+	t.Helper()
+	s, ok := OutputSchemaFor(name)
+	if !ok {
+		t.Fatalf("no output schema for %s", name)
+	}
+	if strings.HasPrefix(out, "❌ ERROR: ") {
+		t.Fatalf("%s returned an error: %s", name, out)
+	}
+	content := strings.TrimPrefix(strings.TrimPrefix(out, "⚠️  PARTIAL: "), "🧪 SIMULATED: ")
+	if i := strings.LastIndex(content, "\n\n["); i >= 0 && strings.HasSuffix(content, "]") {
+		content = content[:i]
+	}
+
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		t.Fatalf("%s returned no content: %q", name, out)
+	}
+	if s.Header != "" {
+		if !regexp.MustCompile(s.Header).MatchString(lines[0]) {
+			t.Errorf("%s header %q does not match %s", name, lines[0], s.Header)
+		}
+		lines = lines[1:]
+	}
+	if s.Item != "" {
+		re := regexp.MustCompile(s.Item)
+		for _, line := range lines {
+			if !re.MatchString(line) {
+				t.Errorf("%s line %q does not match %s", name, line, s.Item)
+			}
+		}
+	}
+}
+
+// TestOutputSchemasAreValid verifies every schema compiles and names real metadata fields
+func TestOutputSchemasAreValid(t *testing.T) {
+	fields := map[string]bool{}
+	mt := reflect.TypeOf(Metadata{})
+	for i := 0; i < mt.NumField(); i++ {
+		fields[strings.Split(mt.Field(i).Tag.Get("json"), ",")[0]] = true
+	}
+
+	for name, s := range outputSchemas {
+		if s.Content == "" {
+			t.Errorf("%s: empty content description", name)
+		}
+		for _, expr := range []string{s.Header, s.Item} {
+			if _, err := regexp.Compile(expr); err != nil {
+				t.Errorf("%s: invalid regexp %q: %v", name, expr, err)
+			}
+		}
+		for _, m := range s.Metadata {
+			if !fields[m] {
+				t.Errorf("%s: unknown metadata field %q", name, m)
+			}
+		}
+	}
+}
+
+// TestWithOutputSchemasAdvertises verifies wrapped tools expose their schema in Info
+func TestWithOutputSchemasAdvertises(t *testing.T) {
+	ctx := context.Background()
+	var base []tool.BaseTool
+	for _, newTool := range []func() (tool.InvokableTool, error){
+		GetGlobTool, GetGrepTool, GetEditFileTool, GetCheckURLsTool, GetListCollectionsTool,
+	} {
+		bt, err := newTool()
+		if err != nil {
+			t.Fatal(err)
+		}
+		base = append(base, bt)
+	}
+	wrapped := WithOutputSchemas(ctx, base)
+
+	for _, bt := range wrapped {
+		info, err := bt.Info(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(info.Desc, "OUTPUT SCHEMA:") {
+			t.Errorf("%s description lacks output schema", info.Name)
+		}
+		s, ok := info.Extra[OutputSchemaExtraKey].(OutputSchema)
+		if !ok {
+			t.Errorf("%s Extra lacks output schema", info.Name)
+			continue
+		}
+		if want, _ := OutputSchemaFor(info.Name); !reflect.DeepEqual(s, want) {
+			t.Errorf("%s advertises %+v, want %+v", info.Name, s, want)
+		}
+		if _, ok := bt.(tool.InvokableTool); !ok {
+			t.Errorf("%s is no longer invokable", info.Name)
+		}
+	}
+
+	t.Setenv("TOOL_OUTPUT_SCHEMA", "false")
+	plain := WithOutputSchemas(ctx, base[:1])
+	info, _ := plain[0].Info(ctx)
+	if strings.Contains(info.Desc, "OUTPUT SCHEMA:") {
+		t.Error("TOOL_OUTPUT_SCHEMA=false should leave descriptions unchanged")
+	}
+}
+
+// TestOutputSchemasMatchResults verifies representative tool results follow their schema
+func TestOutputSchemasMatchResults(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeTestFile(t, dir, "main.go", "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n")
+	writeTestFile(t, dir, "pkg/util.go", "package pkg\n\nfunc Util() {}\n")
+
+	out, _ := GlobToolFunc(ctx, GlobToolParams{Pattern: "**/*.go", Path: dir})
+	checkOutputSchema(t, GlobToolName, out)
+
+	out, _ = GrepToolFunc(ctx, GrepToolParams{Pattern: "func", Dir: dir, Before: 1})
+	checkOutputSchema(t, GrepToolName, out)
+
+	out, _ = EditFileFunc(ctx, EditFileParams{Path: filepath.Join(dir, "main.go"), Search: "hi", Replace: "hello"})
+	checkOutputSchema(t, EditToolName, out)
+
+	out, _ = CopyFileFunc(ctx, CopyFileParams{Source: filepath.Join(dir, "main.go"), Destination: filepath.Join(dir, "copy.go")})
+	checkOutputSchema(t, CopyToolName, out)
+
+	out, _ = StatFileFunc(ctx, StatFileParams{Path: filepath.Join(dir, "main.go")})
+	checkOutputSchema(t, StatToolName, out)
+
+	useFakeKnowledgeStore(t)
+	out, _ = ListCollectionsFunc(ctx, ListCollectionsParams{})
+	checkOutputSchema(t, ListCollectionsToolName, out)
+
+	allowPrivateNetworks = true
+	defer func() { allowPrivateNetworks = false }()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	out, _ = CheckURLsFunc(ctx, CheckURLsParams{URLs: []string{srv.URL + "/ok", srv.URL + "/missing"}})
+	checkOutputSchema(t, CheckURLsToolName, out)
+}
@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"context"
+	"os"
+
+	"github.com/cloudwego/eino/compose"
+)
+
+// readOnlyKey is the context key for read-only mode
+type readOnlyKey struct{}
+
+// WithReadOnly returns a context in which mutating tools (write, edit, delete,
+// move, copy, restore, mkdir, bash) only describe what they would do.
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// IsReadOnly reports whether ctx runs tools in read-only mode
+func IsReadOnly(ctx context.Context) bool {
+	v, _ := ctx.Value(readOnlyKey{}).(bool)
+	return v
+}
+
+// mutatingTools are tools that change files, run commands or modify the
+// knowledge base; repeating one is not equivalent to reusing its result
+var mutatingTools = map[string]bool{
+	WriteToolName:           true,
+	EditToolName:            true,
+	DeleteToolName:          true,
+	MoveToolName:            true,
+	CopyToolName:            true,
+	RestoreToolName:         true,
+	MakeDirToolName:         true,
+	BashToolName:            true,
+	IngestDocumentToolName:  true,
+	IngestDirectoryToolName: true,
+	IngestURLToolName:       true,
+	DeleteDocumentToolName:  true,
+}
+
+// IsMutatingTool reports whether the named tool has side effects
+func IsMutatingTool(name string) bool {
+	return mutatingTools[name]
+}
+
+// ReadOnlyFromEnv reports whether AGENT_READONLY enables read-only mode
+func ReadOnlyFromEnv() bool {
+	val := os.Getenv("AGENT_READONLY")
+	return val == "1" || val == "true"
+}
+
+// ReadOnlyTools is a tool middleware that runs every tool call in read-only mode
+func ReadOnlyTools() compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
+				return next(WithReadOnly(ctx), in)
+			}
+		},
+	}
+}
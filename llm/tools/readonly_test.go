@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestReadOnlyLeavesDiskUntouched verifies mutating tools only describe their change in read-only mode
+func TestReadOnlyLeavesDiskUntouched(t *testing.T) {
+	dir := t.TempDir()
+	ctx := WithReadOnly(WithWorkDir(context.Background(), dir))
+	src := writeTestFile(t, dir, "a.txt", "hello\n")
+
+	outputs := map[string]string{}
+	outputs[WriteToolName], _ = WriteFileFunc(ctx, WriteFileParams{Path: "a.txt", Content: "changed\n"})
+	outputs[EditToolName], _ = EditFileFunc(ctx, EditFileParams{Path: "a.txt", Search: "hello", Replace: "bye"})
+	outputs[DeleteToolName], _ = DeleteFileFunc(ctx, DeleteFileParams{Path: "a.txt"})
+	outputs[MoveToolName], _ = MoveFileFunc(ctx, MoveFileParams{Source: "a.txt", Destination: "b.txt"})
+	outputs[CopyToolName], _ = CopyFileFunc(ctx, CopyFileParams{Source: "a.txt", Destination: "c.txt"})
+	outputs[MakeDirToolName], _ = MakeDirFunc(ctx, MakeDirParams{Path: "new/dir", Recursive: true})
+	outputs[BashToolName], _ = BashToolFunc(ctx, BashToolParams{Command: "New-Item d.txt"})
+
+	for name, out := range outputs {
+		if !strings.HasPrefix(out, "🧪 SIMULATED: ") || !strings.Contains(out, "read-only") {
+			t.Errorf("%s should return a simulated result:\n%s", name, out)
+		}
+		checkOutputSchema(t, name, out)
+	}
+	if !strings.Contains(outputs[WriteToolName], "+changed") {
+		t.Errorf("simulated overwrite should include a diff:\n%s", outputs[WriteToolName])
+	}
+
+	if data, _ := os.ReadFile(src); string(data) != "hello\n" {
+		t.Errorf("file content changed to %q", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("read-only mode created files: %v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(dir, TrashDirName)); !os.IsNotExist(err) {
+		t.Error("read-only delete should not create the trash directory")
+	}
+}
+
+// TestReadOnlyReportsErrors verifies simulated calls still validate their input
+func TestReadOnlyReportsErrors(t *testing.T) {
+	ctx := WithReadOnly(WithWorkDir(context.Background(), t.TempDir()))
+
+	if out, _ := DeleteFileFunc(ctx, DeleteFileParams{Path: "missing.txt"}); !strings.Contains(out, "ERROR") {
+		t.Errorf("deleting a missing file should fail:\n%s", out)
+	}
+	if out, _ := EditFileFunc(ctx, EditFileParams{Path: "missing.txt", Search: "x"}); !strings.Contains(out, "ERROR") {
+		t.Errorf("editing a missing file should fail:\n%s", out)
+	}
+}
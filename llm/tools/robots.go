@@ -0,0 +1,209 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// robotsAgent is the product token matched against robots.txt User-agent lines
+	robotsAgent = "compass-fetch-tool"
+	// robotsTimeout bounds the robots.txt download
+	robotsTimeout = 10 * time.Second
+	// maxRobotsSize caps the robots.txt bytes read (500KB, as crawlers commonly do)
+	maxRobotsSize = 500 * 1024
+	// DefaultRobotsCacheTTL is how long a host's robots.txt rules are reused
+	DefaultRobotsCacheTTL = time.Hour
+)
+
+// RespectRobotsFromEnv reports whether RESPECT_ROBOTS enables robots.txt checks
+func RespectRobotsFromEnv() bool {
+	val := os.Getenv("RESPECT_ROBOTS")
+	return val == "1" || val == "true"
+}
+
+// RobotsCacheTTLFromEnv reads ROBOTS_CACHE_TTL (Go duration)
+func RobotsCacheTTLFromEnv() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("ROBOTS_CACHE_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return DefaultRobotsCacheTTL
+}
+
+var (
+	// respectRobots enables robots.txt checks before fetching
+	respectRobots = RespectRobotsFromEnv()
+	// globalRobotsCache holds robots.txt rules per scheme and host
+	globalRobotsCache = newRobotsCache(RobotsCacheTTLFromEnv())
+)
+
+// robotsRule is one Allow or Disallow line
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// robotsRules are the rules of the group that applies to our user agent
+type robotsRules []robotsRule
+
+// allowed reports whether path may be fetched. The longest matching pattern
+// wins and Allow wins ties; no match means allowed.
+func (r robotsRules) allowed(path string) bool {
+	best, allow := -1, true
+	for _, rule := range r {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || n == best && rule.allow {
+			best, allow = n, rule.allow
+		}
+	}
+	return allow
+}
+
+// robotsMatch matches a path against a robots.txt pattern with * wildcards
+// and an optional trailing $ anchor
+func robotsMatch(pattern, path string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(pattern, "$")), `\*`, ".*")
+	if strings.HasSuffix(pattern, "$") {
+		expr += "$"
+	}
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(path)
+}
+
+// parseRobots returns the rules of the groups naming agent, or of the "*"
+// group when none does. Consecutive User-agent lines share one group.
+func parseRobots(r io.Reader, agent string) robotsRules {
+	agent = strings.ToLower(agent)
+	var specific, wildcard robotsRules
+	var agents []string
+	inRules, hasSpecific := false, false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// A User-agent after rules starts a new group
+			if inRules {
+				agents, inRules = nil, false
+			}
+			a := strings.ToLower(value)
+			agents = append(agents, a)
+			if a != "" && a != "*" && strings.HasPrefix(agent, a) {
+				hasSpecific = true
+			}
+		case "allow", "disallow":
+			inRules = true
+			// An empty Disallow allows everything
+			if value == "" {
+				continue
+			}
+			rule := robotsRule{pattern: value, allow: key == "allow"}
+			for _, a := range agents {
+				if a == "*" {
+					wildcard = append(wildcard, rule)
+				} else if a != "" && strings.HasPrefix(agent, a) {
+					specific = append(specific, rule)
+				}
+			}
+		}
+	}
+	if hasSpecific {
+		return specific
+	}
+	return wildcard
+}
+
+// robotsEntry is a host's cached rules
+type robotsEntry struct {
+	rules robotsRules
+	at    time.Time
+}
+
+// robotsCache caches robots.txt rules per scheme and host
+type robotsCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]robotsEntry
+}
+
+func newRobotsCache(ttl time.Duration) *robotsCache {
+	return &robotsCache{ttl: ttl, now: time.Now, entries: make(map[string]robotsEntry)}
+}
+
+// rules returns the cached rules of origin, downloading them when missing or expired
+func (c *robotsCache) rules(ctx context.Context, origin string) robotsRules {
+	c.mu.Lock()
+	entry, ok := c.entries[origin]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.at) <= c.ttl {
+		return entry.rules
+	}
+
+	rules := fetchRobots(ctx, origin)
+	c.mu.Lock()
+	c.entries[origin] = robotsEntry{rules: rules, at: c.now()}
+	c.mu.Unlock()
+	return rules
+}
+
+// fetchRobots downloads origin's robots.txt. A missing or unreadable file
+// places no restrictions.
+func fetchRobots(ctx context.Context, origin string) robotsRules {
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("User-Agent", fetchUserAgent)
+	resp, err := newHTTPClient(robotsTimeout).Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	return parseRobots(io.LimitReader(resp.Body, maxRobotsSize), robotsAgent)
+}
+
+// checkRobots returns an error if robots.txt checks are enabled and rawURL's
+// path is disallowed for our user agent
+func checkRobots(ctx context.Context, rawURL string) error {
+	if !respectRobots {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if !globalRobotsCache.rules(ctx, origin).allowed(path) {
+		return fmt.Errorf("fetching %s is disallowed by %s/robots.txt for user agent %s", rawURL, origin, robotsAgent)
+	}
+	return nil
+}
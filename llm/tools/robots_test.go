@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testRobots = `# robots for tests
+User-agent: *
+Disallow: /private/
+Allow: /private/public$
+Disallow: /*.pdf$
+
+User-agent: OtherBot
+Disallow: /
+`
+
+// TestParseRobots verifies group selection and longest-match precedence
+func TestParseRobots(t *testing.T) {
+	rules := parseRobots(strings.NewReader(testRobots), robotsAgent)
+	tests := map[string]bool{
+		"/":                        true,
+		"/docs/intro":              true,
+		"/private/notes":           false,
+		"/private/public":          true,
+		"/private/public/more":     false,
+		"/files/report.pdf":        false,
+		"/files/report.pdf?inline": true,
+	}
+	for path, want := range tests {
+		if got := rules.allowed(path); got != want {
+			t.Errorf("allowed(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	// A group naming our agent replaces the * group
+	specific := parseRobots(strings.NewReader(testRobots+"\nUser-agent: compass-fetch-tool\nDisallow: /docs\n"), robotsAgent)
+	if specific.allowed("/docs/intro") || !specific.allowed("/private/notes") {
+		t.Errorf("specific group rules = %+v", specific)
+	}
+}
+
+// TestFetchRespectsRobots verifies disallowed paths are refused and robots.txt is cached per host
+func TestFetchRespectsRobots(t *testing.T) {
+	prevRespect, prevCache := respectRobots, globalRobotsCache
+	respectRobots, globalRobotsCache = true, newRobotsCache(time.Hour)
+	t.Cleanup(func() { respectRobots, globalRobotsCache = prevRespect, prevCache })
+
+	var robotsHits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsHits.Add(1)
+			w.Write([]byte(testRobots))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("page " + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	out, _ := FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL + "/private/notes"})
+	if !strings.Contains(out, "ERROR") || !strings.Contains(out, "disallowed by") {
+		t.Errorf("disallowed path should be refused:\n%s", out)
+	}
+	out, _ = FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL + "/docs/ok"})
+	if !strings.Contains(out, "page /docs/ok") {
+		t.Errorf("allowed path should be fetched:\n%s", out)
+	}
+	if n := robotsHits.Load(); n != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1 (cached)", n)
+	}
+}
+
+// TestRobotsCheckedBeforeCache verifies a cached page and fetch_table both honor robots.txt
+func TestRobotsCheckedBeforeCache(t *testing.T) {
+	useToolCache(t)
+	allowPrivateNetworks = true
+	defer func() { allowPrivateNetworks = false }()
+	prevRespect, prevCache := respectRobots, globalRobotsCache
+	t.Cleanup(func() { respectRobots, globalRobotsCache = prevRespect, prevCache })
+
+	robots := "User-agent: *\nDisallow:\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte(robots))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<table><tr><th>a</th></tr><tr><td>1</td></tr></table>"))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	respectRobots, globalRobotsCache = true, newRobotsCache(time.Hour)
+	if out, _ := FetchToolFunc(ctx, FetchToolParams{URL: srv.URL + "/private/table"}); strings.Contains(out, "ERROR") {
+		t.Fatalf("allowed page should be fetched:\n%s", out)
+	}
+
+	// The site now disallows the page, which is still in the disk cache
+	robots = testRobots
+	globalRobotsCache = newRobotsCache(time.Hour)
+	out, _ := FetchToolFunc(ctx, FetchToolParams{URL: srv.URL + "/private/table"})
+	if !strings.Contains(out, "disallowed by") {
+		t.Errorf("a cached page should not bypass robots.txt:\n%s", out)
+	}
+	out, _ = FetchTableFunc(ctx, FetchTableParams{URL: srv.URL + "/private/table"})
+	if !strings.Contains(out, "disallowed by") {
+		t.Errorf("fetch_table should honor robots.txt:\n%s", out)
+	}
+}
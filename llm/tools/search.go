@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"math/rand/v2"
 	"net/http"
 	"net/url"
@@ -79,6 +78,7 @@ PARAMETERS:
 
 OUTPUT FORMAT:
 Returns formatted search results with titles, URLs, and snippets.
+When enabled, a FETCH PLAN follows: the URLs most relevant to the query, in the order to fetch them.
 
 EXAMPLES:
 - Search news: {"query": "Golang 1.23 release notes"}
@@ -99,16 +99,76 @@ func SearchToolFunc(ctx context.Context, params SearchToolParams) (string, error
 		maxResults = MaxSearchMaxResults
 	}
 
+	// Persisted results from an earlier run skip the request and its rate limit
+	cacheInput := SearchToolParams{Query: params.Query, MaxResults: maxResults}
+	var results []SearchResult
+	if !globalToolCache.Get(SearchToolName, cacheInput, &results) {
+		var err error
+		results, err = searchWeb(ctx, params.Query, maxResults)
+		if err != nil {
+			return Error(err.Error())
+		}
+		if len(results) > 0 {
+			globalToolCache.Put(SearchToolName, cacheInput, results)
+		}
+	}
+
+	// Optional rerank by authority and recency
+	if searchRerankConfig.Enabled {
+		results = rerankSearchResults(results, searchRerankConfig)
+	}
+
+	if len(results) == 0 {
+		return Success(fmt.Sprintf("No results found for '%s'", params.Query),
+			&Metadata{MatchCount: 0}, TierCompact)
+	}
+
+	// Format output
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d search results for '%s':\n\n", len(results), params.Query))
+	for _, res := range results {
+		sb.WriteString(fmt.Sprintf("- **%s**\n", res.Title))
+		sb.WriteString(fmt.Sprintf("  URL: %s\n", res.Link))
+		sb.WriteString(fmt.Sprintf("  Snippet: %s\n", res.Snippet))
+	}
+
+	// Optional ranked fetch plan so the model can fetch without another round of deliberation
+	sb.WriteString(formatFetchPlan(planFetches(params.Query, results, searchFetchPlanSize)))
+
+	md := &Metadata{MatchCount: len(results)}
+	for _, res := range results {
+		md.Sources = append(md.Sources, res.Link)
+	}
+	recordMetadataSources(ctx, md)
+
+	return Success(sb.String(), md, TierCompact)
+}
+
+// searchEndpoint is the DuckDuckGo Lite search URL
+var searchEndpoint = "https://lite.duckduckgo.com/lite/"
+
+// CheckSearch runs a small web search, bypassing the tool cache, to verify
+// that the search endpoint is reachable. It returns the number of results.
+func CheckSearch(ctx context.Context) (int, error) {
+	results, err := searchWeb(ctx, "golang", 1)
+	if err != nil {
+		return 0, err
+	}
+	return len(results), nil
+}
+
+// searchWeb queries DuckDuckGo Lite and parses up to maxResults results
+func searchWeb(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
 	// Rate limiting
 	maybeDelaySearch()
 
 	// Build search URL
-	searchURL := "https://lite.duckduckgo.com/lite/?q=" + url.QueryEscape(params.Query)
+	searchURL := searchEndpoint + "?q=" + url.QueryEscape(query)
 
-	client := &http.Client{Timeout: SearchTimeout}
+	client := newHTTPClient(SearchTimeout)
 	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
-		return Error(fmt.Sprintf("failed to create request: %v", err))
+		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	setRandomizedHeaders(req)
@@ -116,42 +176,25 @@ func SearchToolFunc(ctx context.Context, params SearchToolParams) (string, error
 	// Execute request
 	resp, err := client.Do(req)
 	if err != nil {
-		return Error(fmt.Sprintf("search request failed: %v", err))
+		return nil, fmt.Errorf("search request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return Error(fmt.Sprintf("search failed with status code: %d", resp.StatusCode))
+		return nil, fmt.Errorf("search failed with status code: %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return Error(fmt.Sprintf("failed to read response: %v", err))
+		return nil, fmt.Errorf("failed to read response: %v", err)
 	}
 
 	// Parse results
 	results, err := parseLiteSearchResults(string(body), maxResults)
 	if err != nil {
-		return Error(fmt.Sprintf("failed to parse results: %v", err))
-	}
-
-	if len(results) == 0 {
-		return Success(fmt.Sprintf("No results found for '%s'", params.Query),
-			&Metadata{MatchCount: 0}, TierCompact)
-	}
-
-	// Format output
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Found %d search results for '%s':\n\n", len(results), params.Query))
-	for _, res := range results {
-		sb.WriteString(fmt.Sprintf("- **%s**\n", res.Title))
-		sb.WriteString(fmt.Sprintf("  URL: %s\n", res.Link))
-		sb.WriteString(fmt.Sprintf("  Snippet: %s\n", res.Snippet))
+		return nil, fmt.Errorf("failed to parse results: %v", err)
 	}
-
-	return Success(sb.String(), &Metadata{
-		MatchCount: len(results),
-	}, TierCompact)
+	return results, nil
 }
 
 // setRandomizedHeaders sets randomized HTTP headers to mimic a real browser
@@ -273,14 +316,14 @@ func getTextContent(n *html.Node) string {
 }
 
 // GetSearchTool returns the search tool with enhanced description
-func GetSearchTool() tool.InvokableTool {
+func GetSearchTool() (tool.InvokableTool, error) {
 	t, err := utils.InferTool(
 		SearchToolName,
 		searchDescription,
 		SearchToolFunc,
 	)
 	if err != nil {
-		log.Fatalf("failed to create search tool: %v", err)
+		return nil, fmt.Errorf("failed to create %s tool: %w", SearchToolName, err)
 	}
-	return t
+	return t, nil
 }
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxFetchPlanSize bounds the number of URLs in a fetch plan
+	maxFetchPlanSize = 10
+
+	// Scoring weights: title matches count more than snippet matches, and
+	// engine order breaks ties between equally relevant results
+	fetchPlanTitleWeight     = 2.0
+	fetchPlanSnippetWeight   = 1.0
+	fetchPlanPositionWeight  = 0.5
+	fetchPlanAuthorityWeight = 1.0
+)
+
+// fetchPlanStopwords are query words that say nothing about relevance
+var fetchPlanStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "for": true, "how": true, "in": true,
+	"is": true, "of": true, "on": true, "or": true, "the": true, "to": true, "what": true,
+	"when": true, "where": true, "which": true, "who": true, "why": true, "with": true,
+}
+
+// SearchFetchPlanFromEnv reads SEARCH_FETCH_PLAN, the number of URLs to
+// recommend after web_search results. 0 or unset disables the plan.
+func SearchFetchPlanFromEnv() int {
+	n, err := strconv.Atoi(os.Getenv("SEARCH_FETCH_PLAN"))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return min(n, maxFetchPlanSize)
+}
+
+// searchFetchPlanSize is the active fetch plan size
+var searchFetchPlanSize = SearchFetchPlanFromEnv()
+
+// FetchPlanItem is one recommended URL with the reasons it was picked
+type FetchPlanItem struct {
+	URL     string
+	Title   string
+	Score   float64
+	Reasons []string
+}
+
+// planFetches ranks search results by how well they match the query and
+// returns at most n distinct http(s) URLs to fetch, best first
+func planFetches(query string, results []SearchResult, n int) []FetchPlanItem {
+	if n <= 0 || len(results) == 0 {
+		return nil
+	}
+
+	terms := queryTerms(query)
+	seen := make(map[string]bool)
+	var items []FetchPlanItem
+
+	for i, res := range results {
+		u, err := url.Parse(res.Link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || seen[res.Link] {
+			continue
+		}
+		seen[res.Link] = true
+
+		item := FetchPlanItem{URL: res.Link, Title: res.Title}
+		if len(terms) > 0 {
+			title := matchedTerms(terms, res.Title)
+			snippet := matchedTerms(terms, res.Snippet)
+			item.Score += fetchPlanTitleWeight*float64(title)/float64(len(terms)) +
+				fetchPlanSnippetWeight*float64(snippet)/float64(len(terms))
+			if title > 0 {
+				item.Reasons = append(item.Reasons, fmt.Sprintf("title matches %d/%d query terms", title, len(terms)))
+			}
+			if snippet > 0 {
+				item.Reasons = append(item.Reasons, fmt.Sprintf("snippet matches %d/%d query terms", snippet, len(terms)))
+			}
+		}
+
+		item.Score += fetchPlanPositionWeight * (1 - float64(i)/float64(len(results)))
+		if i < 3 {
+			item.Reasons = append(item.Reasons, fmt.Sprintf("search rank #%d", i+1))
+		}
+
+		if isAuthoritative(res.Link, searchRerankConfig.AuthorityDomains) {
+			item.Score += fetchPlanAuthorityWeight
+			item.Reasons = append(item.Reasons, "authoritative domain")
+		}
+
+		if len(item.Reasons) == 0 {
+			item.Reasons = append(item.Reasons, fmt.Sprintf("search rank #%d", i+1))
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].Score > items[b].Score
+	})
+	if len(items) > n {
+		items = items[:n]
+	}
+	return items
+}
+
+// queryTerms lowercases the query and splits it into distinct words,
+// dropping stopwords and single characters
+func queryTerms(query string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool)
+	var terms []string
+	for _, f := range fields {
+		if len([]rune(f)) < 2 || fetchPlanStopwords[f] || seen[f] {
+			continue
+		}
+		seen[f] = true
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// matchedTerms counts the terms that occur in text, case-insensitively
+func matchedTerms(terms []string, text string) int {
+	text = strings.ToLower(text)
+	n := 0
+	for _, t := range terms {
+		if strings.Contains(text, t) {
+			n++
+		}
+	}
+	return n
+}
+
+// formatFetchPlan renders the plan appended to web_search results
+func formatFetchPlan(items []FetchPlanItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\nFETCH PLAN (fetch these first, in order):\n")
+	for i, item := range items {
+		sb.WriteString(fmt.Sprintf("%d. %s (%s)\n", i+1, item.URL, strings.Join(item.Reasons, "; ")))
+	}
+	return sb.String()
+}
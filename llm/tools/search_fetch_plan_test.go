@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPlanFetchesRanksRelevance verifies the most relevant results lead the plan and it is capped at N
+func TestPlanFetchesRanksRelevance(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Cooking recipes", Link: "https://food.example.com/pasta", Snippet: "Quick dinners for busy weeks"},
+		{Title: "Go news roundup", Link: "https://news.example.com/go", Snippet: "This week: generics in Go and more"},
+		{Title: "Go generics tutorial", Link: "https://go.dev/doc/tutorial/generics", Snippet: "Learn how to use generics in Go"},
+		{Title: "Go generics tutorial", Link: "https://go.dev/doc/tutorial/generics", Snippet: "duplicate entry"},
+		{Title: "FTP mirror", Link: "ftp://mirror.example.com/go", Snippet: "Go generics tutorial archive"},
+		{Title: "Type parameters proposal", Link: "https://go.googlesource.com/proposal", Snippet: "Design of generics for Go"},
+	}
+
+	plan := planFetches("How to use Go generics?", results, 3)
+	if len(plan) != 3 {
+		t.Fatalf("plan has %d items, want 3", len(plan))
+	}
+	if plan[0].URL != "https://go.dev/doc/tutorial/generics" {
+		t.Errorf("most relevant result should lead the plan, got %s", plan[0].URL)
+	}
+	for _, item := range plan {
+		if item.URL == "https://food.example.com/pasta" || strings.HasPrefix(item.URL, "ftp://") {
+			t.Errorf("unexpected URL in plan: %s", item.URL)
+		}
+		if len(item.Reasons) == 0 {
+			t.Errorf("%s has no reasons", item.URL)
+		}
+	}
+	if !strings.Contains(strings.Join(plan[0].Reasons, ";"), "title matches 2/3 query terms") {
+		t.Errorf("unexpected reasons: %v", plan[0].Reasons)
+	}
+
+	if got := planFetches("go generics", results, 0); got != nil {
+		t.Errorf("n=0 should disable the plan, got %v", got)
+	}
+	if got := planFetches("go generics", results, 10); len(got) != 4 {
+		t.Errorf("plan should hold each distinct http(s) URL once, got %d", len(got))
+	}
+}
+
+// TestFormatFetchPlanMatchesSchema verifies the plan lines fit the web_search output schema
+func TestFormatFetchPlanMatchesSchema(t *testing.T) {
+	plan := planFetches("go generics", []SearchResult{
+		{Title: "Go generics tutorial", Link: "https://go.dev/doc/tutorial/generics", Snippet: "generics"},
+		{Title: "Other", Link: "https://example.com/other", Snippet: "unrelated"},
+	}, 2)
+	out := "Found 2 search results for 'go generics':\n\n" +
+		"- **Go generics tutorial**\n  URL: https://go.dev/doc/tutorial/generics\n  Snippet: generics\n" +
+		"- **Other**\n  URL: https://example.com/other\n  Snippet: unrelated\n" +
+		formatFetchPlan(plan)
+	if !strings.Contains(out, "1. https://go.dev/doc/tutorial/generics (title matches 2/2 query terms") {
+		t.Errorf("unexpected plan:\n%s", out)
+	}
+	checkOutputSchema(t, SearchToolName, out)
+
+	if formatFetchPlan(nil) != "" {
+		t.Error("an empty plan should render nothing")
+	}
+}
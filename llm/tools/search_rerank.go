@@ -0,0 +1,150 @@
+package tools
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultAuthorityBoost is the score bonus for authoritative domains
+	defaultAuthorityBoost = 1.0
+	// defaultRecencyBoost is the maximum score bonus for recent pages
+	defaultRecencyBoost = 0.5
+	// recencyHorizon is the age after which a page gets no recency bonus
+	recencyHorizon = 2 * 365 * 24 * time.Hour
+)
+
+// SearchRerankConfig configures post-processing of web search results
+type SearchRerankConfig struct {
+	Enabled          bool     // Whether reranking is applied (default: off)
+	AuthorityDomains []string // Domain patterns such as "*.golang.org", "docs.*", "go.dev"
+	AuthorityBoost   float64  // Score bonus for authoritative domains
+	RecencyBoost     float64  // Maximum score bonus for recent pages
+	Now              func() time.Time
+}
+
+// DefaultSearchRerankConfig returns the rerank configuration from environment
+//   - SEARCH_RERANK: "true" to enable reranking
+//   - SEARCH_AUTHORITY_DOMAINS: comma separated domain patterns
+func DefaultSearchRerankConfig() SearchRerankConfig {
+	return SearchRerankConfig{
+		Enabled:          os.Getenv("SEARCH_RERANK") == "true",
+		AuthorityDomains: parseDomainList(os.Getenv("SEARCH_AUTHORITY_DOMAINS")),
+		AuthorityBoost:   defaultAuthorityBoost,
+		RecencyBoost:     defaultRecencyBoost,
+		Now:              time.Now,
+	}
+}
+
+// searchRerankConfig is the active rerank configuration
+var searchRerankConfig = DefaultSearchRerankConfig()
+
+// snippetDatePatterns matches common date formats found in search snippets
+var snippetDatePatterns = []struct {
+	re     *regexp.Regexp
+	layout string
+}{
+	{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`), "2006-01-02"},
+	{regexp.MustCompile(`\b(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-z]* \d{1,2}, \d{4}\b`), "Jan 2, 2006"},
+	{regexp.MustCompile(`\b\d{1,2} (?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-z]* \d{4}\b`), "2 Jan 2006"},
+}
+
+// rerankSearchResults reorders results by engine position plus authority and
+// recency bonuses. The sort is stable, so ties keep engine order.
+func rerankSearchResults(results []SearchResult, cfg SearchRerankConfig) []SearchResult {
+	if len(results) < 2 {
+		return results
+	}
+
+	now := time.Now()
+	if cfg.Now != nil {
+		now = cfg.Now()
+	}
+
+	type scored struct {
+		result SearchResult
+		score  float64
+	}
+	items := make([]scored, len(results))
+	n := float64(len(results))
+
+	for i, res := range results {
+		// Engine order contributes a score in (0, 1]
+		score := 1.0 - float64(i)/n
+
+		if isAuthoritative(res.Link, cfg.AuthorityDomains) {
+			score += cfg.AuthorityBoost
+		}
+		if date, ok := parseSnippetDate(res.Snippet); ok {
+			score += cfg.RecencyBoost * recencyFactor(now.Sub(date))
+		}
+		items[i] = scored{result: res, score: score}
+	}
+
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].score > items[b].score
+	})
+
+	reranked := make([]SearchResult, len(items))
+	for i, item := range items {
+		reranked[i] = item.result
+		reranked[i].Position = i + 1
+	}
+	return reranked
+}
+
+// isAuthoritative reports whether the link's host matches an authority pattern
+func isAuthoritative(link string, patterns []string) bool {
+	u, err := url.Parse(link)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, p := range patterns {
+		if strings.HasSuffix(p, ".*") {
+			if strings.HasPrefix(host, p[:len(p)-1]) {
+				return true
+			}
+			continue
+		}
+		if matchDomain(host, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSnippetDate extracts the first recognizable date from a snippet
+func parseSnippetDate(snippet string) (time.Time, bool) {
+	for _, p := range snippetDatePatterns {
+		if m := p.re.FindString(snippet); m != "" {
+			if t, err := time.Parse(p.layout, shortMonth(m)); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// shortMonth abbreviates full month names so they fit the "Jan" layouts
+func shortMonth(s string) string {
+	for _, m := range []string{"January", "February", "March", "April", "June", "July", "August", "September", "October", "November", "December"} {
+		s = strings.Replace(s, m, m[:3], 1)
+	}
+	return s
+}
+
+// recencyFactor maps a page age to [0, 1], 1 for pages from the last month
+func recencyFactor(age time.Duration) float64 {
+	if age < 30*24*time.Hour {
+		return 1
+	}
+	if age >= recencyHorizon {
+		return 0
+	}
+	return 1 - float64(age)/float64(recencyHorizon)
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRerankAuthoritativeDomains verifies authoritative domains move up when enabled
+func TestRerankAuthoritativeDomains(t *testing.T) {
+	results := []SearchResult{
+		{Title: "Blog", Link: "https://someblog.example.com/go", Position: 1},
+		{Title: "Forum", Link: "https://forum.example.net/t/1", Position: 2},
+		{Title: "Pkg", Link: "https://pkg.golang.org/net/http", Position: 3},
+		{Title: "Docs", Link: "https://docs.example.org/guide", Position: 4},
+	}
+	cfg := SearchRerankConfig{
+		Enabled:          true,
+		AuthorityDomains: []string{"*.golang.org", "docs.*"},
+		AuthorityBoost:   defaultAuthorityBoost,
+	}
+
+	got := rerankSearchResults(results, cfg)
+	want := []string{"Pkg", "Docs", "Blog", "Forum"}
+	for i, title := range want {
+		if got[i].Title != title {
+			t.Fatalf("position %d: expected %s, got %s", i+1, title, got[i].Title)
+		}
+		if got[i].Position != i+1 {
+			t.Errorf("position not renumbered: %+v", got[i])
+		}
+	}
+
+	// Without matching domains the engine order is kept
+	got = rerankSearchResults(results, SearchRerankConfig{Enabled: true})
+	for i := range results {
+		if got[i].Title != results[i].Title {
+			t.Errorf("order changed without authority domains: %v", got)
+		}
+	}
+}
+
+// TestRerankRecency verifies dated snippets rank recent pages higher
+func TestRerankRecency(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	results := []SearchResult{
+		{Title: "Old", Link: "https://a.example.com", Snippet: "Published March 3, 2019 ..."},
+		{Title: "New", Link: "https://b.example.com", Snippet: "2025-05-20 release notes"},
+	}
+	got := rerankSearchResults(results, SearchRerankConfig{
+		Enabled:      true,
+		RecencyBoost: 1.0,
+		Now:          func() time.Time { return now },
+	})
+	if got[0].Title != "New" {
+		t.Errorf("expected recent result first, got %v", got)
+	}
+
+	if _, ok := parseSnippetDate("no date here"); ok {
+		t.Error("expected no date parsed")
+	}
+	if d, ok := parseSnippetDate("Updated 12 January 2024"); !ok || d.Year() != 2024 || d.Month() != time.January {
+		t.Errorf("failed to parse full month name: %v %v", d, ok)
+	}
+}
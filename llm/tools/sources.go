@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+type sourceCollectorKey struct{}
+
+// SourceCollector accumulates the URLs and knowledge base sources consulted
+// during a run, deduplicated and in first-seen order
+type SourceCollector struct {
+	mu      sync.Mutex
+	seen    map[string]bool
+	sources []string
+}
+
+// NewSourceCollector creates an empty source collector
+func NewSourceCollector() *SourceCollector {
+	return &SourceCollector{seen: make(map[string]bool)}
+}
+
+// Add records sources, ignoring blanks and duplicates
+func (c *SourceCollector) Add(sources ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range sources {
+		s = strings.TrimSpace(s)
+		if s == "" || c.seen[s] {
+			continue
+		}
+		c.seen[s] = true
+		c.sources = append(c.sources, s)
+	}
+}
+
+// Sources returns the recorded sources in first-seen order
+func (c *SourceCollector) Sources() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.sources...)
+}
+
+// WithSourceCollector returns a context whose network and knowledge tools
+// record the sources they consult into c
+func WithSourceCollector(ctx context.Context, c *SourceCollector) context.Context {
+	return context.WithValue(ctx, sourceCollectorKey{}, c)
+}
+
+// SourceCollectorFromContext returns the run's source collector, or nil if unset
+func SourceCollectorFromContext(ctx context.Context) *SourceCollector {
+	c, _ := ctx.Value(sourceCollectorKey{}).(*SourceCollector)
+	return c
+}
+
+// RecordSources adds sources to the context's collector, if any
+func RecordSources(ctx context.Context, sources ...string) {
+	if c := SourceCollectorFromContext(ctx); c != nil {
+		c.Add(sources...)
+	}
+}
+
+// recordMetadataSources records the URL and sources reported in a tool's metadata
+func recordMetadataSources(ctx context.Context, md *Metadata) {
+	if md == nil {
+		return
+	}
+	RecordSources(ctx, md.URL)
+	RecordSources(ctx, md.Sources...)
+}
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestToolsRecordSources verifies fetch and knowledge results are recorded once each
+func TestToolsRecordSources(t *testing.T) {
+	useFakeKnowledgeStore(t)
+	srv := newPageServer(t, 100)
+	collector := NewSourceCollector()
+	ctx := WithSourceCollector(context.Background(), collector)
+
+	dir := t.TempDir()
+	manual := writeTestFile(t, dir, "manual.md", "To reset the password open the admin console and choose the reset option under account settings, then confirm the change by email.\n")
+	if out, _ := IngestDocumentFunc(ctx, IngestDocumentParams{FilePath: manual}); strings.Contains(out, "ERROR") {
+		t.Fatalf("ingest failed: %s", out)
+	}
+
+	FetchToolFunc(ctx, FetchToolParams{URL: srv.URL})
+	FetchToolFunc(ctx, FetchToolParams{URL: srv.URL})
+	out, _ := KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "reset the password"})
+	if !strings.Contains(out, "relevant results") {
+		t.Fatalf("search failed:\n%s", out)
+	}
+
+	want := []string{srv.URL, manual}
+	if got := collector.Sources(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("sources = %v, want %v", got, want)
+	}
+
+	// Without a collector, recording is a no-op
+	FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL + "/other"})
+	if got := collector.Sources(); len(got) != 2 {
+		t.Errorf("sources recorded outside the run: %v", got)
+	}
+}
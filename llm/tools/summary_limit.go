@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// DefaultSummaryConcurrency is the default number of summary sub-agents
+// allowed to run at once
+const DefaultSummaryConcurrency = 2
+
+// SummaryStats is a snapshot of summary sub-agent activity
+type SummaryStats struct {
+	Active    int // Summaries currently running
+	Queued    int // Summaries waiting for a free slot
+	Completed int // Summaries finished since startup
+	Limit     int // Maximum concurrent summaries
+}
+
+// Pending returns the number of summaries running or waiting
+func (s SummaryStats) Pending() int {
+	return s.Active + s.Queued
+}
+
+// SummaryConcurrencyFromEnv reads SUMMARY_MAX_CONCURRENCY, falling back to
+// DefaultSummaryConcurrency when unset or not a positive integer
+func SummaryConcurrencyFromEnv() int {
+	if val := os.Getenv("SUMMARY_MAX_CONCURRENCY"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultSummaryConcurrency
+}
+
+// summaryLimiter bounds concurrent summaries with a FIFO wait queue and
+// publishes SummaryStats on every change
+type summaryLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	active    int
+	completed int
+	queue     []chan struct{} // waiters in arrival order
+	broker    *pubsub.Broker[SummaryStats]
+}
+
+// newSummaryLimiter creates a limiter allowing limit concurrent summaries
+func newSummaryLimiter(limit int) *summaryLimiter {
+	if limit <= 0 {
+		limit = DefaultSummaryConcurrency
+	}
+	return &summaryLimiter{
+		limit:  limit,
+		broker: pubsub.NewBroker[SummaryStats](),
+	}
+}
+
+// summaryLimit is the limiter shared by all summary tools
+var summaryLimit = newSummaryLimiter(SummaryConcurrencyFromEnv())
+
+// SummaryMetrics returns the subscriber for summary concurrency updates
+func SummaryMetrics() pubsub.Subscriber[SummaryStats] {
+	return summaryLimit.broker
+}
+
+// Stats returns the current summary activity
+func (l *summaryLimiter) Stats() SummaryStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.statsLocked()
+}
+
+func (l *summaryLimiter) statsLocked() SummaryStats {
+	return SummaryStats{
+		Active:    l.active,
+		Queued:    len(l.queue),
+		Completed: l.completed,
+		Limit:     l.limit,
+	}
+}
+
+// publishLocked publishes the current stats; the caller holds mu so
+// subscribers observe updates in order
+func (l *summaryLimiter) publishLocked() {
+	l.broker.Publish(pubsub.UpdatedEvent, l.statsLocked())
+}
+
+// acquire waits for a free slot in arrival order. A queued caller whose
+// context ends is removed from the queue and gets the context error.
+func (l *summaryLimiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	if l.active < l.limit && len(l.queue) == 0 {
+		l.active++
+		l.publishLocked()
+		l.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	l.queue = append(l.queue, ready)
+	l.publishLocked()
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		for i, w := range l.queue {
+			if w == ready {
+				l.queue = append(l.queue[:i], l.queue[i+1:]...)
+				l.publishLocked()
+				l.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		l.mu.Unlock()
+		// The slot was handed over while cancelling; pass it on
+		l.release(false)
+		return ctx.Err()
+	}
+}
+
+// release frees a slot, handing it directly to the oldest waiter if any.
+// done reports whether the summary ran to completion.
+func (l *summaryLimiter) release(done bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if done {
+		l.completed++
+	}
+	if len(l.queue) > 0 {
+		next := l.queue[0]
+		l.queue = l.queue[1:]
+		close(next)
+	} else {
+		l.active--
+	}
+	l.publishLocked()
+}
+
+// limitedTool runs an invokable tool under a summaryLimiter
+type limitedTool struct {
+	tool.InvokableTool
+	limiter *summaryLimiter
+}
+
+// InvokableRun waits for a free slot before running the wrapped tool
+func (t *limitedTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	if err := t.limiter.acquire(ctx); err != nil {
+		return "", err
+	}
+	defer t.limiter.release(true)
+	return t.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+}
@@ -0,0 +1,134 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// blockingTool records the order calls start in and blocks until released
+type blockingTool struct {
+	mu      sync.Mutex
+	started []string
+	release chan struct{}
+}
+
+func (t *blockingTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "summarize_url"}, nil
+}
+
+func (t *blockingTool) InvokableRun(ctx context.Context, args string, opts ...tool.Option) (string, error) {
+	t.mu.Lock()
+	t.started = append(t.started, args)
+	t.mu.Unlock()
+	<-t.release
+	return "summary of " + args, nil
+}
+
+// waitStarted polls until n calls have started
+func (t *blockingTool) waitStarted(tb testing.TB, n int) {
+	tb.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		t.mu.Lock()
+		started := len(t.started)
+		t.mu.Unlock()
+		if started >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	tb.Fatalf("only %d of %d calls started", len(t.started), n)
+}
+
+// waitForStats polls until the limiter reports the wanted stats
+func waitForStats(t *testing.T, l *summaryLimiter, want SummaryStats) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if l.Stats() == want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("stats = %+v, want %+v", l.Stats(), want)
+}
+
+// TestSummaryLimiterBurst verifies active/queued counts and FIFO order for a burst of summaries
+func TestSummaryLimiterBurst(t *testing.T) {
+	limiter := newSummaryLimiter(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := limiter.broker.Subscribe(ctx)
+
+	inner := &blockingTool{release: make(chan struct{})}
+	lt := &limitedTool{InvokableTool: inner, limiter: limiter}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(arg string) {
+			defer wg.Done()
+			if _, err := lt.InvokableRun(context.Background(), arg); err != nil {
+				t.Errorf("run %s: %v", arg, err)
+			}
+		}(fmt.Sprintf("url%d", i))
+		// Start calls one at a time so arrival order is deterministic
+		waitForStats(t, limiter, SummaryStats{Active: min(i+1, 2), Queued: max(i-1, 0), Limit: 2})
+		inner.waitStarted(t, min(i+1, 2))
+	}
+
+	var last SummaryStats
+	for len(events) > 0 {
+		last = (<-events).Payload
+	}
+	if last != (SummaryStats{Active: 2, Queued: 3, Limit: 2}) {
+		t.Errorf("last published stats = %+v, want 2 active and 3 queued", last)
+	}
+
+	for done := 1; done <= 5; done++ {
+		inner.release <- struct{}{}
+		waitForStats(t, limiter, SummaryStats{
+			Active:    min(5-done, 2),
+			Queued:    max(3-done, 0),
+			Completed: done,
+			Limit:     2,
+		})
+		// Wait for the promoted call to start before releasing the next one
+		inner.waitStarted(t, min(done+2, 5))
+	}
+	wg.Wait()
+
+	want := []string{"url0", "url1", "url2", "url3", "url4"}
+	if fmt.Sprint(inner.started) != fmt.Sprint(want) {
+		t.Errorf("start order = %v, want %v", inner.started, want)
+	}
+}
+
+// TestSummaryLimiterCancelQueued verifies a queued summary leaves the queue when its context ends
+func TestSummaryLimiterCancelQueued(t *testing.T) {
+	limiter := newSummaryLimiter(1)
+	if err := limiter.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- limiter.acquire(ctx) }()
+	waitForStats(t, limiter, SummaryStats{Active: 1, Queued: 1, Limit: 1})
+
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	waitForStats(t, limiter, SummaryStats{Active: 1, Queued: 0, Limit: 1})
+
+	limiter.release(true)
+	waitForStats(t, limiter, SummaryStats{Active: 0, Queued: 0, Completed: 1, Limit: 1})
+}
@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultToolCacheTTL is how long a persisted tool result stays valid
+	DefaultToolCacheTTL = 24 * time.Hour
+	// DefaultToolCacheMaxBytes bounds the total size of the cache directory
+	DefaultToolCacheMaxBytes = 100 * 1024 * 1024
+)
+
+// ToolCache persists tool results on disk so they survive across runs.
+// Entries live in <dir>/<namespace>/<sha256 of input>.json and expire after
+// ttl; the oldest entries are evicted once the cache exceeds maxBytes.
+// The total size is tracked in memory, so the directory is only walked to
+// measure it once and to evict. A nil *ToolCache is a disabled cache.
+type ToolCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	now      func() time.Time
+	mu       sync.Mutex
+	size     int64 // Total bytes of the entries, -1 until measured
+}
+
+// toolCacheEntry is the on-disk form of a cached result
+type toolCacheEntry struct {
+	SavedAt time.Time       `json:"saved_at"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// NewToolCache creates a disk cache rooted at dir
+func NewToolCache(dir string, ttl time.Duration, maxBytes int64) *ToolCache {
+	return &ToolCache{dir: dir, ttl: ttl, maxBytes: maxBytes, now: time.Now, size: -1}
+}
+
+// ToolCacheFromEnv creates the cache configured by the environment, or nil
+// when disabled
+//   - COMPASS_TOOL_CACHE_DIR: cache directory; unset disables the cache
+//   - COMPASS_TOOL_CACHE_TTL: entry lifetime as a Go duration (default: 24h)
+//   - COMPASS_TOOL_CACHE_MAX_MB: size bound in megabytes (default: 100)
+func ToolCacheFromEnv() *ToolCache {
+	dir := os.Getenv("COMPASS_TOOL_CACHE_DIR")
+	if dir == "" {
+		return nil
+	}
+	ttl := DefaultToolCacheTTL
+	if d, err := time.ParseDuration(os.Getenv("COMPASS_TOOL_CACHE_TTL")); err == nil && d > 0 {
+		ttl = d
+	}
+	maxBytes := int64(DefaultToolCacheMaxBytes)
+	if mb, err := strconv.Atoi(os.Getenv("COMPASS_TOOL_CACHE_MAX_MB")); err == nil && mb > 0 {
+		maxBytes = int64(mb) * 1024 * 1024
+	}
+	return NewToolCache(dir, ttl, maxBytes)
+}
+
+// globalToolCache is the active persistent cache, nil when disabled
+var globalToolCache = ToolCacheFromEnv()
+
+// entryPath returns the file holding the result for a namespace and input
+func (c *ToolCache) entryPath(namespace string, input any) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(namespace+"\x00"), data...))
+	return filepath.Join(c.dir, namespace, hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// Get decodes the cached result for input into out. It reports false when
+// the cache is disabled or the entry is missing, expired or unreadable.
+func (c *ToolCache) Get(namespace string, input any, out any) bool {
+	if c == nil {
+		return false
+	}
+	path, err := c.entryPath(namespace, input)
+	if err != nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var entry toolCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || c.now().Sub(entry.SavedAt) > c.ttl {
+		if os.Remove(path) == nil && c.size >= 0 {
+			c.size -= int64(len(data))
+		}
+		return false
+	}
+	return json.Unmarshal(entry.Value, out) == nil
+}
+
+// Put stores the result for input, then evicts expired and excess entries.
+// Failures are logged; caching never fails the tool call.
+func (c *ToolCache) Put(namespace string, input any, value any) {
+	if c == nil {
+		return
+	}
+	path, err := c.entryPath(namespace, input)
+	if err != nil {
+		return
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(toolCacheEntry{SavedAt: c.now(), Value: raw})
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		slog.Warn("tool cache write failed", "err", err)
+		return
+	}
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	// Write then rename so concurrent readers never see a partial entry
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		slog.Warn("tool cache write failed", "err", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		slog.Warn("tool cache write failed", "err", err)
+		return
+	}
+	c.grow(int64(len(data)) - replaced)
+}
+
+// Clear removes every entry of a namespace, e.g. after the data behind it changed
+func (c *ToolCache) Clear(namespace string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	os.RemoveAll(filepath.Join(c.dir, namespace))
+	c.size = -1 // Measured again on the next Put
+}
+
+// grow adds delta to the tracked size, measuring the directory the first
+// time, and evicts once the cache exceeds maxBytes. Callers hold mu.
+func (c *ToolCache) grow(delta int64) {
+	if c.size < 0 {
+		c.evict()
+		return
+	}
+	c.size += delta
+	if c.maxBytes > 0 && c.size > c.maxBytes {
+		c.evict()
+	}
+}
+
+// evict deletes expired entries, then the oldest entries until the cache
+// fits maxBytes, and records the remaining size. Expiry uses the file time,
+// which Put sets on every write. Callers hold mu.
+func (c *ToolCache) evict() {
+	type file struct {
+		path string
+		size int64
+		mod  time.Time
+	}
+	var files []file
+	var total int64
+	now := c.now()
+
+	filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if now.Sub(info.ModTime()) > c.ttl {
+			os.Remove(path)
+			return nil
+		}
+		files = append(files, file{path: path, size: info.Size(), mod: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	defer func() { c.size = total }()
+	if c.maxBytes <= 0 || total <= c.maxBytes {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod.Before(files[j].mod) })
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// useToolCache installs a persistent tool cache in a temporary directory
+func useToolCache(t *testing.T) *ToolCache {
+	t.Helper()
+	prev := globalToolCache
+	globalToolCache = NewToolCache(t.TempDir(), time.Hour, DefaultToolCacheMaxBytes)
+	t.Cleanup(func() { globalToolCache = prev })
+	return globalToolCache
+}
+
+// newCountingServer serves body and counts the requests it receives
+func newCountingServer(t *testing.T, contentType, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+// TestToolCacheFetchPersists verifies a fetched page is reused across runs until it expires
+func TestToolCacheFetchPersists(t *testing.T) {
+	cache := useToolCache(t)
+	srv, hits := newCountingServer(t, "text/plain", "release notes for version 2")
+	ctx := context.Background()
+
+	first, _ := FetchToolFunc(ctx, FetchToolParams{URL: srv.URL})
+
+	// A new cache over the same directory stands for a later run
+	globalToolCache = NewToolCache(cache.dir, time.Hour, DefaultToolCacheMaxBytes)
+	collector := NewSourceCollector()
+	second, _ := FetchToolFunc(WithSourceCollector(ctx, collector), FetchToolParams{URL: srv.URL})
+	if hits.Load() != 1 || second != first {
+		t.Errorf("second fetch should be served from disk: %d requests\nfirst: %s\nsecond: %s", hits.Load(), first, second)
+	}
+	if got := collector.Sources(); len(got) != 1 || got[0] != srv.URL {
+		t.Errorf("cached fetch should still record its source, got %v", got)
+	}
+
+	FetchToolFunc(ctx, FetchToolParams{URL: srv.URL, Format: "html"})
+	if hits.Load() != 2 {
+		t.Errorf("a different format should miss the cache, %d requests", hits.Load())
+	}
+
+	globalToolCache.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	FetchToolFunc(ctx, FetchToolParams{URL: srv.URL})
+	if hits.Load() != 3 {
+		t.Errorf("an expired entry should be fetched again, %d requests", hits.Load())
+	}
+}
+
+// TestToolCacheSearchPersists verifies search results are reused across invocations
+func TestToolCacheSearchPersists(t *testing.T) {
+	useToolCache(t)
+	page := `<html><body><table>
+<tr><td><a class="result-link" href="https://go.dev/doc">Go docs</a></td></tr>
+<tr><td class="result-snippet">Official documentation</td></tr>
+</table></body></html>`
+	srv, hits := newCountingServer(t, "text/html", page)
+	prev := searchEndpoint
+	searchEndpoint = srv.URL
+	t.Cleanup(func() { searchEndpoint = prev })
+
+	ctx := context.Background()
+	first, _ := SearchToolFunc(ctx, SearchToolParams{Query: "go docs"})
+	second, _ := SearchToolFunc(ctx, SearchToolParams{Query: "go docs"})
+	if !strings.Contains(first, "https://go.dev/doc") || second != first {
+		t.Errorf("unexpected results:\nfirst: %s\nsecond: %s", first, second)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("second search should be served from disk, %d requests", hits.Load())
+	}
+}
+
+// TestToolCacheBounds verifies size-based eviction and namespace clearing
+func TestToolCacheBounds(t *testing.T) {
+	cache := NewToolCache(t.TempDir(), time.Hour, 600)
+	value := strings.Repeat("x", 200)
+	for i := 0; i < 5; i++ {
+		cache.Put("ns", i, value)
+		// Distinct file times so the oldest entry is evicted first
+		path, _ := cache.entryPath("ns", i)
+		old := time.Now().Add(time.Duration(i-10) * time.Minute)
+		os.Chtimes(path, old, old)
+	}
+	cache.Put("ns", 5, value)
+
+	var got string
+	if cache.Get("ns", 0, &got) {
+		t.Error("oldest entry should have been evicted")
+	}
+	if !cache.Get("ns", 5, &got) || got != value {
+		t.Error("newest entry should be kept")
+	}
+	files, _ := filepath.Glob(filepath.Join(cache.dir, "ns", "*.json"))
+	if len(files) > 2 {
+		t.Errorf("%d entries kept, want at most 2 within 600 bytes", len(files))
+	}
+
+	var onDisk int64
+	for _, f := range files {
+		info, _ := os.Stat(f)
+		onDisk += info.Size()
+	}
+	if cache.size != onDisk {
+		t.Errorf("tracked size %d, want %d on disk", cache.size, onDisk)
+	}
+
+	cache.Clear("ns")
+	if cache.Get("ns", 5, &got) {
+		t.Error("Clear should remove the namespace")
+	}
+
+	var disabled *ToolCache
+	disabled.Put("ns", 1, value)
+	if disabled.Get("ns", 1, &got) {
+		t.Error("a nil cache should never hit")
+	}
+}
+
+// TestToolCacheKnowledgeInvalidation verifies persisted knowledge searches are dropped after ingest
+func TestToolCacheKnowledgeInvalidation(t *testing.T) {
+	store := useFakeKnowledgeStore(t)
+	useToolCache(t)
+	ctx := context.Background()
+
+	path := writeTestFile(t, t.TempDir(), "notes.txt",
+		"The staging cluster is rebuilt every Sunday night by the platform team, so deploys pause until Monday morning.")
+	IngestDocumentFunc(ctx, IngestDocumentParams{FilePath: path})
+
+	search := func() string {
+		out, _ := KnowledgeToolFunc(ctx, KnowledgeToolParams{Query: "staging cluster"})
+		return out
+	}
+	search()
+	search()
+	if store.searches != 1 {
+		t.Fatalf("store searched %d times, want 1", store.searches)
+	}
+
+	IngestDocumentFunc(ctx, IngestDocumentParams{FilePath: writeTestFile(t, t.TempDir(), "more.txt",
+		fmt.Sprintf("%s Monday deploys start at nine.", strings.Repeat("The staging cluster rebuild finishes overnight. ", 3)))})
+	search()
+	if store.searches != 2 {
+		t.Errorf("ingest should invalidate persisted searches, store searched %d times", store.searches)
+	}
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+)
+
+// DefaultToolTimeout bounds a tool call when no per-tool override applies
+const DefaultToolTimeout = 2 * time.Minute
+
+// DefaultToolTimeouts are per-tool budgets for tools that are slow by design.
+// Tools with their own timeout parameter get their maximum plus a margin.
+var DefaultToolTimeouts = map[string]time.Duration{
+	BashToolName:            time.Duration(MaxTimeoutMs)*time.Millisecond + 30*time.Second,
+	FetchToolName:           5 * time.Minute,
+	IngestDocumentToolName:  10 * time.Minute,
+	IngestURLToolName:       10 * time.Minute,
+	IngestDirectoryToolName: 30 * time.Minute,
+}
+
+// ToolTimeoutFromEnv reads TOOL_TIMEOUT (Go duration, "0" disables)
+func ToolTimeoutFromEnv() time.Duration {
+	if val := os.Getenv("TOOL_TIMEOUT"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return DefaultToolTimeout
+}
+
+// ToolTimeouts 是工具超时中间件：每次调用在带截止时间的 ctx 中执行，超时后
+// 立即返回超时错误结果，不再等待工具返回。overrides 按工具名覆盖默认时长；
+// 时长 <= 0 表示不限时。
+//
+// 超时的调用被分离：其 ctx 随即取消，但不响应 ctx 的工具仍可能在后台完成。
+// 因此有副作用的工具（见 IsMutatingTool）不受默认时长限制，只有在 overrides
+// 中显式配置时才限时（如 bash 自带的命令超时）。工具 panic 会转为错误返回。
+func ToolTimeouts(timeout time.Duration, overrides map[string]time.Duration) compose.ToolMiddleware {
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
+				d := timeout
+				if IsMutatingTool(in.Name) {
+					d = 0
+				}
+				if o, ok := overrides[in.Name]; ok {
+					d = o
+				}
+				if d <= 0 {
+					return next(ctx, in)
+				}
+
+				callCtx, cancel := context.WithTimeout(ctx, d)
+				defer cancel()
+
+				type result struct {
+					output *compose.ToolOutput
+					err    error
+				}
+				// Buffered so the tool goroutine can finish after a timeout
+				done := make(chan result, 1)
+				go func() {
+					// The tool runs outside the ToolsNode's own panic handling
+					defer func() {
+						if p := recover(); p != nil {
+							done <- result{err: fmt.Errorf("tool %s panicked: %v", in.Name, p)}
+						}
+					}()
+					output, err := next(callCtx, in)
+					done <- result{output, err}
+				}()
+
+				select {
+				case r := <-done:
+					return r.output, r.err
+				case <-callCtx.Done():
+					// Cancellation of the whole run is not a tool timeout
+					if ctx.Err() != nil {
+						return nil, ctx.Err()
+					}
+					return &compose.ToolOutput{Result: timeoutResult(in.Name, d)}, nil
+				}
+			}
+		},
+	}
+}
+
+// timeoutResult is the error result of a tool call that ran out of time
+func timeoutResult(name string, d time.Duration) string {
+	return (&ToolResult{
+		Status:   StatusError,
+		Content:  fmt.Sprintf("%s timed out after %s; try a narrower request", name, d),
+		Metadata: &Metadata{Timeout: true},
+		Tier:     TierCompact,
+	}).String()
+}
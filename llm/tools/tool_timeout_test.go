@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+)
+
+// blockingEndpoint waits for ctx to end, or returns immediately for fast calls
+func blockingEndpoint(ctx context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
+	if in.Arguments == "fast" {
+		return &compose.ToolOutput{Result: "done"}, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestToolTimeouts verifies slow calls return a timeout result and overrides apply per tool
+func TestToolTimeouts(t *testing.T) {
+	mw := ToolTimeouts(20*time.Millisecond, map[string]time.Duration{"ingest": time.Minute})
+	endpoint := mw.Invokable(blockingEndpoint)
+	ctx := context.Background()
+
+	out, err := endpoint(ctx, &compose.ToolInput{Name: "grep"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.Result, "ERROR") || !strings.Contains(out.Result, "grep timed out after 20ms") {
+		t.Errorf("expected a timeout result, got:\n%s", out.Result)
+	}
+
+	if out, _ := endpoint(ctx, &compose.ToolInput{Name: "grep", Arguments: "fast"}); out.Result != "done" {
+		t.Errorf("fast calls should pass through, got %q", out.Result)
+	}
+
+	// An override longer than the wait lets the run's cancellation through instead
+	runCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := endpoint(runCtx, &compose.ToolInput{Name: "ingest"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ingest should run past the default timeout until the run ends, err = %v", err)
+	}
+}
+
+// TestToolTimeoutsPassesErrors verifies tool errors such as interrupts are returned unchanged
+func TestToolTimeoutsPassesErrors(t *testing.T) {
+	interrupt := errors.New("interrupt signal")
+	endpoint := ToolTimeouts(time.Minute, nil).Invokable(func(context.Context, *compose.ToolInput) (*compose.ToolOutput, error) {
+		return nil, interrupt
+	})
+	if _, err := endpoint(context.Background(), &compose.ToolInput{Name: "delete_file"}); err != interrupt {
+		t.Errorf("err = %v, want the tool's error", err)
+	}
+}
+
+// TestToolTimeoutsRecoversPanic verifies a panicking tool returns an error instead of crashing
+func TestToolTimeoutsRecoversPanic(t *testing.T) {
+	endpoint := ToolTimeouts(time.Minute, nil).Invokable(func(context.Context, *compose.ToolInput) (*compose.ToolOutput, error) {
+		panic("boom")
+	})
+	_, err := endpoint(context.Background(), &compose.ToolInput{Name: "grep"})
+	if err == nil || !strings.Contains(err.Error(), "grep panicked: boom") {
+		t.Errorf("err = %v, want the recovered panic", err)
+	}
+}
+
+// TestToolTimeoutsSkipsMutatingTools verifies tools with side effects are not
+// cut off by the default timeout unless configured explicitly
+func TestToolTimeoutsSkipsMutatingTools(t *testing.T) {
+	mw := ToolTimeouts(20*time.Millisecond, map[string]time.Duration{MoveToolName: 20 * time.Millisecond})
+	endpoint := mw.Invokable(blockingEndpoint)
+
+	runCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := endpoint(runCtx, &compose.ToolInput{Name: WriteToolName}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("write should run until the run ends, err = %v", err)
+	}
+
+	out, err := endpoint(context.Background(), &compose.ToolInput{Name: MoveToolName})
+	if err != nil || !strings.Contains(out.Result, "move timed out") {
+		t.Errorf("explicit override should apply, got %v, %v", out, err)
+	}
+}
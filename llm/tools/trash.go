@@ -0,0 +1,220 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// TrashDirName is the workspace directory soft-deleted files are moved to
+const TrashDirName = ".trash"
+
+// trashManifestName is the manifest file inside the trash directory
+const trashManifestName = "manifest.json"
+
+// TrashEntry records a soft-deleted file
+type TrashEntry struct {
+	ID           string    `json:"id"`            // File name inside the trash directory
+	OriginalPath string    `json:"original_path"` // Absolute path the file was deleted from
+	DeletedAt    time.Time `json:"deleted_at"`
+	Size         int64     `json:"size"`
+}
+
+// trashMu serializes manifest reads and writes
+var trashMu sync.Mutex
+
+// SoftDeleteFromEnv reports whether delete moves files to the trash by default
+// (FILE_DELETE_SOFT, default true). The model can still choose per call.
+func SoftDeleteFromEnv() bool {
+	if val := os.Getenv("FILE_DELETE_SOFT"); val != "" {
+		return val == "true" || val == "1"
+	}
+	return true
+}
+
+// trashDir returns the trash directory of the session workspace
+func trashDir(ctx context.Context) string {
+	return resolvePath(ctx, TrashDirName)
+}
+
+// moveToTrash moves path into the trash directory under a timestamped name
+// and records it in the manifest.
+func moveToTrash(ctx context.Context, path string, info os.FileInfo) (TrashEntry, error) {
+	dir := trashDir(ctx)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return TrashEntry{}, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	absPath, _ := filepath.Abs(path)
+	now := time.Now()
+	entry := TrashEntry{
+		ID:           now.Format("20060102-150405.000000") + "_" + filepath.Base(path),
+		OriginalPath: absPath,
+		DeletedAt:    now,
+		Size:         info.Size(),
+	}
+
+	trashMu.Lock()
+	defer trashMu.Unlock()
+
+	entries, err := readTrashManifest(dir)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	if err := renameFile(path, filepath.Join(dir, entry.ID), info.Mode()); err != nil {
+		return TrashEntry{}, err
+	}
+	if err := writeTrashManifest(dir, append(entries, entry)); err != nil {
+		return TrashEntry{}, err
+	}
+	return entry, nil
+}
+
+// restoreFromTrash moves a trashed file back to its original path. id selects the
+// entry; when empty the most recent entry for originalPath is restored.
+func restoreFromTrash(ctx context.Context, id, originalPath string) (TrashEntry, error) {
+	dir := trashDir(ctx)
+
+	trashMu.Lock()
+	defer trashMu.Unlock()
+
+	entries, err := readTrashManifest(dir)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	idx := matchTrashEntry(entries, id, originalPath)
+	if idx == -1 {
+		return TrashEntry{}, errors.New("no matching file in trash")
+	}
+
+	entry := entries[idx]
+	if _, err := os.Stat(entry.OriginalPath); err == nil {
+		return TrashEntry{}, fmt.Errorf("%s already exists, move it away before restoring", entry.OriginalPath)
+	}
+	src := filepath.Join(dir, entry.ID)
+	info, err := os.Stat(src)
+	if err != nil {
+		return TrashEntry{}, fmt.Errorf("trashed file is missing: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.OriginalPath), 0755); err != nil {
+		return TrashEntry{}, fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := renameFile(src, entry.OriginalPath, info.Mode()); err != nil {
+		return TrashEntry{}, err
+	}
+
+	entries = append(entries[:idx], entries[idx+1:]...)
+	if err := writeTrashManifest(dir, entries); err != nil {
+		return TrashEntry{}, err
+	}
+	return entry, nil
+}
+
+// findTrashEntry returns the entry restoreFromTrash would restore, without restoring it
+func findTrashEntry(ctx context.Context, id, originalPath string) (TrashEntry, error) {
+	entries, err := listTrash(ctx)
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	idx := matchTrashEntry(entries, id, originalPath)
+	if idx == -1 {
+		return TrashEntry{}, errors.New("no matching file in trash")
+	}
+	return entries[idx], nil
+}
+
+// matchTrashEntry returns the index of the entry with id, or when id is empty the
+// most recent entry deleted from originalPath; -1 when none matches
+func matchTrashEntry(entries []TrashEntry, id, originalPath string) int {
+	if id != "" {
+		for i, e := range entries {
+			if e.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	abs, _ := filepath.Abs(originalPath)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].OriginalPath == abs {
+			return i
+		}
+	}
+	return -1
+}
+
+// listTrash returns the manifest entries of the session trash
+func listTrash(ctx context.Context) ([]TrashEntry, error) {
+	trashMu.Lock()
+	defer trashMu.Unlock()
+	return readTrashManifest(trashDir(ctx))
+}
+
+// readTrashManifest reads the manifest; a missing manifest means an empty trash
+func readTrashManifest(dir string) ([]TrashEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, trashManifestName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash manifest: %w", err)
+	}
+	var entries []TrashEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse trash manifest: %w", err)
+	}
+	return entries, nil
+}
+
+// writeTrashManifest replaces the manifest atomically
+func writeTrashManifest(dir string, entries []TrashEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, trashManifestName+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write trash manifest: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, trashManifestName))
+}
+
+// renameFile renames src to dst, copying and deleting across filesystems
+func renameFile(src, dst string, mode os.FileMode) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("failed to move file: %w", err)
+	}
+	if _, err := copyFile(src, dst, mode); err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	if err := os.Remove(src); err != nil {
+		return fmt.Errorf("copied file but failed to remove source: %w", err)
+	}
+	return nil
+}
+
+// formatTrashList renders trash entries for the model, newest first
+func formatTrashList(entries []TrashEntry) string {
+	if len(entries) == 0 {
+		return "Trash is empty"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d file(s) in trash:\n", len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		fmt.Fprintf(&sb, "%s  %s  (deleted %s, %d bytes)\n",
+			e.ID, e.OriginalPath, e.DeletedAt.Format("2006-01-02 15:04:05"), e.Size)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestTruncateOutputNote verifies bash output keeps head and tail around a marked note
+func TestTruncateOutputNote(t *testing.T) {
+	short := "all output fits"
+	if out, omitted := truncateOutput(short); out != short || omitted != 0 {
+		t.Errorf("short output changed: %q (omitted %d)", out, omitted)
+	}
+
+	long := strings.Repeat("h", MaxOutputLength) + strings.Repeat("t", 1000)
+	out, omitted := truncateOutput(long)
+	if omitted != 1000 {
+		t.Errorf("omitted = %d, want 1000", omitted)
+	}
+	if !strings.Contains(out, TruncationMarker+": 1000 bytes omitted") {
+		t.Errorf("missing truncation note:\n%s", out[MaxOutputLength/2-10:MaxOutputLength/2+200])
+	}
+	if !strings.HasPrefix(out, "hhh") || !strings.HasSuffix(out, "ttt") {
+		t.Error("truncated output should keep the head and tail")
+	}
+}
+
+// TestFetchTruncationMarker verifies oversized responses are cut, noted and flagged
+func TestFetchTruncationMarker(t *testing.T) {
+	size := int(MaxReadSize) + 500
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	t.Cleanup(srv.Close)
+
+	out, _ := FetchToolFunc(context.Background(), FetchToolParams{URL: srv.URL, Raw: true})
+	if !strings.Contains(out, TruncationMarker+": 500 bytes omitted") {
+		t.Errorf("expected truncation note with omitted bytes:\n%s", out[len(out)-300:])
+	}
+	if !strings.Contains(out, "✂️ truncated") {
+		t.Errorf("metadata line should flag truncation:\n%s", out[len(out)-300:])
+	}
+
+	exact := newPageServer(t, int(MaxReadSize))
+	out, _ = FetchToolFunc(context.Background(), FetchToolParams{URL: exact.URL, Raw: true})
+	if strings.Contains(out, TruncationMarker) {
+		t.Error("a response of exactly MaxReadSize bytes is not truncated")
+	}
+}
+
+// TestListingTruncationMarker verifies glob and grep note when results are capped
+func TestListingTruncationMarker(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		writeTestFile(t, dir, fmt.Sprintf("f%d.go", i), "package main\nfunc a() {}\nfunc b() {}\n")
+	}
+	ctx := context.Background()
+
+	out, _ := GlobToolFunc(ctx, GlobToolParams{Pattern: "*.go", Path: dir, MaxResults: 2})
+	if !strings.Contains(out, TruncationMarker+": showing first 2 of 5 matches]") || !strings.Contains(out, "✂️ truncated") {
+		t.Errorf("glob should mark capped results:\n%s", out)
+	}
+	checkOutputSchema(t, GlobToolName, out)
+
+	out, _ = GrepToolFunc(ctx, GrepToolParams{Pattern: "func", Dir: dir, MaxMatches: 3})
+	if !strings.Contains(out, TruncationMarker+": showing first 3 matches") {
+		t.Errorf("grep should mark capped results:\n%s", out)
+	}
+	checkOutputSchema(t, GrepToolName, out)
+
+	out, _ = GlobToolFunc(ctx, GlobToolParams{Pattern: "*.go", Path: dir})
+	if strings.Contains(out, TruncationMarker) || strings.Contains(out, "truncated") {
+		t.Errorf("complete results should not be marked:\n%s", out)
+	}
+}
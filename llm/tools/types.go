@@ -16,6 +16,8 @@ const (
 	StatusSuccess ResultStatus = "success"
 	StatusError   ResultStatus = "error"
 	StatusPartial ResultStatus = "partial"
+	// StatusSimulated marks results of read-only mode: nothing was changed
+	StatusSimulated ResultStatus = "simulated"
 )
 
 // DisplayTier 展示层级（控制UI显示详细程度）
@@ -30,9 +32,17 @@ const (
 // Metadata contains structured metadata about tool execution
 type Metadata struct {
 	// File operations
-	FilePath  string `json:"file_path,omitempty"`
-	LineCount int    `json:"line_count,omitempty"`
-	ByteCount int    `json:"byte_count,omitempty"`
+	FilePath    string `json:"file_path,omitempty"`
+	LineCount   int    `json:"line_count,omitempty"`
+	ByteCount   int    `json:"byte_count,omitempty"`
+	Replaced    int    `json:"replaced,omitempty"`     // edit_file 替换次数
+	SoftDeleted bool   `json:"soft_deleted,omitempty"` // 文件移入回收站而非永久删除
+	TrashID     string `json:"trash_id,omitempty"`     // 回收站中的文件名，用于恢复
+
+	// File info
+	Mode    string `json:"mode,omitempty"`     // 权限位，如 -rw-r--r--
+	ModTime string `json:"mod_time,omitempty"` // 修改时间，RFC 3339
+	IsDir   bool   `json:"is_dir,omitempty"`
 
 	// Bash execution
 	Command  string `json:"command,omitempty"`
@@ -46,8 +56,44 @@ type Metadata struct {
 	Pattern    string `json:"pattern,omitempty"`
 
 	// Network
-	URL        string `json:"url,omitempty"`
-	StatusCode int    `json:"status_code,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	StatusCode int      `json:"status_code,omitempty"`
+	Sources    []string `json:"sources,omitempty"` // 结果引用的 URL 或知识库来源
+
+	// Tables
+	RowCount    int `json:"row_count,omitempty"`
+	ColumnCount int `json:"column_count,omitempty"`
+
+	// Read-only mode
+	Simulated bool `json:"simulated,omitempty"` // 只读模式下未实际执行，内容描述预期的改动
+
+	// Truncation
+	Truncated    bool `json:"truncated,omitempty"`     // 输出被截断，内容只是一部分
+	OmittedBytes int  `json:"omitted_bytes,omitempty"` // 被省略的字节数，未知时为 0
+}
+
+// TruncationMarker starts the note every truncating tool adds to its content
+const TruncationMarker = "[TRUNCATED"
+
+// truncationNote formats the standard truncation note. omitted is the number
+// of bytes left out (0 when unknown); detail says what part is shown.
+func truncationNote(omitted int, detail string) string {
+	if omitted > 0 {
+		return fmt.Sprintf("%s: %d bytes omitted; %s]", TruncationMarker, omitted, detail)
+	}
+	return fmt.Sprintf("%s: %s]", TruncationMarker, detail)
+}
+
+// Prefixes that mark error and partial results in the text the model sees
+const (
+	errorPrefix   = "❌ ERROR: "
+	partialPrefix = "⚠️  PARTIAL: "
+)
+
+// isCompleteResult reports whether a formatted tool result is neither an
+// error nor a partial result
+func isCompleteResult(result string) bool {
+	return !strings.HasPrefix(result, errorPrefix) && !strings.HasPrefix(result, partialPrefix)
 }
 
 // ToolResult represents a structured tool response
@@ -64,9 +110,11 @@ func (r *ToolResult) String() string {
 
 	// Status indicator
 	if r.Status == StatusError {
-		sb.WriteString("❌ ERROR: ")
+		sb.WriteString(errorPrefix)
 	} else if r.Status == StatusPartial {
-		sb.WriteString("⚠️  PARTIAL: ")
+		sb.WriteString(partialPrefix)
+	} else if r.Status == StatusSimulated {
+		sb.WriteString("🧪 SIMULATED: ")
 	}
 
 	// Content
@@ -92,12 +140,24 @@ func (r *ToolResult) formatLLMMetadata() string {
 	if md.LineCount > 0 {
 		parts = append(parts, fmt.Sprintf("%d lines", md.LineCount))
 	}
+	if md.SoftDeleted {
+		parts = append(parts, fmt.Sprintf("🗑️ trash id %s", md.TrashID))
+	}
+	if md.Replaced > 0 {
+		parts = append(parts, fmt.Sprintf("✏️ %d replaced", md.Replaced))
+	}
 	if md.MatchCount > 0 {
 		parts = append(parts, fmt.Sprintf("🔍 %d matches", md.MatchCount))
 	}
 	if md.Command != "" {
 		parts = append(parts, fmt.Sprintf("⚡ %s", md.Command))
 	}
+	if md.Truncated {
+		parts = append(parts, "✂️ truncated")
+	}
+	if md.Simulated {
+		parts = append(parts, "🔒 read-only, nothing changed")
+	}
 
 	if len(parts) == 0 {
 		return ""
@@ -138,6 +198,20 @@ func Partial(content string, metadata *Metadata) (string, error) {
 	}).String(), nil
 }
 
+// Simulated creates a read-only mode result describing a change that was not made
+func Simulated(content string, metadata *Metadata) (string, error) {
+	if metadata == nil {
+		metadata = &Metadata{}
+	}
+	metadata.Simulated = true
+	return (&ToolResult{
+		Status:   StatusSimulated,
+		Content:  content,
+		Metadata: metadata,
+		Tier:     TierFull,
+	}).String(), nil
+}
+
 // ReadFileSuccess 文件读取成功（最小化显示）
 func ReadFileSuccess(content, filePath string, lineCount, byteCount int) (string, error) {
 	return Success(content, &Metadata{
@@ -147,36 +221,44 @@ func ReadFileSuccess(content, filePath string, lineCount, byteCount int) (string
 	}, TierMinimal)
 }
 
-// GrepSuccess grep搜索成功（最小化显示）
-func GrepSuccess(content string, pattern string, matchCount, fileCount int) (string, error) {
+// GrepSuccess grep搜索成功（最小化显示），matchCount 为全部匹配数，
+// omittedBytes > 0 表示输出超出大小上限被截断
+func GrepSuccess(content string, pattern string, matchCount, fileCount int, truncated bool, omittedBytes int) (string, error) {
 	return Success(content, &Metadata{
-		Pattern:    pattern,
-		MatchCount: matchCount,
-		FileCount:  fileCount,
+		Pattern:      pattern,
+		MatchCount:   matchCount,
+		FileCount:    fileCount,
+		Truncated:    truncated,
+		OmittedBytes: omittedBytes,
 	}, TierMinimal)
 }
 
 // GlobSuccess 文件匹配成功（最小化显示）
-func GlobSuccess(content string, fileCount int) (string, error) {
+func GlobSuccess(content string, fileCount int, truncated bool) (string, error) {
 	return Success(content, &Metadata{
 		FileCount: fileCount,
+		Truncated: truncated,
 	}, TierMinimal)
 }
 
-// BashSuccess bash执行成功（紧凑显示）
-func BashSuccess(content, command string, duration int64, exitCode int) (string, error) {
+// BashSuccess bash执行成功（紧凑显示），omittedBytes > 0 表示输出被截断
+func BashSuccess(content, command string, duration int64, exitCode, omittedBytes int) (string, error) {
 	return Success(content, &Metadata{
-		Command:  command,
-		Duration: duration,
-		ExitCode: exitCode,
+		Command:      command,
+		Duration:     duration,
+		ExitCode:     exitCode,
+		Truncated:    omittedBytes > 0,
+		OmittedBytes: omittedBytes,
 	}, TierCompact)
 }
 
 // FetchSuccess 网页获取成功（紧凑显示）
-func FetchSuccess(content, url string, statusCode int) (string, error) {
+func FetchSuccess(content, url string, statusCode int, truncated bool, omittedBytes int) (string, error) {
 	return Success(content, &Metadata{
-		URL:        url,
-		StatusCode: statusCode,
+		URL:          url,
+		StatusCode:   statusCode,
+		Truncated:    truncated,
+		OmittedBytes: omittedBytes,
 	}, TierCompact)
 }
 
@@ -190,11 +272,26 @@ func WriteFileSuccess(filePath string, byteCount int) (string, error) {
 }
 
 // EditFileSuccess 文件编辑成功（完整显示）
-func EditFileSuccess(filePath string, lineCount int) (string, error) {
-	content := fmt.Sprintf("File edited: %s", filePath)
+func EditFileSuccess(filePath string, lineCount, replaced int, diff string) (string, error) {
+	content := fmt.Sprintf("File edited: %s (%d replaced)", filePath, replaced)
+	if diff != "" {
+		content += "\n\n" + diff
+	}
 	return Success(content, &Metadata{
 		FilePath:  filePath,
 		LineCount: lineCount,
+		Replaced:  replaced,
+	}, TierFull)
+}
+
+// EditFilePreview 文件编辑预览（dry run，未写入）
+func EditFilePreview(filePath string, replaced int, diff string) (string, error) {
+	content := fmt.Sprintf("Dry run, file not modified: %s (%d would be replaced)", filePath, replaced)
+	if diff != "" {
+		content += "\n\n" + diff
+	}
+	return Success(content, &Metadata{
+		FilePath: filePath,
 	}, TierFull)
 }
 
@@ -206,34 +303,91 @@ func DeleteFileSuccess(filePath string) (string, error) {
 	}, TierFull)
 }
 
-// ErrorHandler 是工具错误处理中间件
+// SoftDeleteFileSuccess 文件移入回收站（完整显示）
+func SoftDeleteFileSuccess(filePath, trashID string) (string, error) {
+	content := fmt.Sprintf("File deleted: %s (moved to %s, restore id %s)", filePath, TrashDirName, trashID)
+	return Success(content, &Metadata{
+		FilePath:    filePath,
+		SoftDeleted: true,
+		TrashID:     trashID,
+	}, TierFull)
+}
+
+// RestoreFileSuccess 文件从回收站恢复（完整显示）
+func RestoreFileSuccess(filePath string, byteCount int64) (string, error) {
+	content := fmt.Sprintf("File restored: %s", filePath)
+	return Success(content, &Metadata{
+		FilePath:  filePath,
+		ByteCount: int(byteCount),
+	}, TierFull)
+}
+
+// MoveFileSuccess 文件移动成功（完整显示）
+func MoveFileSuccess(source, destination string, byteCount int) (string, error) {
+	content := fmt.Sprintf("File moved: %s -> %s", source, destination)
+	return Success(content, &Metadata{
+		FilePath:  destination,
+		ByteCount: byteCount,
+	}, TierFull)
+}
+
+// MakeDirSuccess 目录创建成功（完整显示），created 为 false 表示目录已存在
+func MakeDirSuccess(path string, created bool) (string, error) {
+	content := fmt.Sprintf("Directory created: %s", path)
+	if !created {
+		content = fmt.Sprintf("Directory already exists: %s", path)
+	}
+	return Success(content, &Metadata{
+		FilePath: path,
+		IsDir:    true,
+	}, TierFull)
+}
+
+// CopyFileSuccess 文件复制成功（完整显示）
+func CopyFileSuccess(source, destination string, byteCount int) (string, error) {
+	content := fmt.Sprintf("File copied: %s -> %s (%d bytes)", source, destination, byteCount)
+	return Success(content, &Metadata{
+		FilePath:  destination,
+		ByteCount: byteCount,
+	}, TierFull)
+}
+
+// ErrorHandler 是工具错误处理中间件：中断信号（如等待用户批准）原样返回，
+// 其他错误转换为结构化的错误结果交给模型，而不是中止整个运行
 func ErrorHandler() compose.ToolMiddleware {
 	return compose.ToolMiddleware{
 		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
 			return func(ctx context.Context, in *compose.ToolInput) (*compose.ToolOutput, error) {
 				output, err := next(ctx, in)
-				if err != nil {
-					errStr := err.Error()
-					// 跳过中断信号（正常流程）
-					if strings.Contains(errStr, "interrupt signal") {
-						return nil, err
-					}
-
-					// 处理普通错误：提取核心错误信息
-					if idx := strings.Index(errStr, "err="); idx != -1 {
-						coreErr := strings.TrimSpace(errStr[idx+4:])
-						return &compose.ToolOutput{
-							Result: fmt.Sprintf("Error: %s", coreErr),
-						}, nil
-					}
-
-					// 默认错误处理
-					return &compose.ToolOutput{
-						Result: fmt.Sprintf("Error: %s", errStr),
-					}, nil
+				if err == nil {
+					return output, nil
 				}
-				return output, nil
+				// 跳过中断信号（正常流程）
+				if isInterruptError(err) {
+					return nil, err
+				}
+				result, _ := Error(toolErrorMessage(err))
+				return &compose.ToolOutput{Result: result}, nil
 			}
 		},
 	}
 }
+
+// isInterruptError reports whether err is an interrupt that must reach the
+// runner, including signals flattened into another error's message
+func isInterruptError(err error) bool {
+	if _, ok := compose.IsInterruptRerunError(err); ok {
+		return true
+	}
+	return strings.Contains(err.Error(), "interrupt signal")
+}
+
+// toolErrorMessage extracts the core message of a tool error, dropping the
+// framework's "... err=" prefix
+func toolErrorMessage(err error) string {
+	msg := err.Error()
+	if idx := strings.Index(msg, "err="); idx != -1 {
+		return strings.TrimSpace(msg[idx+4:])
+	}
+	return msg
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+)
+
+// TestErrorHandlerConvertsErrors verifies tool errors become structured error results
+func TestErrorHandlerConvertsErrors(t *testing.T) {
+	endpoint := ErrorHandler().Invokable(func(context.Context, *compose.ToolInput) (*compose.ToolOutput, error) {
+		return nil, errors.New("[LocalFunc] failed to invoke tool, toolName=grep, err=pattern too long")
+	})
+	out, err := endpoint(context.Background(), &compose.ToolInput{Name: "grep"})
+	if err != nil {
+		t.Fatalf("error should be converted, got %v", err)
+	}
+	if !strings.HasPrefix(out.Result, "❌ ERROR: pattern too long") {
+		t.Errorf("unexpected result: %q", out.Result)
+	}
+}
+
+// TestErrorHandlerPassesInterrupts verifies approval interrupts reach the runner unchanged
+func TestErrorHandlerPassesInterrupts(t *testing.T) {
+	ctx := context.Background()
+	signal := tool.Interrupt(ctx, "needs approval")
+	for name, interrupt := range map[string]error{
+		"signal":    signal,
+		"wrapped":   fmt.Errorf("invoke tool: %w", signal),
+		"flattened": fmt.Errorf("invoke tool: %v", signal),
+	} {
+		endpoint := ErrorHandler().Invokable(func(context.Context, *compose.ToolInput) (*compose.ToolOutput, error) {
+			return nil, interrupt
+		})
+		if out, err := endpoint(ctx, &compose.ToolInput{Name: "delete_file"}); err != interrupt || out != nil {
+			t.Errorf("%s: interrupt should pass through, got %v, %v", name, out, err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// maxRedirects is the maximum number of redirects followed by guarded clients
+const maxRedirects = 10
+
+var (
+	// allowPrivateNetworks permits requests to loopback/private addresses (FETCH_ALLOW_PRIVATE_NETWORKS=true)
+	allowPrivateNetworks = os.Getenv("FETCH_ALLOW_PRIVATE_NETWORKS") == "true"
+	// blockedDomains is a comma separated deny list of hosts (FETCH_BLOCKED_DOMAINS)
+	blockedDomains = parseDomainList(os.Getenv("FETCH_BLOCKED_DOMAINS"))
+)
+
+// parseDomainList splits a comma separated domain list
+func parseDomainList(s string) []string {
+	var domains []string
+	for _, d := range strings.Split(s, ",") {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// matchDomain reports whether host equals pattern or is a subdomain of it.
+// A leading "*." in the pattern is accepted and matches subdomains only.
+func matchDomain(host, pattern string) bool {
+	host = strings.ToLower(host)
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+// validateURLTarget checks scheme, blocked domains and that the host does not
+// resolve to a loopback, private or link-local address (SSRF protection).
+func validateURLTarget(ctx context.Context, u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL must start with http:// or https://")
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("URL has no host")
+	}
+
+	for _, d := range blockedDomains {
+		if matchDomain(host, d) {
+			return fmt.Errorf("domain %s is blocked", host)
+		}
+	}
+
+	if allowPrivateNetworks {
+		return nil
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return fmt.Errorf("failed to resolve host %s: %v", host, err)
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+			ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+			return fmt.Errorf("access to private network address %s is not allowed", ip)
+		}
+	}
+
+	return nil
+}
+
+// checkRedirectTarget is an http.Client CheckRedirect that applies
+// validateURLTarget to every hop, so a redirect cannot reach a blocked target
+func checkRedirectTarget(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return validateURLTarget(req.Context(), req.URL)
+}
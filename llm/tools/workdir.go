@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// workDirKey is the context key for the session working directory
+type workDirKey struct{}
+
+// WithWorkDir returns a context whose file and bash tools resolve relative
+// paths against dir instead of the process working directory.
+func WithWorkDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, workDirKey{}, dir)
+}
+
+// WorkDirFromContext returns the session working directory, or "" if unset
+func WorkDirFromContext(ctx context.Context) string {
+	dir, _ := ctx.Value(workDirKey{}).(string)
+	return dir
+}
+
+// resolvePath joins relative paths with the session working directory
+func resolvePath(ctx context.Context, path string) string {
+	dir := WorkDirFromContext(ctx)
+	if dir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// NewSessionWorkDir creates an isolated temporary working directory for a session
+func NewSessionWorkDir() (string, error) {
+	dir, err := os.MkdirTemp("", "compass-session-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create session directory: %w", err)
+	}
+	return dir, nil
+}
+
+// RemoveSessionWorkDir deletes a session working directory and its contents
+func RemoveSessionWorkDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	return os.RemoveAll(dir)
+}
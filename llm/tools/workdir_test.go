@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSessionWorkDirIsolation verifies two sessions write relative paths into separate directories
+func TestSessionWorkDirIsolation(t *testing.T) {
+	dirA, err := NewSessionWorkDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirB, err := NewSessionWorkDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirA == dirB {
+		t.Fatal("sessions share the same working directory")
+	}
+
+	ctxA := WithWorkDir(context.Background(), dirA)
+	ctxB := WithWorkDir(context.Background(), dirB)
+
+	WriteFileFunc(ctxA, WriteFileParams{Path: "scratch.txt", Content: "session A"})
+	WriteFileFunc(ctxB, WriteFileParams{Path: "scratch.txt", Content: "session B"})
+
+	for dir, want := range map[string]string{dirA: "session A", dirB: "session B"} {
+		data, err := os.ReadFile(filepath.Join(dir, "scratch.txt"))
+		if err != nil {
+			t.Fatalf("scratch file missing in %s: %v", dir, err)
+		}
+		if string(data) != want {
+			t.Errorf("expected %q in %s, got %q", want, dir, data)
+		}
+	}
+
+	if err := RemoveSessionWorkDir(dirA); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveSessionWorkDir(dirB); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{dirA, dirB} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("session directory %s was not removed", dir)
+		}
+	}
+}
+
+// TestResolvePathAbsolute verifies absolute paths bypass the session directory
+func TestResolvePathAbsolute(t *testing.T) {
+	ctx := WithWorkDir(context.Background(), "/tmp/session")
+	abs := filepath.Join(t.TempDir(), "file.txt")
+	if got := resolvePath(ctx, abs); got != abs {
+		t.Errorf("expected %s, got %s", abs, got)
+	}
+	if got := resolvePath(context.Background(), "file.txt"); got != "file.txt" {
+		t.Errorf("expected path unchanged without session, got %s", got)
+	}
+}
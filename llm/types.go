@@ -11,6 +11,11 @@ type Document struct {
 	Vector     []float32              `json:"vector,omitempty"`
 	Metadata   map[string]interface{} `json:"metadata"`
 	CreatedAt  string                 `json:"created_at"`
+	// EmbeddingText overrides Content as the text sent to the embedding model,
+	// e.g. content prefixed with document context; Content stays for display
+	EmbeddingText string `json:"embedding_text,omitempty"`
+	// ContentHash is the hex sha256 of Content, used to skip duplicate chunks
+	ContentHash string `json:"content_hash,omitempty"`
 }
 
 // SearchResult represents a search result with relevance score
@@ -25,4 +30,6 @@ type ListFilter struct {
 	FileType string // Filter by file type (pdf, docx, md, txt, html)
 	Limit    int    // Maximum number of results
 	Offset   int    // Offset for pagination
+
+	ContentHashes []string // Filter by any of these content hashes
 }
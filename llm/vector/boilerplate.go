@@ -0,0 +1,161 @@
+package vector
+
+import (
+	"bufio"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultBoilerplatePatterns match common web page boilerplate lines such as
+// cookie notices and newsletter calls to action
+var DefaultBoilerplatePatterns = []string{
+	`(?i)\b(we|this (site|website)) uses? cookies\b`,
+	`(?i)\baccept (all )?cookies\b`,
+	`(?i)\bcookie (policy|settings|preferences)\b`,
+	`(?i)^\W*subscribe( now| today)?\W*$`,
+	`(?i)\b(sign up|subscribe) (for|to) (our|the) newsletter\b`,
+	`(?i)\ball rights reserved\b`,
+	`(?i)^\W*(share on|follow us on) (twitter|facebook|linkedin|x)\b`,
+	`(?i)^\W*(skip to (main )?content|back to top)\W*$`,
+}
+
+// BoilerplateConfig configures boilerplate removal applied before chunking
+type BoilerplateConfig struct {
+	Enabled         bool             // Whether boilerplate removal runs at all
+	Patterns        []*regexp.Regexp // Lines matching any pattern are removed
+	MaxRepeats      int              // Lines occurring more often than this in a document are removed (0 disables)
+	MinRepeatLength int              // Shorter lines are never treated as repeated boilerplate
+}
+
+// DefaultBoilerplateConfig returns the boilerplate configuration from environment.
+// BOILERPLATE_PATTERNS_FILE replaces the default patterns with one regexp per
+// line of the file (blank lines and lines starting with # are ignored).
+func DefaultBoilerplateConfig() BoilerplateConfig {
+	patterns := DefaultBoilerplatePatterns
+	if path := os.Getenv("BOILERPLATE_PATTERNS_FILE"); path != "" {
+		loaded, err := loadBoilerplatePatterns(path)
+		if err != nil {
+			slog.Warn("failed to load boilerplate patterns, using defaults", "path", path, "err", err)
+		} else {
+			patterns = loaded
+		}
+	}
+
+	return BoilerplateConfig{
+		Enabled:         os.Getenv("BOILERPLATE_FILTER") != "false",
+		Patterns:        compileBoilerplatePatterns(patterns),
+		MaxRepeats:      getEnvInt("BOILERPLATE_MAX_REPEATS", 2),
+		MinRepeatLength: getEnvInt("BOILERPLATE_MIN_REPEAT_LENGTH", 20),
+	}
+}
+
+// loadBoilerplatePatterns reads one pattern per line from a file
+func loadBoilerplatePatterns(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// compileBoilerplatePatterns compiles patterns, skipping invalid ones
+func compileBoilerplatePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			slog.Warn("skipping invalid boilerplate pattern", "pattern", p, "err", err)
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled
+}
+
+// RemoveBoilerplate strips lines matching a boilerplate pattern and lines
+// repeated more than MaxRepeats times within the document. Lines inside
+// fenced code blocks are kept. Returns the cleaned content and the number
+// of removed lines.
+func RemoveBoilerplate(content string, config BoilerplateConfig) (string, int) {
+	if !config.Enabled {
+		return content, 0
+	}
+
+	lines := strings.Split(content, "\n")
+	inCode := make([]bool, len(lines))
+	counts := make(map[string]int)
+	fenced := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+			inCode[i] = true
+			continue
+		}
+		inCode[i] = fenced
+		if !fenced && len(trimmed) >= config.MinRepeatLength {
+			counts[trimmed]++
+		}
+	}
+
+	kept := make([]string, 0, len(lines))
+	removed := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !inCode[i] && trimmed != "" && isBoilerplateLine(trimmed, counts[trimmed], config) {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if removed == 0 {
+		return content, 0
+	}
+	return collapseBlankLines(strings.Join(kept, "\n")), removed
+}
+
+// isBoilerplateLine reports whether a trimmed line is boilerplate
+func isBoilerplateLine(line string, occurrences int, config BoilerplateConfig) bool {
+	if config.MaxRepeats > 0 && occurrences > config.MaxRepeats {
+		return true
+	}
+	for _, re := range config.Patterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// collapseBlankLines reduces runs of blank lines left by removed lines to a
+// single paragraph break
+func collapseBlankLines(content string) string {
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	blank := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			blank++
+			if blank > 1 {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
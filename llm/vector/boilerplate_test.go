@@ -0,0 +1,74 @@
+package vector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRemoveBoilerplate verifies repeated and pattern-matched lines are stripped while unique content stays
+func TestRemoveBoilerplate(t *testing.T) {
+	banner := "Get the latest release notes delivered weekly"
+	var sb strings.Builder
+	sb.WriteString("We use cookies to improve your experience.\n\n")
+	for i := 0; i < 4; i++ {
+		sb.WriteString("## Section " + string(rune('A'+i)) + "\n\n")
+		sb.WriteString("Unique paragraph number " + string(rune('A'+i)) + " explains a distinct part of the API.\n\n")
+		sb.WriteString(banner + "\n\n")
+	}
+	sb.WriteString("```\n" + banner + "\n" + banner + "\n" + banner + "\n```\n\n")
+	sb.WriteString("Subscribe\n\nShort\n\nShort\n\nShort\n")
+
+	cfg := BoilerplateConfig{
+		Enabled:         true,
+		Patterns:        compileBoilerplatePatterns(DefaultBoilerplatePatterns),
+		MaxRepeats:      2,
+		MinRepeatLength: 20,
+	}
+	out, removed := RemoveBoilerplate(sb.String(), cfg)
+
+	// 4 repeated banners, the cookie notice and the subscribe prompt
+	if removed != 6 {
+		t.Errorf("removed = %d, want 6:\n%s", removed, out)
+	}
+	for _, unwanted := range []string{"We use cookies", "\nSubscribe\n"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output should not contain %q:\n%s", unwanted, out)
+		}
+	}
+	if n := strings.Count(out, banner); n != 3 {
+		t.Errorf("banner inside the code block should be kept, found %d copies", n)
+	}
+	for _, want := range []string{"Unique paragraph number A", "Unique paragraph number D", "## Section C", "Short"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\n\n\n") {
+		t.Error("blank lines left by removed lines should be collapsed")
+	}
+
+	cfg.Enabled = false
+	if out, removed := RemoveBoilerplate(sb.String(), cfg); removed != 0 || out != sb.String() {
+		t.Error("disabled filter should leave content unchanged")
+	}
+}
+
+// TestBoilerplatePatternsFile verifies BOILERPLATE_PATTERNS_FILE replaces the default patterns
+func TestBoilerplatePatternsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "patterns.txt")
+	if err := os.WriteFile(path, []byte("# custom patterns\n\n(?i)^advertisement$\n[invalid\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BOILERPLATE_PATTERNS_FILE", path)
+
+	cfg := DefaultBoilerplateConfig()
+	if len(cfg.Patterns) != 1 {
+		t.Fatalf("expected 1 valid pattern, got %d", len(cfg.Patterns))
+	}
+	out, removed := RemoveBoilerplate("Advertisement\nWe use cookies here.\nBody text", cfg)
+	if removed != 1 || strings.Contains(out, "Advertisement") || !strings.Contains(out, "We use cookies") {
+		t.Errorf("custom patterns not applied: removed=%d\n%s", removed, out)
+	}
+}
@@ -13,6 +13,8 @@ type ChunkConfig struct {
 	ChunkOverlap     int  // Overlap between chunks
 	MinChunkSize     int  // Minimum chunk size to keep
 	SplitByParagraph bool // Whether to prioritize paragraph splitting
+	ContextPrefix    bool // Whether to prefix embedded text with title and nearest heading
+	ChunkByHeading   bool // Whether to keep each # / ## markdown section together
 }
 
 // DefaultChunkConfig returns the default chunk configuration
@@ -22,6 +24,8 @@ func DefaultChunkConfig() ChunkConfig {
 		ChunkOverlap:     getEnvInt("CHUNK_OVERLAP", 200),
 		MinChunkSize:     getEnvInt("MIN_CHUNK_SIZE", 100),
 		SplitByParagraph: true,
+		ContextPrefix:    os.Getenv("CHUNK_CONTEXT_PREFIX") == "true",
+		ChunkByHeading:   os.Getenv("CHUNK_BY_HEADING") == "true",
 	}
 }
 
@@ -40,6 +44,7 @@ func getEnvInt(key string, defaultVal int) int {
 type Chunk struct {
 	Content    string
 	ChunkIndex int
+	Heading    string // Nearest markdown heading at or before the chunk
 }
 
 // ChunkDocument splits a document into chunks based on the configuration
@@ -60,6 +65,13 @@ func ChunkDocument(content string, config ChunkConfig) []Chunk {
 		return []Chunk{}
 	}
 
+	// Heading mode keeps sections whole, so short sections are not filtered
+	if config.ChunkByHeading {
+		chunks := splitByHeading(content, config)
+		renumberChunks(chunks)
+		return chunks
+	}
+
 	var chunks []Chunk
 
 	if config.SplitByParagraph {
@@ -79,14 +91,184 @@ func ChunkDocument(content string, config ChunkConfig) []Chunk {
 		}
 	}
 
-	// Re-index chunks
-	for i := range filteredChunks {
-		filteredChunks[i].ChunkIndex = i
-	}
+	// Splitting and filtering leave gaps or repeats; this pass is authoritative
+	renumberChunks(filteredChunks)
+
+	annotateHeadings(content, filteredChunks)
 
 	return filteredChunks
 }
 
+// renumberChunks sets each chunk's ChunkIndex to its position in chunks
+func renumberChunks(chunks []Chunk) {
+	for i := range chunks {
+		chunks[i].ChunkIndex = i
+	}
+}
+
+// annotateHeadings sets each chunk's Heading to the last markdown heading
+// in content that precedes the chunk's first body (non-heading) line.
+func annotateHeadings(content string, chunks []Chunk) {
+	pos := 0
+	for i := range chunks {
+		line := firstBodyLine(chunks[i].Content)
+		if line == "" {
+			continue
+		}
+		idx := strings.Index(content[pos:], line)
+		if idx == -1 {
+			continue
+		}
+		chunks[i].Heading = lastHeading(content[:pos+idx])
+		pos += idx
+	}
+}
+
+// firstBodyLine returns the first non-empty line that is not a heading
+func firstBodyLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// lastHeading returns the text of the last markdown heading line in text
+func lastHeading(text string) string {
+	lines := strings.Split(text, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			if heading != "" {
+				return heading
+			}
+		}
+	}
+	return ""
+}
+
+// ContextualText prefixes chunk content with its document context, e.g.
+// "From: API Reference > Authentication\n\n<chunk>".
+func ContextualText(title, heading, content string) string {
+	var parts []string
+	if title != "" {
+		parts = append(parts, title)
+	}
+	if heading != "" && heading != title {
+		parts = append(parts, heading)
+	}
+	if len(parts) == 0 {
+		return content
+	}
+	return "From: " + strings.Join(parts, " > ") + "\n\n" + content
+}
+
+// markdownSection is a run of content under one # or ## heading
+type markdownSection struct {
+	level int      // Heading level (1 or 2), 0 for content before the first heading
+	trail []string // Heading texts from the enclosing # heading down to this one
+	body  string   // Section content without its heading line
+}
+
+// splitMarkdownSections splits content at # and ## headings outside fenced
+// code blocks. Deeper headings stay inside their section.
+func splitMarkdownSections(content string) []markdownSection {
+	var sections []markdownSection
+	current := markdownSection{}
+	var body []string
+	var h1 string
+	fenced := false
+
+	flush := func() {
+		current.body = strings.TrimSpace(strings.Join(body, "\n"))
+		sections = append(sections, current)
+		body = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+		}
+		level, text := 0, ""
+		if !fenced {
+			level, text = markdownHeading(trimmed)
+		}
+		if level == 0 || level > 2 {
+			body = append(body, line)
+			continue
+		}
+
+		flush()
+		if level == 1 {
+			h1 = text
+			current = markdownSection{level: 1, trail: []string{text}}
+		} else {
+			var trail []string
+			if h1 != "" {
+				trail = append(trail, h1)
+			}
+			current = markdownSection{level: 2, trail: append(trail, text)}
+		}
+	}
+	flush()
+	return sections
+}
+
+// markdownHeading returns the level and text of an ATX heading line, or 0
+func markdownHeading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(line[level:])
+}
+
+// splitByHeading chunks markdown one # / ## section at a time. Each chunk
+// starts with its heading trail (e.g. "## Guide > Install") so it carries its
+// context; sections larger than the chunk size are split by paragraph.
+func splitByHeading(content string, config ChunkConfig) []Chunk {
+	var chunks []Chunk
+	for _, section := range splitMarkdownSections(content) {
+		if section.body == "" {
+			// Heading-only sections live on in their subsections' trails
+			continue
+		}
+
+		heading := strings.Join(section.trail, " > ")
+		prefix := ""
+		if heading != "" {
+			prefix = strings.Repeat("#", section.level) + " " + heading + "\n\n"
+		}
+
+		pieces := []string{section.body}
+		if len(prefix)+len(section.body) > config.ChunkSize {
+			sub := config
+			sub.ChunkSize = max(config.ChunkSize-len(prefix), config.ChunkSize/2)
+			sub.MinChunkSize = 1
+			pieces = pieces[:0]
+			for _, c := range splitByParagraph(section.body, sub) {
+				pieces = append(pieces, strings.TrimSpace(c.Content))
+			}
+		}
+
+		for _, piece := range pieces {
+			chunks = append(chunks, Chunk{
+				Content:    prefix + piece,
+				ChunkIndex: len(chunks),
+				Heading:    heading,
+			})
+		}
+	}
+	return chunks
+}
+
 // splitByParagraph splits content by paragraph boundaries first
 func splitByParagraph(content string, config ChunkConfig) []Chunk {
 	var chunks []Chunk
@@ -274,16 +456,17 @@ func handleLargeChunks(chunks []Chunk, config ChunkConfig) []Chunk {
 
 	for _, chunk := range chunks {
 		if len(chunk.Content) <= config.ChunkSize {
+			chunk.ChunkIndex = len(result)
 			result = append(result, chunk)
 			continue
 		}
 
 		// Split large chunk
 		subChunks := forceSplit(chunk.Content, config.ChunkSize, config.ChunkOverlap)
-		for i, sc := range subChunks {
+		for _, sc := range subChunks {
 			result = append(result, Chunk{
 				Content:    sc,
-				ChunkIndex: chunk.ChunkIndex + i,
+				ChunkIndex: len(result),
 			})
 		}
 	}
@@ -291,7 +474,9 @@ func handleLargeChunks(chunks []Chunk, config ChunkConfig) []Chunk {
 	return result
 }
 
-// forceSplit splits text into fixed-size chunks
+// forceSplit splits text into fixed-size chunks, each window starting overlap
+// runes before the end of the previous one. Every window advances by at least
+// one rune, so an overlap as large as the size cannot stall the split.
 func forceSplit(text string, size, overlap int) []string {
 	var chunks []string
 
@@ -307,10 +492,15 @@ func forceSplit(text string, size, overlap int) []string {
 		chunk := string(runes[start:end])
 		chunks = append(chunks, chunk)
 
-		start = end - overlap
-		if start < 0 {
-			start = 0
+		// Stepping back by the overlap at the end would loop forever
+		if end == len(runes) {
+			break
+		}
+		next := end - overlap
+		if next <= start {
+			next = start + 1
 		}
+		start = next
 	}
 
 	return chunks
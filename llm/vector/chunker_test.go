@@ -0,0 +1,143 @@
+package vector
+
+import (
+	"strings"
+	"testing"
+
+	"compass/llm"
+)
+
+// TestChunkHeadings verifies chunks are annotated with their nearest heading
+func TestChunkHeadings(t *testing.T) {
+	content := "# API Reference\n\nIntro paragraph " + strings.Repeat("intro ", 30) +
+		"\n\n## Authentication\n\nTokens are sent " + strings.Repeat("auth ", 30) +
+		"\n\n## Rate Limits\n\nRequests are limited " + strings.Repeat("limit ", 30)
+
+	chunks := ChunkDocument(content, ChunkConfig{ChunkSize: 220, MinChunkSize: 10, SplitByParagraph: true})
+	if len(chunks) < 3 {
+		t.Fatalf("expected at least 3 chunks, got %d", len(chunks))
+	}
+
+	last := chunks[len(chunks)-1]
+	if last.Heading != "Rate Limits" {
+		t.Errorf("expected last chunk under 'Rate Limits', got %q", last.Heading)
+	}
+	for _, c := range chunks {
+		if strings.Contains(c.Content, "Tokens are sent") && c.Heading != "Authentication" {
+			t.Errorf("expected auth chunk under 'Authentication', got %q", c.Heading)
+		}
+	}
+}
+
+// TestEmbeddingTextsPrefersContext verifies the contextual text is embedded instead of display content
+func TestEmbeddingTextsPrefersContext(t *testing.T) {
+	prefixed := ContextualText("API Reference", "Authentication", "Tokens are sent in headers.")
+	if prefixed != "From: API Reference > Authentication\n\nTokens are sent in headers." {
+		t.Errorf("unexpected contextual text: %q", prefixed)
+	}
+
+	docs := []llm.Document{
+		{Content: "Tokens are sent in headers.", EmbeddingText: prefixed},
+		{Content: "plain chunk"},
+	}
+	texts := embeddingTexts(docs)
+	if texts[0] != prefixed || texts[1] != "plain chunk" {
+		t.Errorf("unexpected embedding texts: %q", texts)
+	}
+}
+
+// TestForceSplitTerminates verifies the final window ends the split despite the
+// overlap, and that an overlap as large as the size still advances
+func TestForceSplitTerminates(t *testing.T) {
+	chunks := forceSplit(strings.Repeat("x", 1000), 400, 200)
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d", len(chunks))
+	}
+	if last := chunks[len(chunks)-1]; len(last) != 400 {
+		t.Errorf("last chunk length = %d, want 400", len(last))
+	}
+
+	if chunks := forceSplit(strings.Repeat("x", 10), 4, 4); len(chunks) != 7 {
+		t.Errorf("overlap equal to size: expected 7 chunks, got %d", len(chunks))
+	}
+}
+
+// TestChunkByHeading verifies sections stay whole and carry their heading trail
+func TestChunkByHeading(t *testing.T) {
+	content := "Preamble before any heading.\n\n" +
+		"# Guide\n\nOverview of the guide.\n\n" +
+		"## Install\n\nRun the installer.\n\n### Options\n\nUse --prefix to relocate.\n\n" +
+		"```sh\n# not a heading\nmake install\n```\n\n" +
+		"## Configure\n\nEdit the config file."
+
+	chunks := ChunkDocument(content, ChunkConfig{ChunkSize: 1000, MinChunkSize: 100, ChunkByHeading: true})
+	want := []struct{ heading, content string }{
+		{"", "Preamble before any heading."},
+		{"Guide", "# Guide\n\nOverview of the guide."},
+		{"Guide > Install", "## Guide > Install\n\nRun the installer.\n\n### Options\n\nUse --prefix to relocate.\n\n```sh\n# not a heading\nmake install\n```"},
+		{"Guide > Configure", "## Guide > Configure\n\nEdit the config file."},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %d: %+v", len(want), len(chunks), chunks)
+	}
+	for i, w := range want {
+		if chunks[i].Heading != w.heading || chunks[i].Content != w.content || chunks[i].ChunkIndex != i {
+			t.Errorf("chunk %d = %+v, want heading %q content %q", i, chunks[i], w.heading, w.content)
+		}
+	}
+}
+
+// TestChunkByHeadingSplitsLargeSections verifies oversized sections are split with the trail on every piece
+func TestChunkByHeadingSplitsLargeSections(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("# Handbook\n\n## Deployment\n\n")
+	for i := 0; i < 10; i++ {
+		sb.WriteString("Step " + strings.Repeat("deploy ", 10) + "\n\n")
+	}
+	sb.WriteString("## Rollback\n\nRevert the release.")
+
+	chunks := ChunkDocument(sb.String(), ChunkConfig{ChunkSize: 200, MinChunkSize: 100, ChunkByHeading: true})
+	deploy := 0
+	for _, c := range chunks {
+		if c.Heading == "Handbook > Deployment" {
+			deploy++
+			if !strings.HasPrefix(c.Content, "## Handbook > Deployment\n\n") {
+				t.Errorf("piece missing heading trail: %q", c.Content)
+			}
+			if strings.Contains(c.Content, "Revert") {
+				t.Errorf("piece mixes sections: %q", c.Content)
+			}
+		}
+	}
+	if deploy < 2 {
+		t.Errorf("expected the deployment section to be split, got %d pieces", deploy)
+	}
+	if last := chunks[len(chunks)-1]; last.Content != "## Handbook > Rollback\n\nRevert the release." {
+		t.Errorf("short section should be kept whole, got %q", last.Content)
+	}
+}
+
+// TestChunkIndexesUniqueAfterLargeSplit verifies oversized paragraphs do not reuse later chunks' indexes
+func TestChunkIndexesUniqueAfterLargeSplit(t *testing.T) {
+	content := "Short opening paragraph " + strings.Repeat("intro ", 20) +
+		"\n\n" + strings.Repeat("huge ", 400) +
+		"\n\nClosing paragraph " + strings.Repeat("outro ", 20)
+
+	chunks := ChunkDocument(content, ChunkConfig{ChunkSize: 300, ChunkOverlap: 50, MinChunkSize: 10, SplitByParagraph: true})
+	if len(chunks) < 5 {
+		t.Fatalf("expected the huge paragraph to be split, got %d chunks", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.ChunkIndex != i {
+			t.Errorf("chunk %d has ChunkIndex %d", i, c.ChunkIndex)
+		}
+	}
+
+	// handleLargeChunks alone must not collide either
+	split := handleLargeChunks([]Chunk{{Content: strings.Repeat("x", 700), ChunkIndex: 0}, {Content: "tail", ChunkIndex: 1}}, ChunkConfig{ChunkSize: 300})
+	for i, c := range split {
+		if c.ChunkIndex != i {
+			t.Errorf("handleLargeChunks: chunk %d has ChunkIndex %d", i, c.ChunkIndex)
+		}
+	}
+}
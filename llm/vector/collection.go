@@ -0,0 +1,200 @@
+package vector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultCollection is the name of the collection backed by the base index
+const DefaultCollection = "default"
+
+// collectionNamePattern restricts collection names to safe key/index characters
+var collectionNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// CollectionStore is a VectorStore that can be partitioned into named
+// collections, e.g. "work-docs" and "personal-notes". The store itself
+// is the default collection.
+type CollectionStore interface {
+	VectorStore
+
+	// Collection returns the store scoped to the named collection,
+	// creating it if needed
+	Collection(ctx context.Context, name string) (VectorStore, error)
+
+	// ListCollections returns the names of all collections, including the default
+	ListCollections(ctx context.Context) ([]string, error)
+}
+
+// NormalizeCollection validates a collection name, mapping "" to DefaultCollection
+func NormalizeCollection(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DefaultCollection, nil
+	}
+	if !collectionNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid collection name %q: use lowercase letters, digits, '-' and '_' (max 64)", name)
+	}
+	return name, nil
+}
+
+// Collection returns the store scoped to the named collection. Each collection
+// has its own index and key prefix; the default collection is the store itself.
+func (s *RedisStore) Collection(ctx context.Context, name string) (VectorStore, error) {
+	name, err := NormalizeCollection(name)
+	if err != nil {
+		return nil, err
+	}
+	if name == DefaultCollection {
+		return s, nil
+	}
+	root := s.root()
+
+	root.mu.Lock()
+	if c, ok := root.collections[name]; ok {
+		root.mu.Unlock()
+		return c, nil
+	}
+	root.mu.Unlock()
+
+	c := &RedisStore{
+		client:       root.client,
+		embeddingSvc: root.embeddingSvc,
+		config: StoreConfig{
+			EmbeddingDim: root.config.EmbeddingDim,
+			IndexName:    root.config.IndexName + ":" + name,
+			// Must not share the "vec:" prefix, or the base index would cover these keys
+			KeyPrefix:          "vec-" + name + ":",
+			DefaultTTL:         root.config.DefaultTTL,
+			EmbeddingBatchSize: root.config.EmbeddingBatchSize,
+		},
+		efConstruction: root.efConstruction,
+		m:              root.m,
+		parent:         root,
+	}
+	if err := c.ensureIndex(ctx); err != nil {
+		return nil, fmt.Errorf("failed to create collection %s: %w", name, err)
+	}
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	if existing, ok := root.collections[name]; ok {
+		return existing, nil
+	}
+	if root.collections == nil {
+		root.collections = make(map[string]*RedisStore)
+	}
+	root.collections[name] = c
+	c.startVectorMigration()
+	return c, nil
+}
+
+// ListCollections returns the default collection plus every collection index
+func (s *RedisStore) ListCollections(ctx context.Context) ([]string, error) {
+	root := s.root()
+	result, err := root.client.Do(ctx, "FT._LIST").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	names := []string{DefaultCollection}
+	prefix := root.config.IndexName + ":"
+	values, _ := result.([]interface{})
+	for _, v := range values {
+		index, ok := v.(string)
+		if ok && strings.HasPrefix(index, prefix) {
+			names = append(names, strings.TrimPrefix(index, prefix))
+		}
+	}
+	sort.Strings(names[1:])
+	return names, nil
+}
+
+// root returns the store owning the connection
+func (s *RedisStore) root() *RedisStore {
+	if s.parent != nil {
+		return s.parent
+	}
+	return s
+}
+
+// collectionsDir returns the directory holding the collection files of a
+// LocalStore: store.json keeps its collections in store.collections/
+func (s *LocalStore) collectionsDir() string {
+	return strings.TrimSuffix(s.path, filepath.Ext(s.path)) + ".collections"
+}
+
+// Collection returns the store scoped to the named collection. Each collection
+// is a LocalStore with its own JSON file; the default collection is the store itself.
+func (s *LocalStore) Collection(ctx context.Context, name string) (VectorStore, error) {
+	name, err := NormalizeCollection(name)
+	if err != nil {
+		return nil, err
+	}
+	if name == DefaultCollection {
+		return s, nil
+	}
+	root := s.root()
+
+	root.collectionsMu.Lock()
+	defer root.collectionsMu.Unlock()
+	if c, ok := root.collections[name]; ok {
+		return c, nil
+	}
+
+	c := &LocalStore{
+		path:         filepath.Join(root.collectionsDir(), name+".json"),
+		embeddingSvc: root.embeddingSvc,
+		config:       root.config,
+		now:          root.now,
+		journal:      root.journal,
+		compactEvery: root.compactEvery,
+		parent:       root,
+	}
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("failed to load collection %s: %w", name, err)
+	}
+	// Write the file right away so the collection is listed before its first document
+	if _, err := os.Stat(c.path); errors.Is(err, os.ErrNotExist) {
+		if err := c.save(); err != nil {
+			return nil, fmt.Errorf("failed to create collection %s: %w", name, err)
+		}
+	}
+
+	if root.collections == nil {
+		root.collections = make(map[string]*LocalStore)
+	}
+	root.collections[name] = c
+	return c, nil
+}
+
+// ListCollections returns the default collection plus every collection file
+func (s *LocalStore) ListCollections(ctx context.Context) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.root().collectionsDir(), "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	names := []string{DefaultCollection}
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".json")
+		if name != DefaultCollection && collectionNamePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names, nil
+}
+
+// root returns the default store owning the collections
+func (s *LocalStore) root() *LocalStore {
+	if s.parent != nil {
+		return s.parent
+	}
+	return s
+}
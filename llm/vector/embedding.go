@@ -3,27 +3,139 @@ package vector
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"sync"
+	"time"
+
+	"compass/llm/providers"
 
 	"github.com/cloudwego/eino/components/embedding"
 )
 
-// EmbeddingService wraps an embedding model for vector generation
+// EmbeddingOptions configures retries and request batching of an EmbeddingService
+type EmbeddingOptions struct {
+	// MaxRetries is the number of extra attempts on retryable errors
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry; it doubles on every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+	// BatchSize is the maximum number of texts sent in one embedding request
+	BatchSize int
+}
+
+// DefaultEmbeddingOptions returns the settings used when the EMBEDDING_* retry
+// and batch variables are unset
+func DefaultEmbeddingOptions() EmbeddingOptions {
+	return EmbeddingOptions{
+		MaxRetries:     3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+		BatchSize:      64,
+	}
+}
+
+// EmbeddingOptionsFromEnv reads EMBEDDING_MAX_RETRIES, EMBEDDING_RETRY_BACKOFF
+// (Go duration) and EMBEDDING_BATCH_SIZE
+func EmbeddingOptionsFromEnv() EmbeddingOptions {
+	opts := DefaultEmbeddingOptions()
+	if n := getEnvInt("EMBEDDING_MAX_RETRIES", opts.MaxRetries); n >= 0 {
+		opts.MaxRetries = n
+	}
+	opts.InitialBackoff = getEnvDuration("EMBEDDING_RETRY_BACKOFF", opts.InitialBackoff)
+	if n := getEnvInt("EMBEDDING_BATCH_SIZE", opts.BatchSize); n > 0 {
+		opts.BatchSize = n
+	}
+	return opts
+}
+
+// EmbeddingService wraps an embedding model for vector generation. Transient
+// provider errors are retried with exponential backoff, and large batches are
+// split into requests of at most BatchSize texts.
 type EmbeddingService struct {
 	embedder embedding.Embedder
 	dim      int
+	opts     EmbeddingOptions
 	mu       sync.RWMutex
 }
 
-// NewEmbeddingService creates a new embedding service
+// NewEmbeddingService creates a new embedding service configured by
+// EmbeddingOptionsFromEnv
 func NewEmbeddingService(embedder embedding.Embedder, dim int) *EmbeddingService {
+	return NewEmbeddingServiceWithOptions(embedder, dim, EmbeddingOptionsFromEnv())
+}
+
+// NewEmbeddingServiceWithOptions creates a new embedding service with explicit
+// retry and batch settings
+func NewEmbeddingServiceWithOptions(embedder embedding.Embedder, dim int, opts EmbeddingOptions) *EmbeddingService {
 	if dim <= 0 {
 		dim = 1024 // Default dimension for many models
 	}
+	defaults := DefaultEmbeddingOptions()
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaults.MaxBackoff
+	}
 	return &EmbeddingService{
 		embedder: embedder,
 		dim:      dim,
+		opts:     opts,
+	}
+}
+
+// model returns the current embedding model
+func (s *EmbeddingService) model() embedding.Embedder {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.embedder
+}
+
+// SetEmbedder replaces the embedding model used for new embeddings
+func (s *EmbeddingService) SetEmbedder(embedder embedding.Embedder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.embedder = embedder
+}
+
+// BatchSize returns the maximum number of texts sent in one request
+func (s *EmbeddingService) BatchSize() int {
+	return s.opts.BatchSize
+}
+
+// embedStrings calls the embedding model, retrying retryable errors with backoff
+func (s *EmbeddingService) embedStrings(ctx context.Context, texts []string) ([][]float64, error) {
+	backoff := s.opts.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		vectors, err := s.model().EmbedStrings(ctx, texts)
+		if err == nil {
+			return vectors, nil
+		}
+		if attempt >= s.opts.MaxRetries || ctx.Err() != nil || !providers.IsRetryableError(err) {
+			return nil, err
+		}
+		slog.Warn("embedding request failed, retrying", "attempt", attempt+1, "texts", len(texts), "backoff", backoff, "err", err)
+		if err := sleepContext(ctx, backoff); err != nil {
+			return nil, err
+		}
+		backoff = min(backoff*2, s.opts.MaxBackoff)
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
 }
 
@@ -33,7 +145,7 @@ func (s *EmbeddingService) Embed(ctx context.Context, text string) ([]float32, e
 		return nil, fmt.Errorf("text cannot be empty")
 	}
 
-	vectors, err := s.embedder.EmbedStrings(ctx, []string{text})
+	vectors, err := s.embedStrings(ctx, []string{text})
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
@@ -51,7 +163,9 @@ func (s *EmbeddingService) Embed(ctx context.Context, text string) ([]float32, e
 	return result, nil
 }
 
-// EmbedBatch generates embedding vectors for multiple texts
+// EmbedBatch generates embedding vectors for multiple texts, sending at most
+// BatchSize texts per request. Empty texts are skipped and keep a nil vector
+// at their index.
 func (s *EmbeddingService) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	if len(texts) == 0 {
 		return nil, fmt.Errorf("texts cannot be empty")
@@ -71,17 +185,25 @@ func (s *EmbeddingService) EmbedBatch(ctx context.Context, texts []string) ([][]
 		return nil, fmt.Errorf("no valid texts to embed")
 	}
 
-	vectors, err := s.embedder.EmbedStrings(ctx, validTexts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
-	}
-
-	// Convert all vectors to float32
 	result := make([][]float32, len(texts))
-	for i, vec := range vectors {
-		result[indices[i]] = make([]float32, len(vec))
-		for j, v := range vec {
-			result[indices[i]][j] = float32(v)
+	for start := 0; start < len(validTexts); start += s.opts.BatchSize {
+		end := min(start+s.opts.BatchSize, len(validTexts))
+		vectors, err := s.embedStrings(ctx, validTexts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embeddings for texts %d-%d of %d: %w",
+				start+1, end, len(validTexts), err)
+		}
+		if len(vectors) != end-start {
+			return nil, fmt.Errorf("embedding model returned %d vectors for %d texts", len(vectors), end-start)
+		}
+
+		// Convert to float32 at the original positions
+		for i, vec := range vectors {
+			out := make([]float32, len(vec))
+			for j, v := range vec {
+				out[j] = float32(v)
+			}
+			result[indices[start+i]] = out
 		}
 	}
 
@@ -95,9 +217,34 @@ func (s *EmbeddingService) Dimension() int {
 	return s.dim
 }
 
-// GetEmbeddingDimFromEnv reads embedding dimension from environment variable
+// EmbedderSwapper is implemented by stores whose embedding model can be
+// replaced at runtime without re-creating the index
+type EmbedderSwapper interface {
+	// EmbeddingDim returns the vector dimension of the index
+	EmbeddingDim() int
+
+	// SetEmbedder replaces the embedding model used for documents and queries
+	SetEmbedder(embedder embedding.Embedder)
+}
+
+// ProbeDimension embeds a short text to find the vector dimension an
+// embedding model produces
+func ProbeDimension(ctx context.Context, embedder embedding.Embedder) (int, error) {
+	vectors, err := embedder.EmbedStrings(ctx, []string{"dimension probe"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to probe embedding dimension: %w", err)
+	}
+	if len(vectors) == 0 || len(vectors[0]) == 0 {
+		return 0, fmt.Errorf("empty embedding returned")
+	}
+	return len(vectors[0]), nil
+}
+
+// GetEmbeddingDimFromEnv reads the embedding dimension from VECTOR_DIM. It
+// returns 0 when unset, meaning the dimension is probed from the embedding
+// model, since it differs per provider (nomic-embed-text on Ollama is 768)
 func GetEmbeddingDimFromEnv() int {
-	dim := 1024 // Default
+	dim := 0
 	if val := os.Getenv("VECTOR_DIM"); val != "" {
 		if n, err := parseDim(val); err == nil && n > 0 {
 			dim = n
@@ -0,0 +1,96 @@
+package vector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// flakyEmbedder fails with the queued errors first, then embeds each text as
+// [len(text)], recording the size of every request
+type flakyEmbedder struct {
+	errs     []error
+	requests []int
+}
+
+func (e *flakyEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	e.requests = append(e.requests, len(texts))
+	if len(e.errs) > 0 {
+		err := e.errs[0]
+		e.errs = e.errs[1:]
+		return nil, err
+	}
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len(text))}
+	}
+	return vectors, nil
+}
+
+// TestEmbedBatchSubBatches verifies large batches are split and empty texts keep their index
+func TestEmbedBatchSubBatches(t *testing.T) {
+	emb := &flakyEmbedder{}
+	svc := NewEmbeddingServiceWithOptions(emb, 1, EmbeddingOptions{BatchSize: 2})
+
+	vectors, err := svc.EmbedBatch(context.Background(), []string{"a", "", "bb", "ccc", "dddd", "eeeee"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := emb.requests; len(got) != 3 || got[0] != 2 || got[1] != 2 || got[2] != 1 {
+		t.Errorf("request sizes = %v, want [2 2 1]", got)
+	}
+	if vectors[1] != nil {
+		t.Errorf("empty text should keep a nil vector, got %v", vectors[1])
+	}
+	for i, want := range map[int]float32{0: 1, 2: 2, 5: 5} {
+		if len(vectors[i]) != 1 || vectors[i][0] != want {
+			t.Errorf("vector %d = %v, want [%v]", i, vectors[i], want)
+		}
+	}
+}
+
+// TestEmbedRetries verifies transient errors are retried and permanent ones are not
+func TestEmbedRetries(t *testing.T) {
+	emb := &flakyEmbedder{errs: []error{errors.New("status 429: rate limit"), errors.New("503 service unavailable")}}
+	svc := NewEmbeddingServiceWithOptions(emb, 1, EmbeddingOptions{MaxRetries: 2})
+	if _, err := svc.Embed(context.Background(), "text"); err != nil {
+		t.Fatalf("transient errors should be retried: %v", err)
+	}
+	if len(emb.requests) != 3 {
+		t.Errorf("requests = %d, want 3", len(emb.requests))
+	}
+
+	emb = &flakyEmbedder{errs: []error{errors.New("429"), errors.New("429")}}
+	svc = NewEmbeddingServiceWithOptions(emb, 1, EmbeddingOptions{MaxRetries: 1})
+	if _, err := svc.EmbedBatch(context.Background(), []string{"a"}); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("expected the last error after retries, got %v", err)
+	}
+
+	emb = &flakyEmbedder{errs: []error{errors.New("invalid api key")}}
+	svc = NewEmbeddingServiceWithOptions(emb, 1, EmbeddingOptions{MaxRetries: 3})
+	if _, err := svc.Embed(context.Background(), "text"); err == nil || len(emb.requests) != 1 {
+		t.Errorf("permanent errors should fail without retrying: err=%v requests=%d", err, len(emb.requests))
+	}
+}
+
+// TestGetEmbeddingDimFromEnv verifies an unset or invalid VECTOR_DIM leaves the
+// dimension to be probed from the embedding model
+func TestGetEmbeddingDimFromEnv(t *testing.T) {
+	for val, want := range map[string]int{"": 0, "768": 768, "-1": 0, "invalid": 0} {
+		t.Setenv("VECTOR_DIM", val)
+		if got := GetEmbeddingDimFromEnv(); got != want {
+			t.Errorf("VECTOR_DIM=%q: got %d, want %d", val, got, want)
+		}
+	}
+}
+
+// TestProbeDimension verifies the probed dimension is the length of the returned vector
+func TestProbeDimension(t *testing.T) {
+	dim, err := ProbeDimension(context.Background(), &flakyEmbedder{})
+	if err != nil || dim != 1 {
+		t.Errorf("ProbeDimension = %d, %v; want 1", dim, err)
+	}
+}
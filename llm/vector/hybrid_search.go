@@ -0,0 +1,190 @@
+package vector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"compass/llm"
+)
+
+const (
+	// DefaultHybridAlpha weights vector similarity against the text score
+	DefaultHybridAlpha = 0.7
+
+	// hybridCandidateFactor widens each retriever's result set before fusion
+	hybridCandidateFactor = 3
+)
+
+// HybridSearch combines KNN vector similarity with a BM25 full-text score on
+// the content field. alpha weights the vector score (1 = pure vector,
+// 0 = pure text) and is clamped to [0, 1].
+func (s *RedisStore) HybridSearch(ctx context.Context, query string, topK int, alpha float64) ([]llm.SearchResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	if topK <= 0 {
+		topK = 5
+	}
+	if topK > 100 {
+		topK = 100
+	}
+	alpha = max(0, min(alpha, 1))
+
+	candidates := topK * hybridCandidateFactor
+	vectorResults, err := s.Search(ctx, query, candidates, llm.ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	textResults, err := s.textSearch(ctx, query, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	return fuseHybridResults(vectorResults, textResults, alpha, topK), nil
+}
+
+// textSearch runs a BM25 full-text query over the content field. Results
+// carry the raw BM25 score.
+func (s *RedisStore) textSearch(ctx context.Context, query string, limit int) ([]llm.SearchResult, error) {
+	textQuery := buildTextQuery(query)
+	if textQuery == "" {
+		return nil, nil
+	}
+
+	// FT.SEARCH cowork-knowledge "@content:(term1|term2)" WITHSCORES SCORER BM25
+	//   RETURN 6 content source file_type title chunk_index metadata
+	//   LIMIT 0 15
+	result, err := s.client.Do(ctx, "FT.SEARCH", s.config.IndexName, textQuery,
+		"WITHSCORES", "SCORER", "BM25",
+		"RETURN", "6", fieldContent, fieldSource, fieldFileType, fieldTitle, fieldChunkIndex, fieldMetadata,
+		"LIMIT", "0", strconv.Itoa(limit),
+		"DIALECT", "2",
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf("text search failed: %w", err)
+	}
+
+	return s.parseScoredResults(result)
+}
+
+// parseScoredResults parses FT.SEARCH WITHSCORES results, which hold
+// (id, score, fields) triples after the count
+func (s *RedisStore) parseScoredResults(result interface{}) ([]llm.SearchResult, error) {
+	values, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected result format")
+	}
+
+	var results []llm.SearchResult
+	for i := 1; i+2 < len(values); i += 3 {
+		docID, ok := values[i].(string)
+		if !ok {
+			continue
+		}
+		fields, ok := values[i+2].([]interface{})
+		if !ok {
+			continue
+		}
+
+		var score float64
+		switch val := values[i+1].(type) {
+		case string:
+			score, _ = strconv.ParseFloat(val, 64)
+		case float64:
+			score = val
+		}
+
+		doc, err := s.parseDocumentFields(docID, fields)
+		if err != nil {
+			continue
+		}
+		results = append(results, llm.SearchResult{Document: doc, Score: float32(score)})
+	}
+	return results, nil
+}
+
+// buildTextQuery turns a free-form query into a RediSearch OR query over the
+// content field, escaping punctuation so tokens like error codes stay intact
+func buildTextQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		var sb strings.Builder
+		hasWord := false
+		for _, r := range word {
+			switch {
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				hasWord = true
+				sb.WriteRune(r)
+			case r == '_':
+				sb.WriteRune(r)
+			default:
+				sb.WriteRune('\\')
+				sb.WriteRune(r)
+			}
+		}
+		// Skip terms made only of punctuation
+		if hasWord {
+			terms = append(terms, sb.String())
+		}
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("@%s:(%s)", fieldContent, strings.Join(terms, "|"))
+}
+
+// fuseHybridResults blends vector similarity and max-normalized text scores
+// per document as alpha*vector + (1-alpha)*text, returning the top k
+func fuseHybridResults(vectorResults, textResults []llm.SearchResult, alpha float64, topK int) []llm.SearchResult {
+	type fused struct {
+		doc   llm.Document
+		score float64
+	}
+	byID := make(map[string]*fused)
+	var order []string
+	add := func(doc llm.Document, score float64) {
+		f, ok := byID[doc.ID]
+		if !ok {
+			f = &fused{doc: doc}
+			byID[doc.ID] = f
+			order = append(order, doc.ID)
+		}
+		f.score += score
+	}
+
+	for _, r := range vectorResults {
+		similarity := max(0, min(float64(r.Score), 1))
+		add(r.Document, alpha*similarity)
+	}
+
+	var maxText float64
+	for _, r := range textResults {
+		maxText = max(maxText, float64(r.Score))
+	}
+	for _, r := range textResults {
+		var normalized float64
+		if maxText > 0 {
+			normalized = float64(r.Score) / maxText
+		}
+		add(r.Document, (1-alpha)*normalized)
+	}
+
+	// Stable sort keeps vector order for equal scores
+	sort.SliceStable(order, func(i, j int) bool {
+		return byID[order[i]].score > byID[order[j]].score
+	})
+	if len(order) > topK {
+		order = order[:topK]
+	}
+
+	results := make([]llm.SearchResult, len(order))
+	for i, id := range order {
+		results[i] = llm.SearchResult{Document: byID[id].doc, Score: float32(byID[id].score)}
+	}
+	return results
+}
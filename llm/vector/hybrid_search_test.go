@@ -0,0 +1,81 @@
+package vector
+
+import (
+	"math"
+	"testing"
+
+	"compass/llm"
+)
+
+// TestBuildTextQuery verifies free-form queries become escaped OR queries on content
+func TestBuildTextQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"parse_config error", "@content:(parse_config|error)"},
+		{"ERR-1042 timeout", `@content:(ERR\-1042|timeout)`},
+		{"what is v1.2?", `@content:(what|is|v1\.2\?)`},
+		{"-- ?", ""},
+	}
+	for _, tt := range tests {
+		if got := buildTextQuery(tt.query); got != tt.want {
+			t.Errorf("buildTextQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+// TestParseScoredResults verifies WITHSCORES triples are parsed with their text score
+func TestParseScoredResults(t *testing.T) {
+	raw := []interface{}{
+		int64(2),
+		"doc-a", "3.5", []interface{}{fieldContent, "ERR-1042 means timeout"},
+		"doc-b", "1.25", []interface{}{fieldContent, "timeout tuning"},
+	}
+	results, err := (&RedisStore{}).parseScoredResults(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Document.ID != "doc-a" || results[0].Score != 3.5 || results[1].Score != 1.25 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+// TestFuseHybridResults verifies scores are blended with alpha and re-ranked
+func TestFuseHybridResults(t *testing.T) {
+	doc := func(id string) llm.Document { return llm.Document{ID: id} }
+	vectorResults := []llm.SearchResult{
+		{Document: doc("semantic"), Score: 0.9},
+		{Document: doc("both"), Score: 0.6},
+		{Document: doc("weak"), Score: 0.2},
+	}
+	textResults := []llm.SearchResult{
+		{Document: doc("keyword"), Score: 8},
+		{Document: doc("both"), Score: 4},
+	}
+
+	tests := []struct {
+		alpha  float64
+		order  []string
+		scores []float64
+	}{
+		// both: 0.5*0.6 + 0.5*0.5 = 0.55, keyword: 0.5*1 = 0.5, semantic: 0.45
+		{alpha: 0.5, order: []string{"both", "keyword", "semantic"}, scores: []float64{0.55, 0.5, 0.45}},
+		{alpha: 1, order: []string{"semantic", "both", "weak"}, scores: []float64{0.9, 0.6, 0.2}},
+		{alpha: 0, order: []string{"keyword", "both", "semantic"}, scores: []float64{1, 0.5, 0}},
+	}
+	for _, tt := range tests {
+		results := fuseHybridResults(vectorResults, textResults, tt.alpha, 3)
+		if len(results) != len(tt.order) {
+			t.Fatalf("alpha %v: got %d results, want %d", tt.alpha, len(results), len(tt.order))
+		}
+		for i, r := range results {
+			if r.Document.ID != tt.order[i] {
+				t.Errorf("alpha %v: result %d = %s, want %s", tt.alpha, i, r.Document.ID, tt.order[i])
+			}
+			if math.Abs(float64(r.Score)-tt.scores[i]) > 1e-6 {
+				t.Errorf("alpha %v: %s score = %v, want %v", tt.alpha, r.Document.ID, r.Score, tt.scores[i])
+			}
+		}
+	}
+}
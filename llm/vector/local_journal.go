@@ -0,0 +1,160 @@
+package vector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// DefaultCompactEvery is the number of journal entries after which the
+// snapshot is rewritten when LocalConfig.CompactEvery is unset
+const DefaultCompactEvery = 1000
+
+// journalEntry is one line of the append-only journal: documents added or
+// replaced, and IDs removed. Replaying an entry twice has no further effect,
+// so a crash between writing the snapshot and removing the journal is safe.
+type journalEntry struct {
+	Put    []localDocument `json:"put,omitempty"`
+	Delete []string        `json:"delete,omitempty"`
+}
+
+// journalPath returns the journal file kept next to the snapshot
+func (s *LocalStore) journalPath() string {
+	return s.path + ".journal"
+}
+
+// apply applies a journal entry to the in-memory documents
+func (s *LocalStore) apply(entry journalEntry) {
+	for _, doc := range entry.Put {
+		if i := s.indexOf(doc.ID); i >= 0 {
+			s.docs[i] = doc
+		} else {
+			s.docs = append(s.docs, doc)
+		}
+	}
+	if len(entry.Delete) > 0 {
+		s.docs = slices.DeleteFunc(s.docs, func(d localDocument) bool {
+			return slices.Contains(entry.Delete, d.ID)
+		})
+	}
+}
+
+// replayJournal applies the journal on top of the loaded snapshot and returns
+// the number of entries replayed. A missing journal replays nothing. An
+// unreadable last line is a write cut short by a crash and is skipped.
+func (s *LocalStore) replayJournal() (int, error) {
+	data, err := os.ReadFile(s.journalPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	replayed := 0
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry journalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			if i == len(lines)-1 {
+				slog.Warn("skipping incomplete local store journal entry", "path", s.journalPath(), "err", err)
+				break
+			}
+			return 0, fmt.Errorf("corrupt journal entry %d: %w", i+1, err)
+		}
+		s.apply(entry)
+		replayed++
+	}
+	return replayed, nil
+}
+
+// persist records a change that is already applied in memory. Without the
+// journal the whole snapshot is rewritten; with it the entry is appended and
+// the snapshot is rewritten every CompactEvery entries. Callers hold mu.
+func (s *LocalStore) persist(entry journalEntry) error {
+	if !s.journal {
+		return s.save()
+	}
+	if err := s.appendJournal(entry); err != nil {
+		return err
+	}
+	s.journalEntries++
+	if s.journalEntries >= s.compactEvery {
+		return s.compact()
+	}
+	return nil
+}
+
+// appendJournal writes one entry to the journal and syncs it to disk
+func (s *LocalStore) appendJournal(entry journalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+	f, err := os.OpenFile(s.journalPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	w.Write(line)
+	w.WriteByte('\n')
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync journal: %w", err)
+	}
+	return f.Close()
+}
+
+// compact folds the journal into a fresh snapshot and removes it. Callers hold mu.
+func (s *LocalStore) compact() error {
+	if err := s.save(); err != nil {
+		return err
+	}
+	if err := os.Remove(s.journalPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove journal: %w", err)
+	}
+	s.journalEntries = 0
+	return nil
+}
+
+// writeFileAtomic writes data to a temp file in the target's directory and
+// renames it over path, so readers and crashes never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
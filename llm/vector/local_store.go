@@ -0,0 +1,560 @@
+package vector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"compass/llm"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// LocalStore implements VectorStore with a JSON file and brute-force cosine
+// search. It needs no external services and suits small knowledge bases.
+// The file is replaced atomically; with the journal enabled, changes are
+// appended to <path>.journal and folded into the file periodically.
+type LocalStore struct {
+	path         string
+	embeddingSvc *EmbeddingService
+	config       StoreConfig
+	mu           sync.RWMutex
+	docs         []localDocument // Insertion order, guarded by mu
+	now          func() time.Time
+
+	journal        bool // Append changes to the journal instead of rewriting the file
+	compactEvery   int  // Journal entries before the file is rewritten
+	journalEntries int  // Entries in the journal, guarded by mu
+
+	parent        *LocalStore            // Default store owning this collection, nil for the default store
+	collectionsMu sync.Mutex             // Guards collections
+	collections   map[string]*LocalStore // Opened collections of the default store
+}
+
+// LocalConfig holds configuration of the file-backed store
+type LocalConfig struct {
+	Path         string        // JSON file the documents are persisted to
+	VectorDim    int           // Embedding dimension, probe the embedder with ProbeDimension when unset
+	DefaultTTL   time.Duration // Expiry of added documents, 0 keeps them forever
+	Journal      bool          // Persist changes incrementally in an append-only journal
+	CompactEvery int           // Journal entries before compaction (default: DefaultCompactEvery)
+}
+
+// DefaultLocalStorePath is where the local store persists when LOCAL_STORE_PATH is unset
+const DefaultLocalStorePath = "./data/knowledge_store.json"
+
+// DefaultLocalConfig returns default local store configuration from environment
+func DefaultLocalConfig() LocalConfig {
+	return LocalConfig{
+		Path:         getEnvString("LOCAL_STORE_PATH", DefaultLocalStorePath),
+		VectorDim:    GetEmbeddingDimFromEnv(),
+		DefaultTTL:   getEnvDuration("VECTOR_DOCUMENT_TTL", 0),
+		Journal:      os.Getenv("LOCAL_STORE_JOURNAL") == "true",
+		CompactEvery: getEnvInt("LOCAL_STORE_COMPACT_EVERY", DefaultCompactEvery),
+	}
+}
+
+// localDocument is a stored document with its bookkeeping fields
+type localDocument struct {
+	llm.Document
+	UpdatedAt int64 `json:"updated_at,omitempty"`
+	ExpiresAt int64 `json:"expires_at,omitempty"` // Unix seconds, 0 never expires
+}
+
+// localStoreFile is the on-disk layout of a LocalStore
+type localStoreFile struct {
+	Documents []localDocument `json:"documents"`
+}
+
+// NewLocalStore creates a file-backed vector store, loading the documents
+// already saved at cfg.Path
+func NewLocalStore(embedder embedding.Embedder, cfg LocalConfig) (*LocalStore, error) {
+	if embedder == nil {
+		return nil, fmt.Errorf("embedding model is required")
+	}
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("store path is required")
+	}
+
+	compactEvery := cfg.CompactEvery
+	if compactEvery <= 0 {
+		compactEvery = DefaultCompactEvery
+	}
+
+	embeddingSvc := NewEmbeddingService(embedder, cfg.VectorDim)
+	store := &LocalStore{
+		path:         cfg.Path,
+		embeddingSvc: embeddingSvc,
+		config: StoreConfig{
+			EmbeddingDim:       embeddingSvc.Dimension(),
+			DefaultTTL:         cfg.DefaultTTL,
+			EmbeddingBatchSize: embeddingSvc.BatchSize(),
+		},
+		now:          time.Now,
+		journal:      cfg.Journal,
+		compactEvery: compactEvery,
+	}
+	if err := store.load(); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", cfg.Path, err)
+	}
+	return store, nil
+}
+
+// load reads the store file and replays the journal on top of it; a missing
+// file is an empty store. A journal left over with the journal disabled is
+// folded into the file right away.
+func (s *LocalStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		var file localStoreFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return err
+		}
+		s.docs = file.Documents
+	}
+
+	replayed, err := s.replayJournal()
+	if err != nil {
+		return err
+	}
+	s.journalEntries = replayed
+	if replayed > 0 && !s.journal {
+		return s.compact()
+	}
+	return nil
+}
+
+// save atomically writes all live documents to the store file. Callers hold mu.
+func (s *LocalStore) save() error {
+	s.docs = slices.DeleteFunc(s.docs, s.expired)
+	data, err := json.Marshal(localStoreFile{Documents: s.docs})
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	return nil
+}
+
+// expired reports whether a document's TTL has passed
+func (s *LocalStore) expired(doc localDocument) bool {
+	return doc.ExpiresAt > 0 && s.now().Unix() >= doc.ExpiresAt
+}
+
+// indexOf returns the position of the document with the given ID, or -1
+func (s *LocalStore) indexOf(id string) int {
+	return slices.IndexFunc(s.docs, func(d localDocument) bool { return d.ID == id })
+}
+
+// Add adds a single document to the store
+func (s *LocalStore) Add(ctx context.Context, doc llm.Document, opts ...AddOption) error {
+	return s.AddBatch(ctx, []llm.Document{doc}, opts...)
+}
+
+// AddBatch adds multiple documents and saves the store once. A document whose
+// ID is already stored replaces it.
+func (s *LocalStore) AddBatch(ctx context.Context, docs []llm.Document, opts ...AddOption) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	options := ResolveAddOptions(s.config, opts...)
+
+	vectors, err := embedDocuments(ctx, s.embeddingSvc, s.config.EmbeddingDim, docs)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry := journalEntry{Put: make([]localDocument, 0, len(docs))}
+	for i, doc := range docs {
+		if doc.ID == "" {
+			doc.ID = generateID(doc.Source, doc.ChunkIndex)
+		}
+		if doc.CreatedAt == "" {
+			doc.CreatedAt = now.Format(time.RFC3339)
+		}
+		if doc.ContentHash == "" {
+			doc.ContentHash = ContentHash(doc.Content)
+		}
+		doc.Vector = vectors[i]
+		doc.EmbeddingText = ""
+
+		stored := localDocument{Document: doc, UpdatedAt: now.Unix()}
+		if options.TTL > 0 {
+			stored.ExpiresAt = now.Add(options.TTL).Unix()
+		}
+		if j := s.indexOf(doc.ID); j >= 0 {
+			s.docs[j] = stored
+		} else {
+			s.docs = append(s.docs, stored)
+		}
+		entry.Put = append(entry.Put, stored)
+	}
+	return s.persist(entry)
+}
+
+// Search scores every document matching the filter by cosine similarity to
+// the query and returns the best topK. It stops with ctx's error when ctx is
+// cancelled while scoring.
+func (s *LocalStore) Search(ctx context.Context, query string, topK int, filter llm.ListFilter) ([]llm.SearchResult, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	if topK <= 0 {
+		topK = 5
+	}
+	if topK > 100 {
+		topK = 100
+	}
+
+	queryVector, err := s.embeddingSvc.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	scores, err := s.scoreDocuments(ctx, queryVector, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []int
+	for i, score := range scores {
+		if !math.IsNaN(float64(score)) {
+			matched = append(matched, i)
+		}
+	}
+	// Stable over ascending positions, so ties keep insertion order
+	sort.SliceStable(matched, func(a, b int) bool {
+		return scores[matched[a]] > scores[matched[b]]
+	})
+
+	results := make([]llm.SearchResult, min(topK, len(matched)))
+	for r := range results {
+		i := matched[r]
+		results[r] = llm.SearchResult{
+			Document: withoutVector(s.docs[i].Document),
+			Score:    scores[i],
+		}
+	}
+	return results, nil
+}
+
+const (
+	// localSearchParallelMin is the store size from which documents are
+	// scored by parallel workers
+	localSearchParallelMin = 2048
+	// localSearchCheckEvery is the number of documents scored between
+	// cancellation checks
+	localSearchCheckEvery = 256
+)
+
+// localSearchWorkers returns the size of the scoring worker pool
+var localSearchWorkers = func() int { return runtime.GOMAXPROCS(0) }
+
+// scoreDocuments returns the similarity of every document to the query
+// vector, or NaN for documents that are expired or excluded by the filter.
+// Large stores are split into contiguous ranges scored by a worker pool; each
+// position is written by exactly one worker, so results do not depend on
+// scheduling. Callers must hold s.mu.
+func (s *LocalStore) scoreDocuments(ctx context.Context, queryVector []float32, filter llm.ListFilter) ([]float32, error) {
+	scores := make([]float32, len(s.docs))
+	nan := float32(math.NaN())
+	scoreRange := func(lo, hi int) error {
+		for i := lo; i < hi; i++ {
+			if (i-lo)%localSearchCheckEvery == 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default:
+				}
+			}
+			doc := s.docs[i]
+			if s.expired(doc) || !matchesFilter(doc.Document, filter) {
+				scores[i] = nan
+				continue
+			}
+			scores[i] = cosineSimilarity(queryVector, doc.Vector)
+		}
+		return nil
+	}
+
+	workers := localSearchWorkers()
+	if len(s.docs) < localSearchParallelMin || workers < 2 {
+		if err := scoreRange(0, len(s.docs)); err != nil {
+			return nil, err
+		}
+		return scores, nil
+	}
+
+	size := (len(s.docs) + workers - 1) / workers
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		lo, hi := w*size, min((w+1)*size, len(s.docs))
+		if lo >= hi {
+			break
+		}
+		wg.Add(1)
+		go func(w, lo, hi int) {
+			defer wg.Done()
+			errs[w] = scoreRange(lo, hi)
+		}(w, lo, hi)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, ctx.Err()
+	}
+	return scores, nil
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0 when
+// their dimensions differ or either is a zero vector
+func cosineSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(normA) * math.Sqrt(normB)))
+}
+
+// matchesFilter reports whether doc matches the filter's source, file type
+// and content hashes
+func matchesFilter(doc llm.Document, filter llm.ListFilter) bool {
+	if filter.Source != "" && doc.Source != filter.Source {
+		return false
+	}
+	if filter.FileType != "" && doc.FileType != filter.FileType {
+		return false
+	}
+	if len(filter.ContentHashes) > 0 && !slices.Contains(filter.ContentHashes, doc.ContentHash) {
+		return false
+	}
+	return true
+}
+
+// withoutVector returns doc without its vector, as RedisStore returns documents
+func withoutVector(doc llm.Document) llm.Document {
+	doc.Vector = nil
+	return doc
+}
+
+// Update overwrites a stored document. The content is re-embedded only when
+// it changed; created_at is preserved.
+func (s *LocalStore) Update(ctx context.Context, doc llm.Document) error {
+	if doc.ID == "" {
+		return fmt.Errorf("document ID cannot be empty")
+	}
+
+	s.mu.RLock()
+	i := s.indexOf(doc.ID)
+	var old localDocument
+	if i >= 0 {
+		old = s.docs[i]
+	}
+	s.mu.RUnlock()
+	if i < 0 || s.expired(old) {
+		return fmt.Errorf("document not found: %s", doc.ID)
+	}
+
+	doc.Vector = old.Vector
+	if old.Content != doc.Content || doc.EmbeddingText != "" {
+		vectors, err := s.embeddingSvc.EmbedBatch(ctx, embeddingTexts([]llm.Document{doc}))
+		if err != nil {
+			return fmt.Errorf("failed to generate embedding: %w", err)
+		}
+		doc.Vector = vectors[0]
+	}
+	doc.CreatedAt = old.CreatedAt
+	doc.ContentHash = ContentHash(doc.Content)
+	doc.EmbeddingText = ""
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The document may have been deleted while embedding
+	if i = s.indexOf(doc.ID); i < 0 {
+		return fmt.Errorf("document not found: %s", doc.ID)
+	}
+	s.docs[i] = localDocument{Document: doc, UpdatedAt: s.now().Unix(), ExpiresAt: s.docs[i].ExpiresAt}
+	return s.persist(journalEntry{Put: []localDocument{s.docs[i]}})
+}
+
+// Delete removes a document by its ID
+func (s *LocalStore) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("document ID cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.indexOf(id)
+	if i < 0 {
+		return nil
+	}
+	s.docs = slices.Delete(s.docs, i, i+1)
+	return s.persist(journalEntry{Delete: []string{id}})
+}
+
+// DeleteBatch removes documents by ID with a single save and returns the
+// number that existed
+func (s *LocalStore) DeleteBatch(ctx context.Context, ids []string) (int, error) {
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return 0, fmt.Errorf("document ID cannot be empty")
+		}
+		remove[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleteWhere(func(d localDocument) bool { return remove[d.ID] })
+}
+
+// DeleteBySource removes all documents from a specific source file
+func (s *LocalStore) DeleteBySource(ctx context.Context, source string) (int, error) {
+	if source == "" {
+		return 0, fmt.Errorf("source cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleteWhere(func(d localDocument) bool { return d.Source == source })
+}
+
+// deleteWhere removes the matching documents and persists the change once.
+// The documents are restored if persisting fails. Callers must hold s.mu.
+func (s *LocalStore) deleteWhere(match func(localDocument) bool) (int, error) {
+	var ids []string
+	for _, d := range s.docs {
+		if match(d) {
+			ids = append(ids, d.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	before := slices.Clone(s.docs)
+	s.docs = slices.DeleteFunc(s.docs, match)
+	if err := s.persist(journalEntry{Delete: ids}); err != nil {
+		s.docs = before
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// List returns documents matching the filter criteria
+func (s *LocalStore) List(ctx context.Context, filter llm.ListFilter) ([]llm.Document, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+
+	docs := []llm.Document{}
+	err := s.ListAll(ctx, filter, func(doc llm.Document) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// ListAll calls fn for every document matching the filter in insertion order.
+// fn runs on a snapshot, so it may modify the store.
+func (s *LocalStore) ListAll(ctx context.Context, filter llm.ListFilter, fn func(llm.Document) error) error {
+	s.mu.RLock()
+	var matched []llm.Document
+	for _, doc := range s.docs {
+		if !s.expired(doc) && matchesFilter(doc.Document, filter) {
+			matched = append(matched, withoutVector(doc.Document))
+		}
+	}
+	s.mu.RUnlock()
+
+	offset := min(max(filter.Offset, 0), len(matched))
+	matched = matched[offset:]
+	if filter.Limit > 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+	for _, doc := range matched {
+		if err := fn(doc); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns the total number of documents in the store
+func (s *LocalStore) Count(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var count int64
+	for _, doc := range s.docs {
+		if !s.expired(doc) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// EmbeddingDim returns the vector dimension of the store
+func (s *LocalStore) EmbeddingDim() int {
+	return s.config.EmbeddingDim
+}
+
+// SetEmbedder replaces the embedding model used for documents and queries
+func (s *LocalStore) SetEmbedder(embedder embedding.Embedder) {
+	s.embeddingSvc.SetEmbedder(embedder)
+}
+
+// Close releases the store, folding a non-empty journal into the file.
+// Every change is already durable, so this only shortens the next load.
+// Closing the default store also closes its opened collections.
+func (s *LocalStore) Close() error {
+	var errs []error
+	s.collectionsMu.Lock()
+	for _, c := range s.collections {
+		errs = append(errs, c.Close())
+	}
+	s.collectionsMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journalEntries > 0 {
+		errs = append(errs, s.compact())
+	}
+	return errors.Join(errs...)
+}
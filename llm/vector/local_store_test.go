@@ -0,0 +1,313 @@
+package vector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"compass/llm"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// topicEmbedder embeds texts on two axes: mentions of "cat" and of "dog"
+type topicEmbedder struct{}
+
+func (topicEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{
+			float64(strings.Count(text, "cat")) + 0.1,
+			float64(strings.Count(text, "dog")) + 0.1,
+		}
+	}
+	return vectors, nil
+}
+
+// newTestLocalStore creates a LocalStore persisting to a temp file
+func newTestLocalStore(t *testing.T, path string) *LocalStore {
+	t.Helper()
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "store.json")
+	}
+	s, err := NewLocalStore(topicEmbedder{}, LocalConfig{Path: path, VectorDim: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// TestLocalStoreSearch verifies cosine ranking, filters and persistence across reopen
+func TestLocalStoreSearch(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "data", "store.json")
+	s := newTestLocalStore(t, path)
+
+	err := s.AddBatch(ctx, []llm.Document{
+		{ID: "a", Content: "the cat sat", Source: "cats.md", FileType: "md"},
+		{ID: "b", Content: "a dog barked", Source: "dogs.md", FileType: "md"},
+		{ID: "c", Content: "cat and cat again", Source: "cats.txt", FileType: "txt"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopen from disk
+	s = newTestLocalStore(t, path)
+	results, err := s.Search(ctx, "cat", 2, llm.ListFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Document.ID != "a" || results[1].Document.ID != "c" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[0].Document.Vector != nil {
+		t.Error("search results should not carry vectors")
+	}
+
+	results, _ = s.Search(ctx, "cat", 5, llm.ListFilter{FileType: "md"})
+	if len(results) != 2 || results[0].Document.ID != "a" {
+		t.Errorf("file type filter: %+v", results)
+	}
+
+	if n, _ := s.Count(ctx); n != 3 {
+		t.Errorf("count = %d, want 3", n)
+	}
+}
+
+// TestLocalStoreMutations verifies update, batch and source delete counts and paging
+func TestLocalStoreMutations(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStore(t, "")
+	for i, content := range []string{"cat one", "cat two", "dog three"} {
+		source := "cats.md"
+		if i == 2 {
+			source = "dogs.md"
+		}
+		if err := s.Add(ctx, llm.Document{Content: content, Source: source, ChunkIndex: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs, _ := s.List(ctx, llm.ListFilter{Source: "cats.md"})
+	if len(docs) != 2 || docs[0].Content != "cat one" || docs[0].ID == "" || docs[0].ContentHash == "" {
+		t.Fatalf("unexpected list: %+v", docs)
+	}
+	page, _ := s.List(ctx, llm.ListFilter{Offset: 1, Limit: 1})
+	if len(page) != 1 || page[0].Content != "cat two" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	doc := docs[0]
+	doc.Content = "dog dog dog"
+	if err := s.Update(ctx, doc); err != nil {
+		t.Fatal(err)
+	}
+	results, _ := s.Search(ctx, "dog", 1, llm.ListFilter{Source: "cats.md"})
+	if len(results) != 1 || results[0].Document.ID != doc.ID || results[0].Document.CreatedAt != doc.CreatedAt {
+		t.Errorf("update should re-embed and keep created_at: %+v", results)
+	}
+	if err := s.Update(ctx, llm.Document{ID: "missing"}); err == nil {
+		t.Error("updating a missing document should fail")
+	}
+
+	if n, err := s.DeleteBySource(ctx, "cats.md"); err != nil || n != 2 {
+		t.Fatalf("DeleteBySource = %d, %v; want 2", n, err)
+	}
+	if n, err := s.DeleteBySource(ctx, "cats.md"); err != nil || n != 0 {
+		t.Errorf("deleting a removed source = %d, %v; want 0", n, err)
+	}
+	all, _ := s.List(ctx, llm.ListFilter{})
+	if n, err := s.DeleteBatch(ctx, []string{all[0].ID, "missing"}); err != nil || n != 1 {
+		t.Errorf("DeleteBatch = %d, %v; want 1", n, err)
+	}
+	if n, _ := s.Count(ctx); n != 0 {
+		t.Errorf("count after deletes = %d, want 0", n)
+	}
+}
+
+// TestLocalStoreTTL verifies expired documents are hidden
+func TestLocalStoreTTL(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStore(t, "")
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	s.Add(ctx, llm.Document{Content: "cat", Source: "a.md"}, WithTTL(time.Minute))
+	s.Add(ctx, llm.Document{Content: "dog", Source: "b.md"})
+
+	now = now.Add(2 * time.Minute)
+	if n, _ := s.Count(ctx); n != 1 {
+		t.Errorf("count = %d, want 1 after expiry", n)
+	}
+	if docs, _ := s.List(ctx, llm.ListFilter{}); len(docs) != 1 || docs[0].Source != "b.md" {
+		t.Errorf("expired document listed: %+v", docs)
+	}
+}
+
+// TestLocalStoreParallelSearch verifies the worker pool ranks a large store
+// like a sequential scan and that cancellation stops the search
+func TestLocalStoreParallelSearch(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStore(t, "")
+	orig := localSearchWorkers
+	t.Cleanup(func() { localSearchWorkers = orig })
+	localSearchWorkers = func() int { return 4 }
+
+	docs := make([]llm.Document, localSearchParallelMin+500)
+	for i := range docs {
+		docs[i] = llm.Document{
+			ID:      fmt.Sprintf("doc-%d", i),
+			Content: strings.Repeat("cat ", i%7) + strings.Repeat("dog ", i%5),
+			Source:  "animals.md",
+		}
+	}
+	if err := s.AddBatch(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expected ranking from a sequential scan; ties keep insertion order
+	query, _ := topicEmbedder{}.EmbedStrings(ctx, []string{"cat"})
+	queryVector := []float32{float32(query[0][0]), float32(query[0][1])}
+	type scored struct {
+		id    string
+		score float32
+	}
+	var want []scored
+	for _, doc := range docs {
+		v, _ := topicEmbedder{}.EmbedStrings(ctx, []string{doc.Content})
+		want = append(want, scored{doc.ID, cosineSimilarity(queryVector, []float32{float32(v[0][0]), float32(v[0][1])})})
+	}
+	sort.SliceStable(want, func(a, b int) bool { return want[a].score > want[b].score })
+
+	results, err := s.Search(ctx, "cat", 20, llm.ListFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 20 {
+		t.Fatalf("got %d results, want 20", len(results))
+	}
+	for i, r := range results {
+		if r.Document.ID != want[i].id {
+			t.Fatalf("result %d = %s, want %s", i, r.Document.ID, want[i].id)
+		}
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s.Search(cancelled, "cat", 5, llm.ListFilter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled search returned %v, want context.Canceled", err)
+	}
+}
+
+// TestLocalStoreJournal verifies changes are appended to the journal, replayed
+// on reopen, and folded into the snapshot on compaction and close
+func TestLocalStoreJournal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.json")
+	open := func() *LocalStore {
+		s, err := NewLocalStore(topicEmbedder{}, LocalConfig{Path: path, VectorDim: 2, Journal: true, CompactEvery: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	s := open()
+	s.Add(ctx, llm.Document{ID: "a", Content: "cat", Source: "a.md"})
+	s.Add(ctx, llm.Document{ID: "b", Content: "dog", Source: "b.md"})
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("snapshot written before compaction: %v", err)
+	}
+
+	// Reopen without Close: the journal alone must restore both documents,
+	// and a half-written trailing line is skipped
+	f, _ := os.OpenFile(path+".journal", os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"put":[{"id":"c"`)
+	f.Close()
+	s = open()
+	if n, _ := s.Count(ctx); n != 2 {
+		t.Fatalf("count after replay = %d, want 2", n)
+	}
+
+	// The third entry reaches CompactEvery and rewrites the snapshot
+	s.Delete(ctx, "a")
+	if _, err := os.Stat(path + ".journal"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("journal left after compaction: %v", err)
+	}
+	s.Add(ctx, llm.Document{ID: "d", Content: "cat dog", Source: "d.md"})
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".journal"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("journal left after close: %v", err)
+	}
+
+	// Opening without the journal still sees everything
+	plain := newTestLocalStore(t, path)
+	docs, _ := plain.List(ctx, llm.ListFilter{})
+	if len(docs) != 2 || docs[0].ID != "b" || docs[1].ID != "d" {
+		t.Errorf("unexpected documents after compaction: %+v", docs)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if e.Name() != "store.json" {
+			t.Errorf("unexpected file left behind: %s", e.Name())
+		}
+	}
+}
+
+// TestLocalStoreCollections verifies collections are isolated, persisted in
+// their own files and listed after reopening
+func TestLocalStoreCollections(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "store.json")
+	s := newTestLocalStore(t, path)
+
+	work, err := s.Collection(ctx, "work-docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.Collection(ctx, "Work-Docs"); again != work {
+		t.Error("collection should be opened once")
+	}
+	if def, _ := s.Collection(ctx, ""); def != s {
+		t.Error("empty name should select the store itself")
+	}
+	work.Add(ctx, llm.Document{ID: "w", Content: "cat", Source: "w.md"})
+	s.Add(ctx, llm.Document{ID: "d", Content: "dog", Source: "d.md"})
+	if _, err := s.Collection(ctx, "notes"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Collection(ctx, "bad name"); err == nil {
+		t.Error("expected invalid collection name error")
+	}
+
+	if n, _ := work.Count(ctx); n != 1 {
+		t.Errorf("work-docs count = %d, want 1", n)
+	}
+	if n, _ := s.Count(ctx); n != 1 {
+		t.Errorf("default count = %d, want 1", n)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := newTestLocalStore(t, path)
+	names, err := reopened.ListCollections(ctx)
+	if err != nil || strings.Join(names, ",") != "default,notes,work-docs" {
+		t.Errorf("collections = %v, %v", names, err)
+	}
+	work, _ = reopened.Collection(ctx, "work-docs")
+	if docs, _ := work.List(ctx, llm.ListFilter{}); len(docs) != 1 || docs[0].ID != "w" {
+		t.Errorf("work-docs should survive reopening: %+v", docs)
+	}
+}
@@ -1,11 +1,15 @@
 package vector
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
@@ -24,14 +28,23 @@ const (
 	defaultM              = 16
 
 	// Field names in Redis hash
-	fieldContent    = "content"
-	fieldVector     = "vector"
-	fieldSource     = "source"
-	fieldFileType   = "file_type"
-	fieldTitle      = "title"
-	fieldChunkIndex = "chunk_index"
-	fieldCreatedAt  = "created_at"
-	fieldMetadata   = "metadata"
+	fieldContent     = "content"
+	fieldVector      = "vector"
+	fieldSource      = "source"
+	fieldFileType    = "file_type"
+	fieldTitle       = "title"
+	fieldChunkIndex  = "chunk_index"
+	fieldCreatedAt   = "created_at"
+	fieldUpdatedAt   = "updated_at"
+	fieldMetadata    = "metadata"
+	fieldContentHash = "content_hash"
+	fieldScore       = "score" // KNN distance alias, only present in search results
+
+	// fieldVectorEncoding records how the vector field is encoded. Documents
+	// written before binary encoding lack it and hold JSON vectors.
+	fieldVectorEncoding = "vector_encoding"
+	// vectorEncodingFloat32 is little-endian IEEE-754 float32, as RediSearch expects
+	vectorEncodingFloat32 = "f32le"
 )
 
 // RedisStore implements VectorStore using Redis with RediSearch vector search
@@ -43,6 +56,9 @@ type RedisStore struct {
 	mu             sync.RWMutex
 	efConstruction int
 	m              int
+
+	parent      *RedisStore            // Owning store of a collection (nil for the default collection)
+	collections map[string]*RedisStore // Named collections, guarded by mu
 }
 
 // RedisConfig holds Redis connection configuration
@@ -55,6 +71,7 @@ type RedisConfig struct {
 	VectorDim      int
 	EFConstruction int
 	M              int
+	DefaultTTL     time.Duration // Expiry of added documents, 0 keeps them forever
 }
 
 // DefaultRedisConfig returns default Redis configuration from environment
@@ -71,9 +88,20 @@ func DefaultRedisConfig() RedisConfig {
 		VectorDim:      GetEmbeddingDimFromEnv(),
 		EFConstruction: efConstruction,
 		M:              m,
+		DefaultTTL:     getEnvDuration("VECTOR_DOCUMENT_TTL", 0),
 	}
 }
 
+// getEnvDuration reads a Go duration from environment variable
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return defaultVal
+}
+
 // getEnvString reads a string from environment variable
 func getEnvString(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
@@ -82,7 +110,10 @@ func getEnvString(key, defaultVal string) string {
 	return defaultVal
 }
 
-// NewRedisStore creates a new Redis-based vector store
+// NewRedisStore creates a new Redis-based vector store. The embedder is probed
+// once and must produce vectors of the index dimension: the existing index's
+// DIM when the index is already there, otherwise cfg.VectorDim. A zero
+// cfg.VectorDim creates the index with the probed dimension.
 func NewRedisStore(ctx context.Context, embedder embedding.Embedder, cfg RedisConfig) (*RedisStore, error) {
 	if embedder == nil {
 		return nil, fmt.Errorf("embedding model is required")
@@ -101,13 +132,37 @@ func NewRedisStore(ctx context.Context, embedder embedding.Embedder, cfg RedisCo
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
+	// An existing index keeps the dimension it was created with
+	dim := cfg.VectorDim
+	if info, err := client.Do(ctx, "FT.INFO", cfg.IndexName).Result(); err == nil {
+		if indexDim := indexVectorDim(info); indexDim > 0 {
+			dim = indexDim
+		}
+	}
+	embedderDim, err := ProbeDimension(ctx, embedder)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	if dim == 0 {
+		dim = embedderDim
+	}
+	if embedderDim != dim {
+		client.Close()
+		return nil, fmt.Errorf("index %s is %d-dim but embedder returned %d; set VECTOR_DIM or switch to a matching embedding model",
+			cfg.IndexName, dim, embedderDim)
+	}
+
+	embeddingSvc := NewEmbeddingService(embedder, dim)
 	store := &RedisStore{
 		client:       client,
-		embeddingSvc: NewEmbeddingService(embedder, cfg.VectorDim),
+		embeddingSvc: embeddingSvc,
 		config: StoreConfig{
-			EmbeddingDim: cfg.VectorDim,
-			IndexName:    cfg.IndexName,
-			KeyPrefix:    "vec:",
+			EmbeddingDim:       dim,
+			IndexName:          cfg.IndexName,
+			KeyPrefix:          "vec:",
+			DefaultTTL:         cfg.DefaultTTL,
+			EmbeddingBatchSize: embeddingSvc.BatchSize(),
 		},
 		efConstruction: cfg.EFConstruction,
 		m:              cfg.M,
@@ -118,6 +173,7 @@ func NewRedisStore(ctx context.Context, embedder embedding.Embedder, cfg RedisCo
 		client.Close()
 		return nil, fmt.Errorf("failed to create vector index: %w", err)
 	}
+	store.startVectorMigration()
 
 	return store, nil
 }
@@ -131,7 +187,10 @@ func (s *RedisStore) ensureIndex(ctx context.Context) error {
 	indexName := s.config.IndexName
 	_, err := s.client.Do(ctx, "FT.INFO", indexName).Result()
 	if err == nil {
-		// Index exists
+		// Index exists; indexes created before updated_at or content_hash need
+		// the fields added. FT.ALTER fails harmlessly when a field is already in the schema.
+		s.client.Do(ctx, "FT.ALTER", indexName, "SCHEMA", "ADD", fieldUpdatedAt, "NUMERIC")
+		s.client.Do(ctx, "FT.ALTER", indexName, "SCHEMA", "ADD", fieldContentHash, "TAG")
 		s.indexCreated = true
 		return nil
 	}
@@ -149,6 +208,8 @@ func (s *RedisStore) ensureIndex(ctx context.Context) error {
 	//          title TEXT
 	//          chunk_index NUMERIC
 	//          created_at NUMERIC
+	//          updated_at NUMERIC
+	//          content_hash TAG
 
 	_, err = s.client.Do(ctx, "FT.CREATE", indexName,
 		"ON", "HASH",
@@ -164,6 +225,8 @@ func (s *RedisStore) ensureIndex(ctx context.Context) error {
 		fieldTitle, "TEXT",
 		fieldChunkIndex, "NUMERIC",
 		fieldCreatedAt, "NUMERIC",
+		fieldUpdatedAt, "NUMERIC",
+		fieldContentHash, "TAG",
 	).Result()
 
 	if err != nil {
@@ -174,8 +237,61 @@ func (s *RedisStore) ensureIndex(ctx context.Context) error {
 	return nil
 }
 
+// indexVectorDim returns the DIM of the vector field in an FT.INFO reply, or 0
+// when the reply does not describe it
+func indexVectorDim(info interface{}) int {
+	attrs, _ := infoField(info, "attributes").([]interface{})
+	for _, attr := range attrs {
+		if name, _ := infoField(attr, "identifier").(string); name != fieldVector {
+			continue
+		}
+		return infoInt(infoField(attr, "dim"))
+	}
+	return 0
+}
+
+// infoField looks up a key in an FT.INFO reply, which is a flat key/value
+// list over RESP2 and a map over RESP3
+func infoField(reply interface{}, key string) interface{} {
+	switch v := reply.(type) {
+	case []interface{}:
+		for i := 0; i+1 < len(v); i += 2 {
+			if k, ok := v[i].(string); ok && strings.EqualFold(k, key) {
+				return v[i+1]
+			}
+		}
+	case map[interface{}]interface{}:
+		for k, val := range v {
+			if ks, ok := k.(string); ok && strings.EqualFold(ks, key) {
+				return val
+			}
+		}
+	case map[string]interface{}:
+		for k, val := range v {
+			if strings.EqualFold(k, key) {
+				return val
+			}
+		}
+	}
+	return nil
+}
+
+// infoInt converts a numeric FT.INFO value to int, or 0
+func infoInt(v interface{}) int {
+	switch n := v.(type) {
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
 // generateID generates a unique document ID
-func (s *RedisStore) generateID(source string, chunkIndex int) string {
+func generateID(source string, chunkIndex int) string {
 	h := sha256.New()
 	h.Write([]byte(source))
 	h.Write([]byte(fmt.Sprintf("%d", chunkIndex)))
@@ -184,25 +300,21 @@ func (s *RedisStore) generateID(source string, chunkIndex int) string {
 }
 
 // Add adds a single document to the store
-func (s *RedisStore) Add(ctx context.Context, doc llm.Document) error {
-	return s.AddBatch(ctx, []llm.Document{doc})
+func (s *RedisStore) Add(ctx context.Context, doc llm.Document, opts ...AddOption) error {
+	return s.AddBatch(ctx, []llm.Document{doc}, opts...)
 }
 
-// AddBatch adds multiple documents in a single operation
-func (s *RedisStore) AddBatch(ctx context.Context, docs []llm.Document) error {
+// AddBatch adds multiple documents in a single operation. Documents expire
+// after the TTL from WithTTL, or StoreConfig.DefaultTTL when none is given.
+func (s *RedisStore) AddBatch(ctx context.Context, docs []llm.Document, opts ...AddOption) error {
 	if len(docs) == 0 {
 		return nil
 	}
+	options := ResolveAddOptions(s.config, opts...)
 
-	// Generate embeddings for all documents
-	texts := make([]string, len(docs))
-	for i, doc := range docs {
-		texts[i] = doc.Content
-	}
-
-	vectors, err := s.embeddingSvc.EmbedBatch(ctx, texts)
+	vectors, err := s.documentVectors(ctx, docs)
 	if err != nil {
-		return fmt.Errorf("failed to generate embeddings: %w", err)
+		return err
 	}
 
 	// Use pipeline for batch insert
@@ -211,11 +323,14 @@ func (s *RedisStore) AddBatch(ctx context.Context, docs []llm.Document) error {
 	now := time.Now().Unix()
 	for i, doc := range docs {
 		if doc.ID == "" {
-			doc.ID = s.generateID(doc.Source, doc.ChunkIndex)
+			doc.ID = generateID(doc.Source, doc.ChunkIndex)
 		}
 		if doc.CreatedAt == "" {
 			doc.CreatedAt = time.Now().Format(time.RFC3339)
 		}
+		if doc.ContentHash == "" {
+			doc.ContentHash = ContentHash(doc.Content)
+		}
 
 		key := s.config.KeyPrefix + doc.ID
 
@@ -232,13 +347,19 @@ func (s *RedisStore) AddBatch(ctx context.Context, docs []llm.Document) error {
 		pipe.HSet(ctx, key,
 			fieldContent, doc.Content,
 			fieldVector, vectorBytes,
+			fieldVectorEncoding, vectorEncodingFloat32,
 			fieldSource, escapeTagValue(doc.Source),
 			fieldFileType, doc.FileType,
 			fieldTitle, doc.Title,
 			fieldChunkIndex, doc.ChunkIndex,
 			fieldCreatedAt, now,
+			fieldUpdatedAt, now,
 			fieldMetadata, metadataJSON,
+			fieldContentHash, doc.ContentHash,
 		)
+		if options.TTL > 0 {
+			pipe.Expire(ctx, key, options.TTL)
+		}
 	}
 
 	// Execute pipeline
@@ -249,20 +370,154 @@ func (s *RedisStore) AddBatch(ctx context.Context, docs []llm.Document) error {
 	return nil
 }
 
-// encodeVector encodes a float32 vector as bytes for Redis storage
-func encodeVector(vector []float32) ([]byte, error) {
-	// Use JSON encoding for simplicity
-	// For production, consider using binary encoding for efficiency
-	return json.Marshal(vector)
+// documentVectors returns the vector of each document, embedding only the
+// documents that do not carry a precomputed Vector
+func (s *RedisStore) documentVectors(ctx context.Context, docs []llm.Document) ([][]float32, error) {
+	return embedDocuments(ctx, s.embeddingSvc, s.config.EmbeddingDim, docs)
+}
+
+// embedDocuments returns the vector of each document, embedding with svc only
+// the documents that do not carry a precomputed vector of dimension dim
+func embedDocuments(ctx context.Context, svc *EmbeddingService, dim int, docs []llm.Document) ([][]float32, error) {
+	vectors := make([][]float32, len(docs))
+	var missing []llm.Document
+	var indices []int
+	for i, doc := range docs {
+		if len(doc.Vector) == 0 {
+			missing = append(missing, doc)
+			indices = append(indices, i)
+			continue
+		}
+		if len(doc.Vector) != dim {
+			return nil, fmt.Errorf("document %d has a %d-dimensional vector, index expects %d",
+				i, len(doc.Vector), dim)
+		}
+		vectors[i] = doc.Vector
+	}
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	embedded, err := svc.EmbedBatch(ctx, embeddingTexts(missing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+	for j, i := range indices {
+		vectors[i] = embedded[j]
+	}
+	return vectors, nil
+}
+
+// embeddingTexts returns the text to embed for each document, preferring
+// EmbeddingText over the display Content
+func embeddingTexts(docs []llm.Document) []string {
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.Content
+		if doc.EmbeddingText != "" {
+			texts[i] = doc.EmbeddingText
+		}
+	}
+	return texts
 }
 
-// decodeVector decodes a float32 vector from Redis storage
-func decodeVector(data []byte) ([]float32, error) {
-	var vector []float32
-	if err := json.Unmarshal(data, &vector); err != nil {
+// encodeVector encodes a float32 vector as little-endian IEEE-754 bytes,
+// the layout RediSearch expects for FLOAT32 vector fields
+func encodeVector(vector []float32) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, len(vector)*4))
+	if err := binary.Write(buf, binary.LittleEndian, vector); err != nil {
 		return nil, err
 	}
-	return vector, nil
+	return buf.Bytes(), nil
+}
+
+// decodeVector decodes a float32 vector stored with the given encoding.
+// An empty encoding denotes a legacy JSON-encoded vector.
+func decodeVector(data []byte, encoding string) ([]float32, error) {
+	switch encoding {
+	case vectorEncodingFloat32:
+		if len(data)%4 != 0 {
+			return nil, fmt.Errorf("invalid float32 vector length: %d bytes", len(data))
+		}
+		vector := make([]float32, len(data)/4)
+		if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, vector); err != nil {
+			return nil, err
+		}
+		return vector, nil
+	case "":
+		var vector []float32
+		if err := json.Unmarshal(data, &vector); err != nil {
+			return nil, err
+		}
+		return vector, nil
+	default:
+		return nil, fmt.Errorf("unknown vector encoding: %s", encoding)
+	}
+}
+
+// migrateVectorValue re-encodes a legacy JSON vector in binary form. It
+// reports false when the value is already binary or empty.
+func migrateVectorValue(data []byte, encoding string) ([]byte, bool, error) {
+	if encoding != "" || len(data) == 0 {
+		return nil, false, nil
+	}
+	vector, err := decodeVector(data, encoding)
+	if err != nil {
+		return nil, false, err
+	}
+	encoded, err := encodeVector(vector)
+	if err != nil {
+		return nil, false, err
+	}
+	return encoded, true, nil
+}
+
+// startVectorMigration converts legacy JSON vectors in the background so the
+// store is usable immediately; RediSearch indexes each document once rewritten
+func (s *RedisStore) startVectorMigration() {
+	go func() {
+		migrated, err := s.migrateLegacyVectors(context.Background())
+		if err != nil {
+			slog.Error("vector migration failed", "index", s.config.IndexName, "err", err)
+			return
+		}
+		if migrated > 0 {
+			slog.Info("migrated JSON-encoded vectors to binary", "count", migrated, "index", s.config.IndexName)
+		}
+	}()
+}
+
+// migrateLegacyVectors rewrites JSON-encoded vectors under the store's key
+// prefix as binary float32 and returns the number of documents migrated
+func (s *RedisStore) migrateLegacyVectors(ctx context.Context) (int, error) {
+	migrated := 0
+	iter := s.client.Scan(ctx, 0, s.config.KeyPrefix+"*", 200).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		values, err := s.client.HMGet(ctx, key, fieldVector, fieldVectorEncoding).Result()
+		if err != nil {
+			return migrated, err
+		}
+		data, _ := values[0].(string)
+		encoding, _ := values[1].(string)
+
+		encoded, ok, err := migrateVectorValue([]byte(data), encoding)
+		if err != nil {
+			slog.Warn("skipping undecodable vector", "key", key, "err", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := s.client.HSet(ctx, key,
+			fieldVector, encoded,
+			fieldVectorEncoding, vectorEncodingFloat32,
+		).Err(); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, iter.Err()
 }
 
 // escapeTagValue escapes special characters in TAG field values
@@ -279,8 +534,9 @@ func escapeTagValue(value string) string {
 	return result
 }
 
-// Search performs semantic search using vector similarity
-func (s *RedisStore) Search(ctx context.Context, query string, topK int) ([]llm.SearchResult, error) {
+// Search performs semantic search using vector similarity, optionally
+// restricted to documents matching the filter's source and file type
+func (s *RedisStore) Search(ctx context.Context, query string, topK int, filter llm.ListFilter) ([]llm.SearchResult, error) {
 	if query == "" {
 		return nil, fmt.Errorf("query cannot be empty")
 	}
@@ -304,22 +560,29 @@ func (s *RedisStore) Search(ctx context.Context, query string, topK int) ([]llm.
 	}
 
 	// Execute vector search query
-	// FT.SEARCH cowork-knowledge "*=>[KNN 5 @vector $query_vector AS score]"
-	//   PARAMS 2 query_vector "<bytes>"
-	//   RETURN 3 content source title
-	//   SORT BY score
+	// FT.SEARCH cowork-knowledge "(@source:{...})=>[KNN 5 @vector $vec AS score]"
+	//   PARAMS 2 vec "<bytes>"
+	//   RETURN 7 content source file_type title chunk_index metadata score
+	//   SORTBY score
 	//   LIMIT 0 5
+	//   DIALECT 2
 
 	indexName := s.config.IndexName
 
-	// Build the search query with KNN
-	// Note: Don't use 'AS score' as it's deprecated in newer Redis Stack versions
-	queryStr := fmt.Sprintf("*=>[KNN %d @vector $vec]", topK)
+	// Build the search query with KNN, aliasing the cosine distance as score.
+	// The filter becomes the KNN pre-filter; without one all documents qualify.
+	preFilter := "*"
+	if tagFilter := buildTagFilter(filter); tagFilter != "" {
+		preFilter = "(" + tagFilter + ")"
+	}
+	queryStr := fmt.Sprintf("%s=>[KNN %d @vector $vec AS %s]", preFilter, topK, fieldScore)
 
 	result, err := s.client.Do(ctx, "FT.SEARCH", indexName, queryStr,
 		"PARAMS", "2", "vec", queryBytes,
-		"RETURN", "6", fieldContent, fieldSource, fieldFileType, fieldTitle, fieldChunkIndex, fieldMetadata,
+		"RETURN", "7", fieldContent, fieldSource, fieldFileType, fieldTitle, fieldChunkIndex, fieldMetadata, fieldScore,
+		"SORTBY", fieldScore,
 		"LIMIT", "0", strconv.Itoa(topK),
+		"DIALECT", "2",
 	).Result()
 
 	if err != nil {
@@ -327,7 +590,7 @@ func (s *RedisStore) Search(ctx context.Context, query string, topK int) ([]llm.
 	}
 
 	// Parse results
-	results, err := s.parseSearchResults(ctx, result, topK)
+	results, err := s.parseSearchResults(result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse search results: %w", err)
 	}
@@ -336,7 +599,7 @@ func (s *RedisStore) Search(ctx context.Context, query string, topK int) ([]llm.
 }
 
 // parseSearchResults parses Redis search results
-func (s *RedisStore) parseSearchResults(ctx context.Context, result interface{}, topK int) ([]llm.SearchResult, error) {
+func (s *RedisStore) parseSearchResults(result interface{}) ([]llm.SearchResult, error) {
 	// Result format from FT.SEARCH is a list
 	// First element is count, followed by pairs of (id, fields)
 	values, ok := result.([]interface{})
@@ -377,19 +640,40 @@ func (s *RedisStore) parseSearchResults(ctx context.Context, result interface{},
 			continue
 		}
 
-		// Extract score from the search result - Redis FT.SEARCH with KNN
-		// includes the score in a special way
-		// For simplicity, we'll use the order as relevance indicator
-
 		results = append(results, llm.SearchResult{
 			Document: doc,
-			Score:    1.0 - float32(len(results))/float32(topK+1), // Simple decay based on position
+			Score:    parseKNNScore(fields),
 		})
 	}
 
 	return results, nil
 }
 
+// parseKNNScore converts the KNN cosine distance returned as the score field
+// into a similarity (1 - distance). Returns 0 when the field is missing.
+func parseKNNScore(fields []interface{}) float32 {
+	for i := 0; i+1 < len(fields); i += 2 {
+		if name, ok := fields[i].(string); !ok || name != fieldScore {
+			continue
+		}
+		var distance float64
+		var err error
+		switch val := fields[i+1].(type) {
+		case string:
+			distance, err = strconv.ParseFloat(val, 64)
+		case float64:
+			distance = val
+		default:
+			return 0
+		}
+		if err != nil {
+			return 0
+		}
+		return float32(1 - distance)
+	}
+	return 0
+}
+
 // parseDocumentFields parses document fields from Redis result
 func (s *RedisStore) parseDocumentFields(id string, fields []interface{}) (llm.Document, error) {
 	doc := llm.Document{
@@ -434,12 +718,73 @@ func (s *RedisStore) parseDocumentFields(id string, fields []interface{}) (llm.D
 			if val, ok := fieldValue.(string); ok {
 				json.Unmarshal([]byte(val), &doc.Metadata)
 			}
+		case fieldContentHash:
+			if val, ok := fieldValue.(string); ok {
+				doc.ContentHash = val
+			}
 		}
 	}
 
 	return doc, nil
 }
 
+// Update overwrites a stored document in place. The content is re-embedded
+// only when it changed; created_at is preserved and updated_at refreshed.
+func (s *RedisStore) Update(ctx context.Context, doc llm.Document) error {
+	if doc.ID == "" {
+		return fmt.Errorf("document ID cannot be empty")
+	}
+
+	key := s.config.KeyPrefix + doc.ID
+	stored, err := s.client.HMGet(ctx, key, fieldContent, fieldCreatedAt).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read document: %w", err)
+	}
+	if stored[1] == nil {
+		return fmt.Errorf("document not found: %s", doc.ID)
+	}
+
+	var vectorBytes []byte
+	if oldContent, _ := stored[0].(string); oldContent != doc.Content || doc.EmbeddingText != "" {
+		vectors, err := s.embeddingSvc.EmbedBatch(ctx, embeddingTexts([]llm.Document{doc}))
+		if err != nil {
+			return fmt.Errorf("failed to generate embedding: %w", err)
+		}
+		vectorBytes, err = encodeVector(vectors[0])
+		if err != nil {
+			return fmt.Errorf("failed to encode vector: %w", err)
+		}
+	}
+
+	if err := s.client.HSet(ctx, key, updateFields(doc, vectorBytes, time.Now().Unix())...).Err(); err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+	return nil
+}
+
+// updateFields returns the hash fields written by Update. created_at is left
+// untouched and the vector is only included when it was re-embedded.
+func updateFields(doc llm.Document, vectorBytes []byte, now int64) []interface{} {
+	metadataJSON, _ := json.Marshal(doc.Metadata)
+	fields := []interface{}{
+		fieldContent, doc.Content,
+		fieldSource, escapeTagValue(doc.Source),
+		fieldFileType, doc.FileType,
+		fieldTitle, doc.Title,
+		fieldChunkIndex, doc.ChunkIndex,
+		fieldMetadata, metadataJSON,
+		fieldContentHash, ContentHash(doc.Content),
+		fieldUpdatedAt, now,
+	}
+	if vectorBytes != nil {
+		fields = append(fields,
+			fieldVector, vectorBytes,
+			fieldVectorEncoding, vectorEncodingFloat32,
+		)
+	}
+	return fields
+}
+
 // Delete removes a document by its ID
 func (s *RedisStore) Delete(ctx context.Context, id string) error {
 	if id == "" {
@@ -450,98 +795,189 @@ func (s *RedisStore) Delete(ctx context.Context, id string) error {
 	return s.client.Del(ctx, key).Err()
 }
 
-// DeleteBySource removes all documents from a specific source file
-func (s *RedisStore) DeleteBySource(ctx context.Context, source string) error {
-	if source == "" {
-		return fmt.Errorf("source cannot be empty")
+// DeleteBatch removes documents by ID in a single MULTI/EXEC transaction and
+// returns the number of keys that existed
+func (s *RedisStore) DeleteBatch(ctx context.Context, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	for _, id := range ids {
+		if id == "" {
+			return 0, fmt.Errorf("document ID cannot be empty")
+		}
 	}
 
-	// First, find all documents with this source
-	indexName := s.config.IndexName
-	escapedSource := escapeTagValue(source)
-
-	// Use FT.SEARCH to find documents by source tag
-	result, err := s.client.Do(ctx, "FT.SEARCH", indexName,
-		fmt.Sprintf("@source:{%s}", escapedSource),
-		"NOCONTENT",
-		"LIMIT", "0", "1000",
-	).Result()
-
-	if err != nil {
-		// If index doesn't exist or no results, return success
+	var cmds []*redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			cmds = append(cmds, pipe.Del(ctx, s.config.KeyPrefix+id))
+		}
 		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete documents: %w", err)
 	}
 
-	// Extract document IDs
-	values, ok := result.([]interface{})
-	if !ok || len(values) < 2 {
-		return nil
+	deleted := 0
+	for _, cmd := range cmds {
+		deleted += int(cmd.Val())
 	}
+	return deleted, nil
+}
 
-	var keys []string
-	for i := 1; i < len(values); i += 2 {
-		if docID, ok := values[i].(string); ok {
-			keys = append(keys, s.config.KeyPrefix+docID)
-		}
+// DeleteBySource removes all documents from a specific source file. Every
+// matching ID is collected page by page first, then all of them are deleted
+// in one transaction, so a failure part way leaves no orphaned chunks.
+func (s *RedisStore) DeleteBySource(ctx context.Context, source string) (int, error) {
+	if source == "" {
+		return 0, fmt.Errorf("source cannot be empty")
 	}
 
-	// Delete all found documents
-	if len(keys) > 0 {
-		return s.client.Del(ctx, keys...).Err()
+	ids, err := s.sourceIDs(ctx, source)
+	if err != nil {
+		return 0, err
 	}
+	return s.DeleteBatch(ctx, ids)
+}
 
-	return nil
+// sourceIDs returns the IDs of all documents of a source
+func (s *RedisStore) sourceIDs(ctx context.Context, source string) ([]string, error) {
+	query := fmt.Sprintf("@%s:{%s}", fieldSource, escapeTagValue(source))
+	var ids []string
+	for offset := 0; ; offset += listPageSize {
+		result, err := s.client.Do(ctx, "FT.SEARCH", s.config.IndexName, query,
+			"NOCONTENT",
+			"LIMIT", strconv.Itoa(offset), strconv.Itoa(listPageSize),
+		).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find documents of %s: %w", source, err)
+		}
+		page := searchResultIDs(result)
+		ids = append(ids, page...)
+		if len(page) < listPageSize {
+			return ids, nil
+		}
+	}
 }
 
-// List returns documents matching the filter criteria
-func (s *RedisStore) List(ctx context.Context, filter llm.ListFilter) ([]llm.Document, error) {
-	indexName := s.config.IndexName
+// listPageSize is the number of documents fetched per FT.SEARCH page
+const listPageSize = 500
 
-	// Build query
-	var queryParts []string
-	if filter.Source != "" {
-		escapedSource := escapeTagValue(filter.Source)
-		queryParts = append(queryParts, fmt.Sprintf("@source:{%s}", escapedSource))
-	}
-	if filter.FileType != "" {
-		queryParts = append(queryParts, fmt.Sprintf("@file_type:{%s}", filter.FileType))
+// List returns documents matching the filter criteria. Limits larger than a
+// single search page are fetched over several pages.
+func (s *RedisStore) List(ctx context.Context, filter llm.ListFilter) ([]llm.Document, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 100
 	}
 
-	query := "*"
-	if len(queryParts) > 0 {
-		query = strings.Join(queryParts, " ")
+	docs := []llm.Document{}
+	err := s.ListAll(ctx, filter, func(doc llm.Document) error {
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return docs, nil
+}
 
-	limit := filter.Limit
-	if limit <= 0 {
-		limit = 100
-	}
-	if limit > 1000 {
-		limit = 1000
+// ListAll calls fn for every document matching the filter, advancing the
+// FT.SEARCH offset page by page until a short page signals the end
+func (s *RedisStore) ListAll(ctx context.Context, filter llm.ListFilter, fn func(llm.Document) error) error {
+	// Build query
+	query := buildTagFilter(filter)
+	if query == "" {
+		query = "*"
 	}
 
-	offset := filter.Offset
-	if offset < 0 {
-		offset = 0
+	offset := max(filter.Offset, 0)
+	remaining := filter.Limit
+	for {
+		size := listPageSize
+		if remaining > 0 && remaining < size {
+			size = remaining
+		}
+
+		// Execute search
+		result, err := s.client.Do(ctx, "FT.SEARCH", s.config.IndexName, query,
+			"RETURN", "8", fieldContent, fieldSource, fieldFileType, fieldTitle, fieldChunkIndex, fieldCreatedAt, fieldMetadata, fieldContentHash,
+			"LIMIT", strconv.Itoa(offset), strconv.Itoa(size),
+		).Result()
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+
+		// Parse results
+		docs, err := s.parseListResults(result)
+		if err != nil {
+			return fmt.Errorf("failed to parse results: %w", err)
+		}
+		for _, doc := range docs {
+			if err := fn(doc); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		// Unparsable documents are skipped, so page progress counts raw hits
+		hits := len(searchResultIDs(result))
+		if hits < size {
+			return nil
+		}
+		offset += hits
+		if remaining > 0 {
+			if remaining -= hits; remaining <= 0 {
+				return nil
+			}
+		}
 	}
+}
 
-	// Execute search
-	result, err := s.client.Do(ctx, "FT.SEARCH", indexName, query,
-		"RETURN", "7", fieldContent, fieldSource, fieldFileType, fieldTitle, fieldChunkIndex, fieldCreatedAt, fieldMetadata,
-		"LIMIT", strconv.Itoa(offset), strconv.Itoa(limit),
-	).Result()
+// searchResultIDs returns the document IDs of an FT.SEARCH reply, with or
+// without NOCONTENT
+func searchResultIDs(result interface{}) []string {
+	values, ok := result.([]interface{})
+	if !ok || len(values) < 2 {
+		return nil
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+	// Without NOCONTENT every ID is followed by its field list
+	step := 1
+	if len(values) > 2 {
+		if _, ok := values[2].([]interface{}); ok {
+			step = 2
+		}
 	}
 
-	// Parse results
-	docs, err := s.parseListResults(result)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse results: %w", err)
+	var ids []string
+	for i := 1; i < len(values); i += step {
+		if id, ok := values[i].(string); ok {
+			ids = append(ids, id)
+		}
 	}
+	return ids
+}
 
-	return docs, nil
+// buildTagFilter returns the RediSearch tag query for the filter's source, file
+// type and content hashes, or "" when none is set
+func buildTagFilter(filter llm.ListFilter) string {
+	var queryParts []string
+	if filter.Source != "" {
+		queryParts = append(queryParts, fmt.Sprintf("@%s:{%s}", fieldSource, escapeTagValue(filter.Source)))
+	}
+	if filter.FileType != "" {
+		queryParts = append(queryParts, fmt.Sprintf("@%s:{%s}", fieldFileType, escapeTagValue(filter.FileType)))
+	}
+	if len(filter.ContentHashes) > 0 {
+		hashes := make([]string, len(filter.ContentHashes))
+		for i, h := range filter.ContentHashes {
+			hashes[i] = escapeTagValue(h)
+		}
+		queryParts = append(queryParts, fmt.Sprintf("@%s:{%s}", fieldContentHash, strings.Join(hashes, " | ")))
+	}
+	return strings.Join(queryParts, " ")
 }
 
 // parseListResults parses list results
@@ -607,8 +1043,23 @@ func (s *RedisStore) Count(ctx context.Context) (int64, error) {
 	return 0, nil
 }
 
-// Close closes the Redis connection
+// EmbeddingDim returns the vector dimension of the index
+func (s *RedisStore) EmbeddingDim() int {
+	return s.config.EmbeddingDim
+}
+
+// SetEmbedder replaces the embedding model. Collections share the embedding
+// service of their owning store, so the switch applies to all of them.
+func (s *RedisStore) SetEmbedder(embedder embedding.Embedder) {
+	s.embeddingSvc.SetEmbedder(embedder)
+}
+
+// Close closes the Redis connection. Collections share the connection of
+// their owning store, so closing a collection is a no-op.
 func (s *RedisStore) Close() error {
+	if s.parent != nil {
+		return nil
+	}
 	if s.client != nil {
 		return s.client.Close()
 	}
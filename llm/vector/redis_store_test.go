@@ -0,0 +1,253 @@
+package vector
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"compass/llm"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// TestParseSearchResultsScore verifies KNN distances become similarity scores
+func TestParseSearchResultsScore(t *testing.T) {
+	raw := []interface{}{
+		int64(2),
+		"doc-a", []interface{}{fieldContent, "closest", fieldSource, "a.md", fieldScore, "0.125"},
+		"doc-b", []interface{}{fieldContent, "farther", fieldSource, "b.md", fieldScore, "0.6"},
+	}
+
+	results, err := (&RedisStore{}).parseSearchResults(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	want := []float32{0.875, 0.4}
+	for i, r := range results {
+		if math.Abs(float64(r.Score-want[i])) > 1e-6 {
+			t.Errorf("result %d score = %v, want %v", i, r.Score, want[i])
+		}
+	}
+	if results[0].Document.Content != "closest" || results[0].Document.Source != "a.md" {
+		t.Errorf("unexpected document: %+v", results[0].Document)
+	}
+	if _, ok := results[0].Document.Metadata[fieldScore]; ok {
+		t.Error("score should not leak into document metadata")
+	}
+}
+
+// TestEncodeVectorBinary verifies vectors are stored as little-endian float32
+func TestEncodeVectorBinary(t *testing.T) {
+	vector := []float32{1.5, -0.25, 0, 3}
+	data, err := encodeVector(vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 4*len(vector) {
+		t.Fatalf("encoded length = %d, want %d", len(data), 4*len(vector))
+	}
+	if got := math.Float32frombits(binary.LittleEndian.Uint32(data[:4])); got != 1.5 {
+		t.Errorf("first element = %v, want 1.5", got)
+	}
+
+	decoded, err := decodeVector(data, vectorEncodingFloat32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, vector) {
+		t.Errorf("decoded = %v, want %v", decoded, vector)
+	}
+
+	if _, err := decodeVector(data[:5], vectorEncodingFloat32); err == nil {
+		t.Error("expected an error for a truncated vector")
+	}
+}
+
+// TestMigrateVectorValue verifies legacy JSON vectors are converted and binary ones left alone
+func TestMigrateVectorValue(t *testing.T) {
+	vector := []float32{0.5, 2, -1}
+	legacy, _ := json.Marshal(vector)
+
+	encoded, ok, err := migrateVectorValue(legacy, "")
+	if err != nil || !ok {
+		t.Fatalf("expected legacy vector to migrate, ok=%v err=%v", ok, err)
+	}
+	decoded, err := decodeVector(encoded, vectorEncodingFloat32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, vector) {
+		t.Errorf("migrated vector = %v, want %v", decoded, vector)
+	}
+
+	if _, ok, _ := migrateVectorValue(encoded, vectorEncodingFloat32); ok {
+		t.Error("binary vector should not be migrated again")
+	}
+	if _, ok, _ := migrateVectorValue(nil, ""); ok {
+		t.Error("empty vector should not be migrated")
+	}
+	if _, _, err := migrateVectorValue([]byte("not json"), ""); err == nil {
+		t.Error("expected an error for an undecodable legacy vector")
+	}
+}
+
+// TestBuildTagFilter verifies source and file type filters become escaped tag queries
+func TestBuildTagFilter(t *testing.T) {
+	tests := []struct {
+		filter llm.ListFilter
+		want   string
+	}{
+		{llm.ListFilter{}, ""},
+		{llm.ListFilter{Limit: 10}, ""},
+		{llm.ListFilter{Source: "./manual.md"}, "@source:{./manual.md}"},
+		{llm.ListFilter{Source: "my docs/a,b.md", FileType: "md"}, `@source:{my\ docs/a\,b.md} @file_type:{md}`},
+		{llm.ListFilter{ContentHashes: []string{"ab12", "cd34"}}, "@content_hash:{ab12 | cd34}"},
+	}
+	for _, tt := range tests {
+		if got := buildTagFilter(tt.filter); got != tt.want {
+			t.Errorf("buildTagFilter(%+v) = %q, want %q", tt.filter, got, tt.want)
+		}
+	}
+}
+
+// TestUpdateFields verifies updates keep created_at and only rewrite re-embedded vectors
+func TestUpdateFields(t *testing.T) {
+	doc := llm.Document{ID: "doc-1", Content: "new content", Source: "a.md", Title: "A"}
+
+	toMap := func(fields []interface{}) map[string]interface{} {
+		m := make(map[string]interface{})
+		for i := 0; i+1 < len(fields); i += 2 {
+			m[fields[i].(string)] = fields[i+1]
+		}
+		return m
+	}
+
+	unchanged := toMap(updateFields(doc, nil, 1700000000))
+	if unchanged[fieldUpdatedAt] != int64(1700000000) || unchanged[fieldContent] != "new content" {
+		t.Errorf("unexpected fields: %v", unchanged)
+	}
+	if _, ok := unchanged[fieldCreatedAt]; ok {
+		t.Error("created_at must be preserved, not overwritten")
+	}
+	if _, ok := unchanged[fieldVector]; ok {
+		t.Error("vector should not be rewritten when content is unchanged")
+	}
+
+	reembedded := toMap(updateFields(doc, []byte{1, 2, 3, 4}, 1700000000))
+	if reembedded[fieldVectorEncoding] != vectorEncodingFloat32 {
+		t.Errorf("re-embedded vector should record its encoding: %v", reembedded)
+	}
+}
+
+// TestResolveAddOptions verifies per-call TTL overrides the store default
+func TestResolveAddOptions(t *testing.T) {
+	if got := ResolveAddOptions(StoreConfig{}).TTL; got != 0 {
+		t.Errorf("default TTL = %v, want no expiry", got)
+	}
+	cfg := StoreConfig{DefaultTTL: time.Hour}
+	if got := ResolveAddOptions(cfg).TTL; got != time.Hour {
+		t.Errorf("TTL = %v, want store default 1h", got)
+	}
+	if got := ResolveAddOptions(cfg, WithTTL(5*time.Minute)).TTL; got != 5*time.Minute {
+		t.Errorf("TTL = %v, want per-call 5m", got)
+	}
+}
+
+// countingEmbedder returns 2-dimensional vectors and records the texts it embeds
+type countingEmbedder struct {
+	texts []string
+}
+
+func (e *countingEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	e.texts = append(e.texts, texts...)
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = []float64{1, 0}
+	}
+	return vectors, nil
+}
+
+// TestDocumentVectorsSkipsPrecomputed verifies only documents without a vector are embedded
+func TestDocumentVectorsSkipsPrecomputed(t *testing.T) {
+	emb := &countingEmbedder{}
+	s := &RedisStore{embeddingSvc: NewEmbeddingService(emb, 2), config: StoreConfig{EmbeddingDim: 2}}
+
+	vectors, err := s.documentVectors(context.Background(), []llm.Document{
+		{Content: "precomputed", Vector: []float32{0, 1}},
+		{Content: "needs embedding"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(emb.texts, []string{"needs embedding"}) {
+		t.Errorf("embedded %q, want only the document without a vector", emb.texts)
+	}
+	if !reflect.DeepEqual(vectors, [][]float32{{0, 1}, {1, 0}}) {
+		t.Errorf("vectors = %v", vectors)
+	}
+
+	if _, err := s.documentVectors(context.Background(), []llm.Document{{Content: "x", Vector: []float32{1, 2, 3}}}); err == nil {
+		t.Error("expected an error for a vector of the wrong dimension")
+	}
+}
+
+// TestSearchResultIDs verifies IDs are read from replies with and without NOCONTENT
+func TestSearchResultIDs(t *testing.T) {
+	noContent := []interface{}{int64(3), "a", "b", "c"}
+	if got := searchResultIDs(noContent); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("NOCONTENT ids = %v", got)
+	}
+
+	withContent := []interface{}{
+		int64(2),
+		"a", []interface{}{fieldContent, "x"},
+		"b", []interface{}{fieldContent, "y"},
+	}
+	if got := searchResultIDs(withContent); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("content ids = %v", got)
+	}
+
+	if got := searchResultIDs([]interface{}{int64(0)}); len(got) != 0 {
+		t.Errorf("empty reply ids = %v", got)
+	}
+	if got := searchResultIDs([]interface{}{int64(1), "only"}); !reflect.DeepEqual(got, []string{"only"}) {
+		t.Errorf("single id = %v", got)
+	}
+}
+
+// TestIndexVectorDim verifies the vector dimension is read from FT.INFO replies
+func TestIndexVectorDim(t *testing.T) {
+	resp2 := []interface{}{
+		"index_name", "cowork-knowledge",
+		"attributes", []interface{}{
+			[]interface{}{"identifier", "content", "attribute", "content", "type", "TEXT"},
+			[]interface{}{"identifier", "vector", "attribute", "vector", "type", "VECTOR",
+				"algorithm", "HNSW", "data_type", "FLOAT32", "dim", int64(1536), "distance_metric", "COSINE"},
+		},
+		"num_docs", int64(3),
+	}
+	if dim := indexVectorDim(resp2); dim != 1536 {
+		t.Errorf("RESP2 dim = %d, want 1536", dim)
+	}
+
+	resp3 := map[interface{}]interface{}{
+		"attributes": []interface{}{
+			map[interface{}]interface{}{"identifier": "vector", "type": "VECTOR", "dim": "768"},
+		},
+	}
+	if dim := indexVectorDim(resp3); dim != 768 {
+		t.Errorf("RESP3 dim = %d, want 768", dim)
+	}
+
+	if dim := indexVectorDim([]interface{}{"index_name", "x"}); dim != 0 {
+		t.Errorf("missing attributes dim = %d, want 0", dim)
+	}
+}
@@ -3,28 +3,51 @@ package vector
 import (
 	"compass/llm"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
 )
 
 // VectorStore defines the interface for vector storage operations
 type VectorStore interface {
 	// Add adds a single document to the store
-	Add(ctx context.Context, doc llm.Document) error
+	Add(ctx context.Context, doc llm.Document, opts ...AddOption) error
 
-	// AddBatch adds multiple documents in a single operation
-	AddBatch(ctx context.Context, docs []llm.Document) error
+	// AddBatch adds multiple documents in a single operation. Documents that
+	// already carry a Vector are stored without being embedded again.
+	AddBatch(ctx context.Context, docs []llm.Document, opts ...AddOption) error
 
-	// Search performs semantic search and returns top-k results
-	Search(ctx context.Context, query string, topK int) ([]llm.SearchResult, error)
+	// Search performs semantic search and returns top-k results. A non-empty
+	// filter restricts the search to documents matching its source and file type;
+	// Limit and Offset are ignored.
+	Search(ctx context.Context, query string, topK int, filter llm.ListFilter) ([]llm.SearchResult, error)
+
+	// Update overwrites a stored document identified by doc.ID, re-embedding
+	// it only when its content changed
+	Update(ctx context.Context, doc llm.Document) error
 
 	// Delete removes a document by its ID
 	Delete(ctx context.Context, id string) error
 
-	// DeleteBySource removes all documents from a specific source file
-	DeleteBySource(ctx context.Context, source string) error
+	// DeleteBatch removes the documents with the given IDs in one operation and
+	// returns how many of them existed
+	DeleteBatch(ctx context.Context, ids []string) (int, error)
+
+	// DeleteBySource removes all documents from a specific source file in one
+	// operation, so a failure leaves none of them deleted, and returns how many
+	// were removed
+	DeleteBySource(ctx context.Context, source string) (int, error)
 
 	// List returns documents matching the filter criteria
 	List(ctx context.Context, filter llm.ListFilter) ([]llm.Document, error)
 
+	// ListAll calls fn for every document matching the filter, fetching them
+	// page by page. A positive filter.Limit caps the number of documents visited.
+	// Returning ErrStopIteration from fn stops early without an error.
+	ListAll(ctx context.Context, filter llm.ListFilter, fn func(llm.Document) error) error
+
 	// Count returns the total number of documents in the store
 	Count(ctx context.Context) (int64, error)
 
@@ -32,6 +55,9 @@ type VectorStore interface {
 	Close() error
 }
 
+// ErrStopIteration stops a ListAll iteration early without reporting an error
+var ErrStopIteration = errors.New("stop iteration")
+
 // StoreConfig holds configuration for vector store implementations
 type StoreConfig struct {
 	// Embedding dimension (must match the embedding model)
@@ -42,6 +68,37 @@ type StoreConfig struct {
 
 	// Key prefix for stored documents
 	KeyPrefix string
+
+	// DefaultTTL expires added documents when no per-call TTL is given (0 = never)
+	DefaultTTL time.Duration
+
+	// EmbeddingBatchSize is the maximum number of texts per embedding request
+	EmbeddingBatchSize int
+}
+
+// AddOption customizes how documents are added
+type AddOption func(*AddOptions)
+
+// AddOptions holds the resolved options of an add call
+type AddOptions struct {
+	// TTL expires the added documents after this duration (0 = never)
+	TTL time.Duration
+}
+
+// WithTTL expires the added documents after ttl, overriding StoreConfig.DefaultTTL
+func WithTTL(ttl time.Duration) AddOption {
+	return func(o *AddOptions) {
+		o.TTL = ttl
+	}
+}
+
+// ResolveAddOptions applies opts on top of the store defaults
+func ResolveAddOptions(cfg StoreConfig, opts ...AddOption) AddOptions {
+	o := AddOptions{TTL: cfg.DefaultTTL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
 // DefaultStoreConfig returns default configuration
@@ -52,3 +109,10 @@ func DefaultStoreConfig() StoreConfig {
 		KeyPrefix:    "vec:",
 	}
 }
+
+// ContentHash returns the hex sha256 of content with surrounding whitespace
+// trimmed, identifying chunks with the same text
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(content)))
+	return hex.EncodeToString(sum[:])
+}
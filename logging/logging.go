@@ -0,0 +1,56 @@
+// Package logging configures the process-wide leveled logger. Code logs with
+// log/slog; Setup installs the handler, and output of the standard log
+// package is routed through it at info level.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// ParseLevel parses a LOG_LEVEL value (debug, info, warn, error)
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid LOG_LEVEL %q: use debug, info, warn or error", s)
+	}
+}
+
+// New returns a text logger writing records at level and above to w
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Setup installs the default logger from LOG_LEVEL and LOG_FILE. Logs go to
+// LOG_FILE when set (keeping them out of the terminal UI), otherwise to
+// stderr. The returned function closes the log file.
+func Setup() (func() error, error) {
+	level, err := ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		return nil, err
+	}
+
+	var w io.Writer = os.Stderr
+	closeFn := func() error { return nil }
+	if path := os.Getenv("LOG_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		w, closeFn = f, f.Close
+	}
+
+	slog.SetDefault(New(w, level))
+	return closeFn, nil
+}
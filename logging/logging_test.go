@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParseLevel verifies level names and the info default
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{
+		"":        slog.LevelInfo,
+		"DEBUG":   slog.LevelDebug,
+		"warning": slog.LevelWarn,
+		" error ": slog.LevelError,
+	} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+// TestNewFiltersLevel verifies records below the level are dropped
+func TestNewFiltersLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelWarn)
+	logger.Info("hidden")
+	logger.Warn("shown", "err", "boom")
+	if out := buf.String(); strings.Contains(out, "hidden") || !strings.Contains(out, "level=WARN msg=shown err=boom") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+// TestSetupLogFile verifies LOG_FILE receives the default logger's output
+func TestSetupLogFile(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	path := filepath.Join(t.TempDir(), "compass.log")
+	t.Setenv("LOG_FILE", path)
+	t.Setenv("LOG_LEVEL", "debug")
+	closeLog, err := Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.Debug("tracing", "step", 1)
+	closeLog()
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "msg=tracing step=1") {
+		t.Errorf("log file = %q", data)
+	}
+}
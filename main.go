@@ -2,9 +2,11 @@ package main
 
 import (
 	"compass/llm/agent"
+	"compass/logging"
 	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	"compass/tui/chat"
@@ -19,15 +21,40 @@ func init() {
 }
 
 func main() {
+	check := flag.Bool("check", false, "检查模型、向量存储和搜索是否可用，输出报告后退出")
+	flag.Parse()
+
 	ctx := context.Background()
 
+	// 初始化日志
+	closeLog, err := logging.Setup()
+	if err != nil {
+		fmt.Printf("初始化日志失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeLog()
+
 	// 初始化 Agent Runtime
 	runtime, err := agent.SetupRuntime(ctx)
 	if err != nil {
-		log.Fatalf("初始化 Agent 失败: %v", err)
+		slog.Error("初始化 Agent 失败", "err", err)
+		os.Exit(1)
 	}
 	defer runtime.Close()
 
+	// 健康检查模式：输出报告后退出，有检查失败时退出码为 1
+	if *check {
+		report, err := runtime.HealthCheck(ctx)
+		fmt.Print(report)
+		if err != nil {
+			fmt.Println(err)
+			runtime.Close()
+			closeLog()
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 初始化 UI 界面
 	model := chat.InitialModel(runtime)
 	program := tea.NewProgram(
@@ -3,18 +3,66 @@ package pubsub
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 )
 
 const bufferSize = 64
 
+// OverflowPolicy 订阅者缓冲区已满时 Publish 的处理策略
+type OverflowPolicy int
+
+const (
+	// DropNewest 丢弃新事件，发布者不阻塞（Subscribe 的默认策略）
+	DropNewest OverflowPolicy = iota
+	// DropOldest 丢弃缓冲区中最旧的事件为新事件腾出空间，发布者不阻塞
+	DropOldest
+	// Block 阻塞发布者直到订阅者腾出空间、订阅结束或 Broker 关闭
+	Block
+)
+
+// subscription 订阅者的过滤条件、溢出策略和统计
+type subscription struct {
+	types   map[EventType]struct{} // 只接收这些类型的事件，为空表示接收全部
+	policy  OverflowPolicy
+	done    chan struct{} // 订阅结束时关闭，唤醒阻塞中的发布者
+	stopped sync.Once
+	dropped atomic.Uint64 // 因缓冲区已满被丢弃的事件数
+}
+
+// stop 标记订阅结束（可重复调用）
+func (s *subscription) stop() {
+	s.stopped.Do(func() { close(s.done) })
+}
+
+// newSubscription 创建接收指定类型事件的订阅
+func newSubscription(policy OverflowPolicy, eventTypes []EventType) *subscription {
+	sub := &subscription{policy: policy, done: make(chan struct{})}
+	if len(eventTypes) > 0 {
+		sub.types = make(map[EventType]struct{}, len(eventTypes))
+		for _, t := range eventTypes {
+			sub.types[t] = struct{}{}
+		}
+	}
+	return sub
+}
+
+// accepts 判断订阅者是否接收该类型的事件
+func (s *subscription) accepts(t EventType) bool {
+	if len(s.types) == 0 {
+		return true
+	}
+	_, ok := s.types[t]
+	return ok
+}
+
 // Broker 实现了基于内存的发布者/订阅者模型。
 // 它使用泛型 T 来保证事件数据载荷的类型安全。
 type Broker[T any] struct {
-	subs      map[chan Event[T]]struct{} // 活跃订阅者的集合，键为事件通道
-	mu        sync.RWMutex               // 读写锁，保护 subs 映射的并发访问
-	done      chan struct{}              // 关闭信号通道，用于停止所有操作
-	subCount  int                        // 当前订阅者数量（统计用途）
-	maxEvents int                        // 最大事件限制（可用于背压或限制）
+	subs      map[chan Event[T]]*subscription // 活跃订阅者的集合，键为事件通道
+	mu        sync.RWMutex                    // 读写锁，保护 subs 映射的并发访问
+	done      chan struct{}                   // 关闭信号通道，用于停止所有操作
+	subCount  int                             // 当前订阅者数量（统计用途）
+	maxEvents int                             // 最大事件限制（可用于背压或限制）
 }
 
 // NewBroker 创建并返回一个新的具有默认设置的 Broker。
@@ -25,7 +73,7 @@ func NewBroker[T any]() *Broker[T] {
 // NewBrokerWithOptions 创建一个带有自定义通道缓冲区大小和最大事件数限制的 Broker。
 func NewBrokerWithOptions[T any](channelBufferSize, maxEvents int) *Broker[T] {
 	b := &Broker[T]{
-		subs:      make(map[chan Event[T]]struct{}),
+		subs:      make(map[chan Event[T]]*subscription),
 		done:      make(chan struct{}),
 		subCount:  0,
 		maxEvents: maxEvents,
@@ -46,8 +94,9 @@ func (b *Broker[T]) Shutdown() {
 	defer b.mu.Unlock()
 
 	// 关闭所有订阅者的通道并从 map 中移除
-	for ch := range b.subs {
+	for ch, sub := range b.subs {
 		delete(b.subs, ch)
+		sub.stop()
 		close(ch)
 	}
 
@@ -56,7 +105,29 @@ func (b *Broker[T]) Shutdown() {
 
 // Subscribe 注册一个订阅者并返回一个接收事件的通道。
 // 该通道会在 ctx.Done() 信号触发或 Broker 关闭时自动注销并关闭。
+// 缓冲区已满时新事件会被丢弃（DropNewest）。
 func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
+	return b.subscribe(ctx, bufferSize, newSubscription(DropNewest, nil))
+}
+
+// SubscribeFiltered 与 Subscribe 相同，但通道只接收指定类型的事件。
+// 不指定类型时接收全部事件。
+func (b *Broker[T]) SubscribeFiltered(ctx context.Context, eventTypes ...EventType) <-chan Event[T] {
+	return b.subscribe(ctx, bufferSize, newSubscription(DropNewest, eventTypes))
+}
+
+// SubscribeBuffered 注册一个缓冲区大小为 size 的订阅者，缓冲区已满时按 policy 处理：
+// DropNewest/DropOldest 丢弃事件并计入 Dropped，Block 让 Publish 等待订阅者消费。
+// 指定 eventTypes 时只接收这些类型的事件。
+func (b *Broker[T]) SubscribeBuffered(ctx context.Context, size int, policy OverflowPolicy, eventTypes ...EventType) <-chan Event[T] {
+	if size < 0 {
+		size = 0
+	}
+	return b.subscribe(ctx, size, newSubscription(policy, eventTypes))
+}
+
+// subscribe 按过滤条件注册订阅者
+func (b *Broker[T]) subscribe(ctx context.Context, size int, filter *subscription) <-chan Event[T] {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -69,13 +140,20 @@ func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
 	default:
 	}
 
-	sub := make(chan Event[T], bufferSize)
-	b.subs[sub] = struct{}{}
+	sub := make(chan Event[T], size)
+	b.subs[sub] = filter
 	b.subCount++
 
 	// 启动后台协程监听上下文状态以便自动清理
 	go func() {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+		case <-b.done:
+			return
+		}
+
+		// 先唤醒阻塞在该订阅上的发布者，再等待它们释放读锁
+		filter.stop()
 
 		b.mu.Lock()
 		defer b.mu.Unlock()
@@ -97,6 +175,18 @@ func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
 	return sub
 }
 
+// Dropped 返回订阅通道因缓冲区已满丢弃的事件数；通道未注册时返回 0。
+func (b *Broker[T]) Dropped(ch <-chan Event[T]) uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub, filter := range b.subs {
+		if sub == ch {
+			return filter.dropped.Load()
+		}
+	}
+	return 0
+}
+
 // GetSubscriberCount 返回当前活跃的订阅者数量。
 func (b *Broker[T]) GetSubscriberCount() int {
 	b.mu.RLock()
@@ -104,33 +194,57 @@ func (b *Broker[T]) GetSubscriberCount() int {
 	return b.subCount
 }
 
-// Publish 将一个事件分发给所有活跃的订阅者。
-// 该操作是非阻塞的：如果订阅者的缓冲区已满，该订阅者将跳过当前事件。
+// Publish 将一个事件分发给所有接收该类型事件的订阅者。
+// 缓冲区已满时按各订阅者的 OverflowPolicy 处理：默认丢弃新事件，
+// 只有 Block 策略的订阅者会让 Publish 等待。
 func (b *Broker[T]) Publish(t EventType, payload T) {
+	// 分发期间持有读锁，保证订阅通道不会在发送时被关闭
 	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	// 如果 Broker 已关闭，直接放弃分发
 	select {
 	case <-b.done:
-		b.mu.RUnlock()
 		return
 	default:
 	}
 
-	// 复制一份订阅者切片，以缩短持有读锁的时间
-	subscribers := make([]chan Event[T], 0, len(b.subs))
-	for sub := range b.subs {
-		subscribers = append(subscribers, sub)
+	event := Event[T]{Type: t, Payload: payload}
+	for sub, filter := range b.subs {
+		if filter.accepts(t) {
+			b.deliver(sub, filter, event)
+		}
 	}
-	b.mu.RUnlock()
+}
 
-	event := Event[T]{Type: t, Payload: payload}
+// deliver 按订阅者的溢出策略发送一个事件
+func (b *Broker[T]) deliver(sub chan Event[T], filter *subscription, event Event[T]) {
+	select {
+	case sub <- event:
+		return
+	default:
+	}
 
-	// 循环发送，使用 select 默认分支保证非阻塞
-	for _, sub := range subscribers {
+	switch filter.policy {
+	case Block:
+		select {
+		case sub <- event:
+		case <-filter.done:
+		case <-b.done:
+		}
+	case DropOldest:
+		// 取出最旧的事件腾出空间；并发发布时仍可能失败，此时丢弃新事件
+		select {
+		case <-sub:
+			filter.dropped.Add(1)
+		default:
+		}
 		select {
 		case sub <- event:
 		default:
-			// 如果通道已满，则消息无法在不阻塞的情况下发送，直接忽略
+			filter.dropped.Add(1)
 		}
+	default:
+		filter.dropped.Add(1)
 	}
 }
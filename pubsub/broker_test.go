@@ -109,3 +109,136 @@ func TestBrokerShutdown(t *testing.T) {
 		t.Error("Broker 关闭后，订阅通道关闭超时")
 	}
 }
+
+// TestSubscribeFiltered 验证过滤订阅只收到指定类型的事件
+func TestSubscribeFiltered(t *testing.T) {
+	broker := NewBroker[string]()
+	defer broker.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finished := broker.SubscribeFiltered(ctx, FinishedEvent)
+	all := broker.Subscribe(ctx)
+
+	broker.Publish(CreatedEvent, "created")
+	broker.Publish(UpdatedEvent, "updated")
+	broker.Publish(FinishedEvent, "finished")
+
+	select {
+	case event := <-finished:
+		if event.Type != FinishedEvent || event.Payload != "finished" {
+			t.Errorf("过滤订阅收到了 %s/%s, 期望 finished", event.Type, event.Payload)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("接收 finished 事件超时")
+	}
+	select {
+	case event := <-finished:
+		t.Errorf("过滤订阅收到了多余的事件 %s", event.Type)
+	default:
+	}
+
+	// 未过滤的订阅仍收到全部事件
+	if got := len(all); got != 3 {
+		t.Errorf("未过滤订阅收到 %d 个事件, 期望 3", got)
+	}
+}
+
+// TestSubscribeBufferedDropOldest 验证 DropOldest 保留最新的事件并统计丢弃数
+func TestSubscribeBufferedDropOldest(t *testing.T) {
+	broker := NewBroker[int]()
+	defer broker.Shutdown()
+
+	events := broker.SubscribeBuffered(context.Background(), 2, DropOldest)
+	for i := 1; i <= 5; i++ {
+		broker.Publish(UpdatedEvent, i)
+	}
+
+	if got := broker.Dropped(events); got != 3 {
+		t.Errorf("丢弃数为 %d, 期望 3", got)
+	}
+	for _, want := range []int{4, 5} {
+		if event := <-events; event.Payload != want {
+			t.Errorf("收到 %d, 期望 %d", event.Payload, want)
+		}
+	}
+}
+
+// TestSubscribeDropNewestCountsDrops 验证默认订阅丢弃新事件时也会计数
+func TestSubscribeDropNewestCountsDrops(t *testing.T) {
+	broker := NewBroker[int]()
+	defer broker.Shutdown()
+
+	events := broker.Subscribe(context.Background())
+	for i := 0; i < bufferSize+10; i++ {
+		broker.Publish(CreatedEvent, i)
+	}
+
+	if got := broker.Dropped(events); got != 10 {
+		t.Errorf("丢弃数为 %d, 期望 10", got)
+	}
+	if event := <-events; event.Payload != 0 {
+		t.Errorf("第一个事件为 %d, 期望 0", event.Payload)
+	}
+}
+
+// TestSubscribeBufferedBlock 验证 Block 策略让发布者等待订阅者消费，且不丢弃事件
+func TestSubscribeBufferedBlock(t *testing.T) {
+	broker := NewBroker[int]()
+	defer broker.Shutdown()
+
+	events := broker.SubscribeBuffered(context.Background(), 1, Block)
+	broker.Publish(UpdatedEvent, 1)
+
+	published := make(chan struct{})
+	go func() {
+		broker.Publish(UpdatedEvent, 2)
+		close(published)
+	}()
+
+	select {
+	case <-published:
+		t.Fatal("缓冲区已满时 Publish 没有阻塞")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if event := <-events; event.Payload != 1 {
+		t.Errorf("收到 %d, 期望 1", event.Payload)
+	}
+	select {
+	case <-published:
+	case <-time.After(1 * time.Second):
+		t.Fatal("订阅者消费后 Publish 仍未返回")
+	}
+	if event := <-events; event.Payload != 2 {
+		t.Errorf("收到 %d, 期望 2", event.Payload)
+	}
+	if got := broker.Dropped(events); got != 0 {
+		t.Errorf("Block 策略丢弃了 %d 个事件", got)
+	}
+}
+
+// TestSubscribeBufferedBlockUnsubscribe 验证订阅取消时阻塞中的发布者会被释放
+func TestSubscribeBufferedBlockUnsubscribe(t *testing.T) {
+	broker := NewBroker[int]()
+	defer broker.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_ = broker.SubscribeBuffered(ctx, 0, Block)
+
+	published := make(chan struct{})
+	go func() {
+		broker.Publish(UpdatedEvent, 1)
+		close(published)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-published:
+	case <-time.After(1 * time.Second):
+		t.Fatal("取消订阅后 Publish 仍在阻塞")
+	}
+}
@@ -2,25 +2,35 @@ package chat
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"strings"
 
 	"compass/llm/agent"
+	"compass/llm/tools"
 	"compass/pubsub"
 	"compass/tui/component"
+	"compass/tui/component/renderer"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cloudwego/eino/adk"
 )
 
+// messageBufferSize 界面消息订阅的缓冲区大小
+const messageBufferSize = 256
+
 // Model 聊天界面模型
 type Model struct {
 	list   component.ListModel
 	edit   component.EditModel
 	status component.StatusModel
 
-	runtime *agent.Runtime
-	sub     <-chan pubsub.Event[adk.Message]
-	ctx     context.Context
+	runtime   *agent.Runtime
+	streaming bool                             // 是否流式显示助手回复
+	sub       <-chan pubsub.Event[adk.Message] // 用户消息、Agent 消息和运行结束事件
+	summary   <-chan pubsub.Event[tools.SummaryStats]
+	ctx       context.Context
 
 	width  int
 	height int
@@ -30,18 +40,40 @@ type Model struct {
 // InitialModel 创建初始模型
 func InitialModel(runtime *agent.Runtime) Model {
 	ctx := context.Background()
-	sub := runtime.Broker().Subscribe(ctx)
+	// 消息不能丢失：界面跟不上流式输出时让 Agent 等待，而不是丢弃消息。
+	// 运行结束和对话清空事件走同一个订阅，保证在本轮最后一条消息之后处理
+	sub := runtime.Broker().SubscribeBuffered(ctx, messageBufferSize, pubsub.Block,
+		pubsub.CreatedEvent, pubsub.UpdatedEvent, pubsub.FinishedEvent, pubsub.DeletedEvent)
+	summary := tools.SummaryMetrics().Subscribe(ctx)
+
+	list := component.NewListModel()
+	// 恢复上次选择的主题
+	if cfg, err := LoadConfig(); err != nil {
+		slog.Warn("加载界面配置失败", "err", err)
+	} else if cfg.Theme != "" {
+		if err := list.SetTheme(cfg.Theme); err != nil {
+			slog.Warn("恢复主题失败", "err", err)
+		}
+	}
+	// 显示从文件或 Redis 存储恢复的对话历史
+	if history, err := runtime.Store().List(ctx); err != nil {
+		slog.Warn("读取对话历史失败", "err", err)
+	} else {
+		list.SetMessages(history)
+	}
 
 	return Model{
-		list:    component.NewListModel(),
-		edit:    component.NewEditModel(),
-		status:  component.NewStatusModel(),
-		runtime: runtime,
-		sub:     sub,
-		ctx:     ctx,
-		width:   0,
-		height:  0,
-		err:     nil,
+		list:      list,
+		edit:      component.NewEditModel(),
+		status:    component.NewStatusModel(),
+		runtime:   runtime,
+		streaming: agent.StreamingFromEnv(),
+		sub:       sub,
+		summary:   summary,
+		ctx:       ctx,
+		width:     0,
+		height:    0,
+		err:       nil,
 	}
 }
 
@@ -50,22 +82,50 @@ func (m Model) Init() tea.Cmd {
 		m.list.Init(),
 		m.edit.Init(),
 		m.status.Init(),
-		m.waitForAgentMessage(), // 订阅 Agent 消息
+		m.waitForAgentMessage(), // 订阅 Agent 消息和运行结束
+		m.waitForSummaryStats(), // 订阅摘要并发情况
 	)
 }
 
-// waitForAgentMessage 等待 Agent 消息的 Cmd
+// waitForAgentMessage 等待 Agent 消息或运行结束事件的 Cmd
 func (m Model) waitForAgentMessage() tea.Cmd {
 	return func() tea.Msg {
-		event := <-m.sub
+		event, ok := <-m.sub
+		if !ok {
+			return nil // Broker 已关闭
+		}
 		return event
 	}
 }
 
+// waitForSummaryStats 等待摘要并发统计的 Cmd
+func (m Model) waitForSummaryStats() tea.Cmd {
+	return func() tea.Msg {
+		return <-m.summary
+	}
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// 运行结束事件转为 RunFinishedMsg 交给各子组件
+	if event, ok := msg.(pubsub.Event[adk.Message]); ok && event.Type == pubsub.FinishedEvent {
+		msg = component.RunFinishedMsg{}
+		cmds = append(cmds, m.waitForAgentMessage())
+	}
+
+	// 对话清空事件在被取消运行的所有事件之后到达，此时清空界面
+	if event, ok := msg.(pubsub.Event[adk.Message]); ok && event.Type == pubsub.DeletedEvent {
+		m.list.Clear()
+		m.list.AddSystemMessage("对话已清空")
+		m.refreshTokenUsage()
+		return m, m.waitForAgentMessage()
+	}
+
 	switch msg := msg.(type) {
+	case clearFailedMsg:
+		m.list.AddSystemMessage(fmt.Sprintf("清空对话历史失败: %v", msg.err))
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -86,20 +146,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status.SetWidth(m.width)
 
 	case component.EditorSubmitMsg:
+		// 斜杠命令
+		// 命令会修改 m，先执行再返回，不依赖返回值的求值顺序
+		if strings.HasPrefix(msg.Value, "/") {
+			cmd := m.handleCommand(strings.TrimSpace(msg.Value))
+			return m, cmd
+		}
+
 		// 调用 Agent（在 goroutine 中）
 		go func() {
-			_ = m.runtime.Run(msg.Value)
+			if m.streaming {
+				_ = m.runtime.RunStreaming(msg.Value)
+			} else {
+				_ = m.runtime.Run(msg.Value)
+			}
 		}()
 
 	case pubsub.Event[adk.Message]:
 		// 继续等待下一条消息
 		cmds = append(cmds, m.waitForAgentMessage())
+		m.refreshTokenUsage()
 		// list 和 status 会在下面透传处理
 
+	case component.RunFinishedMsg:
+		m.refreshTokenUsage()
+
+	case pubsub.Event[tools.SummaryStats]:
+		cmds = append(cmds, m.waitForSummaryStats())
+
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
+			// 运行中先取消本轮运行并回到输入框，空闲时退出
+			if m.runtime.Cancel() {
+				var cmd tea.Cmd
+				m.status, cmd = m.status.Flash("Run canceled, press again to quit")
+				return m, cmd
+			}
 			return m, tea.Quit
+		case tea.KeyCtrlY:
+			// 复制最近一条助手回复
+			cmd := m.copyLastAssistant()
+			return m, cmd
 		}
 	}
 
@@ -118,6 +206,156 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// refreshTokenUsage 在状态栏显示本会话的 token 用量
+func (m *Model) refreshTokenUsage() {
+	m.status = m.status.WithTokens(m.runtime.TokenUsage().Total(), m.runtime.MaxTokensPerSession())
+}
+
+// copyLastAssistant 复制最近一条助手回复的 Markdown 原文，并在状态栏提示结果
+func (m *Model) copyLastAssistant() tea.Cmd {
+	text, ok := m.list.LastAssistantMessage()
+	status := "Copied last reply to clipboard"
+	if !ok {
+		status = "No assistant reply to copy"
+	} else if err := copyToClipboard(text); err != nil {
+		status = "Copy failed: " + err.Error()
+	}
+	var cmd tea.Cmd
+	m.status, cmd = m.status.Flash(status)
+	return cmd
+}
+
+// handleCommand 处理斜杠命令
+func (m *Model) handleCommand(command string) tea.Cmd {
+	switch command {
+	case "/retry":
+		// 移除界面上的最后一轮回复，重新生成
+		m.list.DropLastTurn()
+		var cmd tea.Cmd
+		m.status, cmd = m.status.Start()
+		go func() {
+			_ = m.runtime.Retry()
+		}()
+		return cmd
+	case "/approve":
+		// 批准等待中的工具调用或计划后继续执行
+		var cmd tea.Cmd
+		m.status, cmd = m.status.Start()
+		go func() {
+			if m.runtime.PendingApproval() {
+				_ = m.runtime.ResolveApproval(true)
+				return
+			}
+			_ = m.runtime.ApprovePlan()
+		}()
+		return cmd
+	case "/cancel":
+		// 取消正在进行的运行
+		m.runtime.Cancel()
+	case "/clear":
+		return m.clearCommand()
+	case "/reject":
+		// 拒绝的工具调用不执行，Agent 收到拒绝说明后继续
+		if m.runtime.PendingApproval() {
+			var cmd tea.Cmd
+			m.status, cmd = m.status.Start()
+			go func() {
+				_ = m.runtime.ResolveApproval(false)
+			}()
+			return cmd
+		}
+		go func() {
+			_ = m.runtime.RejectPlan()
+		}()
+	default:
+		switch args := strings.Fields(command); args[0] {
+		case "/embedding":
+			// 列出或切换 embedding 模型
+			go m.runtime.EmbeddingCommand(args[1:])
+		case "/export":
+			m.exportCommand(args[1:])
+		case "/theme":
+			m.themeCommand(args[1:])
+		case "/find":
+			return m.findCommand(strings.TrimSpace(strings.TrimPrefix(command, "/find")))
+		}
+	}
+	return nil
+}
+
+// clearFailedMsg 清空对话历史失败
+type clearFailedMsg struct {
+	err error
+}
+
+// clearCommand 处理 /clear：在后台取消正在进行的运行并等待其结束，再清空对话存储和 token 用量。
+// 界面在收到清空事件后清空，Agent 保持可用
+func (m *Model) clearCommand() tea.Cmd {
+	runtime, ctx := m.runtime, m.ctx
+	return func() tea.Msg {
+		if err := runtime.Clear(ctx); err != nil {
+			return clearFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// themeCommand 处理 /theme [name]：无参数时列出可用主题，否则切换并保存到界面设置
+func (m *Model) themeCommand(args []string) {
+	if len(args) != 1 {
+		m.list.AddSystemMessage(fmt.Sprintf("当前主题: %s；可用主题: %s\n使用 /theme <name> 切换",
+			m.list.ThemeName(), strings.Join(renderer.ThemeNames(), ", ")))
+		return
+	}
+	if err := m.list.SetTheme(args[0]); err != nil {
+		m.list.AddSystemMessage(fmt.Sprintf("切换主题失败: %v", err))
+		return
+	}
+
+	// 设置文件损坏时以新设置覆盖
+	cfg, err := LoadConfig()
+	if err != nil {
+		cfg = Config{}
+	}
+	cfg.Theme = m.list.ThemeName()
+	if err := SaveConfig(cfg); err != nil {
+		m.list.AddSystemMessage(fmt.Sprintf("已切换到 %s，但保存设置失败: %v", m.list.ThemeName(), err))
+		return
+	}
+	m.list.AddSystemMessage("已切换到主题 " + m.list.ThemeName())
+}
+
+// findCommand 处理 /find <text>：高亮匹配的消息并跳到下一处，重复执行继续向前；
+// 不带参数时结束查找
+func (m *Model) findCommand(query string) tea.Cmd {
+	var status string
+	if query == "" {
+		m.list.ClearFind()
+		status = "Search cleared"
+	} else if current, total := m.list.Find(query); total == 0 {
+		status = fmt.Sprintf("No matches for %q", query)
+	} else {
+		status = fmt.Sprintf("Match %d/%d for %q", current, total, query)
+	}
+	var cmd tea.Cmd
+	m.status, cmd = m.status.Flash(status)
+	return cmd
+}
+
+// exportCommand 处理 /export <file>：按后缀导出为 Markdown 或 JSON，结果以系统消息显示
+func (m *Model) exportCommand(args []string) {
+	if len(args) != 1 {
+		m.list.AddSystemMessage("用法: /export <file.md|file.json>")
+		return
+	}
+	path := args[0]
+	if err := exportConversation(path, m.list.Messages()); err != nil {
+		m.list.AddSystemMessage(fmt.Sprintf("导出失败: %v", err))
+		return
+	}
+	m.list.AddSystemMessage("对话已导出到 " + path)
+}
+
 func (m Model) View() string {
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
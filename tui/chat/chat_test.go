@@ -0,0 +1,108 @@
+package chat
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"compass/llm/agent"
+	"compass/pubsub"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// echoChatModel 返回固定回复的模型
+type echoChatModel struct{}
+
+func (m *echoChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return schema.AssistantMessage("answer", nil), nil
+}
+
+func (m *echoChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("answer", nil)}), nil
+}
+
+func (m *echoChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+// newTestRuntime 创建使用固定回复模型的 Runtime
+func newTestRuntime(t *testing.T) *agent.Runtime {
+	t.Helper()
+	t.Setenv("COMPASS_TUI_CONFIG", filepath.Join(t.TempDir(), "tui.json")) // 不读取真实的界面设置
+	rt, err := agent.NewRuntime(context.Background(), &echoChatModel{}, nil)
+	if err != nil {
+		t.Fatalf("创建 Runtime 失败: %v", err)
+	}
+	t.Cleanup(rt.Close)
+	return rt
+}
+
+// TestInitialModelShowsHistory 验证启动时显示对话存储中已有的历史
+func TestInitialModelShowsHistory(t *testing.T) {
+	rt := newTestRuntime(t)
+	ctx := context.Background()
+	rt.Store().Add(ctx, schema.UserMessage("question"))
+	rt.Store().Add(ctx, schema.AssistantMessage("saved answer", nil))
+
+	m := InitialModel(rt)
+	msgs := m.list.Messages()
+	if len(msgs) != 2 || msgs[0].Content != "question" || msgs[1].Content != "saved answer" {
+		t.Errorf("应显示恢复的历史, 实际: %v", msgs)
+	}
+}
+
+// TestRunFinishedAfterMessages 验证运行结束事件与消息共用订阅，在本轮所有消息之后处理
+func TestRunFinishedAfterMessages(t *testing.T) {
+	rt := newTestRuntime(t)
+	m := InitialModel(rt)
+	if err := rt.RunStreaming("question"); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		msg := m.waitForAgentMessage()()
+		event, ok := msg.(pubsub.Event[adk.Message])
+		if !ok {
+			t.Fatalf("意外的消息: %T", msg)
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+		if event.Type == pubsub.FinishedEvent {
+			break
+		}
+	}
+
+	msgs := m.list.Messages()
+	if len(msgs) != 2 || msgs[1].Content != "answer" || agent.IsPartial(msgs[1]) {
+		t.Errorf("运行结束时应已显示完整回复, 实际: %v", msgs)
+	}
+	if view := m.status.View(); !strings.Contains(view, "Ready") {
+		t.Errorf("运行结束后状态栏应为 Ready: %q", view)
+	}
+}
+
+// TestClearCommand 验证 /clear 清空对话存储，界面在收到清空事件后清空
+func TestClearCommand(t *testing.T) {
+	rt := newTestRuntime(t)
+	ctx := context.Background()
+	rt.Store().Add(ctx, schema.UserMessage("question"))
+	m := InitialModel(rt)
+
+	if msg := m.clearCommand()(); msg != nil {
+		t.Fatalf("清空应成功, 实际: %v", msg)
+	}
+	if history, _ := rt.Store().List(ctx); len(history) != 0 {
+		t.Errorf("对话存储应已清空, 实际: %v", history)
+	}
+
+	updated, _ := m.Update(m.waitForAgentMessage()())
+	m = updated.(Model)
+	msgs := m.list.Messages()
+	if len(msgs) != 1 || msgs[0].Content != "对话已清空" {
+		t.Errorf("界面应只剩清空提示, 实际: %v", msgs)
+	}
+}
@@ -0,0 +1,20 @@
+package chat
+
+import (
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// copyToClipboard 将文本复制到系统剪贴板。
+// SSH 会话或本地剪贴板不可用时（例如缺少 xclip）改用 OSC52 转义序列，由终端完成复制。
+func copyToClipboard(text string) error {
+	if os.Getenv("SSH_TTY") == "" {
+		if err := clipboard.WriteAll(text); err == nil {
+			return nil
+		}
+	}
+	_, err := osc52.New(text).WriteTo(os.Stderr)
+	return err
+}
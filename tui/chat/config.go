@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultConfigFile 界面设置的默认保存位置
+const DefaultConfigFile = ".compass/tui.json"
+
+// Config 需要跨启动保留的界面设置
+type Config struct {
+	Theme string `json:"theme,omitempty"`
+}
+
+// configPath 返回界面设置文件路径，可通过 COMPASS_TUI_CONFIG 覆盖
+func configPath() string {
+	if path := os.Getenv("COMPASS_TUI_CONFIG"); path != "" {
+		return path
+	}
+	return DefaultConfigFile
+}
+
+// LoadConfig 读取界面设置；文件不存在时返回空设置
+func LoadConfig() (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(configPath())
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("读取界面设置失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("解析界面设置失败: %w", err)
+	}
+	return cfg, nil
+}
+
+// SaveConfig 保存界面设置
+func SaveConfig(cfg Config) error {
+	path := configPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("创建设置目录失败: %w", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,168 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"compass/llm/agent"
+	"compass/llm/tools"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// exportedToolCall 导出的工具调用，结果内联在调用之下
+type exportedToolCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+}
+
+// exportedMessage 导出的一轮对话（工具消息已内联到对应调用中）
+type exportedMessage struct {
+	Role      string             `json:"role"`
+	Content   string             `json:"content,omitempty"`
+	Reasoning string             `json:"reasoning,omitempty"`
+	ToolCalls []exportedToolCall `json:"tool_calls,omitempty"`
+}
+
+// exportConversation 将对话写入 path，.json 后缀导出 JSON，其余导出 Markdown
+func exportConversation(path string, msgs []adk.Message) error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var err error
+		data, err = json.MarshalIndent(exportMessages(msgs), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode conversation: %w", err)
+		}
+	} else {
+		data = []byte(exportMarkdown(msgs, time.Now()))
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// indexToolResults 按 ToolCallID 索引工具消息的内容
+func indexToolResults(msgs []adk.Message) map[string]string {
+	results := make(map[string]string)
+	for _, msg := range msgs {
+		if msg.Role == schema.Tool && msg.ToolCallID != "" {
+			results[msg.ToolCallID] = msg.Content
+		}
+	}
+	return results
+}
+
+// exportMessages 转换为 JSON 导出结构，跳过流式临时消息和已内联的工具消息
+func exportMessages(msgs []adk.Message) []exportedMessage {
+	results := indexToolResults(msgs)
+	out := make([]exportedMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.Role == schema.Tool || agent.IsPartial(msg) {
+			continue
+		}
+		em := exportedMessage{
+			Role:      string(msg.Role),
+			Content:   msg.Content,
+			Reasoning: msg.ReasoningContent,
+		}
+		for _, tc := range msg.ToolCalls {
+			em.ToolCalls = append(em.ToolCalls, exportedToolCall{
+				Name:      tc.Function.Name,
+				Arguments: rawJSON(tc.Function.Arguments),
+				Result:    rawJSON(results[tc.ID]),
+			})
+		}
+		out = append(out, em)
+	}
+	return out
+}
+
+// rawJSON 合法 JSON 原样保留，否则编码为字符串
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	encoded, _ := json.Marshal(s)
+	return encoded
+}
+
+// exportMarkdown 渲染为 Markdown：每轮一个二级标题，工具调用及其结果作为三级标题
+func exportMarkdown(msgs []adk.Message, now time.Time) string {
+	results := indexToolResults(msgs)
+
+	var sb strings.Builder
+	sb.WriteString("# Compass Conversation\n\n")
+	fmt.Fprintf(&sb, "_Exported %s_\n", now.Format("2006-01-02 15:04:05"))
+
+	for _, msg := range msgs {
+		if msg.Role == schema.Tool || agent.IsPartial(msg) {
+			continue
+		}
+		switch msg.Role {
+		case schema.User:
+			sb.WriteString("\n## User\n")
+		case schema.Assistant:
+			if agent.IsPlanMessage(msg) {
+				sb.WriteString("\n## Plan\n")
+			} else {
+				sb.WriteString("\n## Assistant\n")
+			}
+		case schema.System:
+			sb.WriteString("\n## System\n")
+		default:
+			continue
+		}
+
+		if msg.ReasoningContent != "" {
+			sb.WriteString("\n> " + strings.ReplaceAll(strings.TrimSpace(msg.ReasoningContent), "\n", "\n> ") + "\n")
+		}
+		if msg.Content != "" {
+			sb.WriteString("\n" + strings.TrimSpace(msg.Content) + "\n")
+		}
+		for _, tc := range msg.ToolCalls {
+			fmt.Fprintf(&sb, "\n### Tool: %s\n\n", tc.Function.Name)
+			if tc.Function.Arguments != "" {
+				sb.WriteString(fence(tc.Function.Arguments, "json"))
+			}
+			if result, ok := results[tc.ID]; ok {
+				sb.WriteString("\nResult:\n\n")
+				sb.WriteString(fence(toolResultText(result), ""))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// toolResultText 结构化工具结果只保留内容，错误状态加上前缀
+func toolResultText(raw string) string {
+	var result tools.ToolResult
+	if err := json.Unmarshal([]byte(raw), &result); err != nil || result.Status == "" {
+		return raw
+	}
+	if result.Status == tools.StatusError {
+		return "Error: " + result.Content
+	}
+	return result.Content
+}
+
+// fence 用代码块包裹文本，内容含反引号时加长围栏
+func fence(text, lang string) string {
+	marker := "```"
+	for strings.Contains(text, marker) {
+		marker += "`"
+	}
+	return marker + lang + "\n" + strings.TrimRight(text, "\n") + "\n" + marker + "\n"
+}
@@ -1,12 +1,16 @@
 package component
 
 import (
+	"strings"
+
+	"compass/llm/agent"
 	"compass/pubsub"
 	"compass/tui/component/renderer"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
 )
 
 // ListModel 封装消息列表组件
@@ -14,12 +18,16 @@ import (
 type ListModel struct {
 	viewport viewport.Model
 	messages []adk.Message
+	partial  int // 流式生成中的临时消息位置，-1 表示没有
 	width    int
 	height   int
 	ready    bool
 
 	// renderer 消息渲染器
 	renderer *renderer.MessageRenderer
+
+	findQuery   string // 查找内容（小写），为空表示未在查找
+	findCurrent int    // 当前定位的匹配消息位置，-1 表示尚未定位
 }
 
 // NewListModel 创建新的消息列表组件
@@ -31,12 +39,14 @@ func NewListModel() ListModel {
 	msgRenderer := renderer.NewMessageRenderer()
 
 	return ListModel{
-		viewport: vp,
-		messages: make([]adk.Message, 0),
-		renderer: msgRenderer,
-		width:    30,
-		height:   5,
-		ready:    true,
+		viewport:    vp,
+		messages:    make([]adk.Message, 0),
+		partial:     -1,
+		findCurrent: -1,
+		renderer:    msgRenderer,
+		width:       30,
+		height:      5,
+		ready:       true,
 	}
 }
 
@@ -58,18 +68,18 @@ func (m ListModel) Update(msg tea.Msg) (ListModel, tea.Cmd) {
 		case tea.MouseButtonWheelDown:
 			m.viewport.ScrollDown(3)
 		}
-	case pubsub.Event[adk.Message]:
-		if msg.Type != pubsub.FinishedEvent {
-			// 更新消息和索引
-			m.messages = append(m.messages, msg.Payload)
-
-			// 索引消息中的工具结果（如果是工具消息）
-			m.renderer.IndexMessage(msg.Payload)
-
+	case RunFinishedMsg:
+		// 运行结束时丢弃未被完整消息取代的临时消息（例如取消或超时）
+		if m.partial != -1 {
+			m.dropPartial()
 			m.updateViewportContent()
-			m.viewport.GotoBottom()
 		}
 		return m, nil
+	case pubsub.Event[adk.Message]:
+		m.addMessage(msg.Payload)
+		m.updateViewportContent()
+		m.viewport.GotoBottom()
+		return m, nil
 	}
 
 	// 更新 viewport
@@ -109,9 +119,195 @@ func (m *ListModel) SetSize(width, height int) {
 	m.viewport.GotoBottom()
 }
 
-// updateViewportContent 更新 viewport 内容
-func (m *ListModel) updateViewportContent() {
-	// 直接使用 renderer 渲染，不再传递 findToolResult
-	content := m.renderer.RenderMessages(m.messages)
+// addMessage 追加消息；流式生成中的临时消息原地更新，直到完整消息取代它
+func (m *ListModel) addMessage(msg adk.Message) {
+	if agent.IsPartial(msg) {
+		if m.partial == -1 {
+			m.partial = len(m.messages)
+			m.messages = append(m.messages, msg)
+		} else {
+			m.messages[m.partial] = msg
+		}
+		return
+	}
+
+	// 同一条回复的完整消息取代临时消息，其他消息（如系统提示）照常追加
+	if m.partial != -1 && m.messages[m.partial].Role == msg.Role {
+		m.messages[m.partial] = msg
+		m.partial = -1
+	} else {
+		m.dropPartial()
+		m.messages = append(m.messages, msg)
+	}
+
+	// 索引消息中的工具结果（如果是工具消息）
+	m.renderer.IndexMessage(msg)
+}
+
+// dropPartial 移除流式生成中的临时消息
+func (m *ListModel) dropPartial() {
+	if m.partial == -1 {
+		return
+	}
+	m.messages = append(m.messages[:m.partial], m.messages[m.partial+1:]...)
+	m.partial = -1
+}
+
+// SetMessages 替换全部消息（例如启动时显示已保存的对话历史）
+func (m *ListModel) SetMessages(msgs []adk.Message) {
+	m.messages = append(make([]adk.Message, 0, len(msgs)), msgs...)
+	m.partial = -1
+	for _, msg := range m.messages {
+		m.renderer.IndexMessage(msg)
+	}
+	if len(m.messages) > 0 {
+		m.updateViewportContent()
+		m.viewport.GotoBottom()
+	}
+}
+
+// SetTheme 切换渲染主题并重新渲染
+func (m *ListModel) SetTheme(name string) error {
+	if err := m.renderer.SetTheme(name); err != nil {
+		return err
+	}
+	m.updateViewportContent()
+	return nil
+}
+
+// ThemeName 返回当前主题名
+func (m *ListModel) ThemeName() string {
+	return m.renderer.ThemeName()
+}
+
+// Clear 清空全部消息、工具结果索引和查找状态，恢复欢迎界面
+func (m *ListModel) Clear() {
+	m.messages = make([]adk.Message, 0)
+	m.partial = -1
+	m.findQuery = ""
+	m.findCurrent = -1
+	m.renderer.ClearIndex()
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+}
+
+// DropLastTurn 移除最后一条用户消息之后的所有消息（用于重新生成）
+func (m *ListModel) DropLastTurn() {
+	m.dropPartial()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].Role == schema.User {
+			m.messages = m.messages[:i+1]
+			break
+		}
+	}
+	m.updateViewportContent()
+	m.viewport.GotoBottom()
+}
+
+// Messages 返回当前显示的全部消息（副本）
+func (m *ListModel) Messages() []adk.Message {
+	return append([]adk.Message(nil), m.messages...)
+}
+
+// AddSystemMessage 显示一条仅用于界面的系统提示（不写入对话存储）
+func (m *ListModel) AddSystemMessage(content string) {
+	m.dropPartial()
+	m.messages = append(m.messages, schema.SystemMessage(content))
+	m.updateViewportContent()
+	m.viewport.GotoBottom()
+}
+
+// LastAssistantMessage 返回最近一条助手回复的原始 Markdown（跳过计划和仅含工具调用的消息）
+func (m *ListModel) LastAssistantMessage() (string, bool) {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := m.messages[i]
+		if msg.Role == schema.Assistant && msg.Content != "" && !agent.IsPlanMessage(msg) {
+			return msg.Content, true
+		}
+	}
+	return "", false
+}
+
+// Find 查找内容包含 query 的消息（不区分大小写），高亮所有匹配并滚动到下一处。
+// 从最新的消息向前定位，重复查找同一内容时继续向前，到头后回到最新的匹配。
+// 返回当前匹配的序号（从 1 开始）和匹配总数，没有匹配时返回 0, 0。
+func (m *ListModel) Find(query string) (int, int) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query != m.findQuery {
+		m.findQuery = query
+		m.findCurrent = -1
+	}
+
+	matches := m.findMatches()
+	if len(matches) == 0 {
+		m.findCurrent = -1
+		m.updateViewportContent()
+		return 0, 0
+	}
+
+	// 取当前位置之前最近的匹配，没有则回到最新的匹配
+	pos := len(matches) - 1
+	for i := len(matches) - 1; i >= 0; i-- {
+		if m.findCurrent != -1 && matches[i] < m.findCurrent {
+			pos = i
+			break
+		}
+	}
+	m.findCurrent = matches[pos]
+
+	offsets := m.updateViewportContent()
+	if m.findCurrent < len(offsets) && offsets[m.findCurrent] >= 0 {
+		m.viewport.SetYOffset(offsets[m.findCurrent])
+	}
+	return pos + 1, len(matches)
+}
+
+// ClearFind 结束查找，恢复正常渲染
+func (m *ListModel) ClearFind() {
+	m.findQuery = ""
+	m.findCurrent = -1
+	m.updateViewportContent()
+	m.viewport.GotoBottom()
+}
+
+// findMatches 返回内容包含查找内容的消息位置（升序）
+func (m *ListModel) findMatches() []int {
+	if m.findQuery == "" {
+		return nil
+	}
+	var matches []int
+	for i, msg := range m.messages {
+		if msg.Role == schema.Tool {
+			continue
+		}
+		if strings.Contains(strings.ToLower(msg.Content), m.findQuery) ||
+			strings.Contains(strings.ToLower(msg.ReasoningContent), m.findQuery) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// updateViewportContent 更新 viewport 内容，返回每条消息的起始行号
+func (m *ListModel) updateViewportContent() []int {
+	var highlight func(int) renderer.Highlight
+	if m.findQuery != "" {
+		matched := make(map[int]bool)
+		for _, i := range m.findMatches() {
+			matched[i] = true
+		}
+		highlight = func(i int) renderer.Highlight {
+			switch {
+			case i == m.findCurrent:
+				return renderer.HighlightCurrent
+			case matched[i]:
+				return renderer.HighlightMatch
+			}
+			return renderer.HighlightNone
+		}
+	}
+
+	content, offsets := m.renderer.RenderMessagesWithOffsets(m.messages, highlight)
 	m.viewport.SetContent(content)
+	return offsets
 }
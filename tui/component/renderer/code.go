@@ -0,0 +1,134 @@
+package renderer
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codePreviewLines 工具结果中代码预览最多显示的行数
+const codePreviewLines = 12
+
+// extLanguages 文件扩展名到代码块语言的映射（glamour/chroma 可识别的名称）
+var extLanguages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".mjs":   "javascript",
+	".jsx":   "jsx",
+	".ts":    "typescript",
+	".tsx":   "tsx",
+	".java":  "java",
+	".kt":    "kotlin",
+	".rs":    "rust",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".rb":    "ruby",
+	".php":   "php",
+	".swift": "swift",
+	".sh":    "bash",
+	".bash":  "bash",
+	".ps1":   "powershell",
+	".sql":   "sql",
+	".json":  "json",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".xml":   "xml",
+	".html":  "html",
+	".css":   "css",
+	".lua":   "lua",
+	".proto": "protobuf",
+	".diff":  "diff",
+	".patch": "diff",
+}
+
+// fencedBlock 匹配带语言提示的 Markdown 代码块开头
+var fencedBlock = regexp.MustCompile("(?m)^```([A-Za-z0-9_+#.-]+)[ \t]*$")
+
+// languageForPath 根据文件扩展名（或 URL 路径的扩展名）返回语言，未知时为空
+func languageForPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	if i := strings.Index(p, "://"); i != -1 {
+		// URL：只看路径部分
+		p = p[i+3:]
+		if j := strings.IndexAny(p, "?#"); j != -1 {
+			p = p[:j]
+		}
+		return extLanguages[strings.ToLower(path.Ext(p))]
+	}
+	if base := filepath.Base(p); base == "Makefile" || base == "Dockerfile" {
+		return strings.ToLower(base)
+	}
+	return extLanguages[strings.ToLower(filepath.Ext(p))]
+}
+
+// splitCode 从工具结果中分离说明文字和代码：
+//   - 已有带语言提示的代码块时使用它
+//   - 包含统一 diff 时，diff 部分作为 diff 代码
+//   - 多行内容且来源文件/URL 的扩展名是已知语言时，整体作为代码
+//
+// 不是代码时 lang 为空。
+func splitCode(content, source string) (text, code, lang string) {
+	if loc := fencedBlock.FindStringSubmatchIndex(content); loc != nil {
+		lang = content[loc[2]:loc[3]]
+		body := content[loc[1]:]
+		body = strings.TrimPrefix(body, "\n")
+		if end := strings.Index(body, "\n```"); end != -1 {
+			body = body[:end]
+		}
+		return strings.TrimSpace(content[:loc[0]]), body, lang
+	}
+
+	if i := diffStart(content); i != -1 {
+		return strings.TrimSpace(content[:i]), content[i:], "diff"
+	}
+
+	if strings.Contains(strings.TrimSpace(content), "\n") {
+		if lang := languageForPath(source); lang != "" {
+			return "", content, lang
+		}
+	}
+	return content, "", ""
+}
+
+// diffStart 返回统一 diff 头（"--- " 后紧跟 "+++ "）的起始位置，没有时为 -1
+func diffStart(content string) int {
+	offset := 0
+	for offset < len(content) {
+		i := strings.Index(content[offset:], "--- ")
+		if i == -1 {
+			return -1
+		}
+		start := offset + i
+		if start == 0 || content[start-1] == '\n' {
+			rest := content[start:]
+			if nl := strings.IndexByte(rest, '\n'); nl != -1 && strings.HasPrefix(rest[nl+1:], "+++ ") {
+				return start
+			}
+		}
+		offset = start + 4
+	}
+	return -1
+}
+
+// codeFence 将代码截断到 codePreviewLines 行后包装为带语言的 Markdown 代码块
+func codeFence(code, lang string) string {
+	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	if len(lines) > codePreviewLines {
+		lines = append(lines[:codePreviewLines], "…")
+	}
+	body := strings.Join(lines, "\n")
+	marker := "```"
+	for strings.Contains(body, marker) {
+		marker += "`"
+	}
+	return marker + lang + "\n" + body + "\n" + marker
+}
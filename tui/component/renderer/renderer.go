@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"compass/llm/agent"
 	"compass/llm/tools"
 
 	"github.com/charmbracelet/glamour"
@@ -21,6 +22,7 @@ type MessageRenderer struct {
 	icons            *Icons
 	toolResults      map[string]string // toolCallID -> JSON string
 	viewportWidth    int
+	themeName        string
 }
 
 // NewMessageRenderer 创建消息渲染器
@@ -34,29 +36,100 @@ func NewMessageRenderer() *MessageRenderer {
 		theme:            DefaultTheme(),
 		icons:            DefaultIcons(),
 		toolResults:      make(map[string]string),
+		themeName:        DefaultThemeName,
 	}
 }
 
+// SetTheme 切换主题：重建 glamour 渲染器并替换界面样式。
+// 名称无效或渲染器创建失败时返回错误，当前主题保持不变。
+func (r *MessageRenderer) SetTheme(name string) error {
+	preset, err := lookupTheme(name)
+	if err != nil {
+		return err
+	}
+	markdownRenderer, err := glamour.NewTermRenderer(
+		glamour.WithStylePath(preset.glamourStyle),
+		glamour.WithWordWrap(0),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create markdown renderer for theme %q: %w", name, err)
+	}
+	r.markdownRenderer = markdownRenderer
+	r.theme = preset.theme()
+	r.themeName = strings.ToLower(strings.TrimSpace(name))
+	return nil
+}
+
+// ThemeName 返回当前主题名
+func (r *MessageRenderer) ThemeName() string {
+	return r.themeName
+}
+
+// Highlight 消息的查找高亮状态
+type Highlight int
+
+const (
+	HighlightNone    Highlight = iota // 不高亮
+	HighlightMatch                    // 匹配查找内容
+	HighlightCurrent                  // 当前定位的匹配
+)
+
 // RenderMessages 渲染所有消息
 func (r *MessageRenderer) RenderMessages(messages []adk.Message) string {
+	content, _ := r.RenderMessagesWithOffsets(messages, nil)
+	return content
+}
+
+// RenderMessagesWithOffsets 渲染所有消息，并返回每条消息在结果中的起始行号（未显示的消息为 -1）。
+// highlight 为 nil 时不高亮，否则按返回值为消息加上左侧标记。
+func (r *MessageRenderer) RenderMessagesWithOffsets(messages []adk.Message, highlight func(int) Highlight) (string, []int) {
 	if len(messages) == 0 {
-		return "Welcome to the chat room!\nType a message and press Enter to send."
+		return "Welcome to the chat room!\nType a message and press Enter to send.", nil
 	}
 
-	var lines []string
-	for _, msg := range messages {
+	offsets := make([]int, len(messages))
+	var parts []string
+	line := 0
+	for i, msg := range messages {
+		offsets[i] = -1
 		rendered := r.RenderMessage(msg)
-		if rendered != "" {
-			lines = append(lines, rendered)
+		if rendered == "" {
+			continue
 		}
+
+		h := HighlightNone
+		if highlight != nil {
+			h = highlight(i)
+		}
+		rendered = r.wrapMessage(rendered, h)
+
+		offsets[i] = line
+		line += strings.Count(rendered, "\n") + 2 // 消息之间空一行
+		parts = append(parts, rendered)
 	}
 
-	content := strings.Join(lines, "\n\n")
+	return strings.Join(parts, "\n\n"), offsets
+}
+
+// wrapMessage 按视口宽度折行，高亮的消息加上左侧标记
+func (r *MessageRenderer) wrapMessage(rendered string, h Highlight) string {
+	var style lipgloss.Style
+	switch h {
+	case HighlightMatch:
+		style = r.theme.Match
+	case HighlightCurrent:
+		style = r.theme.CurrentMatch
+	default:
+		if r.viewportWidth > 0 {
+			return lipgloss.NewStyle().Width(r.viewportWidth).Render(rendered)
+		}
+		return rendered
+	}
 
 	if r.viewportWidth > 0 {
-		return lipgloss.NewStyle().Width(r.viewportWidth).Render(content)
+		style = style.Width(r.viewportWidth - style.GetHorizontalFrameSize())
 	}
-	return content
+	return style.Render(rendered)
 }
 
 // RenderMessage 渲染单条消息
@@ -65,6 +138,9 @@ func (r *MessageRenderer) RenderMessage(msg adk.Message) string {
 	case schema.User:
 		return r.renderUser(msg)
 	case schema.Assistant:
+		if agent.IsPlanMessage(msg) {
+			return r.renderPlan(msg)
+		}
 		return r.renderAssistant(msg)
 	case schema.System:
 		return r.renderSystem(msg)
@@ -106,6 +182,12 @@ func (r *MessageRenderer) renderAssistant(msg adk.Message) string {
 	return strings.Join(parts, "\n")
 }
 
+// renderPlan 渲染执行前的工具调用计划
+func (r *MessageRenderer) renderPlan(msg adk.Message) string {
+	header := r.theme.Thinking.Render("Plan:")
+	return header + "\n" + r.renderMarkdown(msg.Content)
+}
+
 // renderSystem 渲染系统消息
 func (r *MessageRenderer) renderSystem(msg adk.Message) string {
 	if msg.Content == "" {
@@ -169,6 +251,9 @@ func (r *MessageRenderer) renderToolMinimal(result *tools.ToolResult, callNum in
 	}
 
 	// 关键指标
+	if md.IsDir {
+		parts = append(parts, "目录")
+	}
 	if md.LineCount > 0 {
 		parts = append(parts, fmt.Sprintf("%d行", md.LineCount))
 	}
@@ -181,6 +266,9 @@ func (r *MessageRenderer) renderToolMinimal(result *tools.ToolResult, callNum in
 	if md.ByteCount > 0 {
 		parts = append(parts, FormatBytes(md.ByteCount))
 	}
+	if md.Truncated {
+		parts = append(parts, r.truncatedBadge(md))
+	}
 
 	status := r.icons.Success
 	if result.Status == tools.StatusError {
@@ -242,6 +330,9 @@ func (r *MessageRenderer) renderToolCompact(result *tools.ToolResult, callNum in
 				metrics = append(metrics, fmt.Sprintf("📊 %d", md.StatusCode))
 			}
 		}
+		if md.Truncated {
+			metrics = append(metrics, r.truncatedBadge(md))
+		}
 	}
 
 	if len(metrics) > 0 {
@@ -282,11 +373,9 @@ func (r *MessageRenderer) renderToolFull(result *tools.ToolResult, callNum int)
 		}
 	}
 
-	// 内容预览
+	// 内容预览：代码按检测出的语言高亮，其他内容截断显示
 	if result.Content != "" {
-		preview := Truncate(result.Content, 150)
-		lines = append(lines,
-			r.theme.ToolBorder.Render("│  ")+r.theme.Result.Render(preview))
+		lines = append(lines, r.renderContentPreview(result.Content, md)...)
 	}
 
 	lines = append(lines, r.theme.ToolBorder.Render("└─"))
@@ -294,6 +383,34 @@ func (r *MessageRenderer) renderToolFull(result *tools.ToolResult, callNum int)
 	return strings.Join(lines, "\n")
 }
 
+// renderContentPreview 渲染工具结果内容预览。
+// 内容包含代码时（代码块语言提示、diff 或来源文件扩展名），说明文字照常截断显示，
+// 代码包装为带语言的代码块交给 glamour 高亮；非代码内容只显示截断的文本。
+func (r *MessageRenderer) renderContentPreview(content string, md *tools.Metadata) []string {
+	source := ""
+	if md != nil {
+		source = md.FilePath
+		if source == "" {
+			source = md.URL
+		}
+	}
+
+	text, code, lang := splitCode(content, source)
+	var lines []string
+	if text != "" {
+		lines = append(lines,
+			r.theme.ToolBorder.Render("│  ")+r.theme.Result.Render(Truncate(text, 150)))
+	}
+	if lang == "" {
+		return lines
+	}
+
+	for _, line := range strings.Split(r.renderMarkdown(codeFence(code, lang)), "\n") {
+		lines = append(lines, r.theme.ToolBorder.Render("│  ")+line)
+	}
+	return lines
+}
+
 // formatMetadataSummary 格式化元数据摘要
 func (r *MessageRenderer) formatMetadataSummary(md *tools.Metadata) string {
 	var parts []string
@@ -313,10 +430,21 @@ func (r *MessageRenderer) formatMetadataSummary(md *tools.Metadata) string {
 	if md.FileCount > 0 {
 		parts = append(parts, fmt.Sprintf("📁 %d 文件", md.FileCount))
 	}
+	if md.Truncated {
+		parts = append(parts, r.truncatedBadge(md))
+	}
 
 	return strings.Join(parts, " · ")
 }
 
+// truncatedBadge 截断标记，已知省略字节数时一并显示
+func (r *MessageRenderer) truncatedBadge(md *tools.Metadata) string {
+	if md.OmittedBytes > 0 {
+		return fmt.Sprintf("%s 已截断 %s", r.icons.Truncated, FormatBytes(md.OmittedBytes))
+	}
+	return r.icons.Truncated + " 已截断"
+}
+
 // renderMarkdown 渲染 Markdown 内容
 func (r *MessageRenderer) renderMarkdown(content string) string {
 	if r.markdownRenderer == nil {
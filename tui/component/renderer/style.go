@@ -1,6 +1,12 @@
 package renderer
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Theme 主题样式配置
 type Theme struct {
@@ -13,6 +19,10 @@ type Theme struct {
 	Compact    lipgloss.Style
 	Result     lipgloss.Style
 	Arguments  lipgloss.Style
+
+	// 查找高亮（左侧标记）
+	Match        lipgloss.Style
+	CurrentMatch lipgloss.Style
 }
 
 // DefaultTheme 返回默认主题
@@ -48,27 +58,111 @@ func DefaultTheme() *Theme {
 
 		Arguments: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("215")),
+
+		Match: lipgloss.NewStyle().
+			Border(lipgloss.ThickBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("240")).
+			PaddingLeft(1),
+
+		CurrentMatch: lipgloss.NewStyle().
+			Border(lipgloss.ThickBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("212")).
+			PaddingLeft(1),
+	}
+}
+
+// LightTheme 返回适合浅色终端背景的主题
+func LightTheme() *Theme {
+	t := DefaultTheme()
+	t.User = t.User.Foreground(lipgloss.Color("25"))            // Blue
+	t.Assistant = t.Assistant.Foreground(lipgloss.Color("130")) // Brown
+	t.System = t.System.Foreground(lipgloss.Color("244"))
+	t.Thinking = t.Thinking.Foreground(lipgloss.Color("242"))
+	t.ToolBorder = t.ToolBorder.Foreground(lipgloss.Color("246"))
+	t.Minimal = t.Minimal.Foreground(lipgloss.Color("238"))
+	t.Compact = t.Compact.Foreground(lipgloss.Color("236"))
+	t.Result = t.Result.Foreground(lipgloss.Color("24"))
+	t.Arguments = t.Arguments.Foreground(lipgloss.Color("166"))
+	t.Match = t.Match.BorderForeground(lipgloss.Color("250"))
+	t.CurrentMatch = t.CurrentMatch.BorderForeground(lipgloss.Color("162"))
+	return t
+}
+
+// PlainTheme 返回不使用颜色的主题（只保留粗体、斜体和边框）
+func PlainTheme() *Theme {
+	plain := lipgloss.NewStyle()
+	border := plain.Border(lipgloss.ThickBorder(), false, false, false, true).PaddingLeft(1)
+	return &Theme{
+		User:         plain.Bold(true),
+		Assistant:    plain.Bold(true),
+		System:       plain.Italic(true),
+		Thinking:     plain.Italic(true),
+		ToolBorder:   plain,
+		Minimal:      plain,
+		Compact:      plain,
+		Result:       plain,
+		Arguments:    plain,
+		Match:        border.BorderStyle(lipgloss.NormalBorder()),
+		CurrentMatch: border,
+	}
+}
+
+// DefaultThemeName 默认主题名
+const DefaultThemeName = "dracula"
+
+// themePreset 主题预设：glamour 的 Markdown 样式和对应的界面主题
+type themePreset struct {
+	glamourStyle string
+	theme        func() *Theme
+}
+
+// themePresets 可用主题，键为 /theme 使用的名称
+var themePresets = map[string]themePreset{
+	"dracula": {glamourStyle: "dracula", theme: DefaultTheme},
+	"dark":    {glamourStyle: "dark", theme: DefaultTheme},
+	"light":   {glamourStyle: "light", theme: LightTheme},
+	"notty":   {glamourStyle: "notty", theme: PlainTheme},
+}
+
+// ThemeNames 返回可用主题名（按字母排序）
+func ThemeNames() []string {
+	names := make([]string, 0, len(themePresets))
+	for name := range themePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupTheme 按名称（不区分大小写）查找主题预设
+func lookupTheme(name string) (themePreset, error) {
+	preset, ok := themePresets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return themePreset{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
 	}
+	return preset, nil
 }
 
 // Icons 图标配置
 type Icons struct {
-	Tool    string
-	File    string
-	Search  string
-	Clock   string
-	Success string
-	Error   string
+	Tool      string
+	File      string
+	Search    string
+	Clock     string
+	Success   string
+	Error     string
+	Truncated string
 }
 
 // DefaultIcons 返回默认图标
 func DefaultIcons() *Icons {
 	return &Icons{
-		Tool:    "🔧",
-		File:    "📄",
-		Search:  "🔍",
-		Clock:   "⏱",
-		Success: "✅",
-		Error:   "❌",
+		Tool:      "🔧",
+		File:      "📄",
+		Search:    "🔍",
+		Clock:     "⏱",
+		Success:   "✅",
+		Error:     "❌",
+		Truncated: "✂️",
 	}
 }
@@ -2,7 +2,9 @@ package component
 
 import (
 	"fmt"
+	"time"
 
+	"compass/llm/tools"
 	"compass/pubsub"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -11,12 +13,26 @@ import (
 	"github.com/cloudwego/eino/adk"
 )
 
+// RunFinishedMsg Agent 运行结束（对应 Broker 的 FinishedEvent）
+type RunFinishedMsg struct{}
+
+// flashDuration 短暂提示的显示时长
+const flashDuration = 2 * time.Second
+
+// flashExpiredMsg 短暂提示到期，id 用于忽略已被新提示取代的到期消息
+type flashExpiredMsg struct{ id int }
+
 // StatusModel 封装状态显示组件（spinner + 状态文本）
 type StatusModel struct {
 	spinner spinner.Model
 	running bool
 	text    string
 	width   int
+	summary tools.SummaryStats // 摘要子 Agent 的并发情况
+	flash   string             // 短暂提示（例如复制成功），到期后自动清除
+	flashID int
+	tokens  int // 本会话累计的 token 用量
+	budget  int // 会话 token 预算，0 表示不限制
 }
 
 // NewStatusModel 创建新的状态组件
@@ -42,22 +58,26 @@ func (m StatusModel) Init() tea.Cmd {
 // Update 更新组件状态
 func (m StatusModel) Update(msg tea.Msg) (StatusModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case flashExpiredMsg:
+		if msg.id == m.flashID {
+			m.flash = ""
+		}
+		return m, nil
+	case pubsub.Event[tools.SummaryStats]:
+		m.summary = msg.Payload
 	case pubsub.Event[adk.Message]:
-		switch msg.Type {
-		case pubsub.CreatedEvent:
-			// 用户发送消息，启动 spinner
-			if !m.running {
-				m.running = true
-				m.text = "Processing..."
-				return m, m.spinner.Tick
-			}
-		case pubsub.FinishedEvent:
-			// Agent 完成，停止 spinner
-			if m.running {
-				m.running = false
-				m.text = "Ready"
-				return m, nil
-			}
+		// 用户发送消息，启动 spinner
+		if msg.Type == pubsub.CreatedEvent && !m.running {
+			m.running = true
+			m.text = "Processing..."
+			return m, m.spinner.Tick
+		}
+	case RunFinishedMsg:
+		// Agent 完成，停止 spinner
+		if m.running {
+			m.running = false
+			m.text = "Ready"
+			return m, nil
 		}
 	}
 
@@ -77,6 +97,20 @@ func (m StatusModel) View() string {
 	if m.running {
 		content = fmt.Sprintf("%s %s", m.spinner.View(), m.text)
 	}
+	// 有摘要在运行或排队时显示 "summarizing 运行数/总数"
+	if m.summary.Pending() > 0 {
+		content += fmt.Sprintf(" · summarizing %d/%d", m.summary.Active, m.summary.Pending())
+	}
+	// 有用量后显示 "tokens 已用[/预算]"
+	if m.tokens > 0 {
+		content += " · tokens " + formatTokens(m.tokens)
+		if m.budget > 0 {
+			content += "/" + formatTokens(m.budget)
+		}
+	}
+	if m.flash != "" {
+		content += " · " + m.flash
+	}
 	return style.Render(content)
 }
 
@@ -87,6 +121,35 @@ func (m StatusModel) Start() (StatusModel, tea.Cmd) {
 	return m, m.spinner.Tick
 }
 
+// Flash 短暂显示一条提示，flashDuration 后自动清除
+func (m StatusModel) Flash(text string) (StatusModel, tea.Cmd) {
+	m.flashID++
+	m.flash = text
+	id := m.flashID
+	return m, tea.Tick(flashDuration, func(time.Time) tea.Msg {
+		return flashExpiredMsg{id: id}
+	})
+}
+
+// WithTokens 设置本会话的 token 用量和预算（0 表示不限制）
+func (m StatusModel) WithTokens(used, budget int) StatusModel {
+	m.tokens = used
+	m.budget = budget
+	return m
+}
+
+// formatTokens 将 token 数格式化为 950、12.3k、1.2M 等简短形式
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprint(n)
+	}
+}
+
 // Stop 停止 spinner
 func (m StatusModel) Stop() {
 	m.running = false